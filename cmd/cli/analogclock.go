@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/analogclock"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/logging"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
+	"github.com/spf13/cobra"
+)
+
+var (
+	analogclockTargetAddr  string
+	analogclockTimeValue   string
+	analogclockColor       string
+	analogclockShowSeconds bool
+	analogclockVerbose     bool
+)
+
+var AnalogclockCmd = &cobra.Command{
+	Use:   "analogclock",
+	Short: "Renders an analog clock and shows it on the iDot display",
+	Long: `Renders an analog clock face (hour/minute/second hands and tick marks) locally
+and uploads it to the iDot display as a 60 frame animation looping every minute.
+
+Examples:
+  idm-cli analogclock
+  idm-cli analogclock --color cyan --show-seconds=false`,
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := logging.NewLogger(analogclockVerbose)
+		if err := doAnalogClock(logger); err != nil {
+			fmt.Printf("error: %v\n", err)
+		}
+	},
+}
+
+func init() {
+	AnalogclockCmd.Flags().StringVar(&analogclockTargetAddr, "target", "", "Target iDot display MAC address (auto-discovers if not specified)")
+	AnalogclockCmd.Flags().StringVar(&analogclockTimeValue, "time", "", "Time value in RFC1123Z format. As per 'date -R'")
+	AnalogclockCmd.Flags().StringVar(&analogclockColor, "color", "white", fmt.Sprintf("Hands color (%s)", graphic.ColorHelp()))
+	AnalogclockCmd.Flags().BoolVar(&analogclockShowSeconds, "show-seconds", true, "Show the second hand")
+	AnalogclockCmd.Flags().BoolVar(&analogclockVerbose, "verbose", false, "Enable verbose debug logging")
+}
+
+func doAnalogClock(logger log.Logger) error {
+	t := time.Now()
+	if len(analogclockTimeValue) > 0 {
+		var err error
+		t, err = time.Parse(time.RFC1123Z, analogclockTimeValue)
+		if err != nil {
+			return err
+		}
+	}
+
+	color, err := graphic.ParseColor(analogclockColor)
+	if err != nil {
+		return err
+	}
+
+	opts := analogclock.DefaultOptions()
+	opts.HandColor = color
+	opts.ShowSeconds = analogclockShowSeconds
+
+	gifData, err := analogclock.GenerateGIF(t, opts)
+	if err != nil {
+		return err
+	}
+
+	device, err := connectDevice(logger, analogclockTargetAddr)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := device.Disconnect(); err != nil {
+			level.Error(logger).Log("msg", "Failed to disconnect", "err", err)
+		}
+	}()
+
+	if err := protocol.SendGIF(device, gifData, gifUploadConfig(), logger); err != nil {
+		return err
+	}
+
+	// Allow time for BLE writes to complete before disconnecting
+	time.Sleep(500 * time.Millisecond)
+
+	return nil
+}
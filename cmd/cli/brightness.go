@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/logging"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
+	"github.com/spf13/cobra"
+)
+
+var (
+	brightnessTargetAddr string
+	brightnessLevel      int
+	brightnessVerbose    bool
+)
+
+var BrightnessCmd = &cobra.Command{
+	Use:   "brightness",
+	Short: "Sets the backlight brightness of the iDot display",
+	Long: `Sets the hardware backlight brightness of the iDot display, independently of
+the content being shown.
+
+Examples:
+  idm-cli brightness --level 30`,
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := logging.NewLogger(brightnessVerbose)
+		if err := doSetBrightness(logger); err != nil {
+			fmt.Printf("error: %v\n", err)
+		}
+	},
+}
+
+func init() {
+	BrightnessCmd.Flags().StringVar(&brightnessTargetAddr, "target", "", "Target iDot display MAC address (auto-discovers if not specified)")
+	BrightnessCmd.Flags().IntVar(&brightnessLevel, "level", 100, "Brightness level in percent (0-100)")
+	BrightnessCmd.Flags().BoolVar(&brightnessVerbose, "verbose", false, "Enable verbose debug logging")
+}
+
+func doSetBrightness(logger log.Logger) error {
+	if brightnessLevel < 0 || brightnessLevel > 100 {
+		return fmt.Errorf("invalid brightness level %d (valid: 0-100)", brightnessLevel)
+	}
+
+	device, err := connectDevice(logger, brightnessTargetAddr)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := device.Disconnect(); err != nil {
+			level.Error(logger).Log("msg", "Failed to disconnect", "err", err)
+		}
+	}()
+
+	if err := protocol.SetBrightness(device, brightnessLevel); err != nil {
+		return err
+	}
+
+	// Allow time for BLE writes to complete before disconnecting
+	time.Sleep(500 * time.Millisecond)
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+// Chart types
+const (
+	chartTypeBar  = "bar"
+	chartTypeLine = "line"
+)
+
+var (
+	chartTargetAddr string
+	chartValues     string
+	chartType       string
+	chartColorName  string
+	chartVerbose    bool
+)
+
+var ChartCmd = &cobra.Command{
+	Use:   "chart",
+	Short: "Displays a bar or line chart of a series of numbers",
+	Long: fmt.Sprintf(`Displays a tiny bar or line chart of up to %d numbers, scaled between their
+smallest and largest values to fill the iDot display.
+
+Examples:
+  idm-cli chart --values 3,5,2,8,6
+  idm-cli chart --values "21.5,22.1,23.4,22.8" --type line --color cyan`, graphic.DisplayWidth),
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := logging.NewLogger(chartVerbose)
+		if err := doChart(logger); err != nil {
+			fmt.Printf("error: %v\n", err)
+		}
+	},
+}
+
+func init() {
+	ChartCmd.Flags().StringVar(&chartTargetAddr, "target", "", "Target iDot display MAC address (auto-discovers if not specified)")
+	ChartCmd.Flags().StringVar(&chartValues, "values", "", "Comma separated numbers to plot (required)")
+	ChartCmd.Flags().StringVar(&chartType, "type", chartTypeBar, fmt.Sprintf("Chart type (%s, %s)", chartTypeBar, chartTypeLine))
+	ChartCmd.Flags().StringVar(&chartColorName, "color", "green", fmt.Sprintf("Bars or line color (%s)", graphic.ColorHelp()))
+	ChartCmd.Flags().BoolVar(&chartVerbose, "verbose", false, "Enable verbose debug logging")
+	ChartCmd.MarkFlagRequired("values")
+}
+
+func doChart(logger log.Logger) error {
+	var values []float64
+	for _, field := range strings.Split(chartValues, ",") {
+		value, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			return fmt.Errorf("invalid value %q: %w", field, err)
+		}
+		values = append(values, value)
+	}
+
+	color, err := graphic.ParseColor(chartColorName)
+	if err != nil {
+		return err
+	}
+	opts := graphic.DefaultChartOptions()
+	opts.Color = color
+
+	buf := graphic.NewBuffer()
+	switch strings.ToLower(chartType) {
+	case chartTypeBar:
+		err = graphic.DrawBarChart(buf, values, opts)
+	case chartTypeLine:
+		err = graphic.DrawLineChart(buf, values, opts)
+	default:
+		return fmt.Errorf("unknown chart type: %s (valid: %s, %s)", chartType, chartTypeBar, chartTypeLine)
+	}
+	if err != nil {
+		return err
+	}
+
+	if outputFile != "" {
+		return writeOutput(&graphic.Image{Type: graphic.ImageTypeStatic, StaticData: buf}, outputFile)
+	}
+
+	device, err := connectDevice(logger, chartTargetAddr)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := device.Disconnect(); err != nil {
+			level.Error(logger).Log("msg", "Failed to disconnect", "err", err)
+		}
+	}()
+
+	if err := sendStaticImage(device, buf); err != nil {
+		return err
+	}
+
+	// Allow time for BLE writes to complete before disconnecting
+	time.Sleep(500 * time.Millisecond)
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/logging"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
+	"github.com/spf13/cobra"
+)
+
+var (
+	clearTargetAddr string
+	clearVerbose    bool
+)
+
+var ClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Blanks the iDot display to black, keeping it on",
+	Long: `Blanks the iDot display to black. Unlike off, the display stays on and
+shows the next content sent to it right away.
+
+Examples:
+  idm-cli clear`,
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := logging.NewLogger(clearVerbose)
+		if err := doClear(logger); err != nil {
+			fmt.Printf("error: %v\n", err)
+		}
+	},
+}
+
+func init() {
+	ClearCmd.Flags().StringVar(&clearTargetAddr, "target", "", "Target iDot display MAC address (auto-discovers if not specified)")
+	ClearCmd.Flags().BoolVar(&clearVerbose, "verbose", false, "Enable verbose debug logging")
+}
+
+func doClear(logger log.Logger) error {
+	device, err := connectDevice(logger, clearTargetAddr)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := device.Disconnect(); err != nil {
+			level.Error(logger).Log("msg", "Failed to disconnect", "err", err)
+		}
+	}()
+
+	if err := protocol.ClearScreen(device); err != nil {
+		return err
+	}
+
+	// Allow time for BLE writes to complete before disconnecting
+	time.Sleep(500 * time.Millisecond)
+
+	return nil
+}
@@ -2,11 +2,11 @@ package main
 
 import (
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/clock"
 	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
 	"github.com/pracucci/idotmatrix-overclocked/pkg/logging"
 	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
@@ -19,6 +19,8 @@ var clockShow24h bool
 var clockColor string
 var clockTimeValue string
 var clockTargetAddr string
+var clockLocalRender bool
+var clockShowSeconds bool
 var clockVerbose bool
 
 var ClockCmd = &cobra.Command{
@@ -38,7 +40,9 @@ func init() {
 	ClockCmd.Flags().IntVar(&clockClockStyle, "style", protocol.ClockAnimatedHourGlass, "Style of clock. 0:Default 1:Christmas 2:Racing 3:Inverted 4:Hour Glass")
 	ClockCmd.Flags().BoolVar(&clockShowDate, "show-date", true, "Show date as well as time")
 	ClockCmd.Flags().BoolVar(&clockShow24h, "24hour", true, "Show time in 24 hour format")
-	ClockCmd.Flags().StringVar(&clockColor, "color", "white", fmt.Sprintf("Clock color (%s)", strings.Join(graphic.ColorNames(), ", ")))
+	ClockCmd.Flags().StringVar(&clockColor, "color", "white", fmt.Sprintf("Clock color (%s)", graphic.ColorHelp()))
+	ClockCmd.Flags().BoolVar(&clockLocalRender, "local-render", false, "Render the clock locally with the 5x7 font and send it as an image instead of using the device clock mode")
+	ClockCmd.Flags().BoolVar(&clockShowSeconds, "show-seconds", false, "Show seconds (only with --local-render)")
 	ClockCmd.Flags().BoolVar(&clockVerbose, "verbose", false, "Enable verbose debug logging")
 }
 
@@ -60,8 +64,8 @@ func doSetClock(logger log.Logger) error {
 		t = time.Now()
 	}
 
-	device := protocol.NewDevice(logger)
-	if err = device.Connect(clockTargetAddr); err != nil {
+	device, err := connectDevice(logger, clockTargetAddr)
+	if err != nil {
 		return err
 	}
 	defer func() {
@@ -70,23 +74,26 @@ func doSetClock(logger log.Logger) error {
 		}
 	}()
 
-	if err = protocol.SetTime(device, t.Year(), int(t.Month()), t.Day(), int(t.Weekday())+1, t.Hour(),
-		t.Minute(), t.Second()); err != nil {
-		return err
-	}
-
 	var customColor graphic.Color
 	if clockColor != "" {
-		colorName := strings.ToLower(strings.TrimSpace(clockColor))
-		color, ok := graphic.ColorPalette[colorName]
-		if !ok {
-			return fmt.Errorf("unknown color: %s (valid: %s)", colorName, strings.Join(graphic.ColorNames(), ", "))
+		if customColor, err = graphic.ParseColor(clockColor); err != nil {
+			return err
 		}
-		customColor = graphic.Color{color[0], color[1], color[2]}
 	}
 
-	if err := protocol.SetClockMode(device, clockClockStyle, clockShowDate, clockShow24h, customColor); err != nil {
-		return err
+	if clockLocalRender {
+		if err := sendLocalClock(device, t, customColor); err != nil {
+			return err
+		}
+	} else {
+		if err = protocol.SetTime(device, t.Year(), int(t.Month()), t.Day(), int(t.Weekday())+1, t.Hour(),
+			t.Minute(), t.Second()); err != nil {
+			return err
+		}
+
+		if err := protocol.SetClockMode(device, clockClockStyle, clockShowDate, clockShow24h, customColor); err != nil {
+			return err
+		}
 	}
 
 	// Allow time for BLE writes to complete before disconnecting
@@ -94,3 +101,22 @@ func doSetClock(logger log.Logger) error {
 
 	return nil
 }
+
+// sendLocalClock renders the clock with the 5x7 font and sends it as a static image.
+func sendLocalClock(device protocol.DeviceConnection, t time.Time, color graphic.Color) error {
+	opts := clock.DefaultDigitalOptions()
+	opts.TextColor = color
+	opts.ShadowColor = graphic.ShadowFor(color)
+	opts.Hour24 = clockShow24h
+	opts.ShowSeconds = clockShowSeconds
+	opts.ShowDate = clockShowDate
+
+	rawBytes, err := clock.GenerateDigitalImage(t, opts).RawBytes()
+	if err != nil {
+		return err
+	}
+	if err := protocol.SetDrawMode(device, 1); err != nil {
+		return err
+	}
+	return protocol.SendImage(device, rawBytes)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultConfigName is the config file looked up in the home directory when
+// --config isn't given
+const defaultConfigName = ".idm-cli.yaml"
+
+// configFile is the YAML file with flag defaults (empty uses ~/.idm-cli.yaml)
+var configFile string
+
+// loadConfig reads a YAML config file mapping flag names to their default
+// values, e.g. "target: AA:BB:CC:DD:EE:FF". A missing default config file is
+// not an error, a missing --config file is.
+func loadConfig(path string) (map[string]string, error) {
+	explicit := path != ""
+	if !explicit {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, nil
+		}
+		path = filepath.Join(home, defaultConfigName)
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var raw map[string]any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	values := make(map[string]string, len(raw))
+	for name, value := range raw {
+		switch value.(type) {
+		case nil:
+			return nil, fmt.Errorf("config %s: %q has no value", path, name)
+		case map[string]any, []any:
+			return nil, fmt.Errorf("config %s: %q must be a single value", path, name)
+		}
+		values[name] = fmt.Sprint(value)
+	}
+	return values, nil
+}
+
+// applyConfig sets the flags of cmd that weren't given on the command line to
+// their config values. Flags always win over the config, and config entries
+// the command has no flag for are ignored, since one config is shared by all
+// the commands.
+func applyConfig(cmd *cobra.Command, values map[string]string) error {
+	var err error
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		value, ok := values[flag.Name]
+		if !ok || flag.Changed || err != nil {
+			return
+		}
+		if setErr := flag.Value.Set(value); setErr != nil {
+			err = fmt.Errorf("invalid config value %q for %s: %w", value, flag.Name, setErr)
+		}
+	})
+	return err
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
+)
+
+// connectedTarget runs the CLI with args and returns the target address the
+// command connected to
+func connectedTarget(t *testing.T, args ...string) string {
+	t.Helper()
+
+	originalConnect := connectDevice
+	t.Cleanup(func() {
+		connectDevice = originalConnect
+		resetFlags(t)
+	})
+
+	var target string
+	connectDevice = func(_ log.Logger, targetAddr string) (*protocol.Device, error) {
+		target = targetAddr
+		return nil, fmt.Errorf("not connecting in tests")
+	}
+
+	rootCmd.SetArgs(args)
+	require.NoError(t, rootCmd.Execute())
+	return target
+}
+
+// resetFlags restores the text command flags to their defaults, so flags
+// given in a test don't leak into the next one
+func resetFlags(t *testing.T) {
+	TextCmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		require.NoError(t, flag.Value.Set(flag.DefValue))
+		flag.Changed = false
+	})
+}
+
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestConfigTarget(t *testing.T) {
+	config := writeConfig(t, "target: AA:BB:CC:DD:EE:FF\nverbose: true\n")
+
+	t.Run("used when the flag is absent", func(t *testing.T) {
+		target := connectedTarget(t, "text", "--text", "HI", "--config", config)
+		assert.Equal(t, "AA:BB:CC:DD:EE:FF", target)
+	})
+
+	t.Run("ignored when the flag is present", func(t *testing.T) {
+		target := connectedTarget(t, "text", "--text", "HI", "--config", config, "--target", "11:22:33:44:55:66")
+		assert.Equal(t, "11:22:33:44:55:66", target)
+	})
+}
+
+func TestLoadConfig(t *testing.T) {
+	values, err := loadConfig(writeConfig(t, "target: AA:BB:CC:DD:EE:FF\nbrightness: 50\nverbose: true\n"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"target": "AA:BB:CC:DD:EE:FF", "brightness": "50", "verbose": "true"}, values)
+
+	_, err = loadConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+
+	_, err = loadConfig(writeConfig(t, "target: [a, b]\n"))
+	assert.Error(t, err)
+
+	_, err = loadConfig(writeConfig(t, "target:\n"))
+	assert.Error(t, err)
+}
+
+func TestApplyConfigInvalidValue(t *testing.T) {
+	t.Cleanup(func() { resetFlags(t) })
+	assert.Error(t, applyConfig(TextCmd, map[string]string{"loops": "many"}))
+}
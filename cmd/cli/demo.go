@@ -7,20 +7,18 @@ import (
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
-	"github.com/pracucci/idotmatrix-overclocked/pkg/assets"
-	"github.com/pracucci/idotmatrix-overclocked/pkg/emoji"
-	"github.com/pracucci/idotmatrix-overclocked/pkg/fire"
-	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
-	"github.com/pracucci/idotmatrix-overclocked/pkg/grot"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/demo"
 	"github.com/pracucci/idotmatrix-overclocked/pkg/logging"
 	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
-	"github.com/pracucci/idotmatrix-overclocked/pkg/text"
 	"github.com/spf13/cobra"
 )
 
 var (
 	demoTargetAddr string
 	demoVerbose    bool
+	demoItems      string
+	demoDurations  string
+	demoShuffle    bool
 )
 
 var DemoCmd = &cobra.Command{
@@ -31,9 +29,13 @@ var DemoCmd = &cobra.Command{
 The demo cycles through emojis, animations, and text effects in random order.
 Press Ctrl+C to stop.
 
+Categories: snake, emoji, grot, fire, text
+
 Examples:
   idm-cli demo
-  idm-cli demo --target AA:BB:CC:DD:EE:FF`,
+  idm-cli demo --target AA:BB:CC:DD:EE:FF
+  idm-cli demo --items emoji,fire,text --durations emoji=5s,text=8s
+  idm-cli demo --items text,emoji --shuffle=false`,
 	Run: func(cmd *cobra.Command, args []string) {
 		logger := logging.NewLogger(demoVerbose)
 		if err := doDemo(logger); err != nil {
@@ -45,19 +47,28 @@ Examples:
 func init() {
 	DemoCmd.Flags().StringVar(&demoTargetAddr, "target", "", "Target iDot display MAC address (auto-discovers if not specified)")
 	DemoCmd.Flags().BoolVar(&demoVerbose, "verbose", false, "Enable verbose debug logging")
-}
-
-// demoItem represents one item in the demo sequence
-type demoItem struct {
-	name     string
-	generate func() ([]byte, error)
-	duration time.Duration
+	DemoCmd.Flags().StringVar(&demoItems, "items", "", "Comma separated categories to show, in this order when not shuffled (default: all)")
+	DemoCmd.Flags().StringVar(&demoDurations, "durations", "", "Comma separated per-category durations, e.g. emoji=5s,text=8s")
+	DemoCmd.Flags().BoolVar(&demoShuffle, "shuffle", true, "Show the items in random order, reshuffled on every loop")
 }
 
 func doDemo(logger log.Logger) error {
+	categories, err := demo.ParseCategories(demoItems)
+	if err != nil {
+		return err
+	}
+	durations, err := demo.ParseDurations(demoDurations)
+	if err != nil {
+		return err
+	}
+	items, err := demo.Select(categories, durations)
+	if err != nil {
+		return err
+	}
+
 	// Connect to device
-	device := protocol.NewDevice(logger)
-	if err := device.Connect(demoTargetAddr); err != nil {
+	device, err := connectDevice(logger, demoTargetAddr)
+	if err != nil {
 		return err
 	}
 	defer func() {
@@ -66,83 +77,36 @@ func doDemo(logger log.Logger) error {
 		}
 	}()
 
-	items := []demoItem{
-		{"snake", func() ([]byte, error) { return assets.Preview.ReadFile("preview/snake-preview.gif") }, 4 * time.Second},
-		{"emoji: rocket", generateEmojiGIF("rocket"), 3 * time.Second},
-		{"emoji: thumbsup", generateEmojiGIF("thumbsup"), 3 * time.Second},
-		{"emoji: rofl", generateEmojiGIF("rofl"), 3 * time.Second},
-		{"grot: halloween-4", generateGrotGIF("halloween-4"), 3 * time.Second},
-		{"grot: matrix", generateGrotGIF("matrix"), 4 * time.Second},
-		{"fire", generateFireGIF(), 3 * time.Second},
-		{"text: FIRE! (fireworks)", generateTextGIF("FIRE!", "fireworks", graphic.Red), 4 * time.Second},
-		{"text: LGTM (appear-disappear)", generateTextGIF("LGTM", "appear-disappear", graphic.Green), 4 * time.Second},
+	order := "random order"
+	if !demoShuffle {
+		order = "fixed order"
 	}
-
-	fmt.Printf("Starting demo with %d items (random order)\n", len(items))
+	fmt.Printf("Starting demo with %d items (%s)\n", len(items), order)
 	fmt.Println("Press Ctrl+C to stop")
 
 	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
 
 	for {
 		// Shuffle items for each iteration
-		rng.Shuffle(len(items), func(i, j int) {
-			items[i], items[j] = items[j], items[i]
-		})
+		if demoShuffle {
+			demo.Shuffle(items, rng)
+		}
 
 		for _, item := range items {
-			fmt.Printf("Showing: %s\n", item.name)
+			fmt.Printf("Showing: %s\n", item.Name)
 
-			gifBytes, err := item.generate()
+			gifBytes, err := item.Generate()
 			if err != nil {
-				level.Error(logger).Log("msg", "Failed to generate", "name", item.name, "err", err)
+				level.Error(logger).Log("msg", "Failed to generate", "name", item.Name, "err", err)
 				continue
 			}
 
-			if err := protocol.SendGIF(device, gifBytes, logger); err != nil {
-				level.Error(logger).Log("msg", "Failed to send GIF", "name", item.name, "err", err)
+			if err := protocol.SendGIF(device, gifBytes, gifUploadConfig(), logger); err != nil {
+				level.Error(logger).Log("msg", "Failed to send GIF", "name", item.Name, "err", err)
 				continue
 			}
 
-			time.Sleep(item.duration)
-		}
-	}
-}
-
-func generateEmojiGIF(name string) func() ([]byte, error) {
-	return func() ([]byte, error) {
-		img, err := emoji.Generate(name)
-		if err != nil {
-			return nil, err
-		}
-		return img.GIFBytes()
-	}
-}
-
-func generateGrotGIF(name string) func() ([]byte, error) {
-	return func() ([]byte, error) {
-		img, err := grot.Generate(name)
-		if err != nil {
-			return nil, err
-		}
-		return img.GIFBytes()
-	}
-}
-
-func generateFireGIF() func() ([]byte, error) {
-	return func() ([]byte, error) {
-		return fire.GenerateGIF(), nil
-	}
-}
-
-func generateTextGIF(msg, animation string, color graphic.Color) func() ([]byte, error) {
-	return func() ([]byte, error) {
-		opts := text.DefaultAnimationOptions()
-		opts.TextColor = color
-		opts.ShadowColor = graphic.ShadowFor(color)
-		img, errMsg := text.GenerateAnimation(animation, msg, opts)
-		if errMsg != "" {
-			return nil, fmt.Errorf("%s", errMsg)
+			time.Sleep(item.Duration)
 		}
-		return img.GIFBytes()
 	}
 }
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
 	"os"
@@ -11,19 +12,30 @@ import (
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/pracucci/idotmatrix-overclocked/pkg/logging"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
 	"github.com/spf13/cobra"
 	"tinygo.org/x/bluetooth"
 )
 
 var discoverMaxScanTime uint32
 var discoverVerbose bool
+var discoverJSON bool
+
+// discoverDefaultJSONScanTime is the scan time used with --json when --scan-time isn't set
+const discoverDefaultJSONScanTime = 5
 
 var DiscoverCmd = &cobra.Command{
 	Use:   "discover",
 	Short: "Discover nearby Bluetooth devices",
 	Run: func(cmd *cobra.Command, args []string) {
 		logger := logging.NewLogger(discoverVerbose)
-		if err := doBTScan(logger); err != nil {
+		var err error
+		if discoverJSON {
+			err = doDiscoverJSON()
+		} else {
+			err = doBTScan(logger)
+		}
+		if err != nil {
 			fmt.Printf("Failed: %v\n", err)
 		}
 	},
@@ -32,6 +44,26 @@ var DiscoverCmd = &cobra.Command{
 func init() {
 	DiscoverCmd.Flags().Uint32Var(&discoverMaxScanTime, "scan-time", 0, "Max number of seconds to perform scan. 0 means infinite")
 	DiscoverCmd.Flags().BoolVar(&discoverVerbose, "verbose", false, "Verbose output during scan")
+	DiscoverCmd.Flags().BoolVar(&discoverJSON, "json", false, "Print the iDotMatrix devices found as JSON (scans for 5 seconds unless --scan-time is set)")
+}
+
+func doDiscoverJSON() error {
+	scanTime := discoverMaxScanTime
+	if scanTime == 0 {
+		scanTime = discoverDefaultJSONScanTime
+	}
+
+	devices, err := protocol.Discover(time.Duration(scanTime) * time.Second)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(devices, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
 }
 
 func doBTScan(logger log.Logger) error {
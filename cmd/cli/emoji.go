@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -8,14 +9,23 @@ import (
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/pracucci/idotmatrix-overclocked/pkg/emoji"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
 	"github.com/pracucci/idotmatrix-overclocked/pkg/logging"
 	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/text"
 	"github.com/spf13/cobra"
 )
 
 var (
 	emojiTargetAddr string
 	emojiName       string
+	emojiFile       string
+	emojiDir        string
+	emojiLoop       bool
+	emojiFrame      int
+	emojiCaption    string
+	emojiList       bool
+	emojiJSON       bool
 	emojiVerbose    bool
 )
 
@@ -32,10 +42,22 @@ Examples:
   idm-cli emoji --name thumbsup
   idm-cli emoji --name +1
   idm-cli emoji --name party
-  idm-cli emoji --target AA:BB:CC:DD:EE:FF --name rocket`, strings.Join(emoji.Names(), ", ")),
+  idm-cli emoji --name party,tada,confetti
+  idm-cli emoji --name rocket --caption LAUNCH
+  idm-cli emoji --target AA:BB:CC:DD:EE:FF --name rocket
+  idm-cli emoji --file my-emoji.gif
+  idm-cli emoji --dir ~/emojis --name my-emoji
+  idm-cli emoji --list
+  idm-cli emoji --list --json`, strings.Join(emoji.Names(), ", ")),
 	Run: func(cmd *cobra.Command, args []string) {
 		logger := logging.NewLogger(emojiVerbose)
-		if err := doEmoji(logger); err != nil {
+		var err error
+		if emojiList {
+			err = doListEmojis()
+		} else {
+			err = doEmoji(logger)
+		}
+		if err != nil {
 			fmt.Printf("error: %v\n", err)
 		}
 	},
@@ -44,26 +66,106 @@ Examples:
 func init() {
 	EmojiCmd.Flags().StringVar(&emojiTargetAddr, "target", "", "Target iDot display MAC address (auto-discovers if not specified)")
 
-	EmojiCmd.Flags().StringVar(&emojiName, "name", "", fmt.Sprintf("Emoji name (%s)", strings.Join(emoji.Names(), ", ")))
-	EmojiCmd.MarkFlagRequired("name")
+	EmojiCmd.Flags().StringVar(&emojiName, "name", "", fmt.Sprintf("Emoji name, or comma separated names played back to back (%s)", strings.Join(emoji.Names(), ", ")))
+	EmojiCmd.Flags().StringVar(&emojiFile, "file", "", "Custom emoji GIF file, resized to 64x64 if needed (instead of --name)")
+	EmojiCmd.Flags().StringVar(&emojiDir, "dir", "", "Directory of custom emoji GIFs, each available by its file name (without extension)")
 
+	EmojiCmd.Flags().IntVar(&emojiFrame, "frame", -1, "Show only this frame of the animation as a still image, counting from 0")
+	EmojiCmd.Flags().StringVar(&emojiCaption, "caption", "", "Short label drawn at the bottom of the emoji (up to 10 characters)")
+	EmojiCmd.Flags().BoolVar(&emojiLoop, "loop", true, "Loop the animation forever (false plays it once and stops on the last frame)")
+	EmojiCmd.Flags().BoolVar(&emojiList, "list", false, "List the emojis with their category and aliases, without connecting to the display")
+	EmojiCmd.Flags().BoolVar(&emojiJSON, "json", false, "With --list, print the emojis as JSON, including a base64 PNG thumbnail of each")
 	EmojiCmd.Flags().BoolVar(&emojiVerbose, "verbose", false, "Enable verbose debug logging")
 }
 
+func doListEmojis() error {
+	if len(emojiDir) > 0 {
+		if err := emoji.RegisterDir(emojiDir); err != nil {
+			return err
+		}
+	}
+
+	infos, err := emoji.List()
+	if err != nil {
+		return err
+	}
+
+	if emojiJSON {
+		data, err := json.MarshalIndent(infos, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	for _, info := range infos {
+		line := fmt.Sprintf("%-12s %-12s", info.Name, info.Category)
+		if len(info.Aliases) > 0 {
+			line += " aliases: " + strings.Join(info.Aliases, ", ")
+		}
+		fmt.Println(strings.TrimRight(line, " "))
+	}
+	return nil
+}
+
 func doEmoji(logger log.Logger) error {
-	if len(emojiName) == 0 {
-		return fmt.Errorf("missing --name option")
+	if len(emojiName) == 0 && len(emojiFile) == 0 {
+		return fmt.Errorf("missing --name or --file option")
+	}
+	if len(emojiName) > 0 && len(emojiFile) > 0 {
+		return fmt.Errorf("--name and --file cannot be used together")
+	}
+
+	if len(emojiDir) > 0 {
+		if err := emoji.RegisterDir(emojiDir); err != nil {
+			return err
+		}
 	}
 
 	// Generate emoji image
-	image, err := emoji.Generate(emojiName)
+	var image *graphic.Image
+	var err error
+	if len(emojiFile) > 0 {
+		image, err = emoji.GenerateFromFile(emojiFile)
+	} else if names := strings.Split(emojiName, ","); len(names) > 1 {
+		image, err = emoji.GenerateSequence(names)
+	} else {
+		image, err = emoji.Generate(emojiName)
+	}
 	if err != nil {
 		return err
 	}
 
+	// Extract the still frame before connecting, so an invalid --frame fails fast
+	var still *graphic.Image
+	if emojiFrame >= 0 {
+		if still, err = image.Frame(emojiFrame); err != nil {
+			return err
+		}
+	}
+
+	if len(emojiCaption) > 0 {
+		if still != nil {
+			still, err = text.CaptionImage(still, emojiCaption, text.DefaultTextOptions())
+		} else {
+			image, err = text.CaptionImage(image, emojiCaption, text.DefaultTextOptions())
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if outputFile != "" {
+		if still != nil {
+			return writeOutput(still, outputFile)
+		}
+		return writeOutput(image, outputFile)
+	}
+
 	// Connect to device
-	device := protocol.NewDevice(logger)
-	if err := device.Connect(emojiTargetAddr); err != nil {
+	device, err := connectDevice(logger, emojiTargetAddr)
+	if err != nil {
 		return err
 	}
 	defer func() {
@@ -72,14 +174,24 @@ func doEmoji(logger log.Logger) error {
 		}
 	}()
 
-	// Send animated GIF to device
-	gifBytes, err := image.GIFBytes()
-	if err != nil {
-		return err
-	}
+	if still != nil {
+		if err := sendStaticImage(device, still.StaticData); err != nil {
+			return err
+		}
+	} else {
+		// Send animated GIF to device
+		gifBytes, err := image.GIFBytes()
+		if err != nil {
+			return err
+		}
 
-	if err := protocol.SendGIF(device, gifBytes, logger); err != nil {
-		return err
+		send := protocol.SendGIF
+		if !emojiLoop {
+			send = protocol.SendGIFOnce
+		}
+		if err := send(device, gifBytes, gifUploadConfig(), logger); err != nil {
+			return err
+		}
 	}
 
 	// Allow time for BLE writes to complete before disconnecting
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/spf13/cobra"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/equalizer"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/logging"
+)
+
+var (
+	eqTargetAddr string
+	eqGradient   string
+	eqVerbose    bool
+)
+
+var EqCmd = &cobra.Command{
+	Use:   "eq",
+	Short: "Renders audio band levels streamed on stdin as equalizer bars",
+	Long: fmt.Sprintf(`Renders audio band levels as vertical equalizer bars across the iDot display.
+
+Levels are read from stdin, one update per line, as 1-%d numbers from 0 (empty)
+to 1 (full height) separated by commas or spaces. The first update is sent as a
+full image, then only the pixels that changed are sent, so external tools can
+stream levels quickly. Updates arriving while the previous one is still being
+sent are skipped, only the latest one is shown.
+
+Examples:
+  echo "0.2,0.9,0.5,0.7" | idm-cli eq
+  my-audio-analyzer | idm-cli eq --gradient blue,purple,white`, equalizer.MaxBands),
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := logging.NewLogger(eqVerbose)
+		if err := doEq(logger); err != nil {
+			fmt.Printf("error: %v\n", err)
+		}
+	},
+}
+
+func init() {
+	EqCmd.Flags().StringVar(&eqTargetAddr, "target", "", "Target iDot display MAC address (auto-discovers if not specified)")
+	EqCmd.Flags().StringVar(&eqGradient, "gradient", "green,yellow,red", fmt.Sprintf("Comma separated colors of the bars from bottom to top (%s)", graphic.ColorHelp()))
+	EqCmd.Flags().BoolVar(&eqVerbose, "verbose", false, "Enable verbose debug logging")
+}
+
+func doEq(logger log.Logger) error {
+	var gradient []graphic.Color
+	for _, name := range strings.Split(eqGradient, ",") {
+		color, err := graphic.ParseColor(strings.TrimSpace(name))
+		if err != nil {
+			return err
+		}
+		gradient = append(gradient, color)
+	}
+
+	device, err := connectDevice(logger, eqTargetAddr)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := device.Disconnect(); err != nil {
+			level.Error(logger).Log("msg", "Failed to disconnect", "err", err)
+		}
+	}()
+
+	renderer := equalizer.NewRenderer(device, gradient)
+	renderer.Upload = pixelUploadConfig()
+
+	// Upload the first update in full, then only send the diff
+	shown := false
+	lines, readErr := latestLines(os.Stdin)
+	for line := range lines {
+		levels, err := equalizer.ParseLevels(line)
+		if err != nil {
+			return err
+		}
+
+		renderer.Render(levels)
+		if !shown {
+			err = renderer.Show()
+			shown = true
+		} else {
+			err = renderer.Flush()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := <-readErr; err != nil {
+		return fmt.Errorf("failed to read levels: %w", err)
+	}
+
+	// Allow time for final writes to complete
+	time.Sleep(500 * time.Millisecond)
+
+	return nil
+}
+
+// latestLines reads the non-empty lines of r in the background. The returned
+// channel holds at most one line: a line not received yet is replaced by the
+// next one, so a slow consumer always gets the latest line. The channel is
+// closed at the end of r, after the read error (nil at EOF) is sent on the
+// error channel.
+func latestLines(r io.Reader) (<-chan string, <-chan error) {
+	lines := make(chan string, 1)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			select {
+			case lines <- line:
+			default:
+				// Drop the pending line, unless it was received meanwhile
+				select {
+				case <-lines:
+				default:
+				}
+				lines <- line
+			}
+		}
+		errs <- scanner.Err()
+	}()
+	return lines, errs
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatestLines(t *testing.T) {
+	t.Run("a slow consumer only gets the latest line", func(t *testing.T) {
+		r, w := io.Pipe()
+		lines, errs := latestLines(r)
+
+		_, err := io.WriteString(w, "0.1\n\n0.2\n0.3\n")
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		// Wait for the reader to finish before receiving anything
+		require.NoError(t, <-errs)
+		var received []string
+		for line := range lines {
+			received = append(received, line)
+		}
+		assert.Equal(t, []string{"0.3"}, received)
+	})
+
+	t.Run("a fast consumer gets every line", func(t *testing.T) {
+		r, w := io.Pipe()
+		lines, errs := latestLines(r)
+
+		for _, line := range []string{"0.1", "0.2"} {
+			_, err := io.WriteString(w, line+"\n")
+			require.NoError(t, err)
+			assert.Equal(t, line, <-lines)
+		}
+		require.NoError(t, w.Close())
+
+		_, open := <-lines
+		assert.False(t, open)
+		require.NoError(t, <-errs)
+	})
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/logging"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fillTargetAddr string
+	fillColorName  string
+	fillBrightness int
+	fillVerbose    bool
+)
+
+var FillCmd = &cobra.Command{
+	Use:   "fill",
+	Short: "Fills the iDot display with a single color",
+	Long: `Fills the whole iDot display with a single color, e.g. as a mood light.
+
+Color options: ` + graphic.ColorHelp() + `
+
+Examples:
+  idm-cli fill --color orange
+  idm-cli fill --color "#FF8800" --brightness 40`,
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := logging.NewLogger(fillVerbose)
+		if err := doFill(logger); err != nil {
+			fmt.Printf("error: %v\n", err)
+		}
+	},
+}
+
+func init() {
+	FillCmd.Flags().StringVar(&fillTargetAddr, "target", "", "Target iDot display MAC address (auto-discovers if not specified)")
+	FillCmd.Flags().StringVar(&fillColorName, "color", "", fmt.Sprintf("Fill color (%s)", graphic.ColorHelp()))
+	FillCmd.Flags().IntVar(&fillBrightness, "brightness", 100, "Scale the color to this percentage (0-100)")
+	FillCmd.Flags().BoolVar(&fillVerbose, "verbose", false, "Enable verbose debug logging")
+	FillCmd.MarkFlagRequired("color")
+}
+
+func doFill(logger log.Logger) error {
+	if fillBrightness < 0 || fillBrightness > 100 {
+		return fmt.Errorf("invalid brightness %d (valid: 0-100)", fillBrightness)
+	}
+
+	color, err := graphic.ParseColor(fillColorName)
+	if err != nil {
+		return err
+	}
+	color = graphic.Color(graphic.ScaleBrightness(color[:], fillBrightness))
+
+	device, err := connectDevice(logger, fillTargetAddr)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := device.Disconnect(); err != nil {
+			level.Error(logger).Log("msg", "Failed to disconnect", "err", err)
+		}
+	}()
+
+	if err := protocol.FillScreen(device, color); err != nil {
+		return err
+	}
+
+	// Allow time for BLE writes to complete before disconnecting
+	time.Sleep(500 * time.Millisecond)
+
+	return nil
+}
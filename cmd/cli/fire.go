@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/go-kit/log"
@@ -13,6 +14,11 @@ import (
 )
 
 var fireTargetAddr string
+var fireLoops int
+var firePalette string
+var fireDirection string
+var fireWind float64
+var fireCooling int
 var fireVerbose bool
 
 var FireCmd = &cobra.Command{
@@ -28,16 +34,38 @@ var FireCmd = &cobra.Command{
 
 func init() {
 	FireCmd.Flags().StringVar(&fireTargetAddr, "target", "", "Target iDot display MAC address (auto-discovers if not specified)")
+	FireCmd.Flags().IntVar(&fireLoops, "loops", 0, "Number of times the animation plays (0 loops forever)")
+	FireCmd.Flags().StringVar(&firePalette, "palette", "classic", fmt.Sprintf("Fire palette (%s)", strings.Join(fire.PaletteNames(), ", ")))
+	FireCmd.Flags().StringVar(&fireDirection, "direction", fire.DirectionUp, fmt.Sprintf("Spread direction (%s, %s)", fire.DirectionUp, fire.DirectionDown))
+	FireCmd.Flags().Float64Var(&fireWind, "wind", 0, "Horizontal lean of the flames, from -1.0 (left) to 1.0 (right)")
+	FireCmd.Flags().IntVar(&fireCooling, "cooling", 2, "Flame decay rate, higher values make shorter flames")
 	FireCmd.Flags().BoolVar(&fireVerbose, "verbose", false, "Enable verbose debug logging")
 }
 
 func doFire(logger log.Logger) error {
+	if fireLoops < 0 {
+		return fmt.Errorf("invalid --loops %d (must be >= 0)", fireLoops)
+	}
+
 	fmt.Println("Generating DOOM fire animation...")
-	gifData := fire.GenerateGIF()
+	opts := fire.DefaultOptions()
+	opts.Palette = firePalette
+	opts.Direction = fireDirection
+	opts.Loops = gifLoopCount(fireLoops)
+	opts.Wind = fireWind
+	opts.Cooling = fireCooling
+	gifData, err := fire.GenerateGIFWithOptions(opts)
+	if err != nil {
+		return err
+	}
 	fmt.Printf("Generated GIF: %d bytes\n", len(gifData))
 
-	device := protocol.NewDevice(logger)
-	if err := device.Connect(fireTargetAddr); err != nil {
+	if outputFile != "" {
+		return writeGIFOutput(gifData, outputFile)
+	}
+
+	device, err := connectDevice(logger, fireTargetAddr)
+	if err != nil {
 		return err
 	}
 	defer func() {
@@ -46,7 +74,7 @@ func doFire(logger log.Logger) error {
 		}
 	}()
 
-	if err := protocol.SendGIF(device, gifData, logger); err != nil {
+	if err := protocol.SendGIF(device, gifData, gifUploadConfig(), logger); err != nil {
 		return err
 	}
 
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/spf13/cobra"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/logging"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
+)
+
+var (
+	frameTargetAddr string
+	frameFile       string
+	frameMirror     string
+	frameBrightness int
+	frameGamma      float64
+	frameVerbose    bool
+)
+
+var FrameCmd = &cobra.Command{
+	Use:   "frame",
+	Short: "Send a raw 64x64 RGB frame to the display",
+	Long: `Send a raw 64x64 RGB frame to the display, without any image decoding.
+
+The input must be exactly 12288 bytes (64*64*3): one R, G, B byte triple per
+pixel, row by row from the top-left corner. This is the fastest way to show
+content produced by an external renderer.
+
+Examples:
+  idm-cli frame --file frame.rgb
+  my-renderer | idm-cli frame --file - --mirror horizontal --gamma 2.2`,
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := logging.NewLogger(frameVerbose)
+		if err := doFrame(logger); err != nil {
+			fmt.Printf("error: %v\n", err)
+		}
+	},
+}
+
+func init() {
+	FrameCmd.Flags().StringVar(&frameTargetAddr, "target", "", "Target iDot display MAC address (auto-discovers if not specified)")
+	FrameCmd.Flags().StringVar(&frameFile, "file", "", "Path to the raw RGB frame file, or - for stdin (required)")
+	FrameCmd.Flags().StringVar(&frameMirror, "mirror", "", "Mirror the frame (horizontal, vertical)")
+	FrameCmd.Flags().IntVar(&frameBrightness, "brightness", 100, "Scale the frame colors to this percentage (0-100)")
+	FrameCmd.Flags().Float64Var(&frameGamma, "gamma", 1, "Gamma correction applied to the frame colors (1 means none)")
+	FrameCmd.Flags().BoolVar(&frameVerbose, "verbose", false, "Enable verbose debug logging")
+	FrameCmd.MarkFlagRequired("file")
+}
+
+func doFrame(logger log.Logger) error {
+	if frameBrightness < 0 || frameBrightness > 100 {
+		return fmt.Errorf("invalid brightness %d (valid: 0-100)", frameBrightness)
+	}
+	if frameGamma <= 0 {
+		return fmt.Errorf("invalid gamma %g (must be positive)", frameGamma)
+	}
+
+	var (
+		frame []byte
+		err   error
+	)
+	if frameFile == "-" {
+		frame, err = io.ReadAll(os.Stdin)
+	} else {
+		frame, err = os.ReadFile(frameFile)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read frame: %w", err)
+	}
+	if err := graphic.ValidateFrame(frame); err != nil {
+		return err
+	}
+
+	switch frameMirror {
+	case "":
+	case "horizontal":
+		frame = graphic.MirrorHorizontal(frame)
+	case "vertical":
+		frame = graphic.MirrorVertical(frame)
+	default:
+		return fmt.Errorf("invalid mirror %q (valid: horizontal, vertical)", frameMirror)
+	}
+	if frameBrightness != 100 {
+		frame = graphic.ScaleBrightness(frame, frameBrightness)
+	}
+	if frameGamma != 1 {
+		frame = graphic.ApplyGamma(frame, frameGamma)
+	}
+
+	device, err := connectDevice(logger, frameTargetAddr)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := device.Disconnect(); err != nil {
+			level.Error(logger).Log("msg", "Failed to disconnect", "err", err)
+		}
+	}()
+
+	if err := protocol.SendFrame(device, frame); err != nil {
+		return err
+	}
+
+	// Allow time for BLE writes to complete before disconnecting
+	time.Sleep(500 * time.Millisecond)
+
+	return nil
+}
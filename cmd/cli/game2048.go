@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/spf13/cobra"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/games/game2048"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/logging"
+)
+
+var (
+	game2048TargetAddr string
+	game2048Verbose    bool
+)
+
+var Game2048Cmd = &cobra.Command{
+	Use:   "2048",
+	Short: "Play 2048 on the iDot display",
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := logging.NewLogger(game2048Verbose)
+		if err := run2048(logger); err != nil {
+			fmt.Printf("error: %v\n", err)
+		}
+	},
+}
+
+func init() {
+	Game2048Cmd.Flags().StringVar(&game2048TargetAddr, "target", "", "Target iDot display MAC address (auto-discovers if not specified)")
+	Game2048Cmd.Flags().BoolVar(&game2048Verbose, "verbose", false, "Enable verbose debug logging")
+}
+
+func run2048(logger log.Logger) error {
+	device, err := connectDevice(logger, game2048TargetAddr)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := device.Disconnect(); err != nil {
+			level.Error(logger).Log("msg", "Failed to disconnect", "err", err)
+		}
+	}()
+
+	game := game2048.NewGame(device)
+	game.SetUploadConfig(pixelUploadConfig())
+	return game.Run()
+}
@@ -2,11 +2,14 @@ package main
 
 import (
 	"fmt"
+	"image"
+	"os"
 	"strings"
 	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
 	"github.com/pracucci/idotmatrix-overclocked/pkg/grot"
 	"github.com/pracucci/idotmatrix-overclocked/pkg/logging"
 	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
@@ -16,6 +19,16 @@ import (
 var (
 	grotTargetAddr string
 	grotName       string
+	grotLoop       bool
+	grotFrame      int
+	grotMessage    string
+	grotDissolve   bool
+	grotColumns    int
+	grotDensity    int
+	grotFrameDelay int
+	grotHeadColor  string
+	grotTailColor  string
+	grotBaseImage  string
 	grotVerbose    bool
 )
 
@@ -31,6 +44,9 @@ Grot animations from Giphy.
 Examples:
   idm-cli grot --name halloween-1
   idm-cli grot --name halloween-3
+  idm-cli grot --name matrix --message "wake up neo"
+  idm-cli grot --name matrix --base-image logo.png
+  idm-cli grot --name matrix --columns 20 --density 2 --head-color white --tail-color red
   idm-cli grot --target AA:BB:CC:DD:EE:FF --name halloween-5`, strings.Join(grot.Names(), ", ")),
 	Run: func(cmd *cobra.Command, args []string) {
 		logger := logging.NewLogger(grotVerbose)
@@ -46,6 +62,16 @@ func init() {
 	GrotCmd.Flags().StringVar(&grotName, "name", "", fmt.Sprintf("Grot name (%s)", strings.Join(grot.Names(), ", ")))
 	GrotCmd.MarkFlagRequired("name")
 
+	GrotCmd.Flags().StringVar(&grotMessage, "message", "", "Message whose characters rain down in the matrix animation (matrix only)")
+	GrotCmd.Flags().BoolVar(&grotDissolve, "dissolve", true, "Let blocks of the base image dissolve and fall (matrix only)")
+	GrotCmd.Flags().IntVar(&grotColumns, "columns", grot.DefaultMatrixOptions().Columns, "Number of falling columns, 1-60 (matrix only)")
+	GrotCmd.Flags().IntVar(&grotDensity, "density", grot.DefaultMatrixOptions().Density, "Falling streams per column (matrix only)")
+	GrotCmd.Flags().IntVar(&grotFrameDelay, "frame-delay", grot.DefaultMatrixOptions().FrameDelay, "Delay between frames in 1/100s, higher is slower (matrix only)")
+	GrotCmd.Flags().StringVar(&grotHeadColor, "head-color", "", fmt.Sprintf("Leading character color, defaults to white-green (matrix only; %s)", graphic.ColorHelp()))
+	GrotCmd.Flags().StringVar(&grotTailColor, "tail-color", "", fmt.Sprintf("Tail color, defaults to green (matrix only; %s)", graphic.ColorHelp()))
+	GrotCmd.Flags().StringVar(&grotBaseImage, "base-image", "", "PNG/JPEG/GIF image used as the dissolving base, resized to 64x64 if needed (matrix only)")
+	GrotCmd.Flags().IntVar(&grotFrame, "frame", -1, "Show only this frame of the animation as a still image, counting from 0")
+	GrotCmd.Flags().BoolVar(&grotLoop, "loop", true, "Loop the animation forever (false plays it once and stops on the last frame)")
 	GrotCmd.Flags().BoolVar(&grotVerbose, "verbose", false, "Enable verbose debug logging")
 }
 
@@ -55,14 +81,64 @@ func doGrot(logger log.Logger) error {
 	}
 
 	// Generate grot image
-	image, err := grot.Generate(grotName)
+	var image *graphic.Image
+	var err error
+	if strings.ToLower(grotName) == "matrix" {
+		opts := grot.DefaultMatrixOptions()
+		opts.Dissolve = grotDissolve
+		opts.Columns = grotColumns
+		opts.Density = grotDensity
+		opts.FrameDelay = grotFrameDelay
+		if grotHeadColor != "" {
+			if opts.HeadColor, err = parseGrotColor(grotHeadColor); err != nil {
+				return err
+			}
+		}
+		if grotTailColor != "" {
+			if opts.TailColor, err = parseGrotColor(grotTailColor); err != nil {
+				return err
+			}
+		}
+		if grotBaseImage != "" {
+			if grotMessage != "" {
+				return fmt.Errorf("--message cannot be combined with --base-image")
+			}
+			base, loadErr := loadGrotBaseImage(grotBaseImage)
+			if loadErr != nil {
+				return loadErr
+			}
+			image, err = grot.GenerateMatrixFromImage(base, opts)
+		} else {
+			image, err = grot.GenerateMatrixText(grotMessage, opts)
+		}
+	} else {
+		if grotMessage != "" || grotBaseImage != "" {
+			return fmt.Errorf("--message and --base-image are only supported by the matrix grot")
+		}
+		image, err = grot.Generate(grotName)
+	}
 	if err != nil {
 		return err
 	}
 
+	// Extract the still frame before connecting, so an invalid --frame fails fast
+	var still *graphic.Image
+	if grotFrame >= 0 {
+		if still, err = image.Frame(grotFrame); err != nil {
+			return err
+		}
+	}
+
+	if outputFile != "" {
+		if still != nil {
+			return writeOutput(still, outputFile)
+		}
+		return writeOutput(image, outputFile)
+	}
+
 	// Connect to device
-	device := protocol.NewDevice(logger)
-	if err := device.Connect(grotTargetAddr); err != nil {
+	device, err := connectDevice(logger, grotTargetAddr)
+	if err != nil {
 		return err
 	}
 	defer func() {
@@ -71,20 +147,50 @@ func doGrot(logger log.Logger) error {
 		}
 	}()
 
-	// Send animated GIF to device
-	gifBytes, err := image.GIFBytes()
-	if err != nil {
-		return err
-	}
+	if still != nil {
+		if err := sendStaticImage(device, still.StaticData); err != nil {
+			return err
+		}
+	} else {
+		// Send animated GIF to device
+		gifBytes, err := image.GIFBytes()
+		if err != nil {
+			return err
+		}
 
-	level.Info(logger).Log("msg", "Uploading GIF to device", "name", grotName)
-	if err := protocol.SendGIF(device, gifBytes, logger); err != nil {
-		return err
+		level.Info(logger).Log("msg", "Uploading GIF to device", "name", grotName)
+		send := protocol.SendGIF
+		if !grotLoop {
+			send = protocol.SendGIFOnce
+		}
+		if err := send(device, gifBytes, gifUploadConfig(), logger); err != nil {
+			return err
+		}
+		level.Info(logger).Log("msg", "GIF upload complete", "name", grotName)
 	}
-	level.Info(logger).Log("msg", "GIF upload complete", "name", grotName)
 
 	// Allow time for BLE writes to complete before disconnecting
 	time.Sleep(500 * time.Millisecond)
 
 	return nil
 }
+
+// parseGrotColor parses a color name from the graphic palette or a hex color.
+func parseGrotColor(name string) (graphic.Color, error) {
+	return graphic.ParseColor(name)
+}
+
+// loadGrotBaseImage decodes the image file used as the matrix base.
+func loadGrotBaseImage(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open base image: %w", err)
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode base image: %w", err)
+	}
+	return img, nil
+}
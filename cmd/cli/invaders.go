@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/spf13/cobra"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/games/invaders"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/logging"
+)
+
+var (
+	invadersTargetAddr string
+	invadersVerbose    bool
+)
+
+var InvadersCmd = &cobra.Command{
+	Use:   "invaders",
+	Short: "Play Space Invaders on the iDot display",
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := logging.NewLogger(invadersVerbose)
+		if err := runInvaders(logger); err != nil {
+			fmt.Printf("error: %v\n", err)
+		}
+	},
+}
+
+func init() {
+	InvadersCmd.Flags().StringVar(&invadersTargetAddr, "target", "", "Target iDot display MAC address (auto-discovers if not specified)")
+	InvadersCmd.Flags().BoolVar(&invadersVerbose, "verbose", false, "Enable verbose debug logging")
+}
+
+func runInvaders(logger log.Logger) error {
+	device, err := connectDevice(logger, invadersTargetAddr)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := device.Disconnect(); err != nil {
+			level.Error(logger).Log("msg", "Failed to disconnect", "err", err)
+		}
+	}()
+
+	game := invaders.NewGame(device)
+	game.SetUploadConfig(pixelUploadConfig())
+	return game.Run()
+}
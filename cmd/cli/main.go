@@ -1,14 +1,72 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/gif"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/go-kit/log"
 	"github.com/spf13/cobra"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
 )
 
+var (
+	// packetDelay overrides the delay between BLE packets of GIF and pixel uploads
+	packetDelay time.Duration
+
+	// uploadRetries is how many times a GIF chunk is re-sent when the device doesn't respond
+	uploadRetries int
+
+	// scoresFile is the JSON file where game high scores are recorded (empty disables recording)
+	scoresFile string
+
+	// outputFile is where text, fire, grot, emoji, snow, screensaver, qr and chart write the generated image
+	// instead of sending it to the display (empty sends it to the display)
+	outputFile string
+
+	// connectTimeout bounds scanning for and connecting to the display (0 waits forever)
+	connectTimeout time.Duration
+)
+
+// connectDevice connects to the display. It's a variable so tests can check
+// that a command writing to --output never connects.
+var connectDevice = func(logger log.Logger, targetAddr string) (*protocol.Device, error) {
+	ctx := context.Background()
+	if connectTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, connectTimeout)
+		defer cancel()
+	}
+
+	device := protocol.NewDevice(logger)
+	if err := device.ConnectContext(ctx, targetAddr); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("display not connected within %s (raise --connect-timeout if it's slow to respond): %w", connectTimeout, err)
+		}
+		return nil, err
+	}
+	return device, nil
+}
+
 var rootCmd = &cobra.Command{
 	Use:   "idm-cli",
 	Short: "A simple CLI application to interact with iDot displays",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		values, err := loadConfig(configFile)
+		if err != nil {
+			return err
+		}
+		return applyConfig(cmd, values)
+	},
 }
 
 func main() {
@@ -19,17 +77,122 @@ func main() {
 }
 
 func init() {
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "YAML file with flag defaults such as target, brightness, mirror and verbose (default: ~/"+defaultConfigName+")")
+	rootCmd.PersistentFlags().DurationVar(&connectTimeout, "connect-timeout", 30*time.Second, "Give up scanning for and connecting to the display after this long (0 waits forever)")
+	rootCmd.PersistentFlags().DurationVar(&packetDelay, "packet-delay", 0, "Delay between BLE packets of GIF and pixel uploads, e.g. 20ms (default: 10ms for GIFs, 50ms for pixels)")
+	rootCmd.PersistentFlags().IntVar(&uploadRetries, "upload-retries", protocol.DefaultGIFUploadConfig().MaxRetries, "Times a GIF chunk is re-sent when the device doesn't respond in time")
+	rootCmd.PersistentFlags().StringVar(&outputFile, "output", "", "Write the generated image to this file instead of the display, as a PNG of the first frame if it ends with .png and as a GIF otherwise (text, fire, grot, emoji, snow, screensaver, qr, chart)")
+	rootCmd.PersistentFlags().StringVar(&scoresFile, "scores-file", "", "JSON file where snake and tetris high scores are recorded (default: not recorded)")
+
+	rootCmd.AddCommand(AnalogclockCmd)
+	rootCmd.AddCommand(BrightnessCmd)
+	rootCmd.AddCommand(ChartCmd)
+	rootCmd.AddCommand(ClearCmd)
 	rootCmd.AddCommand(DiscoverCmd)
 	rootCmd.AddCommand(EmojiCmd)
+	rootCmd.AddCommand(EqCmd)
 	rootCmd.AddCommand(DemoCmd)
+	rootCmd.AddCommand(FillCmd)
 	rootCmd.AddCommand(FireCmd)
+	rootCmd.AddCommand(FrameCmd)
+	rootCmd.AddCommand(Game2048Cmd)
 	rootCmd.AddCommand(ClockCmd)
 	rootCmd.AddCommand(GrotCmd)
+	rootCmd.AddCommand(InvadersCmd)
+	rootCmd.AddCommand(MoodlightCmd)
 	rootCmd.AddCommand(OffCmd)
 	rootCmd.AddCommand(OnCmd)
+	rootCmd.AddCommand(PixelsCmd)
+	rootCmd.AddCommand(PlaylistCmd)
+	rootCmd.AddCommand(ProgressCmd)
+	rootCmd.AddCommand(QrCmd)
+	rootCmd.AddCommand(ScheduleCmd)
+	rootCmd.AddCommand(ScoresCmd)
+	rootCmd.AddCommand(ScreensaverCmd)
 	rootCmd.AddCommand(ShowgifCmd)
 	rootCmd.AddCommand(ShowimageCmd)
+	rootCmd.AddCommand(SnowCmd)
 	rootCmd.AddCommand(TextCmd)
+	rootCmd.AddCommand(TickerCmd)
+	rootCmd.AddCommand(TimerCmd)
 	rootCmd.AddCommand(SnakeCmd)
 	rootCmd.AddCommand(TetrisCmd)
 }
+
+// gifLoopCount returns the GIF loop count playing an animation the given
+// number of times: the loop count is how many times it repeats after the first
+// play, with -1 playing it once. 0 plays it forever.
+func gifLoopCount(plays int) int {
+	switch plays {
+	case 0:
+		return 0
+	case 1:
+		return -1
+	}
+	return plays - 1
+}
+
+// gifUploadConfig returns the GIF upload timings, honoring --packet-delay and --upload-retries
+func gifUploadConfig() protocol.UploadConfig {
+	cfg := protocol.DefaultGIFUploadConfig()
+	if packetDelay > 0 {
+		cfg.PacketDelay = packetDelay
+	}
+	cfg.MaxRetries = max(uploadRetries, 0)
+	return cfg
+}
+
+// pixelUploadConfig returns the pixel upload timings, honoring --packet-delay
+func pixelUploadConfig() protocol.UploadConfig {
+	cfg := protocol.DefaultPixelUploadConfig()
+	if packetDelay > 0 {
+		cfg.PacketDelay = packetDelay
+	}
+	return cfg
+}
+
+// sendStaticImage shows a 64x64 RGB image on the display
+func sendStaticImage(device protocol.DeviceConnection, rgbData []byte) error {
+	if err := protocol.SetDrawMode(device, 1); err != nil {
+		return err
+	}
+	return protocol.SendImage(device, rgbData)
+}
+
+// writeOutput writes the image to path: a PNG of its first frame if path ends
+// with .png, a GIF otherwise. Static images are written as a single frame GIF.
+func writeOutput(img *graphic.Image, path string) error {
+	var data []byte
+	var err error
+	switch {
+	case strings.EqualFold(filepath.Ext(path), ".png"):
+		data, err = img.PNGBytes()
+	case img.Type == graphic.ImageTypeStatic:
+		var buf bytes.Buffer
+		err = gif.EncodeAll(&buf, &gif.GIF{
+			Image: []*image.Paletted{graphic.RGBToPaletted(img.StaticData)},
+			Delay: []int{0},
+		})
+		data = buf.Bytes()
+	default:
+		data, err = img.GIFBytes()
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	fmt.Printf("Wrote %s (%d bytes)\n", path, len(data))
+	return nil
+}
+
+// writeGIFOutput writes encoded GIF bytes to path like writeOutput.
+func writeGIFOutput(data []byte, path string) error {
+	g, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to decode GIF: %w", err)
+	}
+	return writeOutput(&graphic.Image{Type: graphic.ImageTypeAnimated, GIFData: g}, path)
+}
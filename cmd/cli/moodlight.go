@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/logging"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
+	"github.com/spf13/cobra"
+)
+
+// moodlightMaxFrames is the most frames the display plays back smoothly
+const moodlightMaxFrames = 64
+
+var (
+	moodlightTargetAddr     string
+	moodlightColors         string
+	moodlightFramesPerColor int
+	moodlightVerbose        bool
+)
+
+var MoodlightCmd = &cobra.Command{
+	Use:   "moodlight",
+	Short: "Slowly cycles the iDot display through a list of colors",
+	Long: `Fills the whole iDot display with a color, smoothly crossfading through a list
+of colors forever.
+
+Color options: ` + graphic.ColorHelp() + `
+
+Examples:
+  idm-cli moodlight --colors red,orange,purple
+  idm-cli moodlight --colors "#ff0000,#0000ff" --frames-per-color 32`,
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := logging.NewLogger(moodlightVerbose)
+		if err := doMoodlight(logger); err != nil {
+			fmt.Printf("error: %v\n", err)
+		}
+	},
+}
+
+func init() {
+	MoodlightCmd.Flags().StringVar(&moodlightTargetAddr, "target", "", "Target iDot display MAC address (auto-discovers if not specified)")
+	MoodlightCmd.Flags().StringVar(&moodlightColors, "colors", "", fmt.Sprintf("Comma separated colors to cycle through (%s)", graphic.ColorHelp()))
+	MoodlightCmd.Flags().IntVar(&moodlightFramesPerColor, "frames-per-color", 16, "Frames of the crossfade from each color to the next")
+	MoodlightCmd.Flags().BoolVar(&moodlightVerbose, "verbose", false, "Enable verbose debug logging")
+	MoodlightCmd.MarkFlagRequired("colors")
+}
+
+func doMoodlight(logger log.Logger) error {
+	var colors []graphic.Color
+	for _, name := range strings.Split(moodlightColors, ",") {
+		color, err := graphic.ParseColor(strings.TrimSpace(name))
+		if err != nil {
+			return err
+		}
+		colors = append(colors, color)
+	}
+
+	if moodlightFramesPerColor < 1 {
+		return fmt.Errorf("invalid --frames-per-color %d (must be >= 1)", moodlightFramesPerColor)
+	}
+	if frames := len(colors) * moodlightFramesPerColor; frames > moodlightMaxFrames {
+		return fmt.Errorf("%d colors with %d frames each make %d frames (max %d): use fewer colors or a lower --frames-per-color", len(colors), moodlightFramesPerColor, frames, moodlightMaxFrames)
+	}
+
+	image, err := graphic.GenerateColorCycle(colors, moodlightFramesPerColor)
+	if err != nil {
+		return err
+	}
+	gifBytes, err := image.GIFBytes()
+	if err != nil {
+		return err
+	}
+
+	device, err := connectDevice(logger, moodlightTargetAddr)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := device.Disconnect(); err != nil {
+			level.Error(logger).Log("msg", "Failed to disconnect", "err", err)
+		}
+	}()
+
+	if err := protocol.SendGIF(device, gifBytes, gifUploadConfig(), logger); err != nil {
+		return err
+	}
+
+	// Allow time for BLE writes to complete before disconnecting
+	time.Sleep(500 * time.Millisecond)
+
+	return nil
+}
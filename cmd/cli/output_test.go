@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"image/gif"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
+)
+
+// runWithoutDevice runs the CLI with args, failing the test if it connects
+func runWithoutDevice(t *testing.T, args ...string) {
+	t.Helper()
+
+	originalConnect := connectDevice
+	t.Cleanup(func() {
+		connectDevice = originalConnect
+		outputFile = ""
+	})
+	connectDevice = func(log.Logger, string) (*protocol.Device, error) {
+		t.Fatal("unexpected connection to the device")
+		return nil, nil
+	}
+
+	rootCmd.SetArgs(args)
+	require.NoError(t, rootCmd.Execute())
+}
+
+func TestTextOutputGIF(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hello.gif")
+	runWithoutDevice(t, "text", "--text", "HELLO", "--output", path)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	g, err := gif.DecodeAll(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Len(t, g.Image, 1)
+	assert.Equal(t, 64, g.Config.Width)
+	assert.Equal(t, 64, g.Config.Height)
+}
+
+func TestTextOutputAnimatedGIF(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hello.gif")
+	runWithoutDevice(t, "text", "--text", "HELLO", "--animation", "blink", "--output", path)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	g, err := gif.DecodeAll(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Greater(t, len(g.Image), 1)
+}
+
+func TestEmojiOutputPNG(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "emoji.png")
+	runWithoutDevice(t, "emoji", "--name", "thumbsup", "--output", path)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, 64, img.Bounds().Dx())
+}
+
+func TestGIFLoopCount(t *testing.T) {
+	assert.Equal(t, 0, gifLoopCount(0))
+	assert.Equal(t, -1, gifLoopCount(1))
+	assert.Equal(t, 1, gifLoopCount(2))
+	assert.Equal(t, 2, gifLoopCount(3))
+}
+
+func TestTextOutputLoops(t *testing.T) {
+	for plays, loopCount := range map[string]int{"1": -1, "3": 2} {
+		path := filepath.Join(t.TempDir(), "hello.gif")
+		runWithoutDevice(t, "text", "--text", "HELLO", "--animation", "blink", "--loops", plays, "--output", path)
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		g, err := gif.DecodeAll(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, loopCount, g.LoopCount, "--loops %s", plays)
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/spf13/cobra"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/logging"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
+)
+
+var (
+	pixelsTargetAddr string
+	pixelsFile       string
+	pixelsVerbose    bool
+)
+
+var PixelsCmd = &cobra.Command{
+	Use:   "pixels",
+	Short: "Draw individual pixels on top of the current content",
+	Long: `Draw individual pixels on top of the current content using the graffiti protocol.
+
+Pixels are read from a JSON array of {x, y, r, g, b} objects, where coordinates
+are 0-63 and colors are 0-255. Up to 4096 pixels (one full frame) are accepted.
+Pixels sharing a color are sent together, so drawing few colors is faster.
+
+  [
+    {"x": 0, "y": 0, "r": 255, "g": 0, "b": 0},
+    {"x": 63, "y": 63, "r": 0, "g": 0, "b": 255}
+  ]
+
+Examples:
+  idm-cli pixels --file pixels.json
+  cat pixels.json | idm-cli pixels --file -`,
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := logging.NewLogger(pixelsVerbose)
+		if err := doPixels(logger); err != nil {
+			fmt.Printf("error: %v\n", err)
+		}
+	},
+}
+
+func init() {
+	PixelsCmd.Flags().StringVar(&pixelsTargetAddr, "target", "", "Target iDot display MAC address (auto-discovers if not specified)")
+	PixelsCmd.Flags().StringVar(&pixelsFile, "file", "", "Path to the pixels JSON file, or - for stdin (required)")
+	PixelsCmd.Flags().BoolVar(&pixelsVerbose, "verbose", false, "Enable verbose debug logging")
+	PixelsCmd.MarkFlagRequired("file")
+}
+
+func doPixels(logger log.Logger) error {
+	var (
+		data []byte
+		err  error
+	)
+	if pixelsFile == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(pixelsFile)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read pixels: %w", err)
+	}
+
+	var pixels []protocol.Pixel
+	if err := json.Unmarshal(data, &pixels); err != nil {
+		return fmt.Errorf("failed to parse pixels: %w", err)
+	}
+	if err := protocol.ValidatePixels(pixels); err != nil {
+		return err
+	}
+
+	device, err := connectDevice(logger, pixelsTargetAddr)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := device.Disconnect(); err != nil {
+			level.Error(logger).Log("msg", "Failed to disconnect", "err", err)
+		}
+	}()
+
+	if err := protocol.DrawPixels(device, pixels, pixelUploadConfig()); err != nil {
+		return err
+	}
+
+	// Allow time for BLE writes to complete before disconnecting
+	time.Sleep(500 * time.Millisecond)
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/spf13/cobra"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/games/input"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/logging"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/playlist"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
+)
+
+var (
+	playlistTargetAddr string
+	playlistFile       string
+	playlistVerbose    bool
+)
+
+var PlaylistCmd = &cobra.Command{
+	Use:   "playlist",
+	Short: "Loop through a playlist of content loaded from a JSON file",
+	Long: `Loop through an ordered playlist of content, showing each item for its duration.
+
+The playlist is a JSON array of items with a type, optional params and a duration:
+
+  [
+    {"type": "emoji", "params": {"name": "rocket"}, "duration": "5s"},
+    {"type": "text", "params": {"text": "HELLO", "animation": "rainbow", "color": "cyan"}, "duration": "10s"},
+    {"type": "grot", "params": {"name": "matrix"}, "duration": "8s"},
+    {"type": "fire", "duration": "5s"},
+    {"type": "gif", "params": {"path": "animation.gif"}, "duration": "5s"}
+  ]
+
+Controls: P=Pause/resume, Q=Quit
+
+Examples:
+  idm-cli playlist --file playlist.json
+  idm-cli playlist --target AA:BB:CC:DD:EE:FF --file playlist.json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := logging.NewLogger(playlistVerbose)
+		if err := doPlaylist(logger); err != nil {
+			fmt.Printf("error: %v\n", err)
+		}
+	},
+}
+
+func init() {
+	PlaylistCmd.Flags().StringVar(&playlistTargetAddr, "target", "", "Target iDot display MAC address (auto-discovers if not specified)")
+	PlaylistCmd.Flags().StringVar(&playlistFile, "file", "", "Path to the playlist JSON file (required)")
+	PlaylistCmd.Flags().BoolVar(&playlistVerbose, "verbose", false, "Enable verbose debug logging")
+	PlaylistCmd.MarkFlagRequired("file")
+}
+
+func doPlaylist(logger log.Logger) error {
+	items, err := playlist.Load(playlistFile)
+	if err != nil {
+		return err
+	}
+
+	// Connect to device
+	device, err := connectDevice(logger, playlistTargetAddr)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := device.Disconnect(); err != nil {
+			level.Error(logger).Log("msg", "Failed to disconnect", "err", err)
+		}
+	}()
+
+	keys := input.NewTerminalInput()
+	stopInput := keys.Start()
+	defer stopInput()
+
+	player := playlist.NewPlayer(items)
+	show := func() {
+		index, item := player.Current()
+		fmt.Printf("Showing %d/%d: %s\r\n", index+1, len(items), item.Type)
+
+		gifBytes, err := playlist.Generate(item)
+		if err != nil {
+			level.Error(logger).Log("msg", "Failed to generate", "type", item.Type, "err", err)
+			return
+		}
+		if err := protocol.SendGIF(device, gifBytes, gifUploadConfig(), logger); err != nil {
+			level.Error(logger).Log("msg", "Failed to send GIF", "type", item.Type, "err", err)
+		}
+	}
+
+	fmt.Printf("Starting playlist with %d items\r\n", len(items))
+	fmt.Print("Press P to pause/resume, Q to quit\r\n")
+
+	player.Start(time.Now())
+	show()
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case key := <-keys.Keys():
+			switch key {
+			case 'p', 'P':
+				if player.Paused() {
+					player.Resume(time.Now())
+					fmt.Print("Resumed\r\n")
+				} else {
+					player.Pause(time.Now())
+					fmt.Print("Paused\r\n")
+				}
+			case 'q', 'Q', 3: // 3 is Ctrl+C in raw mode
+				return nil
+			}
+		case now := <-ticker.C:
+			if player.Update(now) {
+				show()
+			}
+		}
+	}
+}
@@ -47,8 +47,8 @@ func init() {
 }
 
 func doSetPower(logger interface{ Log(...interface{}) error }, targetAddr string, on bool) error {
-	device := protocol.NewDevice(logger)
-	if err := device.Connect(targetAddr); err != nil {
+	device, err := connectDevice(logger, targetAddr)
+	if err != nil {
 		return err
 	}
 	defer func() {
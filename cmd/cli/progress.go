@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/spf13/cobra"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/logging"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/progress"
+)
+
+var (
+	progressTargetAddr string
+	progressValue      int
+	progressColorName  string
+	progressVerbose    bool
+)
+
+var ProgressCmd = &cobra.Command{
+	Use:   "progress",
+	Short: "Shows a progress bar with a percentage on the iDot display",
+	Long: `Shows a framed horizontal progress bar with its percentage on the iDot display.
+
+With --value the bar is shown once. Without it, values from 0 to 100 are read
+from stdin, one per line: the first one is sent as a full image, then only the
+pixels that changed are sent, so frequent updates are cheap.
+
+Examples:
+  idm-cli progress --value 42
+  my-long-task --print-progress | idm-cli progress --color orange`,
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := logging.NewLogger(progressVerbose)
+		if err := doProgress(logger, cmd.Flags().Changed("value")); err != nil {
+			fmt.Printf("error: %v\n", err)
+		}
+	},
+}
+
+func init() {
+	ProgressCmd.Flags().StringVar(&progressTargetAddr, "target", "", "Target iDot display MAC address (auto-discovers if not specified)")
+	ProgressCmd.Flags().IntVar(&progressValue, "value", 0, "Progress percentage to show (0-100), read from stdin if not specified")
+	ProgressCmd.Flags().StringVar(&progressColorName, "color", "green", fmt.Sprintf("Bar and label color (%s)", graphic.ColorHelp()))
+	ProgressCmd.Flags().BoolVar(&progressVerbose, "verbose", false, "Enable verbose debug logging")
+}
+
+func doProgress(logger log.Logger, once bool) error {
+	if once {
+		if err := progress.ValidateValue(progressValue); err != nil {
+			return err
+		}
+	}
+
+	color, err := graphic.ParseColor(progressColorName)
+	if err != nil {
+		return err
+	}
+	opts := progress.DefaultOptions()
+	opts.Color = color
+
+	device, err := connectDevice(logger, progressTargetAddr)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := device.Disconnect(); err != nil {
+			level.Error(logger).Log("msg", "Failed to disconnect", "err", err)
+		}
+	}()
+
+	renderer := progress.NewRenderer(device, opts)
+	renderer.Upload = pixelUploadConfig()
+
+	if once {
+		renderer.Render(progressValue)
+		if err := renderer.Show(); err != nil {
+			return err
+		}
+	} else {
+		// Upload the first value in full, then only send the diff
+		shown := false
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			line := strings.TrimSuffix(strings.TrimSpace(scanner.Text()), "%")
+			if line == "" {
+				continue
+			}
+			value, err := strconv.Atoi(line)
+			if err != nil {
+				return fmt.Errorf("invalid value %q: %w", line, err)
+			}
+			if err := progress.ValidateValue(value); err != nil {
+				return err
+			}
+
+			renderer.Render(value)
+			if !shown {
+				err = renderer.Show()
+				shown = true
+			} else {
+				err = renderer.Flush()
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("failed to read values: %w", err)
+		}
+	}
+
+	// Allow time for final writes to complete
+	time.Sleep(500 * time.Millisecond)
+
+	return nil
+}
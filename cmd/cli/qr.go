@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/logging"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/qrcode"
+	"github.com/spf13/cobra"
+)
+
+var (
+	qrTargetAddr string
+	qrData       string
+	qrVerbose    bool
+)
+
+var QrCmd = &cobra.Command{
+	Use:   "qr",
+	Short: "Displays a QR code on the iDot display",
+	Long: fmt.Sprintf(`Displays a QR code of the given data, such as a URL or WiFi credentials, with
+black modules on a white background filling the iDot display.
+
+Up to %d bytes of data fit the display legibly.
+
+Examples:
+  idm-cli qr --data https://example.com
+  idm-cli qr --data "WIFI:S:home;T:WPA;P:secret;;"`, qrcode.MaxDataLength),
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := logging.NewLogger(qrVerbose)
+		if err := doQr(logger); err != nil {
+			fmt.Printf("error: %v\n", err)
+		}
+	},
+}
+
+func init() {
+	QrCmd.Flags().StringVar(&qrTargetAddr, "target", "", "Target iDot display MAC address (auto-discovers if not specified)")
+	QrCmd.Flags().StringVar(&qrData, "data", "", fmt.Sprintf("Data to encode, up to %d bytes (required)", qrcode.MaxDataLength))
+	QrCmd.Flags().BoolVar(&qrVerbose, "verbose", false, "Enable verbose debug logging")
+	QrCmd.MarkFlagRequired("data")
+}
+
+func doQr(logger log.Logger) error {
+	code, err := qrcode.Encode(qrData)
+	if err != nil {
+		return err
+	}
+	level.Debug(logger).Log("msg", "Encoded QR code", "version", code.Version, "size", code.Size, "mask", code.Mask, "quiet_zone", code.QuietZone())
+
+	buf := graphic.NewBuffer()
+	code.Draw(buf, graphic.Black, graphic.White)
+
+	if outputFile != "" {
+		return writeOutput(&graphic.Image{Type: graphic.ImageTypeStatic, StaticData: buf}, outputFile)
+	}
+
+	device, err := connectDevice(logger, qrTargetAddr)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := device.Disconnect(); err != nil {
+			level.Error(logger).Log("msg", "Failed to disconnect", "err", err)
+		}
+	}()
+
+	if err := sendStaticImage(device, buf); err != nil {
+		return err
+	}
+
+	// Allow time for BLE writes to complete before disconnecting
+	time.Sleep(500 * time.Millisecond)
+
+	return nil
+}
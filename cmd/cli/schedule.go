@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/spf13/cobra"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/logging"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/playlist"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/schedule"
+)
+
+var (
+	scheduleTargetAddr string
+	scheduleFile       string
+	scheduleAddAt      string
+	scheduleDays       string
+	scheduleType       string
+	scheduleParams     []string
+	scheduleVerbose    bool
+)
+
+var ScheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Show content at given times of the day",
+	Long: `Show content at given times of the day, e.g. every day at 09:00 show a text.
+
+Entries are stored in a JSON file so they survive restarts. Use --add-at to
+register a new entry, or run without it to keep showing entries as they
+become due (press Ctrl+C to stop).
+
+Item types and params are the same as the playlist command.
+
+Examples:
+  idm-cli schedule --file schedule.json --add-at 09:00 --type text --param "text=GOOD MORNING"
+  idm-cli schedule --file schedule.json --add-at 18:00 --days mon,tue,wed,thu,fri --type fire
+  idm-cli schedule --file schedule.json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := logging.NewLogger(scheduleVerbose)
+		var err error
+		if scheduleAddAt != "" {
+			err = doAddSchedule()
+		} else {
+			err = runSchedule(logger)
+		}
+		if err != nil {
+			fmt.Printf("error: %v\n", err)
+		}
+	},
+}
+
+func init() {
+	ScheduleCmd.Flags().StringVar(&scheduleTargetAddr, "target", "", "Target iDot display MAC address (auto-discovers if not specified)")
+	ScheduleCmd.Flags().StringVar(&scheduleFile, "file", "schedule.json", "Path to the schedule JSON file")
+	ScheduleCmd.Flags().StringVar(&scheduleAddAt, "add-at", "", "Add an entry at this time of day (HH:MM, 24h) instead of running the schedule")
+	ScheduleCmd.Flags().StringVar(&scheduleDays, "days", "", "Comma separated days of the week for the new entry (mon..sun, default: every day)")
+	ScheduleCmd.Flags().StringVar(&scheduleType, "type", "", "Item type for the new entry (emoji, grot, fire, text, gif)")
+	ScheduleCmd.Flags().StringArrayVar(&scheduleParams, "param", nil, "Item param for the new entry as key=value (repeatable)")
+	ScheduleCmd.Flags().BoolVar(&scheduleVerbose, "verbose", false, "Enable verbose debug logging")
+}
+
+func doAddSchedule() error {
+	entry := schedule.Entry{
+		At:   scheduleAddAt,
+		Item: playlist.Item{Type: scheduleType, Params: map[string]string{}},
+	}
+	if scheduleDays != "" {
+		for _, day := range strings.Split(scheduleDays, ",") {
+			entry.Days = append(entry.Days, strings.TrimSpace(day))
+		}
+	}
+	for _, param := range scheduleParams {
+		key, value, ok := strings.Cut(param, "=")
+		if !ok {
+			return fmt.Errorf("invalid --param %q (expected key=value)", param)
+		}
+		entry.Item.Params[key] = value
+	}
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+
+	entries, err := schedule.Load(scheduleFile)
+	if err != nil {
+		return err
+	}
+	entries = append(entries, entry)
+	if err := schedule.Save(scheduleFile, entries); err != nil {
+		return err
+	}
+
+	fmt.Printf("Added %s at %s (%d entries in %s)\n", entry.Item.Type, entry.At, len(entries), scheduleFile)
+	return nil
+}
+
+func runSchedule(logger log.Logger) error {
+	entries, err := schedule.Load(scheduleFile)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("no entries in %s (add one with --add-at)", scheduleFile)
+	}
+
+	// Connect to device
+	device, err := connectDevice(logger, scheduleTargetAddr)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := device.Disconnect(); err != nil {
+			level.Error(logger).Log("msg", "Failed to disconnect", "err", err)
+		}
+	}()
+
+	fmt.Printf("Running schedule with %d entries\n", len(entries))
+	fmt.Println("Press Ctrl+C to stop")
+
+	scheduler := schedule.NewScheduler(entries, time.Now())
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		entry, ok := scheduler.Due(now)
+		if !ok {
+			continue
+		}
+		fmt.Printf("%s: showing %s\n", now.Format("15:04"), entry.Item.Type)
+
+		gifBytes, err := playlist.Generate(entry.Item)
+		if err != nil {
+			level.Error(logger).Log("msg", "Failed to generate", "type", entry.Item.Type, "err", err)
+			continue
+		}
+		if err := protocol.SendGIF(device, gifBytes, gifUploadConfig(), logger); err != nil {
+			level.Error(logger).Log("msg", "Failed to send GIF", "type", entry.Item.Type, "err", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/games/scores"
+)
+
+// scoresShownAtGameOver is how many high scores are printed when a game is over
+const scoresShownAtGameOver = 5
+
+var (
+	scoresGame string
+	scoresTop  int
+)
+
+var ScoresCmd = &cobra.Command{
+	Use:   "scores",
+	Short: "Show the recorded high scores of a game",
+	Long: `Show the high scores recorded with --scores-file while playing snake or tetris.
+
+Examples:
+  idm-cli snake --scores-file scores.json
+  idm-cli scores --scores-file scores.json --game snake`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := doShowScores(); err != nil {
+			fmt.Printf("error: %v\n", err)
+		}
+	},
+}
+
+func init() {
+	ScoresCmd.Flags().StringVar(&scoresGame, "game", "", "Game to show the scores of (snake, tetris)")
+	ScoresCmd.Flags().IntVar(&scoresTop, "top", 10, "Number of scores to show")
+	ScoresCmd.MarkFlagRequired("game")
+}
+
+func doShowScores() error {
+	if scoresFile == "" {
+		return fmt.Errorf("missing --scores-file option")
+	}
+	if scoresTop < 1 {
+		return fmt.Errorf("invalid --top %d (must be >= 1)", scoresTop)
+	}
+
+	top, err := scores.NewStore(scoresFile).Top(scoresGame, scoresTop)
+	if err != nil {
+		return err
+	}
+	printScores(scoresGame, top)
+	return nil
+}
+
+// highScoreRecorder returns a game over callback recording the score in
+// --scores-file and printing the high scores, or nil if --scores-file isn't set.
+func highScoreRecorder(game string) func(score int) {
+	if scoresFile == "" {
+		return nil
+	}
+	store := scores.NewStore(scoresFile)
+
+	return func(score int) {
+		if err := store.Record(game, score); err != nil {
+			fmt.Printf("Failed to record score: %v\n", err)
+			return
+		}
+		top, err := store.Top(game, scoresShownAtGameOver)
+		if err != nil {
+			fmt.Printf("Failed to read high scores: %v\n", err)
+			return
+		}
+		printScores(game, top)
+	}
+}
+
+// printScores prints a numbered list of scores
+func printScores(game string, top []int) {
+	if len(top) == 0 {
+		fmt.Printf("No %s scores recorded yet\n", game)
+		return
+	}
+	fmt.Printf("High scores (%s):\n", game)
+	for i, score := range top {
+		fmt.Printf("%3d. %d\n", i+1, score)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/logging"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/screensaver"
+	"github.com/spf13/cobra"
+)
+
+var (
+	screensaverTargetAddr string
+	screensaverLabel      string
+	screensaverColors     string
+	screensaverSpeed      int
+	screensaverVerbose    bool
+)
+
+var ScreensaverCmd = &cobra.Command{
+	Use:   "screensaver",
+	Short: "Displays a logo bouncing off the display edges",
+	Long: `Displays a labeled tile bouncing around the iDot display, DVD player style.
+The tile changes color every time it hits an edge, and the animation loops
+seamlessly once the tile is back where it started with its starting color.
+
+Examples:
+  idm-cli screensaver
+  idm-cli screensaver --label HI --speed 1
+  idm-cli screensaver --colors white,orange,green`,
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := logging.NewLogger(screensaverVerbose)
+		if err := doScreensaver(logger); err != nil {
+			fmt.Printf("error: %v\n", err)
+		}
+	},
+}
+
+func init() {
+	defaults := screensaver.DefaultOptions()
+	ScreensaverCmd.Flags().StringVar(&screensaverTargetAddr, "target", "", "Target iDot display MAC address (auto-discovers if not specified)")
+	ScreensaverCmd.Flags().StringVar(&screensaverLabel, "label", defaults.Label, "Text shown in the bouncing tile (1-3 characters)")
+	ScreensaverCmd.Flags().StringVar(&screensaverColors, "colors", "red,yellow,cyan,magenta", fmt.Sprintf("Comma separated colors the tile cycles through on each hit (%s)", graphic.ColorHelp()))
+	ScreensaverCmd.Flags().IntVar(&screensaverSpeed, "speed", defaults.Speed, "Pixels the tile moves per frame on each axis (1-4)")
+	ScreensaverCmd.Flags().BoolVar(&screensaverVerbose, "verbose", false, "Enable verbose debug logging")
+}
+
+func doScreensaver(logger log.Logger) error {
+	var colors []graphic.Color
+	for _, name := range strings.Split(screensaverColors, ",") {
+		color, err := graphic.ParseColor(strings.TrimSpace(name))
+		if err != nil {
+			return err
+		}
+		colors = append(colors, color)
+	}
+
+	opts := screensaver.DefaultOptions()
+	opts.Label = screensaverLabel
+	opts.Colors = colors
+	opts.Speed = screensaverSpeed
+	gifData, err := screensaver.GenerateGIF(opts)
+	if err != nil {
+		return err
+	}
+
+	if outputFile != "" {
+		return writeGIFOutput(gifData, outputFile)
+	}
+
+	device, err := connectDevice(logger, screensaverTargetAddr)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := device.Disconnect(); err != nil {
+			level.Error(logger).Log("msg", "Failed to disconnect", "err", err)
+		}
+	}()
+
+	if err := protocol.SendGIF(device, gifData, gifUploadConfig(), logger); err != nil {
+		return err
+	}
+
+	// Allow time for final writes to complete
+	time.Sleep(500 * time.Millisecond)
+
+	return nil
+}
@@ -1,31 +1,28 @@
 package main
 
 import (
-	"bytes"
+	"context"
 	"fmt"
-	"image"
-	"image/color/palette"
-	"image/draw"
-	"image/gif"
-	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
 	"github.com/pracucci/idotmatrix-overclocked/pkg/logging"
 	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
 	"github.com/spf13/cobra"
 )
 
-const (
-	showgifDisplaySize    = 64
-	showgifMaxFrames      = 64
-	showgifMaxDurationMs  = 2000
-	showgifMinFrameTimeMs = 16 // ~60 FPS limit
-)
-
 var showgifTargetAddr string
 var showgifGifFile string
+var showgifLoop bool
+var showgifLoops int
+var showgifQuantize string
+var showgifDither bool
+var showgifBgColor string
+var showgifFitDuration bool
 var showgifVerbose bool
 
 var ShowgifCmd = &cobra.Command{
@@ -45,109 +42,71 @@ func init() {
 	ShowgifCmd.Flags().StringVar(&showgifGifFile, "gif-file", "", "Path to a 64x64 animated GIF file")
 	ShowgifCmd.MarkFlagRequired("gif-file")
 
+	ShowgifCmd.Flags().BoolVar(&showgifLoop, "loop", true, "Loop the animation forever (false plays it once and stops on the last frame)")
+	ShowgifCmd.Flags().IntVar(&showgifLoops, "loops", 0, "Number of times the animation plays (0 loops forever)")
+	ShowgifCmd.MarkFlagsMutuallyExclusive("loop", "loops")
+	ShowgifCmd.Flags().StringVar(&showgifQuantize, "quantize", graphic.QuantizeGIF, "Frame color quantization (gif: one median cut palette for the whole GIF, median-cut: one per frame, plan9: fixed palette)")
+	ShowgifCmd.Flags().BoolVar(&showgifDither, "dither", false, "Apply Floyd-Steinberg dithering when quantizing frames (smoother gradients)")
+	ShowgifCmd.Flags().StringVar(&showgifBgColor, "bgcolor", "black", fmt.Sprintf("Background shown through transparent regions of the GIF (%s)", graphic.ColorHelp()))
+	ShowgifCmd.Flags().BoolVar(&showgifFitDuration, "fit-duration", false, fmt.Sprintf("Speed up GIFs longer than %dms so they fit, keeping the relative frame timing", graphic.MaxGIFDurationMs))
 	ShowgifCmd.Flags().BoolVar(&showgifVerbose, "verbose", false, "Enable verbose debug logging")
 }
 
-// loadAndReencodeGIF loads a GIF, re-composites frames, and re-encodes it for the device.
-func loadAndReencodeGIF(filePath string) ([]byte, error) {
-	file, err := os.Open(filePath)
+// loadAndReencodeGIF loads a GIF and re-encodes it for the device, reporting
+// the frames dropped and the duration over the device limits.
+func loadAndReencodeGIF(filePath string, opts graphic.ReencodeOptions) ([]byte, error) {
+	result, err := graphic.LoadGIF(filePath, opts)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
-
-	g, err := gif.DecodeAll(file)
-	if err != nil {
-		return nil, fmt.Errorf("failed to decode GIF: %w", err)
-	}
-
-	// Validate dimensions
-	if g.Config.Width != showgifDisplaySize || g.Config.Height != showgifDisplaySize {
-		return nil, fmt.Errorf("GIF is %dx%d, expected %dx%d", g.Config.Width, g.Config.Height, showgifDisplaySize, showgifDisplaySize)
-	}
-
-	numFrames := len(g.Image)
-	if numFrames == 0 {
-		return nil, fmt.Errorf("GIF has no frames")
-	}
-	if numFrames > showgifMaxFrames {
-		fmt.Printf("Warning: GIF has %d frames, limiting to %d\n", numFrames, showgifMaxFrames)
-		numFrames = showgifMaxFrames
-	}
 
-	// Calculate and adjust frame delays
-	totalDurationMs := 0
-	delays := make([]int, numFrames)
-	for i := 0; i < numFrames; i++ {
-		delay := g.Delay[i]
-		if delay < showgifMinFrameTimeMs/10 {
-			delay = showgifMinFrameTimeMs / 10 // Minimum 16ms (delay is in 1/100s)
-		}
-		delays[i] = delay
-		totalDurationMs += delay * 10
+	if result.Frames < result.SourceFrames {
+		fmt.Printf("GIF has %d frames, sampling %d of them\n", result.SourceFrames, result.Frames)
 	}
-
-	if totalDurationMs > showgifMaxDurationMs {
-		fmt.Printf("Warning: GIF duration %dms exceeds %dms limit\n", totalDurationMs, showgifMaxDurationMs)
+	if result.SpedUp {
+		fmt.Printf("GIF duration %dms exceeds %dms limit, speeding it up\n", result.SourceDurationMs, graphic.MaxGIFDurationMs)
+	} else if result.DurationMs > graphic.MaxGIFDurationMs {
+		fmt.Printf("Warning: GIF duration %dms exceeds %dms limit\n", result.DurationMs, graphic.MaxGIFDurationMs)
 	}
 
-	// Re-composite and re-encode frames
-	canvas := image.NewRGBA(image.Rect(0, 0, showgifDisplaySize, showgifDisplaySize))
-	newFrames := make([]*image.Paletted, numFrames)
+	fmt.Printf("Loaded GIF: %d frames, %dms total, re-encoded to %d bytes\n", result.Frames, result.DurationMs, len(result.Data))
 
-	for i := 0; i < numFrames; i++ {
-		frame := g.Image[i]
-		bounds := frame.Bounds()
-
-		// Composite frame onto canvas
-		draw.Draw(canvas, bounds, frame, bounds.Min, draw.Over)
-
-		// Create new paletted frame from canvas
-		palettedFrame := image.NewPaletted(image.Rect(0, 0, showgifDisplaySize, showgifDisplaySize), palette.Plan9)
-		draw.Draw(palettedFrame, palettedFrame.Bounds(), canvas, image.Point{}, draw.Src)
-		newFrames[i] = palettedFrame
+	return result.Data, nil
+}
 
-		// Handle disposal (disposal=2 means restore to background)
-		if i < len(g.Disposal) && g.Disposal[i] == gif.DisposalBackground {
-			draw.Draw(canvas, bounds, image.Black, image.Point{}, draw.Src)
-		}
+func doShowGIF(logger log.Logger) error {
+	if len(showgifGifFile) == 0 {
+		return fmt.Errorf("missing --gif-file option")
 	}
 
-	// Re-encode GIF with loop forever and disposal=2 (restore to background)
-	newGIF := &gif.GIF{
-		Image:     newFrames,
-		Delay:     delays,
-		LoopCount: 0, // loop forever
-		Disposal:  make([]byte, numFrames),
-	}
-	// Set disposal=2 (DisposalBackground) for all frames
-	for i := range newGIF.Disposal {
-		newGIF.Disposal[i] = gif.DisposalBackground
+	if showgifLoops < 0 {
+		return fmt.Errorf("invalid --loops %d (must be >= 0)", showgifLoops)
 	}
 
-	// Encode to bytes
-	var buf bytes.Buffer
-	if err := gif.EncodeAll(&buf, newGIF); err != nil {
-		return nil, fmt.Errorf("failed to re-encode GIF: %w", err)
+	switch showgifQuantize {
+	case graphic.QuantizeGIF, graphic.QuantizeMedianCut, graphic.QuantizePlan9:
+	default:
+		return fmt.Errorf("invalid --quantize %q (valid: %s, %s, %s)", showgifQuantize, graphic.QuantizeGIF, graphic.QuantizeMedianCut, graphic.QuantizePlan9)
 	}
 
-	fmt.Printf("Loaded GIF: %d frames, %dms total, re-encoded to %d bytes\n", numFrames, totalDurationMs, buf.Len())
-
-	return buf.Bytes(), nil
-}
-
-func doShowGIF(logger log.Logger) error {
-	if len(showgifGifFile) == 0 {
-		return fmt.Errorf("missing --gif-file option")
+	background, err := graphic.ParseColor(showgifBgColor)
+	if err != nil {
+		return err
 	}
 
-	gifData, err := loadAndReencodeGIF(showgifGifFile)
+	gifData, err := loadAndReencodeGIF(showgifGifFile, graphic.ReencodeOptions{
+		LoopCount:   gifLoopCount(showgifLoops),
+		Quantize:    showgifQuantize,
+		Dither:      showgifDither,
+		Background:  background,
+		FitDuration: showgifFitDuration,
+	})
 	if err != nil {
 		return err
 	}
 
-	device := protocol.NewDevice(logger)
-	if err = device.Connect(showgifTargetAddr); err != nil {
+	device, err := connectDevice(logger, showgifTargetAddr)
+	if err != nil {
 		return err
 	}
 	defer func() {
@@ -156,10 +115,22 @@ func doShowGIF(logger log.Logger) error {
 		}
 	}()
 
+	// Abort the upload on Ctrl+C or SIGTERM, still disconnecting cleanly
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
 	// Send GIF directly - no SetDrawMode needed
-	if err := protocol.SendGIF(device, gifData, logger); err != nil {
+	send := protocol.SendGIFContext
+	if !showgifLoop {
+		send = protocol.SendGIFOnceContext
+	}
+	var stats protocol.UploadStats
+	cfg := gifUploadConfig()
+	cfg.Stats = &stats
+	if err := send(ctx, device, gifData, cfg, logger); err != nil {
 		return err
 	}
+	fmt.Printf("GIF %s\n", stats)
 
 	// Allow time for final writes to complete
 	time.Sleep(500 * time.Millisecond)
@@ -11,14 +11,20 @@ import (
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
 	"github.com/pracucci/idotmatrix-overclocked/pkg/logging"
 	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/text"
 	"github.com/spf13/cobra"
 )
 
 var showimageTargetAddr string
 var showimageImageFile string
 var showimageDisplaySize int
+var showimagePulse bool
+var showimageAutoLevels bool
+var showimagePerChannel bool
+var showimageCaption string
 var showimageVerbose bool
 
 var ShowimageCmd = &cobra.Command{
@@ -39,11 +45,15 @@ func init() {
 	ShowimageCmd.MarkFlagRequired("image-file")
 
 	ShowimageCmd.Flags().IntVar(&showimageDisplaySize, "size", 64, "Display size (32 or 64)")
+	ShowimageCmd.Flags().BoolVar(&showimagePulse, "pulse", false, "Slowly pulse the image brightness in a loop")
+	ShowimageCmd.Flags().BoolVar(&showimageAutoLevels, "auto-levels", false, "Stretch the brightness range so the darkest pixel is black and the brightest is white, for washed out or dark images")
+	ShowimageCmd.Flags().BoolVar(&showimagePerChannel, "per-channel", false, "With --auto-levels, stretch each color channel on its own, also correcting a color cast")
+	ShowimageCmd.Flags().StringVar(&showimageCaption, "caption", "", "Short label drawn at the bottom of the image (up to 10 characters, 64x64 display only)")
 	ShowimageCmd.Flags().BoolVar(&showimageVerbose, "verbose", false, "Enable verbose debug logging")
 }
 
 // loadAndConvertImage loads an image file and converts it to raw RGB data
-func loadAndConvertImage(filePath string, expectedSize int) ([]byte, error) {
+func loadAndConvertImage(filePath string, display graphic.Display) ([]byte, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return nil, err
@@ -56,45 +66,49 @@ func loadAndConvertImage(filePath string, expectedSize int) ([]byte, error) {
 	}
 
 	bounds := img.Bounds()
-	width := bounds.Max.X - bounds.Min.X
-	height := bounds.Max.Y - bounds.Min.Y
-
-	if width != expectedSize || height != expectedSize {
-		return nil, fmt.Errorf("image is %dx%d, expected %dx%d", width, height, expectedSize, expectedSize)
+	if bounds.Dx() != display.Width || bounds.Dy() != display.Height {
+		return nil, fmt.Errorf("image is %dx%d, expected %dx%d", bounds.Dx(), bounds.Dy(), display.Width, display.Height)
 	}
 
 	// Convert to raw RGB data (3 bytes per pixel)
-	rgbData := make([]byte, expectedSize*expectedSize*3)
-	idx := 0
-	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
-		for x := bounds.Min.X; x < bounds.Max.X; x++ {
-			r, g, b, _ := img.At(x, y).RGBA()
-			// RGBA returns 16-bit values, convert to 8-bit
-			rgbData[idx] = uint8(r >> 8)
-			rgbData[idx+1] = uint8(g >> 8)
-			rgbData[idx+2] = uint8(b >> 8)
-			idx += 3
-		}
-	}
-
-	return rgbData, nil
+	return display.ImageToRGB(img), nil
 }
 
 func doShowImage(logger log.Logger) error {
 	if len(showimageImageFile) == 0 {
 		return fmt.Errorf("missing --image-file option")
 	}
-	if showimageDisplaySize != 32 && showimageDisplaySize != 64 {
-		return fmt.Errorf("invalid display size: %d (must be 32 or 64)", showimageDisplaySize)
+	display, err := graphic.DisplayForSize(showimageDisplaySize)
+	if err != nil {
+		return err
 	}
 
-	rgbData, err := loadAndConvertImage(showimageImageFile, showimageDisplaySize)
+	rgbData, err := loadAndConvertImage(showimageImageFile, display)
 	if err != nil {
 		return err
 	}
+	if showimagePerChannel && !showimageAutoLevels {
+		return fmt.Errorf("--per-channel requires --auto-levels")
+	}
+	if showimageAutoLevels {
+		mode := graphic.LevelsLuminance
+		if showimagePerChannel {
+			mode = graphic.LevelsPerChannel
+		}
+		rgbData = graphic.AutoLevelsWithMode(rgbData, mode)
+	}
+	fmt.Printf("Dominant color: %s\n", graphic.DominantColor(rgbData).Hex())
+	if len(showimageCaption) > 0 {
+		if display != graphic.Display64 {
+			return fmt.Errorf("--caption is only supported on the 64x64 display")
+		}
+		if err := text.DrawCaption(rgbData, showimageCaption, text.DefaultTextOptions()); err != nil {
+			return err
+		}
+	}
 
-	device := protocol.NewDevice(logger)
-	if err = device.Connect(showimageTargetAddr); err != nil {
+	device, err := connectDevice(logger, showimageTargetAddr)
+	if err != nil {
 		return err
 	}
 	defer func() {
@@ -103,12 +117,24 @@ func doShowImage(logger log.Logger) error {
 		}
 	}()
 
-	if err := protocol.SetDrawMode(device, 1); err != nil {
-		return err
-	}
+	if showimagePulse {
+		gifData, err := display.GeneratePulse(rgbData, graphic.DefaultPulseOptions()).GIFBytes()
+		if err != nil {
+			return err
+		}
+		if err := protocol.SendGIF(device, gifData, gifUploadConfig(), logger); err != nil {
+			return err
+		}
+	} else {
+		if err := protocol.SetDrawMode(device, 1); err != nil {
+			return err
+		}
 
-	if err := protocol.SendImage(device, rgbData); err != nil {
-		return err
+		var stats protocol.UploadStats
+		if err := protocol.SendImageWithStats(device, rgbData, &stats); err != nil {
+			return err
+		}
+		fmt.Printf("Image %s\n", stats)
 	}
 
 	// Allow time for BLE writes to complete before disconnecting
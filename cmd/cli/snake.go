@@ -2,20 +2,33 @@ package main
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/spf13/cobra"
 
 	"github.com/pracucci/idotmatrix-overclocked/pkg/games/snake"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
 	"github.com/pracucci/idotmatrix-overclocked/pkg/logging"
-	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
 )
 
 var (
 	snakeTargetAddr string
 	snakeStartLevel int
 	snakeVerbose    bool
+	snakeRecord     string
+	snakeAuto       bool
+
+	snakeSpeed           int
+	snakeSpeedCurve      float64
+	snakeWrapWalls       bool
+	snakeObstacleDensity float64
+	snakeSpecials        bool
+	snakeGrowth          int
+	snakeApplesPerLevel  int
+	snakeInitialLength   int
+	snakePixelDelay      time.Duration
 )
 
 var SnakeCmd = &cobra.Command{
@@ -32,12 +45,38 @@ var SnakeCmd = &cobra.Command{
 func init() {
 	SnakeCmd.Flags().StringVar(&snakeTargetAddr, "target", "", "Target iDot display MAC address (auto-discovers if not specified)")
 	SnakeCmd.Flags().IntVar(&snakeStartLevel, "level", 1, "Starting level (default: 1)")
+	SnakeCmd.Flags().IntVar(&snakeSpeed, "speed", int(snake.SlowTickDelay/time.Millisecond), "Tick delay in milliseconds at level 1 (lower is faster)")
+	SnakeCmd.Flags().Float64Var(&snakeSpeedCurve, "speed-curve", 1, "How quickly levels speed up (0 = constant speed, >1 = steeper)")
+	SnakeCmd.Flags().BoolVar(&snakeWrapWalls, "wrap-walls", false, "Wrap around the display edges instead of dying on the walls")
+	SnakeCmd.Flags().Float64Var(&snakeObstacleDensity, "obstacle-density", 1, "Multiplier for the number of rocks and lakes (0 = no obstacles)")
+	SnakeCmd.Flags().BoolVar(&snakeSpecials, "specials", false, "Place portals and power-ups on the map")
+	SnakeCmd.Flags().IntVar(&snakeGrowth, "growth", snake.GrowthPerApple, "Segments the snake grows per apple eaten")
+	SnakeCmd.Flags().IntVar(&snakeApplesPerLevel, "apples-per-level", snake.ApplesPerLevel, "Apples needed to advance to the next level")
+	SnakeCmd.Flags().IntVar(&snakeInitialLength, "initial-length", snake.InitialLength, fmt.Sprintf("Snake length at the start of a game (1-%d)", snake.MaxInitialLength))
+	SnakeCmd.Flags().BoolVar(&snakeAuto, "auto", false, "Let a bot play (hands-free demo, press Q to quit)")
+	SnakeCmd.Flags().StringVar(&snakeRecord, "record", "", "Record the game to a GIF file that can be replayed with showgif")
+	SnakeCmd.Flags().DurationVar(&snakePixelDelay, "pixel-delay", snake.PixelDelay, "Delay after each pixel sent to the display (raise it if the display falls behind)")
 	SnakeCmd.Flags().BoolVar(&snakeVerbose, "verbose", false, "Enable verbose debug logging")
 }
 
 func runSnake(logger log.Logger) error {
-	device := protocol.NewDevice(logger)
-	if err := device.Connect(snakeTargetAddr); err != nil {
+	options := snake.GameOptions{
+		StartSpeed:      time.Duration(snakeSpeed) * time.Millisecond,
+		SpeedCurve:      snakeSpeedCurve,
+		WrapWalls:       snakeWrapWalls,
+		ObstacleDensity: snakeObstacleDensity,
+		Specials:        snakeSpecials,
+		GrowthPerApple:  snakeGrowth,
+		ApplesPerLevel:  snakeApplesPerLevel,
+		InitialLength:   snakeInitialLength,
+		PixelDelay:      snakePixelDelay,
+	}
+	if err := options.Validate(); err != nil {
+		return err
+	}
+
+	device, err := connectDevice(logger, snakeTargetAddr)
+	if err != nil {
 		return err
 	}
 	defer func() {
@@ -46,6 +85,21 @@ func runSnake(logger log.Logger) error {
 		}
 	}()
 
-	game := snake.NewGame(device, snakeStartLevel)
+	game := snake.NewGame(device, snakeStartLevel, options)
+	game.SetOnGameOver(highScoreRecorder("snake"))
+	if snakeAuto {
+		game.SetAutoPlay(snake.NewAutoInput(time.Now().UnixNano()))
+	}
+	if snakeRecord != "" {
+		recorder := graphic.NewRecorder(snakeRecord)
+		game.SetRecorder(recorder)
+		defer func() {
+			if err := recorder.Stop(); err != nil {
+				level.Error(logger).Log("msg", "Failed to save recording", "err", err)
+				return
+			}
+			fmt.Printf("Recorded %d frames to %s\n", recorder.Frames(), snakeRecord)
+		}()
+	}
 	return game.Run()
 }
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/logging"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/particles"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
+	"github.com/spf13/cobra"
+)
+
+var (
+	snowTargetAddr string
+	snowKind       string
+	snowCount      int
+	snowColorName  string
+	snowSpeed      int
+	snowWind       int
+	snowLoops      int
+	snowVerbose    bool
+)
+
+var SnowCmd = &cobra.Command{
+	Use:   "snow",
+	Short: "Displays falling snow or drifting stars",
+	Long: `Displays a seamlessly looping animation of falling snow or twinkling stars
+drifting across the iDot display.
+
+Examples:
+  idm-cli snow
+  idm-cli snow --wind 1 --count 120
+  idm-cli snow --kind stars --color yellow`,
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := logging.NewLogger(snowVerbose)
+		if err := doSnow(logger); err != nil {
+			fmt.Printf("error: %v\n", err)
+		}
+	},
+}
+
+func init() {
+	defaults := particles.DefaultOptions()
+	SnowCmd.Flags().StringVar(&snowTargetAddr, "target", "", "Target iDot display MAC address (auto-discovers if not specified)")
+	SnowCmd.Flags().StringVar(&snowKind, "kind", defaults.Kind, fmt.Sprintf("Particles (%s, %s)", particles.KindSnow, particles.KindStars))
+	SnowCmd.Flags().IntVar(&snowCount, "count", defaults.Count, "Number of particles")
+	SnowCmd.Flags().StringVar(&snowColorName, "color", "white", fmt.Sprintf("Particle color (%s)", graphic.ColorHelp()))
+	SnowCmd.Flags().IntVar(&snowSpeed, "speed", defaults.Speed, "Times the fastest particles cross the display per loop (1-4)")
+	SnowCmd.Flags().IntVar(&snowWind, "wind", defaults.Wind, "Times snow is blown across the display per loop, negative to the left (-4 to 4)")
+	SnowCmd.Flags().IntVar(&snowLoops, "loops", 0, "Number of times the animation plays (0 loops forever)")
+	SnowCmd.Flags().BoolVar(&snowVerbose, "verbose", false, "Enable verbose debug logging")
+}
+
+func doSnow(logger log.Logger) error {
+	if snowLoops < 0 {
+		return fmt.Errorf("invalid --loops %d (must be >= 0)", snowLoops)
+	}
+
+	color, err := graphic.ParseColor(snowColorName)
+	if err != nil {
+		return err
+	}
+
+	opts := particles.DefaultOptions()
+	opts.Kind = snowKind
+	opts.Count = snowCount
+	opts.Color = color
+	opts.Speed = snowSpeed
+	opts.Wind = snowWind
+	opts.Loops = gifLoopCount(snowLoops)
+	opts.Seed = time.Now().UnixNano()
+	gifData, err := particles.GenerateGIF(opts)
+	if err != nil {
+		return err
+	}
+
+	if outputFile != "" {
+		return writeGIFOutput(gifData, outputFile)
+	}
+
+	device, err := connectDevice(logger, snowTargetAddr)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := device.Disconnect(); err != nil {
+			level.Error(logger).Log("msg", "Failed to disconnect", "err", err)
+		}
+	}()
+
+	if err := protocol.SendGIF(device, gifData, gifUploadConfig(), logger); err != nil {
+		return err
+	}
+
+	// Allow time for final writes to complete
+	time.Sleep(500 * time.Millisecond)
+
+	return nil
+}
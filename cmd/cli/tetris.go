@@ -2,19 +2,25 @@ package main
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/spf13/cobra"
 
 	"github.com/pracucci/idotmatrix-overclocked/pkg/games/tetris"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
 	"github.com/pracucci/idotmatrix-overclocked/pkg/logging"
-	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
 )
 
 var (
 	tetrisTargetAddr string
 	tetrisVerbose    bool
+	tetrisRecord     string
+	tetrisAuto       bool
+
+	tetrisDropInterval   time.Duration
+	tetrisRenderInterval time.Duration
 )
 
 var TetrisCmd = &cobra.Command{
@@ -30,12 +36,24 @@ var TetrisCmd = &cobra.Command{
 
 func init() {
 	TetrisCmd.Flags().StringVar(&tetrisTargetAddr, "target", "", "Target iDot display MAC address (auto-discovers if not specified)")
+	TetrisCmd.Flags().BoolVar(&tetrisAuto, "auto", false, "Let a bot play (hands-free demo, press Q to quit)")
+	TetrisCmd.Flags().StringVar(&tetrisRecord, "record", "", "Record the game to a GIF file that can be replayed with showgif")
+	TetrisCmd.Flags().DurationVar(&tetrisDropInterval, "drop-interval", tetris.DropInterval, "Gravity speed at level 1, later levels are proportionally faster (lower is faster)")
+	TetrisCmd.Flags().DurationVar(&tetrisRenderInterval, "render-interval", tetris.RenderInterval, "How often the board is sent to the display (raise it if the display falls behind)")
 	TetrisCmd.Flags().BoolVar(&tetrisVerbose, "verbose", false, "Enable verbose debug logging")
 }
 
 func runTetris(logger log.Logger) error {
-	device := protocol.NewDevice(logger)
-	if err := device.Connect(tetrisTargetAddr); err != nil {
+	timing := tetris.Timing{
+		DropInterval:   tetrisDropInterval,
+		RenderInterval: tetrisRenderInterval,
+	}
+	if err := timing.Validate(); err != nil {
+		return err
+	}
+
+	device, err := connectDevice(logger, tetrisTargetAddr)
+	if err != nil {
 		return err
 	}
 	defer func() {
@@ -45,5 +63,20 @@ func runTetris(logger log.Logger) error {
 	}()
 
 	game := tetris.NewGame(device)
+	game.SetUploadConfig(pixelUploadConfig())
+	game.SetTiming(timing)
+	game.SetOnGameOver(highScoreRecorder("tetris"))
+	game.SetAutoPlay(tetrisAuto)
+	if tetrisRecord != "" {
+		recorder := graphic.NewRecorder(tetrisRecord)
+		game.SetRecorder(recorder)
+		defer func() {
+			if err := recorder.Stop(); err != nil {
+				level.Error(logger).Log("msg", "Failed to save recording", "err", err)
+				return
+			}
+			fmt.Printf("Recorded %d frames to %s\n", recorder.Frames(), tetrisRecord)
+		}()
+	}
 	return game.Run()
 }
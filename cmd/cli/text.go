@@ -15,11 +15,19 @@ import (
 )
 
 var (
-	textTargetAddr string
-	textMsg        string
-	textAnimation  string
-	textColorName  string
-	textVerbose    bool
+	textTargetAddr   string
+	textMsg          string
+	textAnimation    string
+	textColorName    string
+	textLoop         bool
+	textLoops        int
+	textProportional bool
+	textOutline      bool
+	textAlign        string
+	textLineSpacing  int
+	textScrollStep   int
+	textOverflow     string
+	textVerbose      bool
 )
 
 // animationTypesHelp returns a formatted help string for all animation types.
@@ -49,7 +57,7 @@ Text is automatically word-wrapped to fit the display width.
 
 Animation options:
 ` + animationTypesHelp() + `
-Color options: ` + strings.Join(graphic.ColorNames(), ", ") + `
+Color options: ` + graphic.ColorHelp() + `
 
 Examples:
   idm-cli text --target AA:BB:CC:DD:EE:FF --text "HELLO"
@@ -67,11 +75,20 @@ Examples:
 func init() {
 	TextCmd.Flags().StringVar(&textTargetAddr, "target", "", "Target iDot display MAC address (auto-discovers if not specified)")
 
-	TextCmd.Flags().StringVar(&textMsg, "text", "", "Text to display (uppercase A-Z, 0-9, space, basic punctuation)")
+	TextCmd.Flags().StringVar(&textMsg, "text", "", "Text to display (A-Z, a-z, 0-9, space, common punctuation)")
 	TextCmd.MarkFlagRequired("text")
 
 	TextCmd.Flags().StringVar(&textAnimation, "animation", "none", "Animation type: "+text.AnimationTypeNamesString())
-	TextCmd.Flags().StringVar(&textColorName, "color", "white", fmt.Sprintf("Text color (%s)", strings.Join(graphic.ColorNames(), ", ")))
+	TextCmd.Flags().StringVar(&textColorName, "color", "white", fmt.Sprintf("Text color (%s)", graphic.ColorHelp()))
+	TextCmd.Flags().BoolVar(&textLoop, "loop", true, "Loop the animation forever (false plays it once and stops on the last frame)")
+	TextCmd.Flags().IntVar(&textLoops, "loops", 0, "Number of times the animation plays (0 loops forever)")
+	TextCmd.MarkFlagsMutuallyExclusive("loop", "loops")
+	TextCmd.Flags().BoolVar(&textProportional, "proportional", false, "Use proportional glyph widths to fit more text per line (none, blink and scroll-up animations)")
+	TextCmd.Flags().BoolVar(&textOutline, "outline", false, "Outline the text with the shadow color instead of a drop shadow (all animations except rainbow)")
+	TextCmd.Flags().StringVar(&textAlign, "align", "center", "Horizontal alignment of the lines: left, center, right (none, blink and appear animations)")
+	TextCmd.Flags().IntVar(&textLineSpacing, "line-spacing", text.LineSpacing, "Pixels between lines (none, blink and appear animations)")
+	TextCmd.Flags().IntVar(&textScrollStep, "scroll-step", 1, "Pixels scrolled per frame (scroll-up animation)")
+	TextCmd.Flags().StringVar(&textOverflow, "overflow", "error", "What to do with text too tall for the display: error, scroll (switch to the scroll-up animation), truncate (cut with an ellipsis)")
 	TextCmd.Flags().BoolVar(&textVerbose, "verbose", false, "Enable verbose debug logging")
 }
 
@@ -80,36 +97,63 @@ func doShowText(logger log.Logger) error {
 		return fmt.Errorf("missing --text option")
 	}
 
-	// Convert text to uppercase (font only has uppercase)
-	msg := strings.ToUpper(textMsg)
+	if textLoops < 0 {
+		return fmt.Errorf("invalid --loops %d (must be >= 0)", textLoops)
+	}
+
+	if textScrollStep < 1 {
+		return fmt.Errorf("invalid --scroll-step %d (must be >= 1)", textScrollStep)
+	}
+
+	if textLineSpacing < 1 {
+		return fmt.Errorf("invalid --line-spacing %d (must be >= 1)", textLineSpacing)
+	}
 
-	// Wrap text and validate total height fits
-	lines := text.WrapText(msg)
-	blockHeight := text.TextBlockHeight(lines)
-	if blockHeight > graphic.DisplayHeight {
-		return fmt.Errorf("text too long: wrapped to %d lines (%d pixels, max %d)", len(lines), blockHeight, graphic.DisplayHeight)
+	align, err := text.ParseAlign(textAlign)
+	if err != nil {
+		return err
+	}
+
+	overflow, err := text.ParseOverflow(textOverflow)
+	if err != nil {
+		return err
 	}
 
 	// Parse color
-	colorName := strings.ToLower(strings.TrimSpace(textColorName))
-	color, ok := graphic.ColorPalette[colorName]
-	if !ok {
-		return fmt.Errorf("unknown color: %s (valid: %s)", colorName, strings.Join(graphic.ColorNames(), ", "))
+	color, err := graphic.ParseColor(textColorName)
+	if err != nil {
+		return err
 	}
 
 	// Generate the image based on animation type
 	opts := text.DefaultAnimationOptions()
 	opts.TextOptions.TextColor = color
 	opts.TextOptions.ShadowColor = graphic.ShadowFor(color)
+	opts.Loops = gifLoopCount(textLoops)
+	opts.TextOptions.Proportional = textProportional
+	opts.TextOptions.Outline = textOutline
+	opts.TextOptions.Align = align
+	opts.TextOptions.LineSpacing = textLineSpacing
+	opts.ScrollStep = textScrollStep
+
+	// Make sure the wrapped text fits (scrolling text can be taller than the display)
+	msg, animation, err := text.FitText(textMsg, textAnimation, opts.TextOptions, overflow)
+	if err != nil {
+		return err
+	}
 
-	image, errMsg := text.GenerateAnimation(textAnimation, msg, opts)
+	image, errMsg := text.GenerateAnimation(animation, msg, opts)
 	if errMsg != "" {
 		return fmt.Errorf("%s", errMsg)
 	}
 
+	if outputFile != "" {
+		return writeOutput(image, outputFile)
+	}
+
 	// Connect to device
-	device := protocol.NewDevice(logger)
-	if err := device.Connect(textTargetAddr); err != nil {
+	device, err := connectDevice(logger, textTargetAddr)
+	if err != nil {
 		return err
 	}
 	defer func() {
@@ -135,7 +179,11 @@ func doShowText(logger log.Logger) error {
 		if err != nil {
 			return err
 		}
-		if err := protocol.SendGIF(device, gifBytes, logger); err != nil {
+		send := protocol.SendGIF
+		if !textLoop {
+			send = protocol.SendGIFOnce
+		}
+		if err := send(device, gifBytes, gifUploadConfig(), logger); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/spf13/cobra"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/logging"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/playlist"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/ticker"
+)
+
+var (
+	tickerTargetAddr string
+	tickerSource     string
+	tickerRefresh    time.Duration
+	tickerColorName  string
+	tickerVerbose    bool
+)
+
+var TickerCmd = &cobra.Command{
+	Use:   "ticker",
+	Short: "Scroll text read from a URL or a file, refreshing it periodically",
+	Long: `Scroll text read from a URL or a local file, like a news or status ticker.
+
+The source is read again every --refresh interval, and the display is only
+updated when the text changes. If reading the source fails, the last text
+keeps showing. Press Ctrl+C to stop.
+
+Examples:
+  idm-cli ticker --source https://example.com/status.txt
+  idm-cli ticker --source status.txt --refresh 10s --color yellow`,
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := logging.NewLogger(tickerVerbose)
+		if err := doTicker(logger); err != nil {
+			fmt.Printf("error: %v\n", err)
+		}
+	},
+}
+
+func init() {
+	TickerCmd.Flags().StringVar(&tickerTargetAddr, "target", "", "Target iDot display MAC address (auto-discovers if not specified)")
+	TickerCmd.Flags().StringVar(&tickerSource, "source", "", "URL (http or https) or file path to read the text from (required)")
+	TickerCmd.Flags().DurationVar(&tickerRefresh, "refresh", time.Minute, "How often to read the source again")
+	TickerCmd.Flags().StringVar(&tickerColorName, "color", "white", fmt.Sprintf("Text color (%s)", graphic.ColorHelp()))
+	TickerCmd.Flags().BoolVar(&tickerVerbose, "verbose", false, "Enable verbose debug logging")
+	TickerCmd.MarkFlagRequired("source")
+}
+
+func doTicker(logger log.Logger) error {
+	if tickerRefresh <= 0 {
+		return fmt.Errorf("invalid refresh interval %s (must be positive)", tickerRefresh)
+	}
+	color, err := graphic.ParseColor(tickerColorName)
+	if err != nil {
+		return err
+	}
+
+	// Read the source once before connecting, so a wrong source fails early
+	tk := ticker.New(tickerSource)
+	if _, err := tk.Refresh(); err != nil {
+		return err
+	}
+
+	// Connect to device
+	device, err := connectDevice(logger, tickerTargetAddr)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := device.Disconnect(); err != nil {
+			level.Error(logger).Log("msg", "Failed to disconnect", "err", err)
+		}
+	}()
+
+	show := func() {
+		gifBytes, err := playlist.GenerateTextGIF(tk.Text(), "scroll-up", color)
+		if err != nil {
+			level.Error(logger).Log("msg", "Failed to generate ticker", "err", err)
+			return
+		}
+		if err := protocol.SendGIF(device, gifBytes, gifUploadConfig(), logger); err != nil {
+			level.Error(logger).Log("msg", "Failed to send GIF", "err", err)
+		}
+	}
+
+	fmt.Printf("Showing ticker from %s, refreshing every %s\n", tickerSource, tickerRefresh)
+	fmt.Println("Press Ctrl+C to stop")
+	show()
+
+	refresh := time.NewTicker(tickerRefresh)
+	defer refresh.Stop()
+
+	for range refresh.C {
+		changed, err := tk.Refresh()
+		if err != nil {
+			level.Error(logger).Log("msg", "Failed to refresh ticker, keeping the last text", "err", err)
+			continue
+		}
+		if changed {
+			level.Info(logger).Log("msg", "Ticker text changed")
+			show()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/logging"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/text"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/timer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	timerTargetAddr string
+	timerDuration   time.Duration
+	timerMessage    string
+	timerColorName  string
+	timerVerbose    bool
+)
+
+var TimerCmd = &cobra.Command{
+	Use:   "timer",
+	Short: "Shows a countdown timer on the iDot display",
+	Long: `Shows a MM:SS countdown on the iDot display. Each second only the changed
+pixels are sent to the display. When the countdown reaches zero, a blinking
+message is shown.
+
+Examples:
+  idm-cli timer --duration 5m
+  idm-cli timer --duration 90s --message "TEA IS READY" --color green`,
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := logging.NewLogger(timerVerbose)
+		if err := doTimer(logger); err != nil {
+			fmt.Printf("error: %v\n", err)
+		}
+	},
+}
+
+func init() {
+	TimerCmd.Flags().StringVar(&timerTargetAddr, "target", "", "Target iDot display MAC address (auto-discovers if not specified)")
+	TimerCmd.Flags().DurationVar(&timerDuration, "duration", 5*time.Minute, "Countdown duration (e.g. 90s, 5m, 1h30m)")
+	TimerCmd.Flags().StringVar(&timerMessage, "message", "TIME UP", "Message to flash when the countdown reaches zero")
+	TimerCmd.Flags().StringVar(&timerColorName, "color", "white", fmt.Sprintf("Text color (%s)", graphic.ColorHelp()))
+	TimerCmd.Flags().BoolVar(&timerVerbose, "verbose", false, "Enable verbose debug logging")
+}
+
+func doTimer(logger log.Logger) error {
+	if timerDuration <= 0 {
+		return fmt.Errorf("duration must be positive")
+	}
+
+	color, err := graphic.ParseColor(timerColorName)
+	if err != nil {
+		return err
+	}
+
+	opts := text.DefaultAnimationOptions()
+	opts.TextColor = color
+	opts.ShadowColor = graphic.ShadowFor(color)
+
+	if lines := text.WrapText(timerMessage); text.TextBlockHeight(lines) > graphic.DisplayHeight {
+		return fmt.Errorf("message too long: wrapped to %d lines", len(lines))
+	}
+
+	device, err := connectDevice(logger, timerTargetAddr)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := device.Disconnect(); err != nil {
+			level.Error(logger).Log("msg", "Failed to disconnect", "err", err)
+		}
+	}()
+
+	deadline := time.Now().Add(timerDuration)
+	renderer := timer.NewRenderer(device, opts.TextOptions)
+	renderer.Upload = pixelUploadConfig()
+
+	// Upload the first frame in full, then only send the diff every second
+	renderer.Render(time.Until(deadline))
+	if err := renderer.Show(); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for time.Until(deadline) > 0 {
+		<-ticker.C
+		renderer.Render(time.Until(deadline))
+		if err := renderer.Flush(); err != nil {
+			return err
+		}
+	}
+
+	level.Debug(logger).Log("msg", "Countdown finished")
+
+	gifBytes, err := text.GenerateBlinkingText(timerMessage, opts).GIFBytes()
+	if err != nil {
+		return err
+	}
+	if err := protocol.SendGIF(device, gifBytes, gifUploadConfig(), logger); err != nil {
+		return err
+	}
+
+	// Allow time for BLE writes to complete before disconnecting
+	time.Sleep(500 * time.Millisecond)
+
+	return nil
+}
@@ -0,0 +1,118 @@
+// Package analogclock renders an analog clock face as an animated GIF for the iDotMatrix display.
+package analogclock
+
+import (
+	"image"
+	"image/gif"
+	"math"
+	"time"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+)
+
+// Animation and geometry constants
+const (
+	frameCount = 60  // One frame per second, loops every minute
+	frameDelay = 100 // 1s per frame (delay is in 1/100s)
+
+	centerX       = graphic.DisplayWidth / 2
+	centerY       = graphic.DisplayHeight / 2
+	faceRadius    = 30
+	hourHandLen   = 14
+	minuteHandLen = 22
+	secondHandLen = 26
+	longTickLen   = 4 // Tick length at 12, 3, 6 and 9
+	shortTickLen  = 2 // Tick length at the other hours
+)
+
+// Options configures analog clock rendering.
+type Options struct {
+	HandColor   graphic.Color // Hour and minute hands color
+	SecondColor graphic.Color // Second hand color
+	TickColor   graphic.Color // Hour tick marks color
+	Background  graphic.Color // Background fill color
+	ShowSeconds bool          // Draw the second hand
+}
+
+// DefaultOptions returns sensible default analog clock options.
+func DefaultOptions() Options {
+	return Options{
+		HandColor:   graphic.White,
+		SecondColor: graphic.Red,
+		TickColor:   graphic.Gray,
+		Background:  graphic.Black,
+		ShowSeconds: true,
+	}
+}
+
+// HandAngles returns the hour, minute and second hand angles in degrees,
+// measured clockwise from 12 o'clock.
+func HandAngles(t time.Time) (hour, minute, second float64) {
+	second = float64(t.Second()) * 6
+	minute = float64(t.Minute())*6 + float64(t.Second())*0.1
+	hour = float64(t.Hour()%12)*30 + float64(t.Minute())*0.5
+	return hour, minute, second
+}
+
+// DrawFace draws the clock face (tick marks and hands) for the given time onto buf.
+func DrawFace(buf []byte, t time.Time, opts Options) {
+	// Hour tick marks
+	for h := 0; h < 12; h++ {
+		tickLen := shortTickLen
+		if h%3 == 0 {
+			tickLen = longTickLen
+		}
+		x0, y0 := handEnd(float64(h)*30, faceRadius-tickLen+1)
+		x1, y1 := handEnd(float64(h)*30, faceRadius)
+		graphic.DrawLine(buf, x0, y0, x1, y1, opts.TickColor)
+	}
+
+	hourAngle, minuteAngle, secondAngle := HandAngles(t)
+
+	x, y := handEnd(hourAngle, hourHandLen)
+	graphic.DrawLine(buf, centerX, centerY, x, y, opts.HandColor)
+
+	x, y = handEnd(minuteAngle, minuteHandLen)
+	graphic.DrawLine(buf, centerX, centerY, x, y, opts.HandColor)
+
+	if opts.ShowSeconds {
+		x, y = handEnd(secondAngle, secondHandLen)
+		graphic.DrawLine(buf, centerX, centerY, x, y, opts.SecondColor)
+	}
+
+	// Center pin
+	graphic.SetPixel(buf, centerX, centerY, opts.HandColor)
+}
+
+// GenerateGIF creates a 60-frame looping analog clock animation starting at t.
+// Each frame advances the clock by one second.
+func GenerateGIF(t time.Time, opts Options) ([]byte, error) {
+	frames := make([]*image.Paletted, frameCount)
+	delays := make([]int, frameCount)
+
+	for i := 0; i < frameCount; i++ {
+		buf := graphic.NewBufferWithColor(opts.Background)
+		DrawFace(buf, t.Add(time.Duration(i)*time.Second), opts)
+		frames[i] = graphic.RGBToPaletted(buf)
+		delays[i] = frameDelay
+	}
+
+	img := &graphic.Image{
+		Type: graphic.ImageTypeAnimated,
+		GIFData: &gif.GIF{
+			Image:     frames,
+			Delay:     delays,
+			LoopCount: 0, // Loop forever
+		},
+	}
+	return img.GIFBytes()
+}
+
+// handEnd returns the display coordinates at the given distance from the
+// center along the given angle (degrees clockwise from 12 o'clock).
+func handEnd(angle float64, length int) (int, int) {
+	rad := angle * math.Pi / 180
+	x := float64(centerX) + float64(length)*math.Sin(rad)
+	y := float64(centerY) - float64(length)*math.Cos(rad)
+	return int(math.Round(x)), int(math.Round(y))
+}
@@ -0,0 +1,96 @@
+package analogclock
+
+import (
+	"bytes"
+	"image/gif"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+)
+
+func TestHandAngles(t *testing.T) {
+	tests := []struct {
+		name           string
+		hour, minute   int
+		expectedHour   float64
+		expectedMinute float64
+	}{
+		{
+			name:           "3:00",
+			hour:           3,
+			minute:         0,
+			expectedHour:   90,
+			expectedMinute: 0,
+		},
+		{
+			name:           "6:00",
+			hour:           6,
+			minute:         0,
+			expectedHour:   180,
+			expectedMinute: 0,
+		},
+		{
+			name:           "15:00 uses 12-hour dial",
+			hour:           15,
+			minute:         0,
+			expectedHour:   90,
+			expectedMinute: 0,
+		},
+		{
+			name:           "6:30 hour hand halfway to 7",
+			hour:           6,
+			minute:         30,
+			expectedHour:   195,
+			expectedMinute: 180,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clockTime := time.Date(2024, 1, 1, tt.hour, tt.minute, 0, 0, time.UTC)
+			hour, minute, second := HandAngles(clockTime)
+			assert.InDelta(t, tt.expectedHour, hour, 0.001)
+			assert.InDelta(t, tt.expectedMinute, minute, 0.001)
+			assert.InDelta(t, 0, second, 0.001)
+		})
+	}
+}
+
+func TestDrawFaceHourHand(t *testing.T) {
+	opts := DefaultOptions()
+	opts.ShowSeconds = false
+	opts.TickColor = graphic.Black
+
+	t.Run("3:00 hour hand points right", func(t *testing.T) {
+		buf := graphic.NewBuffer()
+		DrawFace(buf, time.Date(2024, 1, 1, 3, 0, 0, 0, time.UTC), opts)
+
+		offset := (centerY*graphic.DisplayWidth + centerX + hourHandLen) * 3
+		assert.Equal(t, opts.HandColor[:], buf[offset:offset+3])
+	})
+
+	t.Run("6:00 hour hand points down", func(t *testing.T) {
+		buf := graphic.NewBuffer()
+		DrawFace(buf, time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC), opts)
+
+		offset := ((centerY+hourHandLen)*graphic.DisplayWidth + centerX) * 3
+		assert.Equal(t, opts.HandColor[:], buf[offset:offset+3])
+	})
+}
+
+func TestGenerateGIF(t *testing.T) {
+	data, err := GenerateGIF(time.Date(2024, 1, 1, 10, 10, 0, 0, time.UTC), DefaultOptions())
+	require.NoError(t, err)
+
+	g, err := gif.DecodeAll(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Len(t, g.Image, frameCount)
+	assert.Equal(t, 0, g.LoopCount)
+	for _, delay := range g.Delay {
+		assert.Equal(t, frameDelay, delay)
+	}
+}
@@ -0,0 +1,71 @@
+// Package clock renders clock faces locally using the 5x7 text font,
+// as an alternative to the device's built-in clock modes.
+package clock
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/text"
+)
+
+// DigitalOptions configures digital clock rendering.
+type DigitalOptions struct {
+	text.TextOptions
+	Hour24      bool // Show time in 24 hour format
+	ShowSeconds bool // Show seconds after minutes
+	ShowDate    bool // Show the date below the time
+}
+
+// DefaultDigitalOptions returns sensible default digital clock options.
+func DefaultDigitalOptions() DigitalOptions {
+	return DigitalOptions{
+		TextOptions: text.DefaultTextOptions(),
+		Hour24:      true,
+		ShowSeconds: false,
+		ShowDate:    false,
+	}
+}
+
+// FormatTime formats the time of day as HH:MM (or HH:MM:SS).
+func FormatTime(t time.Time, opts DigitalOptions) string {
+	layout := "03:04"
+	if opts.Hour24 {
+		layout = "15:04"
+	}
+	if opts.ShowSeconds {
+		layout += ":05"
+	}
+	return t.Format(layout)
+}
+
+// FormatDate formats the date as an uppercase "MON 02 JAN" string,
+// which fits the display width with the 5x7 font.
+func FormatDate(t time.Time) string {
+	return strings.ToUpper(t.Format("Mon 02 Jan"))
+}
+
+// GenerateDigitalImage creates a static image showing the given time
+// (and optionally the date) centered on the display.
+func GenerateDigitalImage(t time.Time, opts DigitalOptions) *graphic.Image {
+	buf := graphic.NewBufferWithColor(opts.Background)
+
+	lines := []string{FormatTime(t, opts)}
+	if opts.ShowDate {
+		lines = append(lines, FormatDate(t))
+	}
+
+	totalHeight := text.TextBlockHeight(lines)
+	startY := (graphic.DisplayHeight - totalHeight) / 2
+	for i, line := range lines {
+		x := (graphic.DisplayWidth - text.TextWidth(line)) / 2
+		y := startY + i*(text.FontHeight+text.LineSpacing)
+		text.DrawTextShadowed(buf, line, x, y, opts.TextOptions)
+	}
+
+	return &graphic.Image{
+		Type:       graphic.ImageTypeStatic,
+		StaticData: buf,
+	}
+}
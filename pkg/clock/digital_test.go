@@ -0,0 +1,75 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/text"
+)
+
+func TestFormatTime(t *testing.T) {
+	clockTime := time.Date(2024, 12, 25, 14, 5, 9, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		hour24      bool
+		showSeconds bool
+		expected    string
+	}{
+		{name: "24 hour", hour24: true, expected: "14:05"},
+		{name: "12 hour", hour24: false, expected: "02:05"},
+		{name: "24 hour with seconds", hour24: true, showSeconds: true, expected: "14:05:09"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultDigitalOptions()
+			opts.Hour24 = tt.hour24
+			opts.ShowSeconds = tt.showSeconds
+			assert.Equal(t, tt.expected, FormatTime(clockTime, opts))
+		})
+	}
+}
+
+func TestFormatDate(t *testing.T) {
+	date := time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, "WED 25 DEC", FormatDate(date))
+	assert.LessOrEqual(t, text.TextWidth(FormatDate(date)), graphic.DisplayWidth)
+}
+
+func TestGenerateDigitalImage(t *testing.T) {
+	opts := DefaultDigitalOptions()
+	opts.TextColor = graphic.Red
+
+	img := GenerateDigitalImage(time.Date(2024, 1, 1, 12, 34, 0, 0, time.UTC), opts)
+	data, err := img.RawBytes()
+	require.NoError(t, err)
+
+	pixelAt := func(x, y int) graphic.Color {
+		offset := (y*graphic.DisplayWidth + x) * 3
+		return graphic.Color{data[offset], data[offset+1], data[offset+2]}
+	}
+
+	// "12:34" is 5*6-1 = 29 pixels wide, so it starts at x = (64-29)/2 = 17
+	// and y = (64-7)/2 = 28. Characters start at x = 17, 23, 29, 35, 41.
+	y := 28
+
+	// '1' has its stem in column 2 on the first row
+	assert.Equal(t, graphic.Red, pixelAt(17+2, y))
+
+	// ':' has dots in column 2 on rows 2 and 5
+	assert.Equal(t, graphic.Red, pixelAt(29+2, y+2))
+	assert.Equal(t, graphic.Red, pixelAt(29+2, y+5))
+	assert.NotEqual(t, graphic.Red, pixelAt(29+2, y+3))
+
+	// '3' and '4' start on their own columns
+	assert.Equal(t, graphic.Red, pixelAt(35+1, y))
+	assert.Equal(t, graphic.Red, pixelAt(41+3, y))
+
+	// Nothing drawn left of the text block
+	assert.Equal(t, graphic.Black, pixelAt(16, y))
+}
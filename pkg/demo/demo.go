@@ -0,0 +1,188 @@
+// Package demo defines the slideshow showcasing the non-interactive display
+// features, so it can be run by the CLI or any other frontend.
+package demo
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/assets"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/playlist"
+)
+
+// Item categories
+const (
+	CategorySnake = "snake"
+	CategoryEmoji = "emoji"
+	CategoryGrot  = "grot"
+	CategoryFire  = "fire"
+	CategoryText  = "text"
+)
+
+// Categories lists the item categories in their default order.
+var Categories = []string{CategorySnake, CategoryEmoji, CategoryGrot, CategoryFire, CategoryText}
+
+// Item is one slide of the demo.
+type Item struct {
+	Category string
+	Name     string
+	Generate func() ([]byte, error) // Returns the GIF bytes to display
+	Duration time.Duration
+}
+
+// registry returns the demo items of each category, in their default order.
+func registry() map[string][]Item {
+	return map[string][]Item{
+		CategorySnake: {
+			{CategorySnake, "snake", func() ([]byte, error) { return assets.Preview.ReadFile("preview/snake-preview.gif") }, 4 * time.Second},
+		},
+		CategoryEmoji: {
+			{CategoryEmoji, "emoji: rocket", emojiGIF("rocket"), 3 * time.Second},
+			{CategoryEmoji, "emoji: thumbsup", emojiGIF("thumbsup"), 3 * time.Second},
+			{CategoryEmoji, "emoji: rofl", emojiGIF("rofl"), 3 * time.Second},
+		},
+		CategoryGrot: {
+			{CategoryGrot, "grot: halloween-4", grotGIF("halloween-4"), 3 * time.Second},
+			{CategoryGrot, "grot: matrix", grotGIF("matrix"), 4 * time.Second},
+		},
+		CategoryFire: {
+			{CategoryFire, "fire", fireGIF(), 3 * time.Second},
+		},
+		CategoryText: {
+			{CategoryText, "text: FIRE! (fireworks)", textGIF("FIRE!", "fireworks", graphic.Red), 4 * time.Second},
+			{CategoryText, "text: LGTM (appear-disappear)", textGIF("LGTM", "appear-disappear", graphic.Green), 4 * time.Second},
+		},
+	}
+}
+
+// Items returns all the demo items in their default order.
+func Items() []Item {
+	items, _ := Select(nil, nil)
+	return items
+}
+
+// Select returns the items of the given categories, in the order the
+// categories are given, or of all categories if none is given. Durations
+// override the default duration of the items of a category.
+func Select(categories []string, durations map[string]time.Duration) ([]Item, error) {
+	if len(categories) == 0 {
+		categories = Categories
+	}
+	for category, d := range durations {
+		if err := validateCategory(category); err != nil {
+			return nil, err
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("%s duration must be positive", category)
+		}
+	}
+
+	reg := registry()
+	var items []Item
+	seen := make(map[string]bool, len(categories))
+	for _, category := range categories {
+		if err := validateCategory(category); err != nil {
+			return nil, err
+		}
+		if seen[category] {
+			continue
+		}
+		seen[category] = true
+
+		for _, item := range reg[category] {
+			if d, ok := durations[category]; ok {
+				item.Duration = d
+			}
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+// ParseCategories parses a comma separated list of categories (e.g.
+// "emoji,fire,text"). An empty string selects all categories.
+func ParseCategories(s string) ([]string, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	var categories []string
+	for _, category := range strings.Split(s, ",") {
+		category = strings.ToLower(strings.TrimSpace(category))
+		if err := validateCategory(category); err != nil {
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+	return categories, nil
+}
+
+// ParseDurations parses a comma separated list of per-category durations
+// (e.g. "emoji=5s,text=10s").
+func ParseDurations(s string) (map[string]time.Duration, error) {
+	durations := make(map[string]time.Duration)
+	if strings.TrimSpace(s) == "" {
+		return durations, nil
+	}
+	for _, entry := range strings.Split(s, ",") {
+		category, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid duration %q: must be category=duration (e.g. emoji=5s)", entry)
+		}
+		category = strings.ToLower(strings.TrimSpace(category))
+		if err := validateCategory(category); err != nil {
+			return nil, err
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s duration: %w", category, err)
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("%s duration must be positive", category)
+		}
+		durations[category] = d
+	}
+	return durations, nil
+}
+
+func validateCategory(category string) error {
+	for _, c := range Categories {
+		if c == category {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown demo category: %q (valid: %s)", category, strings.Join(Categories, ", "))
+}
+
+// Shuffle randomizes the order of the items in place.
+func Shuffle(items []Item, rng *rand.Rand) {
+	rng.Shuffle(len(items), func(i, j int) {
+		items[i], items[j] = items[j], items[i]
+	})
+}
+
+func emojiGIF(name string) func() ([]byte, error) {
+	return playlistItemGIF(playlist.Item{Type: playlist.TypeEmoji, Params: map[string]string{"name": name}})
+}
+
+func grotGIF(name string) func() ([]byte, error) {
+	return playlistItemGIF(playlist.Item{Type: playlist.TypeGrot, Params: map[string]string{"name": name}})
+}
+
+func fireGIF() func() ([]byte, error) {
+	return playlistItemGIF(playlist.Item{Type: playlist.TypeFire})
+}
+
+func textGIF(msg, animation string, color graphic.Color) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		return playlist.GenerateTextGIF(msg, animation, color)
+	}
+}
+
+func playlistItemGIF(item playlist.Item) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		return playlist.Generate(item)
+	}
+}
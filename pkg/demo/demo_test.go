@@ -0,0 +1,126 @@
+package demo
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func itemNames(items []Item) []string {
+	names := make([]string, len(items))
+	for i, item := range items {
+		names[i] = item.Name
+	}
+	return names
+}
+
+func TestItemsGenerateGIFs(t *testing.T) {
+	items := Items()
+	require.Len(t, items, 9)
+
+	for _, item := range items {
+		t.Run(item.Name, func(t *testing.T) {
+			assert.Positive(t, item.Duration)
+
+			data, err := item.Generate()
+			require.NoError(t, err)
+			meta, err := graphic.GetGIFMetadata(data)
+			require.NoError(t, err)
+			assert.Positive(t, meta.Frames)
+		})
+	}
+}
+
+func TestShuffle(t *testing.T) {
+	items := Items()
+	Shuffle(items, rand.New(rand.NewSource(1)))
+
+	// Same items, in a reproducible order given the seed
+	assert.ElementsMatch(t, itemNames(Items()), itemNames(items))
+
+	again := Items()
+	Shuffle(again, rand.New(rand.NewSource(1)))
+	assert.Equal(t, itemNames(items), itemNames(again))
+}
+
+func TestItemsDefaultOrder(t *testing.T) {
+	assert.Equal(t, []string{
+		"snake",
+		"emoji: rocket",
+		"emoji: thumbsup",
+		"emoji: rofl",
+		"grot: halloween-4",
+		"grot: matrix",
+		"fire",
+		"text: FIRE! (fireworks)",
+		"text: LGTM (appear-disappear)",
+	}, itemNames(Items()))
+}
+
+func TestSelect(t *testing.T) {
+	t.Run("subset in the given order", func(t *testing.T) {
+		items, err := Select([]string{CategoryText, CategoryFire, CategoryEmoji}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"text: FIRE! (fireworks)",
+			"text: LGTM (appear-disappear)",
+			"fire",
+			"emoji: rocket",
+			"emoji: thumbsup",
+			"emoji: rofl",
+		}, itemNames(items))
+	})
+
+	t.Run("repeated category is selected once", func(t *testing.T) {
+		items, err := Select([]string{CategoryFire, CategoryFire}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"fire"}, itemNames(items))
+	})
+
+	t.Run("duration overrides", func(t *testing.T) {
+		items, err := Select([]string{CategoryGrot, CategoryFire}, map[string]time.Duration{CategoryGrot: 10 * time.Second})
+		require.NoError(t, err)
+		require.Len(t, items, 3)
+		assert.Equal(t, 10*time.Second, items[0].Duration)
+		assert.Equal(t, 10*time.Second, items[1].Duration)
+		assert.Equal(t, 3*time.Second, items[2].Duration)
+	})
+
+	t.Run("unknown category", func(t *testing.T) {
+		_, err := Select([]string{"clock"}, nil)
+		assert.ErrorContains(t, err, "unknown demo category")
+	})
+
+	t.Run("non-positive duration", func(t *testing.T) {
+		_, err := Select(nil, map[string]time.Duration{CategoryFire: 0})
+		assert.ErrorContains(t, err, "must be positive")
+	})
+}
+
+func TestParseCategories(t *testing.T) {
+	categories, err := ParseCategories("emoji, Fire,text")
+	require.NoError(t, err)
+	assert.Equal(t, []string{CategoryEmoji, CategoryFire, CategoryText}, categories)
+
+	categories, err = ParseCategories("")
+	require.NoError(t, err)
+	assert.Empty(t, categories)
+
+	_, err = ParseCategories("emoji,clock")
+	assert.ErrorContains(t, err, "unknown demo category")
+}
+
+func TestParseDurations(t *testing.T) {
+	durations, err := ParseDurations("emoji=5s, text=1m")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{CategoryEmoji: 5 * time.Second, CategoryText: time.Minute}, durations)
+
+	for _, invalid := range []string{"emoji", "clock=5s", "fire=soon", "fire=-1s"} {
+		_, err := ParseDurations(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -9,41 +9,56 @@ package emoji
 import (
 	"bytes"
 	"fmt"
+	"image"
 	"image/gif"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/pracucci/idotmatrix-overclocked/pkg/assets"
 	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
 )
 
+// Emoji categories
+const (
+	CategoryFaces       = "faces"
+	CategoryHands       = "hands"
+	CategoryCelebration = "celebration"
+	CategoryObjects     = "objects"
+	CategoryCustom      = "custom" // Emojis registered at runtime
+)
+
 // Emoji defines an emoji with its aliases and asset filename.
 type Emoji struct {
 	Names    []string // All valid names (lowercase)
 	Filename string   // Asset filename (without path)
+	Category string   // Suggested category, e.g. for a picker
+
+	data []byte // GIF data for emojis registered at runtime
 }
 
 var registry = []Emoji{
-	{Names: []string{"thumbsup", "+1"}, Filename: "thumbsup.gif"},
-	{Names: []string{"thumbsdown", "-1"}, Filename: "thumbsdown.gif"},
-	{Names: []string{"hearthands"}, Filename: "hearthands.gif"},
-	{Names: []string{"clap"}, Filename: "clap.gif"},
-	{Names: []string{"joy"}, Filename: "joy.gif"},
-	{Names: []string{"rofl", "lol"}, Filename: "rofl.gif"},
-	{Names: []string{"party"}, Filename: "party.gif"},
-	{Names: []string{"scream"}, Filename: "scream.gif"},
-	{Names: []string{"rage"}, Filename: "rage.gif"},
-	{Names: []string{"scared"}, Filename: "scared.gif"},
-	{Names: []string{"mindblow"}, Filename: "mindblow.gif"},
-	{Names: []string{"coldface"}, Filename: "coldface.gif"},
-	{Names: []string{"hotface"}, Filename: "hotface.gif"},
-	{Names: []string{"robot"}, Filename: "robot.gif"},
-	{Names: []string{"sparkles"}, Filename: "sparkles.gif"},
-	{Names: []string{"tada"}, Filename: "tada.gif"},
-	{Names: []string{"100"}, Filename: "100.gif"},
-	{Names: []string{"confetti"}, Filename: "confetti.gif"},
-	{Names: []string{"risinghands"}, Filename: "risinghands.gif"},
-	{Names: []string{"rocket"}, Filename: "rocket.gif"},
-	{Names: []string{"birthday"}, Filename: "birthday.gif"},
+	{Names: []string{"thumbsup", "+1"}, Filename: "thumbsup.gif", Category: CategoryHands},
+	{Names: []string{"thumbsdown", "-1"}, Filename: "thumbsdown.gif", Category: CategoryHands},
+	{Names: []string{"hearthands"}, Filename: "hearthands.gif", Category: CategoryHands},
+	{Names: []string{"clap"}, Filename: "clap.gif", Category: CategoryHands},
+	{Names: []string{"joy"}, Filename: "joy.gif", Category: CategoryFaces},
+	{Names: []string{"rofl", "lol"}, Filename: "rofl.gif", Category: CategoryFaces},
+	{Names: []string{"party"}, Filename: "party.gif", Category: CategoryFaces},
+	{Names: []string{"scream"}, Filename: "scream.gif", Category: CategoryFaces},
+	{Names: []string{"rage"}, Filename: "rage.gif", Category: CategoryFaces},
+	{Names: []string{"scared"}, Filename: "scared.gif", Category: CategoryFaces},
+	{Names: []string{"mindblow"}, Filename: "mindblow.gif", Category: CategoryFaces},
+	{Names: []string{"coldface"}, Filename: "coldface.gif", Category: CategoryFaces},
+	{Names: []string{"hotface"}, Filename: "hotface.gif", Category: CategoryFaces},
+	{Names: []string{"robot"}, Filename: "robot.gif", Category: CategoryFaces},
+	{Names: []string{"sparkles"}, Filename: "sparkles.gif", Category: CategoryCelebration},
+	{Names: []string{"tada"}, Filename: "tada.gif", Category: CategoryCelebration},
+	{Names: []string{"100"}, Filename: "100.gif", Category: CategoryObjects},
+	{Names: []string{"confetti"}, Filename: "confetti.gif", Category: CategoryCelebration},
+	{Names: []string{"risinghands"}, Filename: "risinghands.gif", Category: CategoryHands},
+	{Names: []string{"rocket"}, Filename: "rocket.gif", Category: CategoryObjects},
+	{Names: []string{"birthday"}, Filename: "birthday.gif", Category: CategoryCelebration},
 }
 
 // Lookup finds an emoji by name (case-insensitive).
@@ -68,6 +83,76 @@ func Names() []string {
 	return names
 }
 
+// EmojiInfo describes an emoji for listings, e.g. an emoji picker.
+type EmojiInfo struct {
+	Name      string   `json:"name"`
+	Aliases   []string `json:"aliases,omitempty"`
+	Category  string   `json:"category"`
+	Thumbnail []byte   `json:"thumbnail"` // First frame as PNG (base64 in JSON)
+}
+
+// List returns every emoji once, under its primary name, with its category
+// and a thumbnail of its first frame.
+func List() ([]EmojiInfo, error) {
+	infos := make([]EmojiInfo, 0, len(registry))
+	for _, e := range registry {
+		img, err := Generate(e.Names[0])
+		if err != nil {
+			return nil, err
+		}
+		thumbnail, err := img.PNGBytes()
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s thumbnail: %w", e.Names[0], err)
+		}
+		infos = append(infos, EmojiInfo{
+			Name:      e.Names[0],
+			Aliases:   e.Names[1:],
+			Category:  e.Category,
+			Thumbnail: thumbnail,
+		})
+	}
+	return infos, nil
+}
+
+// Register adds a custom emoji from GIF data at runtime, so that it appears in
+// Names() and can be displayed with Generate(). GIFs that are not 64x64 are
+// resized when generated.
+func Register(name string, gifBytes []byte) error {
+	nameLower := strings.ToLower(strings.TrimSpace(name))
+	if nameLower == "" {
+		return fmt.Errorf("missing emoji name")
+	}
+	if Lookup(nameLower) != nil {
+		return fmt.Errorf("emoji already exists: %s", nameLower)
+	}
+	if _, err := gif.DecodeAll(bytes.NewReader(gifBytes)); err != nil {
+		return fmt.Errorf("failed to decode emoji GIF: %w", err)
+	}
+
+	registry = append(registry, Emoji{Names: []string{nameLower}, Category: CategoryCustom, data: gifBytes})
+	return nil
+}
+
+// RegisterDir registers every .gif file in dir as a custom emoji, named
+// after the file (without extension).
+func RegisterDir(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.gif"))
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read emoji file: %w", err)
+		}
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		if err := Register(name, data); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return nil
+}
+
 // Generate creates an animated Image for the given emoji name.
 func Generate(name string) (*graphic.Image, error) {
 	emoji := Lookup(name)
@@ -75,18 +160,109 @@ func Generate(name string) (*graphic.Image, error) {
 		return nil, fmt.Errorf("unknown emoji: %s (available: %s)", name, strings.Join(Names(), ", "))
 	}
 
-	data, err := assets.Emoji.ReadFile("emoji/" + emoji.Filename)
+	data := emoji.data
+	if data == nil {
+		var err error
+		data, err = assets.Emoji.ReadFile("emoji/" + emoji.Filename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read emoji asset: %w", err)
+		}
+	}
+
+	return decodeGIF(data)
+}
+
+// SequenceHold is how long the last frame of each emoji of a sequence is held
+// before the next emoji starts, in 10ms units.
+const SequenceHold = 50
+
+// GenerateSequence creates an animated Image playing the given emojis back to
+// back, each once, holding the last frame of each for SequenceHold.
+func GenerateSequence(names []string) (*graphic.Image, error) {
+	if len(names) == 0 {
+		return nil, fmt.Errorf("missing emoji names")
+	}
+
+	// Validate all the names first, so a typo doesn't waste the decoding
+	for _, name := range names {
+		if Lookup(strings.TrimSpace(name)) == nil {
+			return nil, fmt.Errorf("unknown emoji: %s (available: %s)", name, strings.Join(Names(), ", "))
+		}
+	}
+
+	sequence := &gif.GIF{}
+	for _, name := range names {
+		part, err := Generate(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		g := part.GIFData
+
+		sequence.Image = append(sequence.Image, g.Image...)
+		sequence.Delay = append(sequence.Delay, g.Delay...)
+		sequence.Delay[len(sequence.Delay)-1] += SequenceHold
+		if len(g.Disposal) == len(g.Image) {
+			sequence.Disposal = append(sequence.Disposal, g.Disposal...)
+		} else {
+			sequence.Disposal = append(sequence.Disposal, make([]byte, len(g.Image))...)
+		}
+	}
+	sequence.Config = image.Config{
+		ColorModel: sequence.Image[0].Palette,
+		Width:      graphic.DisplayWidth,
+		Height:     graphic.DisplayHeight,
+	}
+
+	return &graphic.Image{
+		Type:    graphic.ImageTypeAnimated,
+		GIFData: sequence,
+	}, nil
+}
+
+// GenerateFromFile creates an animated Image from a GIF file, resized to 64x64 if needed.
+func GenerateFromFile(path string) (*graphic.Image, error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read emoji asset: %w", err)
+		return nil, fmt.Errorf("failed to read emoji file: %w", err)
 	}
+	return decodeGIF(data)
+}
 
+// decodeGIF decodes GIF data into an animated Image, resizing it to 64x64 if needed.
+func decodeGIF(data []byte) (*graphic.Image, error) {
 	g, err := gif.DecodeAll(bytes.NewReader(data))
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode emoji GIF: %w", err)
 	}
 
+	if g.Config.Width != graphic.DisplayWidth || g.Config.Height != graphic.DisplayHeight {
+		g = resizeGIF(g)
+	}
+
 	return &graphic.Image{
 		Type:    graphic.ImageTypeAnimated,
 		GIFData: g,
 	}, nil
 }
+
+// resizeGIF scales every frame of g to 64x64. Frames are composited with
+// CompositeGIF first, since GIF frames may only cover part of the canvas and
+// rely on their disposal methods. Transparent regions are black.
+func resizeGIF(g *gif.GIF) *gif.GIF {
+	canvases := graphic.CompositeGIF(g, graphic.Black)
+	frames := make([]*image.Paletted, len(canvases))
+	for i, canvas := range canvases {
+		frames[i] = graphic.RGBToPaletted(graphic.ResizeRGB(canvas))
+	}
+
+	return &gif.GIF{
+		Image:     frames,
+		Delay:     g.Delay,
+		LoopCount: g.LoopCount,
+		Config: image.Config{
+			ColorModel: frames[0].Palette,
+			Width:      graphic.DisplayWidth,
+			Height:     graphic.DisplayHeight,
+		},
+	}
+}
@@ -0,0 +1,226 @@
+package emoji
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/assets"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+)
+
+// encodeTestGIF returns a 2 frame GIF of the given size filled with red and blue.
+func encodeTestGIF(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	pal := color.Palette{color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255}}
+	g := &gif.GIF{}
+	for i := 0; i < 2; i++ {
+		frame := image.NewPaletted(image.Rect(0, 0, width, height), pal)
+		for p := range frame.Pix {
+			frame.Pix[p] = uint8(i)
+		}
+		g.Image = append(g.Image, frame)
+		g.Delay = append(g.Delay, 10)
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, gif.EncodeAll(&buf, g))
+	return buf.Bytes()
+}
+
+// restoreRegistry resets the registry to its current state at the end of the test.
+func restoreRegistry(t *testing.T) {
+	original := registry
+	t.Cleanup(func() { registry = original })
+}
+
+func TestRegister(t *testing.T) {
+	restoreRegistry(t)
+
+	require.NoError(t, Register("MyLogo", encodeTestGIF(t, 64, 64)))
+	assert.Contains(t, Names(), "mylogo")
+
+	img, err := Generate("mylogo")
+	require.NoError(t, err)
+	assert.Len(t, img.GIFData.Image, 2)
+	assert.Equal(t, graphic.DisplayWidth, img.GIFData.Config.Width)
+
+	t.Run("duplicate name", func(t *testing.T) {
+		assert.Error(t, Register("mylogo", encodeTestGIF(t, 64, 64)))
+		assert.Error(t, Register("thumbsup", encodeTestGIF(t, 64, 64)))
+	})
+
+	t.Run("invalid GIF", func(t *testing.T) {
+		assert.Error(t, Register("broken", []byte("not a gif")))
+		assert.NotContains(t, Names(), "broken")
+	})
+
+	t.Run("missing name", func(t *testing.T) {
+		assert.Error(t, Register(" ", encodeTestGIF(t, 64, 64)))
+	})
+}
+
+func TestRegisterDir(t *testing.T) {
+	restoreRegistry(t)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "wave.gif"), encodeTestGIF(t, 64, 64), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0644))
+
+	require.NoError(t, RegisterDir(dir))
+	assert.Contains(t, Names(), "wave")
+	assert.NotContains(t, Names(), "notes")
+
+	_, err := Generate("wave")
+	assert.NoError(t, err)
+}
+
+func TestGenerateFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "small.gif")
+	require.NoError(t, os.WriteFile(path, encodeTestGIF(t, 32, 32), 0644))
+
+	img, err := GenerateFromFile(path)
+	require.NoError(t, err)
+
+	g := img.GIFData
+	assert.Equal(t, graphic.DisplayWidth, g.Config.Width)
+	assert.Equal(t, graphic.DisplayHeight, g.Config.Height)
+	require.Len(t, g.Image, 2)
+	assert.Equal(t, []int{10, 10}, g.Delay)
+	for _, frame := range g.Image {
+		assert.Equal(t, image.Rect(0, 0, graphic.DisplayWidth, graphic.DisplayHeight), frame.Bounds())
+	}
+
+	// The second frame is blue all over, including the upscaled corner
+	r, gr, b, _ := g.Image[1].At(63, 63).RGBA()
+	assert.Equal(t, []uint32{0, 0, 0xffff}, []uint32{r, gr, b})
+
+	_, err = GenerateFromFile(filepath.Join(t.TempDir(), "missing.gif"))
+	assert.Error(t, err)
+}
+
+func TestGenerateFromFileHonorsDisposal(t *testing.T) {
+	// A full red frame disposed to the background, then a blue top-left quarter
+	pal := color.Palette{color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255}}
+	full := image.NewPaletted(image.Rect(0, 0, 32, 32), pal)
+	quarter := image.NewPaletted(image.Rect(0, 0, 16, 16), pal)
+	for p := range quarter.Pix {
+		quarter.Pix[p] = 1
+	}
+	g := &gif.GIF{
+		Image:    []*image.Paletted{full, quarter},
+		Delay:    []int{10, 10},
+		Disposal: []byte{gif.DisposalBackground, gif.DisposalNone},
+		Config:   image.Config{Width: 32, Height: 32},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, gif.EncodeAll(&buf, g))
+	path := filepath.Join(t.TempDir(), "disposal.gif")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0644))
+
+	img, err := GenerateFromFile(path)
+	require.NoError(t, err)
+	require.Len(t, img.GIFData.Image, 2)
+
+	// The red frame was cleared: blue in the top-left quarter, black elsewhere
+	second := img.GIFData.Image[1]
+	r, gr, b, _ := second.At(0, 0).RGBA()
+	assert.Equal(t, []uint32{0, 0, 0xffff}, []uint32{r, gr, b})
+	r, gr, b, _ = second.At(63, 63).RGBA()
+	assert.Equal(t, []uint32{0, 0, 0}, []uint32{r, gr, b})
+}
+
+func TestGenerateSequence(t *testing.T) {
+	names := []string{"party", "tada", "confetti"}
+
+	expectedFrames := 0
+	for _, name := range names {
+		img, err := Generate(name)
+		require.NoError(t, err)
+		expectedFrames += len(img.GIFData.Image)
+	}
+
+	img, err := GenerateSequence(names)
+	require.NoError(t, err)
+	assert.Equal(t, graphic.ImageTypeAnimated, img.Type)
+	assert.Len(t, img.GIFData.Image, expectedFrames)
+	assert.Len(t, img.GIFData.Delay, expectedFrames)
+
+	// The combined GIF encodes and decodes with all the frames
+	data, err := img.GIFBytes()
+	require.NoError(t, err)
+	meta, err := graphic.GetGIFMetadata(data)
+	require.NoError(t, err)
+	assert.Equal(t, expectedFrames, meta.Frames)
+}
+
+func TestGenerateSequenceHoldsLastFrames(t *testing.T) {
+	party, err := Generate("party")
+	require.NoError(t, err)
+	partyDelays := party.GIFData.Delay
+
+	img, err := GenerateSequence([]string{"party", "party"})
+	require.NoError(t, err)
+
+	delays := img.GIFData.Delay
+	last := len(partyDelays) - 1
+	assert.Equal(t, partyDelays[last]+SequenceHold, delays[last])
+	assert.Equal(t, partyDelays[last]+SequenceHold, delays[len(delays)-1])
+	assert.Equal(t, partyDelays[0], delays[len(partyDelays)])
+}
+
+func TestGenerateSequenceUnknownEmoji(t *testing.T) {
+	_, err := GenerateSequence([]string{"party", "nope"})
+	assert.ErrorContains(t, err, "unknown emoji: nope")
+
+	_, err = GenerateSequence(nil)
+	assert.Error(t, err)
+}
+
+func TestList(t *testing.T) {
+	infos, err := List()
+	require.NoError(t, err)
+
+	// Every embedded emoji is listed exactly once, with a PNG thumbnail
+	entries, err := assets.Emoji.ReadDir("emoji")
+	require.NoError(t, err)
+	listed := map[string]int{}
+	for _, info := range infos {
+		e := Lookup(info.Name)
+		require.NotNil(t, e, info.Name)
+		listed[e.Filename]++
+
+		assert.Contains(t, []string{CategoryFaces, CategoryHands, CategoryCelebration, CategoryObjects}, info.Category, info.Name)
+		thumbnail, err := png.Decode(bytes.NewReader(info.Thumbnail))
+		require.NoError(t, err, info.Name)
+		assert.Equal(t, image.Rect(0, 0, graphic.DisplayWidth, graphic.DisplayHeight), thumbnail.Bounds(), info.Name)
+	}
+	for _, entry := range entries {
+		assert.Equal(t, 1, listed[entry.Name()], entry.Name())
+	}
+	assert.Len(t, infos, len(entries))
+
+	// Aliases are listed with their emoji, not as separate entries
+	assert.Equal(t, []string{"+1"}, infos[0].Aliases)
+}
+
+func TestListCustomEmoji(t *testing.T) {
+	restoreRegistry(t)
+	require.NoError(t, Register("mylogo", encodeTestGIF(t, 64, 64)))
+
+	infos, err := List()
+	require.NoError(t, err)
+	last := infos[len(infos)-1]
+	assert.Equal(t, "mylogo", last.Name)
+	assert.Equal(t, CategoryCustom, last.Category)
+	assert.NotEmpty(t, last.Thumbnail)
+}
@@ -0,0 +1,128 @@
+// Package equalizer renders audio band levels as vertical bars, VU-meter style,
+// updating the display with only the pixels that changed between levels.
+package equalizer
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
+)
+
+// MaxBands is the most bands fitting the display, each at least 2 pixels wide
+const MaxBands = graphic.DisplayWidth / 2
+
+// GradientBands is how many solid color bands the gradient is split into.
+// Flushes send one packet per color, so a few bands keep updates fast.
+const GradientBands = 4
+
+// DefaultGradient colors the bars green at the bottom, yellow in the middle and
+// red at the top
+var DefaultGradient = []graphic.Color{graphic.Green, graphic.Yellow, graphic.Red}
+
+// BarHeight returns how many rows a band level fills, from 0 (empty) to 1 (the
+// full display height). Levels out of range are clamped.
+func BarHeight(level float64) int {
+	if math.IsNaN(level) {
+		return 0
+	}
+	level = max(0, min(level, 1))
+	return int(math.Round(level * graphic.DisplayHeight))
+}
+
+// GradientColor returns the color at t, from 0 (the first stop) to 1 (the last
+// stop), mixing the two closest stops.
+func GradientColor(stops []graphic.Color, t float64) graphic.Color {
+	if len(stops) == 1 {
+		return stops[0]
+	}
+	t = max(0, min(t, 1))
+	pos := t * float64(len(stops)-1)
+	i := min(int(pos), len(stops)-2)
+	return graphic.MixColors(stops[i], stops[i+1], pos-float64(i))
+}
+
+// bandColumns returns the first and last column of a band. Bands wider than 2
+// pixels leave a 1 pixel gap on their right.
+func bandColumns(band, bands int) (int, int) {
+	from := band * graphic.DisplayWidth / bands
+	to := (band+1)*graphic.DisplayWidth/bands - 1
+	if to-from >= 2 {
+		to--
+	}
+	return from, to
+}
+
+// ValidateLevels checks there are between 1 and MaxBands levels.
+func ValidateLevels(levels []float64) error {
+	if len(levels) < 1 || len(levels) > MaxBands {
+		return fmt.Errorf("invalid number of bands: %d (valid: 1-%d)", len(levels), MaxBands)
+	}
+	return nil
+}
+
+// ParseLevels parses a line of band levels separated by commas or spaces, such
+// as "0.2, 0.8, 0.5", and validates their number.
+func ParseLevels(line string) ([]float64, error) {
+	fields := strings.FieldsFunc(line, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	levels := make([]float64, 0, len(fields))
+	for _, field := range fields {
+		level, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid level %q: %w", field, err)
+		}
+		levels = append(levels, level)
+	}
+	if err := ValidateLevels(levels); err != nil {
+		return nil, err
+	}
+	return levels, nil
+}
+
+// RowColor returns the color of a bar row, counted from the bottom: the
+// gradient is quantized into GradientBands solid bands of equal height.
+func RowColor(gradient []graphic.Color, row int) graphic.Color {
+	band := min(row*GradientBands/graphic.DisplayHeight, GradientBands-1)
+	return GradientColor(gradient, float64(band)/float64(GradientBands-1))
+}
+
+// DrawBars draws one vertical bar per level across the display width, rising
+// from the bottom. Each row is colored by its height along the gradient, so
+// louder bands reach further into the last colors.
+func DrawBars(buf []byte, levels []float64, gradient []graphic.Color) {
+	for band, level := range levels {
+		from, to := bandColumns(band, len(levels))
+		for row := 0; row < BarHeight(level); row++ {
+			color := RowColor(gradient, row)
+			for x := from; x <= to; x++ {
+				graphic.SetPixel(buf, x, graphic.DisplayHeight-1-row, color)
+			}
+		}
+	}
+}
+
+// Renderer handles diff-based rendering of the bars to the device
+type Renderer struct {
+	*protocol.DiffRenderer
+	gradient []graphic.Color
+}
+
+// NewRenderer creates a new renderer coloring the bars with the given gradient
+func NewRenderer(device protocol.DeviceConnection, gradient []graphic.Color) *Renderer {
+	return &Renderer{
+		DiffRenderer: protocol.NewDiffRenderer(device),
+		gradient:     gradient,
+	}
+}
+
+// Render draws the bars of the given levels on the current buffer.
+// This is a pure function that updates the buffer without I/O
+func (r *Renderer) Render(levels []float64) {
+	copy(r.Buffer(), graphic.NewBuffer())
+	DrawBars(r.Buffer(), levels, r.gradient)
+}
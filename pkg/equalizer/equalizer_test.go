@@ -0,0 +1,181 @@
+package equalizer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
+)
+
+// recordingDevice records the packets written to it
+type recordingDevice struct {
+	packets [][]byte
+}
+
+func (d *recordingDevice) WritePacket(packet []byte) error {
+	d.packets = append(d.packets, append([]byte(nil), packet...))
+	return nil
+}
+
+func (d *recordingDevice) ReadResponse() ([]byte, error) {
+	return []byte{0x05, 0x00, 0x00, 0x00, 0x01}, nil
+}
+func (d *recordingDevice) PollResponse() ([]byte, bool) { return nil, false }
+func (d *recordingDevice) DrainResponses()              {}
+
+func TestBarHeight(t *testing.T) {
+	tests := []struct {
+		level    float64
+		expected int
+	}{
+		{level: 0, expected: 0},
+		{level: 0.5, expected: 32},
+		{level: 1, expected: 64},
+		{level: -0.3, expected: 0},
+		{level: 1.7, expected: 64},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, BarHeight(tt.level), "level %v", tt.level)
+	}
+}
+
+// columnHeight returns how many lit pixels column x has, checking they are
+// contiguous from the bottom
+func columnHeight(t *testing.T, buf []byte, x int) int {
+	height := 0
+	for y := graphic.DisplayHeight - 1; y >= 0; y-- {
+		offset := (y*graphic.DisplayWidth + x) * 3
+		if buf[offset] == 0 && buf[offset+1] == 0 && buf[offset+2] == 0 {
+			break
+		}
+		height++
+	}
+	for y := graphic.DisplayHeight - 1 - height; y >= 0; y-- {
+		offset := (y*graphic.DisplayWidth + x) * 3
+		require.Equal(t, []byte{0, 0, 0}, buf[offset:offset+3], "column %d has a gap", x)
+	}
+	return height
+}
+
+func TestDrawBars(t *testing.T) {
+	buf := graphic.NewBuffer()
+	DrawBars(buf, []float64{1, 0.5, 0, 0.25}, DefaultGradient)
+
+	// Four 16 pixel wide bands, each with a 1 pixel gap on the right
+	for x := 0; x < 15; x++ {
+		assert.Equal(t, 64, columnHeight(t, buf, x), "column %d", x)
+		assert.Equal(t, 32, columnHeight(t, buf, 16+x), "column %d", 16+x)
+		assert.Equal(t, 0, columnHeight(t, buf, 32+x), "column %d", 32+x)
+		assert.Equal(t, 16, columnHeight(t, buf, 48+x), "column %d", 48+x)
+	}
+	for _, gap := range []int{15, 31, 47, 63} {
+		assert.Equal(t, 0, columnHeight(t, buf, gap), "gap column %d", gap)
+	}
+
+	// The full bar goes from green at the bottom to red at the top
+	assert.Equal(t, []byte{0, 255, 0}, buf[(63*graphic.DisplayWidth)*3:(63*graphic.DisplayWidth)*3+3])
+	assert.Equal(t, []byte{255, 0, 0}, buf[0:3])
+}
+
+func TestRowColor(t *testing.T) {
+	// 4 bands of 16 rows: green, yellow-green, orange, red
+	colors := map[graphic.Color]bool{}
+	for row := 0; row < graphic.DisplayHeight; row++ {
+		colors[RowColor(DefaultGradient, row)] = true
+	}
+	assert.Len(t, colors, GradientBands)
+
+	assert.Equal(t, graphic.Green, RowColor(DefaultGradient, 0))
+	assert.Equal(t, graphic.Green, RowColor(DefaultGradient, 15))
+	assert.Equal(t, GradientColor(DefaultGradient, 1.0/3), RowColor(DefaultGradient, 16))
+	assert.Equal(t, graphic.Red, RowColor(DefaultGradient, 48))
+	assert.Equal(t, graphic.Red, RowColor(DefaultGradient, 63))
+}
+
+func TestFullBarsFlushOneColorPerGradientBand(t *testing.T) {
+	device := &recordingDevice{}
+	r := NewRenderer(device, DefaultGradient)
+	r.Upload = protocol.UploadConfig{}
+
+	r.Render([]float64{1, 1, 1, 1})
+	require.NoError(t, r.Flush())
+
+	// 16 rows of 60 pixels per gradient band, in 4 packets of up to 255 pixels
+	colors := map[graphic.Color]bool{}
+	for _, packet := range device.packets {
+		colors[graphic.Color{packet[5], packet[6], packet[7]}] = true
+	}
+	assert.Len(t, colors, GradientBands)
+	assert.Equal(t, 4*GradientBands, len(device.packets))
+}
+
+func TestGradientColor(t *testing.T) {
+	assert.Equal(t, graphic.Green, GradientColor(DefaultGradient, 0))
+	assert.Equal(t, graphic.Yellow, GradientColor(DefaultGradient, 0.5))
+	assert.Equal(t, graphic.Red, GradientColor(DefaultGradient, 1))
+	assert.Equal(t, graphic.Color{128, 255, 0}, GradientColor(DefaultGradient, 0.25))
+	assert.Equal(t, graphic.Blue, GradientColor([]graphic.Color{graphic.Blue}, 0.7))
+}
+
+func TestValidateLevels(t *testing.T) {
+	assert.NoError(t, ValidateLevels([]float64{0.5}))
+	assert.NoError(t, ValidateLevels(make([]float64, MaxBands)))
+	assert.Error(t, ValidateLevels(nil))
+	assert.Error(t, ValidateLevels(make([]float64, MaxBands+1)))
+}
+
+func TestParseLevels(t *testing.T) {
+	levels, err := ParseLevels("0.2, 0.8 0.5\t1")
+	require.NoError(t, err)
+	assert.Equal(t, []float64{0.2, 0.8, 0.5, 1}, levels)
+
+	_, err = ParseLevels("0.2,loud")
+	assert.Error(t, err)
+	_, err = ParseLevels("  ")
+	assert.Error(t, err)
+}
+
+func TestRendererSendsOnlyChangedPixels(t *testing.T) {
+	device := &recordingDevice{}
+	r := NewRenderer(device, DefaultGradient)
+	r.Upload = protocol.UploadConfig{}
+
+	r.Render([]float64{0.5, 0.5, 0.5, 0.5})
+	r.SetPrevBuffer(r.Buffer())
+
+	// Same levels again: nothing to send
+	r.Render([]float64{0.5, 0.5, 0.5, 0.5})
+	assert.Empty(t, r.ComputeDiff())
+	require.NoError(t, r.Flush())
+	assert.Empty(t, device.packets)
+
+	// Raising the second band only touches its columns above the old bar
+	r.Render([]float64{0.5, 0.75, 0.5, 0.5})
+	require.NoError(t, r.Flush())
+	require.NotEmpty(t, device.packets)
+
+	pixels := 0
+	for _, packet := range device.packets {
+		require.Equal(t, byte(0x05), packet[2], "only multi-pixel packets are sent")
+		for i := 8; i < len(packet); i += 2 {
+			x, y := int(packet[i]), int(packet[i+1])
+			assert.GreaterOrEqual(t, x, 16)
+			assert.Less(t, x, 31)
+			assert.GreaterOrEqual(t, y, 16)
+			assert.Less(t, y, 32)
+			pixels++
+		}
+	}
+	assert.Equal(t, 15*16, pixels)
+
+	// Lowering it back clears those same pixels to black
+	device.packets = nil
+	r.Render([]float64{0.5, 0.5, 0.5, 0.5})
+	diff := r.ComputeDiff()
+	require.Len(t, diff, 1)
+	assert.Len(t, diff[graphic.Black], 15*16)
+}
@@ -2,19 +2,24 @@ package fire
 
 import (
 	"bytes"
+	"fmt"
 	"image"
 	"image/color"
 	"image/gif"
+	"math"
 	"math/rand"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
 )
 
 const (
-	numFrames   = 32
-	frameDelay  = 5 // 50ms per frame (delay is in 1/100s)
-	paletteSize = 37
+	numFrames    = 32
+	frameDelay   = 5 // 50ms per frame (delay is in 1/100s)
+	paletteSize  = 37
+	maxWindShift = 3 // Horizontal spread shift in pixels at full wind
 )
 
 // DOOM fire palette: black → red → orange → yellow → white
@@ -58,9 +63,89 @@ var palette = []color.RGBA{
 	{0xF0, 0xE0, 0x50, 0xFF}, // warm yellow (heat source)
 }
 
-// GenerateGIF generates a DOOM-style fire animation GIF.
-func GenerateGIF() []byte {
-	rand.Seed(time.Now().UnixNano())
+// Spread directions
+const (
+	DirectionUp   = "up"   // Heat source at the bottom, flames rise (default)
+	DirectionDown = "down" // Heat source at the top, flames fall like a waterfall
+)
+
+// Palettes maps palette names to fire palettes, from coldest to hottest.
+// The alternative palettes are channel permutations of the classic one.
+var Palettes = map[string][]color.RGBA{
+	"classic": palette,
+	"ice":     permutePalette(func(c color.RGBA) color.RGBA { return color.RGBA{c.B, c.G, c.R, c.A} }),
+	"green":   permutePalette(func(c color.RGBA) color.RGBA { return color.RGBA{c.G, c.R, c.B, c.A} }),
+	"purple":  permutePalette(func(c color.RGBA) color.RGBA { return color.RGBA{c.R, c.B, c.R, c.A} }),
+}
+
+// PaletteNames returns the sorted list of available palette names.
+func PaletteNames() []string {
+	names := make([]string, 0, len(Palettes))
+	for name := range Palettes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Options configures the fire animation.
+type Options struct {
+	Palette   string  // Palette name (see Palettes)
+	Direction string  // DirectionUp or DirectionDown
+	Loops     int     // GIF loop count (0 loops forever)
+	Wind      float64 // Horizontal lean, from -1.0 (left) to 1.0 (right)
+	Cooling   int     // Decay bias, higher values make shorter flames (default: 2)
+}
+
+// DefaultOptions returns the classic upward DOOM fire, looping forever.
+func DefaultOptions() Options {
+	return Options{
+		Palette:   "classic",
+		Direction: DirectionUp,
+		Loops:     0,
+		Wind:      0,
+		Cooling:   2,
+	}
+}
+
+// GenerateGIF generates a DOOM-style fire animation GIF with the default options.
+// loops is the GIF loop count (0 loops forever).
+func GenerateGIF(loops int) []byte {
+	opts := DefaultOptions()
+	opts.Loops = loops
+	gifData, _ := GenerateGIFWithOptions(opts) // Default options are always valid
+	return gifData
+}
+
+// GenerateGIFSeeded generates a fire animation GIF with the default options,
+// using the given seed for the random source. The same seed always produces
+// byte-identical output, which is useful for reproducible previews.
+func GenerateGIFSeeded(seed int64) []byte {
+	gifData, _ := generateGIF(DefaultOptions(), rand.New(rand.NewSource(seed))) // Default options are always valid
+	return gifData
+}
+
+// GenerateGIFWithOptions generates a DOOM-style fire animation GIF.
+// Returns an error if the palette or direction is unknown.
+func GenerateGIFWithOptions(opts Options) ([]byte, error) {
+	return generateGIF(opts, rand.New(rand.NewSource(time.Now().UnixNano())))
+}
+
+func generateGIF(opts Options, rng *rand.Rand) ([]byte, error) {
+	firePalette, ok := Palettes[strings.ToLower(opts.Palette)]
+	if !ok {
+		return nil, fmt.Errorf("unknown palette: %s (valid: %s)", opts.Palette, strings.Join(PaletteNames(), ", "))
+	}
+	direction := strings.ToLower(opts.Direction)
+	if direction != DirectionUp && direction != DirectionDown {
+		return nil, fmt.Errorf("unknown direction: %s (valid: %s, %s)", opts.Direction, DirectionUp, DirectionDown)
+	}
+	if opts.Wind < -1 || opts.Wind > 1 {
+		return nil, fmt.Errorf("invalid wind: %v (valid: -1.0 to 1.0)", opts.Wind)
+	}
+	if opts.Cooling < 0 {
+		return nil, fmt.Errorf("invalid cooling: %d (must be >= 0)", opts.Cooling)
+	}
 
 	displaySize := graphic.DisplayWidth
 
@@ -75,12 +160,12 @@ func GenerateGIF() []byte {
 	// Warmup: run simulation until fire reaches steady state
 	// This ensures the GIF starts with flames already burning
 	for i := 0; i < 200; i++ {
-		spreadFireFrame(firePixels, displaySize)
+		spreadFireFrame(firePixels, displaySize, opts, rng)
 	}
 
 	// Build color palette for GIF
 	gifPalette := make(color.Palette, paletteSize)
-	for i, c := range palette {
+	for i, c := range firePalette {
 		gifPalette[i] = c
 	}
 
@@ -90,15 +175,19 @@ func GenerateGIF() []byte {
 	for t := 0; t < numFrames; t++ {
 		// Simulate fire spread multiple times per frame
 		for i := 0; i < 4; i++ {
-			spreadFireFrame(firePixels, displaySize)
+			spreadFireFrame(firePixels, displaySize, opts, rng)
 		}
 
-		// Create frame from buffer
+		// Create frame from buffer, flipped vertically for a downward spread
 		frame := image.NewPaletted(image.Rect(0, 0, displaySize, displaySize), gifPalette)
 		for y := 0; y < displaySize; y++ {
+			frameY := y
+			if direction == DirectionDown {
+				frameY = displaySize - 1 - y
+			}
 			for x := 0; x < displaySize; x++ {
 				idx := firePixels[y*displaySize+x]
-				frame.SetColorIndex(x, y, uint8(idx))
+				frame.SetColorIndex(x, frameY, uint8(idx))
 			}
 		}
 		frames[t] = frame
@@ -108,35 +197,53 @@ func GenerateGIF() []byte {
 	g := &gif.GIF{
 		Image:     frames,
 		Delay:     delays,
-		LoopCount: 0,
+		LoopCount: opts.Loops,
 	}
 	var buf bytes.Buffer
-	gif.EncodeAll(&buf, g)
-	return buf.Bytes()
+	if err := gif.EncodeAll(&buf, g); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// permutePalette derives a palette from the classic one by remapping each color.
+func permutePalette(remap func(color.RGBA) color.RGBA) []color.RGBA {
+	out := make([]color.RGBA, len(palette))
+	for i, c := range palette {
+		out[i] = remap(c)
+	}
+	return out
 }
 
-func spreadFireFrame(firePixels []int, displaySize int) {
+func spreadFireFrame(firePixels []int, displaySize int, opts Options, rng *rand.Rand) {
 	for x := 0; x < displaySize; x++ {
 		for y := 1; y < displaySize; y++ {
-			spreadFire(firePixels, y*displaySize+x, displaySize)
+			spreadFire(firePixels, y*displaySize+x, displaySize, opts, rng)
 		}
 	}
 }
 
-func spreadFire(firePixels []int, src int, displaySize int) {
+// spreadOffset returns the horizontal offset where heat spreads to, given the
+// random value (0-7). Without wind the offset ranges from -6 to +1, wind shifts
+// the whole range by up to maxWindShift pixels.
+func spreadOffset(randVal int, wind float64) int {
+	return 1 - randVal + int(math.Round(wind*maxWindShift))
+}
+
+func spreadFire(firePixels []int, src int, displaySize int, opts Options, rng *rand.Rand) {
 	pixel := firePixels[src]
 	if pixel == 0 {
 		firePixels[src-displaySize] = 0
 		return
 	}
-	randVal := rand.Intn(8)
-	dst := src - randVal + 1
+	randVal := rng.Intn(8)
+	dst := src + spreadOffset(randVal, opts.Wind)
 	if dst < displaySize {
 		dst = displaySize
 	}
-	// Decay rate: average 1.0 per row so flames die out in ~36 pixels
-	// Values: 0,0,1,1,1,1,2,2 -> average 8/8 = 1.0
-	decay := (randVal + 2) / 4
+	// Decay rate: with the default cooling of 2 the average is 1.0 per row so
+	// flames die out in ~36 pixels. Values: 0,0,1,1,1,1,2,2 -> average 8/8 = 1.0
+	decay := (randVal + opts.Cooling) / 4
 	newVal := pixel - decay
 	if newVal < 0 {
 		newVal = 0
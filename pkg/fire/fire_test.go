@@ -0,0 +1,120 @@
+package fire
+
+import (
+	"bytes"
+	"image/color"
+	"image/gif"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+)
+
+func TestGenerateGIFWithOptions(t *testing.T) {
+	for _, name := range PaletteNames() {
+		for _, direction := range []string{DirectionUp, DirectionDown} {
+			t.Run(name+"/"+direction, func(t *testing.T) {
+				opts := DefaultOptions()
+				opts.Palette = name
+				opts.Direction = direction
+
+				data, err := GenerateGIFWithOptions(opts)
+				require.NoError(t, err)
+
+				g, err := gif.DecodeAll(bytes.NewReader(data))
+				require.NoError(t, err)
+				require.Len(t, g.Image, numFrames)
+
+				expected := Palettes[name]
+				heatRow := graphic.DisplayHeight - 1
+				if direction == DirectionDown {
+					heatRow = 0
+				}
+
+				for _, frame := range g.Image {
+					// Frames use the selected palette
+					for i, c := range expected {
+						assert.Equal(t, color.Color(c), frame.Palette[i])
+					}
+					// The heat source row uses the hottest color of the palette
+					for x := 0; x < graphic.DisplayWidth; x++ {
+						assert.Equal(t, color.Color(expected[paletteSize-1]), frame.At(x, heatRow))
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestGenerateGIFWithOptionsInvalid(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Palette = "rainbow"
+	_, err := GenerateGIFWithOptions(opts)
+	assert.ErrorContains(t, err, "unknown palette")
+
+	opts = DefaultOptions()
+	opts.Direction = "sideways"
+	_, err = GenerateGIFWithOptions(opts)
+	assert.ErrorContains(t, err, "unknown direction")
+}
+
+func TestPalettesAreDistinct(t *testing.T) {
+	seen := map[color.RGBA]string{}
+	for name, p := range Palettes {
+		require.Len(t, p, paletteSize)
+		c := p[paletteSize/2]
+		_, dup := seen[c]
+		assert.False(t, dup, "palette %s shares its colors with %s", name, seen[c])
+		seen[c] = name
+	}
+}
+
+func TestSpreadOffsetWind(t *testing.T) {
+	averageOffset := func(wind float64) float64 {
+		sum := 0
+		for randVal := 0; randVal < 8; randVal++ {
+			sum += spreadOffset(randVal, wind)
+		}
+		return float64(sum) / 8
+	}
+
+	assert.Greater(t, averageOffset(1), averageOffset(0))
+	assert.Greater(t, averageOffset(0.5), averageOffset(0))
+	assert.Less(t, averageOffset(-1), averageOffset(0))
+
+	// No wind keeps the classic DOOM spread
+	assert.Equal(t, 1, spreadOffset(0, 0))
+	assert.Equal(t, -6, spreadOffset(7, 0))
+}
+
+func TestGenerateGIFWithOptionsWindAndCooling(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Wind = 0.8
+	opts.Cooling = 4
+	_, err := GenerateGIFWithOptions(opts)
+	require.NoError(t, err)
+
+	opts.Wind = 1.5
+	_, err = GenerateGIFWithOptions(opts)
+	assert.ErrorContains(t, err, "invalid wind")
+
+	opts = DefaultOptions()
+	opts.Cooling = -1
+	_, err = GenerateGIFWithOptions(opts)
+	assert.ErrorContains(t, err, "invalid cooling")
+}
+
+func TestGenerateGIFSeeded(t *testing.T) {
+	first := GenerateGIFSeeded(42)
+	second := GenerateGIFSeeded(42)
+	assert.Equal(t, first, second, "same seed should produce byte-identical output")
+
+	other := GenerateGIFSeeded(43)
+	assert.NotEqual(t, first, other, "different seeds should produce different output")
+
+	g, err := gif.DecodeAll(bytes.NewReader(first))
+	require.NoError(t, err)
+	assert.Len(t, g.Image, numFrames)
+}
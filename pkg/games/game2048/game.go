@@ -0,0 +1,381 @@
+package game2048
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/games/input"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
+)
+
+// Board constants
+const (
+	GridSize    = 4
+	WinningTile = 2048
+)
+
+// Direction represents a slide direction.
+type Direction int
+
+const (
+	Left Direction = iota
+	Right
+	Up
+	Down
+)
+
+// RandSource is an interface for random number generation (for testing)
+type RandSource interface {
+	Intn(n int) int
+}
+
+// defaultRand wraps math/rand for production use
+type defaultRand struct{}
+
+func (defaultRand) Intn(n int) int { return rand.Intn(n) }
+
+// GameState contains all testable game state (no I/O dependencies)
+type GameState struct {
+	Grid     [GridSize][GridSize]int // Tile values indexed by [row][col], 0 is empty
+	Score    int
+	Won      bool // Set once a WinningTile has been reached (play can continue)
+	GameOver bool
+}
+
+// NewGameState creates a new empty game state
+func NewGameState() *GameState {
+	return &GameState{}
+}
+
+// slideLine slides a line of tiles toward index 0, merging equal adjacent
+// tiles once per move. Returns the new line and the points scored.
+func slideLine(line [GridSize]int) ([GridSize]int, int) {
+	var out [GridSize]int
+	score := 0
+	n := 0
+	merged := false // Whether out[n-1] is the result of a merge in this move
+
+	for _, v := range line {
+		if v == 0 {
+			continue
+		}
+		if n > 0 && out[n-1] == v && !merged {
+			out[n-1] *= 2
+			score += out[n-1]
+			merged = true
+			continue
+		}
+		out[n] = v
+		n++
+		merged = false
+	}
+	return out, score
+}
+
+// getLine returns the i-th line of the grid ordered so that index 0 is the
+// edge tiles slide toward in the given direction.
+func (s *GameState) getLine(dir Direction, i int) [GridSize]int {
+	var line [GridSize]int
+	for j := 0; j < GridSize; j++ {
+		row, col := lineCell(dir, i, j)
+		line[j] = s.Grid[row][col]
+	}
+	return line
+}
+
+// setLine writes back a line returned by getLine
+func (s *GameState) setLine(dir Direction, i int, line [GridSize]int) {
+	for j := 0; j < GridSize; j++ {
+		row, col := lineCell(dir, i, j)
+		s.Grid[row][col] = line[j]
+	}
+}
+
+// lineCell maps position j of line i in the given direction to grid coordinates
+func lineCell(dir Direction, i, j int) (int, int) {
+	switch dir {
+	case Left:
+		return i, j
+	case Right:
+		return i, GridSize - 1 - j
+	case Up:
+		return j, i
+	default: // Down
+		return GridSize - 1 - j, i
+	}
+}
+
+// Move slides all tiles in the given direction, merging equal tiles.
+// Returns true if any tile moved or merged.
+func (s *GameState) Move(dir Direction) bool {
+	if s.GameOver {
+		return false
+	}
+
+	changed := false
+	for i := 0; i < GridSize; i++ {
+		line := s.getLine(dir, i)
+		slid, score := slideLine(line)
+		if slid != line {
+			changed = true
+			s.setLine(dir, i, slid)
+		}
+		s.Score += score
+	}
+
+	if !s.Won {
+		s.Won = s.MaxTile() >= WinningTile
+	}
+	return changed
+}
+
+// EmptyCells returns the number of empty cells
+func (s *GameState) EmptyCells() int {
+	count := 0
+	for row := 0; row < GridSize; row++ {
+		for col := 0; col < GridSize; col++ {
+			if s.Grid[row][col] == 0 {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// MaxTile returns the highest tile value on the grid
+func (s *GameState) MaxTile() int {
+	max := 0
+	for row := 0; row < GridSize; row++ {
+		for col := 0; col < GridSize; col++ {
+			if s.Grid[row][col] > max {
+				max = s.Grid[row][col]
+			}
+		}
+	}
+	return max
+}
+
+// SpawnTile places a new tile (2 with 90% probability, 4 otherwise) on a
+// random empty cell. Returns false if the grid is full.
+func (s *GameState) SpawnTile(rng RandSource) bool {
+	empty := s.EmptyCells()
+	if empty == 0 {
+		return false
+	}
+
+	value := 2
+	if rng.Intn(10) == 0 {
+		value = 4
+	}
+
+	target := rng.Intn(empty)
+	for row := 0; row < GridSize; row++ {
+		for col := 0; col < GridSize; col++ {
+			if s.Grid[row][col] != 0 {
+				continue
+			}
+			if target == 0 {
+				s.Grid[row][col] = value
+				return true
+			}
+			target--
+		}
+	}
+	return false
+}
+
+// CanMove returns true if any move is possible: there is an empty cell or
+// two equal tiles are adjacent.
+func (s *GameState) CanMove() bool {
+	for row := 0; row < GridSize; row++ {
+		for col := 0; col < GridSize; col++ {
+			v := s.Grid[row][col]
+			if v == 0 {
+				return true
+			}
+			if col+1 < GridSize && s.Grid[row][col+1] == v {
+				return true
+			}
+			if row+1 < GridSize && s.Grid[row+1][col] == v {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CheckGameOver checks if no moves are left
+func (s *GameState) CheckGameOver() bool {
+	if !s.CanMove() {
+		s.GameOver = true
+		return true
+	}
+	return false
+}
+
+// Game orchestrates gameplay with I/O dependencies
+type Game struct {
+	state      *GameState
+	device     protocol.DeviceConnection
+	renderer   *Renderer
+	background []byte
+	input      input.Source
+	running    bool
+	randSource RandSource
+}
+
+// NewGame creates a new 2048 game
+func NewGame(device protocol.DeviceConnection) *Game {
+	return &Game{
+		device:     device,
+		renderer:   NewRenderer(device),
+		input:      input.NewTerminalInput(),
+		running:    true,
+		randSource: defaultRand{},
+	}
+}
+
+// SetUploadConfig sets the delays used when sending pixels to the device
+func (g *Game) SetUploadConfig(cfg protocol.UploadConfig) {
+	g.renderer.Upload = cfg
+}
+
+// SetInput replaces the terminal as the source of key presses, e.g. with an
+// input.ChannelInput in tests or for a remote controller
+func (g *Game) SetInput(src input.Source) {
+	g.input = src
+}
+
+// reset initializes the game state for a new game with two starting tiles
+func (g *Game) reset() {
+	g.state = NewGameState()
+	g.state.SpawnTile(g.randSource)
+	g.state.SpawnTile(g.randSource)
+	g.background = GenerateGameBackground()
+}
+
+// handleKey applies a key press to the game state
+func (g *Game) handleKey(key rune) {
+	var dir Direction
+	switch key {
+	case 'a', 'A':
+		dir = Left
+	case 'd', 'D':
+		dir = Right
+	case 'w', 'W':
+		dir = Up
+	case 's', 'S':
+		dir = Down
+	case 'q', 'Q':
+		g.running = false
+		return
+	default:
+		return
+	}
+
+	if g.state.Move(dir) {
+		g.state.SpawnTile(g.randSource)
+		g.state.CheckGameOver()
+	}
+}
+
+// render draws the current game state to the display
+func (g *Game) render() error {
+	g.renderer.RenderState(g.state, g.background)
+	return g.renderer.Flush()
+}
+
+// showImage displays a static image on the device
+func (g *Game) showImage(rgbData []byte) error {
+	if err := protocol.SetDrawMode(g.device, 1); err != nil {
+		return err
+	}
+	if err := protocol.SendImage(g.device, rgbData); err != nil {
+		return err
+	}
+	time.Sleep(500 * time.Millisecond)
+	return nil
+}
+
+// waitForKey blocks until a key is pressed
+func (g *Game) waitForKey() rune {
+	return <-g.input.Keys()
+}
+
+// runGame runs the main game loop until the game is over or the player quits.
+// The game is turn based, so the display is only updated after each key
+// press. Returns an error if the display can't be updated anymore (e.g. the
+// BLE link dropped)
+func (g *Game) runGame() error {
+	// Initialize renderer with background
+	g.renderer.SetPrevBuffer(g.background)
+	g.renderer.SetCurrBuffer(g.background)
+
+	// Display initial background
+	if err := g.showImage(g.background); err != nil {
+		return err
+	}
+
+	for g.running && !g.state.GameOver {
+		if err := g.render(); err != nil {
+			return fmt.Errorf("failed to draw on the display: %w", err)
+		}
+		g.handleKey(g.waitForKey())
+	}
+
+	// Show the final board before the game over screen
+	if g.state.GameOver {
+		if err := g.render(); err != nil {
+			return fmt.Errorf("failed to draw on the display: %w", err)
+		}
+		time.Sleep(2 * time.Second)
+	}
+	return nil
+}
+
+// Run starts the main game loop
+func (g *Game) Run() error {
+	fmt.Println("Starting 2048!")
+	fmt.Println("Controls: WASD/Arrows=Slide tiles, Q=Quit")
+
+	stop := g.input.Start()
+	defer stop()
+
+	for g.running {
+		// Show cover image and wait for key to start
+		if err := g.showImage(GenerateCoverImage()); err != nil {
+			return err
+		}
+		fmt.Print("Press any key to start...")
+		key := g.waitForKey()
+		fmt.Println()
+		if key == 'q' || key == 'Q' {
+			break
+		}
+
+		// Reset and start game
+		g.reset()
+		if err := g.runGame(); err != nil {
+			return err
+		}
+
+		if !g.running {
+			break
+		}
+
+		// Show game over screen
+		if err := g.showImage(GenerateGameOverImage()); err != nil {
+			return err
+		}
+		fmt.Printf("Game Over! Score: %d, Best tile: %d\n", g.state.Score, g.state.MaxTile())
+		fmt.Print("Press any key to restart (Q to quit)...")
+		key = g.waitForKey()
+		fmt.Println()
+		if key == 'q' || key == 'Q' {
+			break
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,315 @@
+package game2048
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
+)
+
+// mockRand provides deterministic random numbers for testing
+type mockRand struct {
+	values []int
+	index  int
+}
+
+func (m *mockRand) Intn(n int) int {
+	if m.index >= len(m.values) {
+		return 0
+	}
+	v := m.values[m.index] % n
+	m.index++
+	return v
+}
+
+func TestSlideLine(t *testing.T) {
+	tests := []struct {
+		name          string
+		line          [GridSize]int
+		expected      [GridSize]int
+		expectedScore int
+	}{
+		{name: "empty line", line: [GridSize]int{0, 0, 0, 0}, expected: [GridSize]int{0, 0, 0, 0}},
+		{name: "single tile slides", line: [GridSize]int{0, 0, 0, 2}, expected: [GridSize]int{2, 0, 0, 0}},
+		{name: "two 2s merge to 4", line: [GridSize]int{2, 2, 0, 0}, expected: [GridSize]int{4, 0, 0, 0}, expectedScore: 4},
+		{name: "merge across gap", line: [GridSize]int{2, 0, 0, 2}, expected: [GridSize]int{4, 0, 0, 0}, expectedScore: 4},
+		{name: "triple 2 merges only the leading pair", line: [GridSize]int{2, 2, 2, 0}, expected: [GridSize]int{4, 2, 0, 0}, expectedScore: 4},
+		{name: "four 2s merge into two 4s", line: [GridSize]int{2, 2, 2, 2}, expected: [GridSize]int{4, 4, 0, 0}, expectedScore: 8},
+		{name: "merged tile does not merge again", line: [GridSize]int{4, 4, 8, 0}, expected: [GridSize]int{8, 8, 0, 0}, expectedScore: 8},
+		{name: "different tiles don't merge", line: [GridSize]int{2, 4, 2, 4}, expected: [GridSize]int{2, 4, 2, 4}},
+		{name: "two pairs", line: [GridSize]int{4, 4, 2, 2}, expected: [GridSize]int{8, 4, 0, 0}, expectedScore: 12},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, score := slideLine(tt.line)
+			if got != tt.expected {
+				t.Errorf("slideLine(%v) = %v, want %v", tt.line, got, tt.expected)
+			}
+			if score != tt.expectedScore {
+				t.Errorf("score = %d, want %d", score, tt.expectedScore)
+			}
+		})
+	}
+}
+
+func TestGameStateMove(t *testing.T) {
+	start := [GridSize][GridSize]int{
+		{2, 2, 2, 0},
+		{0, 0, 0, 0},
+		{2, 0, 0, 0},
+		{2, 0, 0, 4},
+	}
+
+	tests := []struct {
+		name     string
+		dir      Direction
+		expected [GridSize][GridSize]int
+		score    int
+	}{
+		{
+			name: "left",
+			dir:  Left,
+			expected: [GridSize][GridSize]int{
+				{4, 2, 0, 0},
+				{0, 0, 0, 0},
+				{2, 0, 0, 0},
+				{2, 4, 0, 0},
+			},
+			score: 4,
+		},
+		{
+			name: "right",
+			dir:  Right,
+			expected: [GridSize][GridSize]int{
+				{0, 0, 2, 4},
+				{0, 0, 0, 0},
+				{0, 0, 0, 2},
+				{0, 0, 2, 4},
+			},
+			score: 4,
+		},
+		{
+			name: "up",
+			dir:  Up,
+			expected: [GridSize][GridSize]int{
+				{4, 2, 2, 4},
+				{2, 0, 0, 0},
+				{0, 0, 0, 0},
+				{0, 0, 0, 0},
+			},
+			score: 4,
+		},
+		{
+			name: "down",
+			dir:  Down,
+			expected: [GridSize][GridSize]int{
+				{0, 0, 0, 0},
+				{0, 0, 0, 0},
+				{2, 0, 0, 0},
+				{4, 2, 2, 4},
+			},
+			score: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewGameState()
+			s.Grid = start
+
+			if !s.Move(tt.dir) {
+				t.Fatal("expected the move to change the grid")
+			}
+			if s.Grid != tt.expected {
+				t.Errorf("Grid = %v, want %v", s.Grid, tt.expected)
+			}
+			if s.Score != tt.score {
+				t.Errorf("Score = %d, want %d", s.Score, tt.score)
+			}
+		})
+	}
+}
+
+func TestGameStateMoveNoChange(t *testing.T) {
+	s := NewGameState()
+	s.Grid = [GridSize][GridSize]int{
+		{2, 4, 0, 0},
+		{8, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+	}
+
+	if s.Move(Left) {
+		t.Error("expected no change when tiles are already packed to the left")
+	}
+	if !s.Move(Right) {
+		t.Error("expected tiles to slide right")
+	}
+}
+
+func TestGameStateWon(t *testing.T) {
+	s := NewGameState()
+	s.Grid[0][0] = 1024
+	s.Grid[0][1] = 1024
+
+	s.Move(Left)
+
+	if !s.Won {
+		t.Error("expected Won after reaching 2048")
+	}
+	if s.Score != 2048 {
+		t.Errorf("Score = %d, want 2048", s.Score)
+	}
+}
+
+func TestGameStateSpawnTile(t *testing.T) {
+	s := NewGameState()
+	s.Grid[0][0] = 2
+	s.Grid[0][1] = 2
+
+	// First value picks the tile (1 of 10 chance of a 4), second the empty cell
+	rng := &mockRand{values: []int{0, 0}}
+	if !s.SpawnTile(rng) {
+		t.Fatal("expected a tile to spawn")
+	}
+	if s.Grid[0][2] != 4 {
+		t.Errorf("Grid[0][2] = %d, want 4 in the first empty cell", s.Grid[0][2])
+	}
+
+	rng = &mockRand{values: []int{5, 12}}
+	s.SpawnTile(rng)
+	if s.Grid[3][3] != 2 {
+		t.Errorf("Grid[3][3] = %d, want 2 in the last empty cell", s.Grid[3][3])
+	}
+
+	full := NewGameState()
+	for row := 0; row < GridSize; row++ {
+		for col := 0; col < GridSize; col++ {
+			full.Grid[row][col] = 2
+		}
+	}
+	if full.SpawnTile(&mockRand{}) {
+		t.Error("expected no spawn on a full grid")
+	}
+}
+
+func TestGameStateGameOver(t *testing.T) {
+	tests := []struct {
+		name     string
+		grid     [GridSize][GridSize]int
+		gameOver bool
+	}{
+		{
+			name: "empty cell left",
+			grid: [GridSize][GridSize]int{
+				{2, 4, 2, 4},
+				{4, 2, 4, 2},
+				{2, 4, 2, 4},
+				{4, 2, 4, 0},
+			},
+			gameOver: false,
+		},
+		{
+			name: "horizontal merge left",
+			grid: [GridSize][GridSize]int{
+				{2, 4, 2, 4},
+				{4, 2, 4, 2},
+				{2, 4, 2, 4},
+				{4, 2, 8, 8},
+			},
+			gameOver: false,
+		},
+		{
+			name: "vertical merge left",
+			grid: [GridSize][GridSize]int{
+				{2, 4, 2, 4},
+				{4, 2, 4, 2},
+				{2, 4, 2, 8},
+				{4, 2, 4, 8},
+			},
+			gameOver: false,
+		},
+		{
+			name: "no moves left",
+			grid: [GridSize][GridSize]int{
+				{2, 4, 2, 4},
+				{4, 2, 4, 2},
+				{2, 4, 2, 4},
+				{4, 2, 4, 2},
+			},
+			gameOver: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewGameState()
+			s.Grid = tt.grid
+
+			if got := s.CheckGameOver(); got != tt.gameOver {
+				t.Errorf("CheckGameOver() = %v, want %v", got, tt.gameOver)
+			}
+			if s.GameOver != tt.gameOver {
+				t.Errorf("GameOver = %v, want %v", s.GameOver, tt.gameOver)
+			}
+		})
+	}
+}
+
+func TestGameStateNoMovesAfterGameOver(t *testing.T) {
+	s := NewGameState()
+	s.Grid[0][3] = 2
+	s.GameOver = true
+
+	if s.Move(Left) {
+		t.Error("expected no moves after game over")
+	}
+}
+
+var errDisconnected = errors.New("disconnected")
+
+// failingDevice accepts failAfter packets, then fails every write as if the
+// BLE link dropped
+type failingDevice struct {
+	writes    int
+	failAfter int
+}
+
+func (d *failingDevice) WritePacket(packet []byte) error {
+	d.writes++
+	if d.writes > d.failAfter {
+		return errDisconnected
+	}
+	return nil
+}
+func (d *failingDevice) ReadResponse() ([]byte, error) { return nil, nil }
+func (d *failingDevice) PollResponse() ([]byte, bool)  { return nil, false }
+func (d *failingDevice) DrainResponses()               {}
+
+func TestGameStopsOnDeviceError(t *testing.T) {
+	newGame := func(device protocol.DeviceConnection) *Game {
+		g := NewGame(device)
+		g.SetUploadConfig(protocol.UploadConfig{})
+		g.randSource = &mockRand{}
+		g.reset()
+		return g
+	}
+
+	// Count the writes showing the background, then drop the link on the first render
+	counter := &failingDevice{failAfter: math.MaxInt}
+	if err := newGame(counter).showImage(GenerateGameBackground()); err != nil {
+		t.Fatal(err)
+	}
+	device := &failingDevice{failAfter: counter.writes}
+
+	// The render fails before the game waits for a key press
+	err := newGame(device).runGame()
+	if !errors.Is(err, errDisconnected) {
+		t.Fatalf("runGame() error = %v, want the device error", err)
+	}
+	if device.writes != device.failAfter+1 {
+		t.Errorf("%d writes after the failure, want the game to stop", device.writes-device.failAfter-1)
+	}
+}
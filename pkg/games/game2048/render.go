@@ -0,0 +1,48 @@
+package game2048
+
+import (
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/text"
+)
+
+// GenerateCoverImage creates the title screen with "2048" text and a few decorative tiles
+func GenerateCoverImage() []byte {
+	img := GenerateGameBackground()
+
+	// Draw "2048" title (4 chars * 6 pixels - 1 = 23 pixels wide, center at (64-23)/2 = 20)
+	// Draw shadow first
+	text.DrawText(img, "2048", 21, 9, graphic.DarkWhite)
+	// Draw main text in yellow
+	text.DrawText(img, "2048", 20, 8, tileColors[WinningTile])
+
+	// Draw a row of decorative tiles below the title
+	values := []int{2, 8, 64, 512}
+	r := NewRenderer(nil)
+	r.SetCurrBuffer(img)
+	for col, v := range values {
+		r.drawTile(2, col, v)
+	}
+	copy(img, r.Buffer())
+
+	return img
+}
+
+// GenerateGameOverImage creates the game over screen
+func GenerateGameOverImage() []byte {
+	// Dark red tinted background
+	img := graphic.NewBufferWithGradient(graphic.Color{10, 0, 0}, graphic.Color{17, 0, 0})
+
+	// Draw "GAME" and "OVER" text centered
+	// "GAME" is 4 chars * 6 = 24 pixels, center at (64-24)/2 = 20
+	text.DrawText(img, "GAME", 21, 25, graphic.DarkRed)
+	text.DrawText(img, "GAME", 20, 24, graphic.Red)
+	text.DrawText(img, "OVER", 21, 35, graphic.DarkRed)
+	text.DrawText(img, "OVER", 20, 34, graphic.Red)
+
+	return img
+}
+
+// GenerateGameBackground creates the background for gameplay
+func GenerateGameBackground() []byte {
+	return graphic.NewBufferWithColor(BackgroundColor)
+}
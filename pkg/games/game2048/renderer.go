@@ -0,0 +1,128 @@
+package game2048
+
+import (
+	"strconv"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/text"
+)
+
+// Rendering constants
+const (
+	TileSize   = 14 // Each tile = 14x14 display pixels
+	TileGap    = 2  // Gap between tiles
+	GridOffset = 1  // Offset to center grid: (64 - (4*14 + 3*2)) / 2
+)
+
+// Colors
+var (
+	BackgroundColor = graphic.Color{10, 10, 15}
+	EmptyTileColor  = graphic.Color{25, 25, 30}
+	LightTextColor  = graphic.White
+	DarkTextColor   = graphic.Color{20, 20, 20}
+)
+
+// tileColors maps tile values to their fill color
+var tileColors = map[int]graphic.Color{
+	2:    {90, 80, 70},
+	4:    {110, 90, 60},
+	8:    {200, 100, 30},
+	16:   {220, 80, 30},
+	32:   {220, 50, 40},
+	64:   {230, 20, 10},
+	128:  {200, 180, 40},
+	256:  {210, 190, 30},
+	512:  {220, 200, 20},
+	1024: {230, 210, 10},
+	2048: {240, 220, 0},
+}
+
+// superTileColor is used for tiles above 2048
+var superTileColor = graphic.Color{120, 0, 200}
+
+// TileColor returns the fill color of a tile value
+func TileColor(value int) graphic.Color {
+	if value == 0 {
+		return EmptyTileColor
+	}
+	if c, ok := tileColors[value]; ok {
+		return c
+	}
+	return superTileColor
+}
+
+// TileTextColor returns the label color of a tile value
+func TileTextColor(value int) graphic.Color {
+	if value >= 128 && value <= WinningTile {
+		return DarkTextColor // Bright yellow tiles need dark text
+	}
+	return LightTextColor
+}
+
+// TileLabel returns the text shown on a tile. Values of 1024 and above are
+// shortened to thousands (e.g. 2048 is "2K").
+func TileLabel(value int) string {
+	if value < 1000 {
+		return strconv.Itoa(value)
+	}
+	return strconv.Itoa(value/1024) + "K"
+}
+
+// TileOrigin returns the top-left display position of the tile at row, col
+func TileOrigin(row, col int) (int, int) {
+	return GridOffset + col*(TileSize+TileGap), GridOffset + row*(TileSize+TileGap)
+}
+
+// Renderer handles diff-based rendering to the device
+type Renderer struct {
+	*protocol.DiffRenderer
+}
+
+// NewRenderer creates a new renderer
+func NewRenderer(device protocol.DeviceConnection) *Renderer {
+	return &Renderer{DiffRenderer: protocol.NewDiffRenderer(device)}
+}
+
+// RenderState converts game state to the pixel buffer
+// This is a pure function that updates the buffer without I/O
+func (r *Renderer) RenderState(state *GameState, background []byte) {
+	// Start with background
+	copy(r.Buffer(), background)
+
+	for row := 0; row < GridSize; row++ {
+		for col := 0; col < GridSize; col++ {
+			r.drawTile(row, col, state.Grid[row][col])
+		}
+	}
+}
+
+// drawTile draws a single tile with its centered label
+func (r *Renderer) drawTile(row, col, value int) {
+	x0, y0 := TileOrigin(row, col)
+	color := TileColor(value)
+	for dy := 0; dy < TileSize; dy++ {
+		for dx := 0; dx < TileSize; dx++ {
+			graphic.SetPixel(r.Buffer(), x0+dx, y0+dy, color)
+		}
+	}
+
+	if value == 0 {
+		return
+	}
+
+	label := TileLabel(value)
+	textColor := TileTextColor(value)
+
+	// Use the 5x7 font when the label fits with a 1 pixel margin, otherwise the 3x5 one
+	if width := text.TextWidth(label); width <= TileSize-2 {
+		x := x0 + (TileSize-width+1)/2
+		y := y0 + (TileSize-text.FontHeight)/2
+		text.DrawText(r.Buffer(), label, x, y, textColor)
+		return
+	}
+
+	x := x0 + (TileSize-text.SmallTextWidth(label)+1)/2
+	y := y0 + (TileSize-text.SmallFontHeight+1)/2
+	text.DrawSmallText(r.Buffer(), label, x, y, textColor)
+}
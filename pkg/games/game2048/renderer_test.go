@@ -0,0 +1,111 @@
+package game2048
+
+import (
+	"testing"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+)
+
+func pixelAt(buf []byte, x, y int) graphic.Color {
+	offset := (y*graphic.DisplayWidth + x) * 3
+	return graphic.Color{buf[offset], buf[offset+1], buf[offset+2]}
+}
+
+func TestTileLabel(t *testing.T) {
+	tests := []struct {
+		value    int
+		expected string
+	}{
+		{2, "2"},
+		{64, "64"},
+		{512, "512"},
+		{1024, "1K"},
+		{2048, "2K"},
+		{16384, "16K"},
+	}
+
+	for _, tt := range tests {
+		if got := TileLabel(tt.value); got != tt.expected {
+			t.Errorf("TileLabel(%d) = %q, want %q", tt.value, got, tt.expected)
+		}
+	}
+}
+
+func TestTilesFitDisplay(t *testing.T) {
+	x, y := TileOrigin(GridSize-1, GridSize-1)
+	if x+TileSize > graphic.DisplayWidth || y+TileSize > graphic.DisplayHeight {
+		t.Errorf("last tile ends at %d,%d, outside the display", x+TileSize, y+TileSize)
+	}
+}
+
+func TestRendererRenderState(t *testing.T) {
+	s := NewGameState()
+	s.Grid[0][0] = 2
+	s.Grid[1][2] = 512
+
+	r := NewRenderer(nil)
+	r.RenderState(s, GenerateGameBackground())
+	buf := r.GetCurrBuffer()
+
+	// Tile corners are filled with the tile color
+	x, y := TileOrigin(0, 0)
+	if got := pixelAt(buf, x, y); got != TileColor(2) {
+		t.Errorf("tile 2 corner = %v, want %v", got, TileColor(2))
+	}
+	x, y = TileOrigin(3, 3)
+	if got := pixelAt(buf, x, y); got != EmptyTileColor {
+		t.Errorf("empty tile corner = %v, want %v", got, EmptyTileColor)
+	}
+
+	// Gaps show the background
+	if got := pixelAt(buf, GridOffset+TileSize, GridOffset); got != BackgroundColor {
+		t.Errorf("gap = %v, want %v", got, BackgroundColor)
+	}
+
+	// Labels are drawn inside their tile
+	countLabelPixels := func(row, col int, color graphic.Color) int {
+		x0, y0 := TileOrigin(row, col)
+		count := 0
+		for dy := 0; dy < TileSize; dy++ {
+			for dx := 0; dx < TileSize; dx++ {
+				if pixelAt(buf, x0+dx, y0+dy) == color {
+					count++
+				}
+			}
+		}
+		return count
+	}
+	if countLabelPixels(0, 0, TileTextColor(2)) == 0 {
+		t.Error("expected the 2 tile to have a label")
+	}
+	if countLabelPixels(1, 2, TileTextColor(512)) == 0 {
+		t.Error("expected the 512 tile to have a label")
+	}
+}
+
+func TestRendererComputeDiff(t *testing.T) {
+	s := NewGameState()
+	background := GenerateGameBackground()
+
+	r := NewRenderer(nil)
+	r.RenderState(s, background)
+	r.SetPrevBuffer(r.GetCurrBuffer())
+
+	// Filling a single tile only changes pixels inside that tile
+	s.Grid[2][1] = 8
+	r.RenderState(s, background)
+
+	x0, y0 := TileOrigin(2, 1)
+	total := 0
+	for _, points := range r.ComputeDiff() {
+		for _, p := range points {
+			if p.X < x0 || p.X >= x0+TileSize || p.Y < y0 || p.Y >= y0+TileSize {
+				t.Fatalf("pixel %v changed outside the tile", p)
+			}
+			total++
+		}
+	}
+	if total != TileSize*TileSize {
+		t.Errorf("changed pixels = %d, want %d", total, TileSize*TileSize)
+	}
+}
@@ -0,0 +1,49 @@
+package gametime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeSleep(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFake(start)
+
+	wallStart := time.Now()
+	clock.Sleep(time.Hour)
+	if got := clock.Now().Sub(start); got != time.Hour {
+		t.Errorf("clock advanced by %v, want 1h", got)
+	}
+	if time.Since(wallStart) > time.Second {
+		t.Error("Sleep waited on the wall clock")
+	}
+}
+
+func TestFakeAfter(t *testing.T) {
+	clock := NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	ch := clock.After(100 * time.Millisecond)
+
+	clock.Advance(50 * time.Millisecond)
+	select {
+	case <-ch:
+		t.Fatal("After fired before the clock reached it")
+	default:
+	}
+
+	clock.Advance(50 * time.Millisecond)
+	select {
+	case at := <-ch:
+		if at != clock.Now() {
+			t.Errorf("After fired with %v, want %v", at, clock.Now())
+		}
+	default:
+		t.Fatal("After didn't fire once the clock reached it")
+	}
+
+	// A non-positive duration fires right away
+	select {
+	case <-clock.After(0):
+	default:
+		t.Error("After(0) didn't fire right away")
+	}
+}
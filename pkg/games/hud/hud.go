@@ -0,0 +1,111 @@
+// Package hud draws a score/level strip on top of a game frame, e.g.
+// "SCORE 5" in a corner of the display. Labels too wide for the space left by
+// the playfield can wrap onto several lines.
+package hud
+
+import (
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/text"
+)
+
+// Corner is the display corner the strip is anchored to.
+type Corner int
+
+// Corners
+const (
+	TopLeft Corner = iota
+	TopRight
+	BottomLeft
+	BottomRight
+)
+
+// LineSpacing is the vertical distance between the lines of a wrapped label.
+const LineSpacing = text.FontHeight + 1
+
+// Options configures the strip.
+type Options struct {
+	Corner     Corner
+	OffsetX    int // Horizontal distance between the strip and the corner
+	OffsetY    int // Vertical distance between the strip and the corner
+	TextColor  graphic.Color
+	Background graphic.Color // Fill color of the strip, so old digits are cleared
+	Padding    int           // Pixels between the strip edge and the text
+	MaxChars   int           // Characters per line, wrapping longer labels (0 keeps one line)
+}
+
+// DefaultOptions returns a white on black strip in the top-left corner.
+func DefaultOptions() Options {
+	return Options{
+		Corner:     TopLeft,
+		TextColor:  graphic.White,
+		Background: graphic.Black,
+		Padding:    1,
+	}
+}
+
+// Region is the rectangle of the display covered by the strip.
+type Region struct {
+	X, Y          int // Top-left corner
+	Width, Height int
+}
+
+// Contains returns true if the pixel is inside the region.
+func (r Region) Contains(x, y int) bool {
+	return x >= r.X && x < r.X+r.Width && y >= r.Y && y < r.Y+r.Height
+}
+
+// Lines splits label into the lines drawn by the strip, maxChars characters
+// each. The label is kept on one line if maxChars isn't positive.
+func Lines(label string, maxChars int) []string {
+	chars := []rune(label)
+	if maxChars <= 0 || len(chars) <= maxChars {
+		return []string{label}
+	}
+
+	var lines []string
+	for i := 0; i < len(chars); i += maxChars {
+		lines = append(lines, string(chars[i:min(i+maxChars, len(chars))]))
+	}
+	return lines
+}
+
+// Layout returns the region the strip for label would cover, without drawing.
+func Layout(label string, opts Options) Region {
+	padding := max(opts.Padding, 0)
+	lines := Lines(label, opts.MaxChars)
+
+	width := 0
+	for _, line := range lines {
+		width = max(width, text.TextWidth(line))
+	}
+	region := Region{
+		X:      opts.OffsetX,
+		Y:      opts.OffsetY,
+		Width:  width + 2*padding,
+		Height: len(lines)*LineSpacing - 1 + 2*padding,
+	}
+	if opts.Corner == TopRight || opts.Corner == BottomRight {
+		region.X = graphic.DisplayWidth - region.Width - opts.OffsetX
+	}
+	if opts.Corner == BottomLeft || opts.Corner == BottomRight {
+		region.Y = graphic.DisplayHeight - region.Height - opts.OffsetY
+	}
+	return region
+}
+
+// Draw fills the strip with the background color and draws label on it.
+// Returns the region it covered, so renderers know which pixels changed.
+func Draw(buf []byte, label string, opts Options) Region {
+	region := Layout(label, opts)
+	for y := region.Y; y < region.Y+region.Height; y++ {
+		for x := region.X; x < region.X+region.Width; x++ {
+			graphic.SetPixel(buf, x, y, opts.Background)
+		}
+	}
+
+	padding := max(opts.Padding, 0)
+	for i, line := range Lines(label, opts.MaxChars) {
+		text.DrawText(buf, line, region.X+padding, region.Y+padding+i*LineSpacing, opts.TextColor)
+	}
+	return region
+}
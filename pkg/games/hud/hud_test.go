@@ -0,0 +1,153 @@
+package hud
+
+import (
+	"testing"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/text"
+)
+
+func pixelAt(buf []byte, x, y int) graphic.Color {
+	offset := (y*graphic.DisplayWidth + x) * 3
+	return graphic.Color{buf[offset], buf[offset+1], buf[offset+2]}
+}
+
+func TestDraw(t *testing.T) {
+	opts := DefaultOptions()
+	buf := graphic.NewBufferWithColor(graphic.Blue)
+	region := Draw(buf, "SCORE 5", opts)
+
+	// 7 characters of 6 pixels minus the trailing gap, plus 1 pixel of padding
+	expected := Region{X: 0, Y: 0, Width: 41 + 2, Height: text.FontHeight + 2}
+	if region != expected {
+		t.Fatalf("expected region %+v, got %+v", expected, region)
+	}
+
+	// Each glyph is drawn in its own 5 pixel column range, the space is blank
+	for i, char := range "SCORE 5" {
+		first := opts.Padding + i*text.FontSpacing
+		inked := 0
+		for x := first; x < first+text.FontWidth; x++ {
+			for y := 0; y < graphic.DisplayHeight; y++ {
+				if pixelAt(buf, x, y) == opts.TextColor {
+					inked++
+				}
+			}
+		}
+		if char == ' ' && inked != 0 {
+			t.Errorf("expected no pixels for the space at column %d, got %d", first, inked)
+		}
+		if char != ' ' && inked == 0 {
+			t.Errorf("expected pixels for %q at column %d", char, first)
+		}
+	}
+
+	// Glyphs stay within the strip, and pixels outside it are untouched
+	for y := 0; y < graphic.DisplayHeight; y++ {
+		for x := 0; x < graphic.DisplayWidth; x++ {
+			pixel := pixelAt(buf, x, y)
+			if region.Contains(x, y) {
+				if pixel != opts.TextColor && pixel != opts.Background {
+					t.Errorf("unexpected color %v at (%d, %d) inside the strip", pixel, x, y)
+				}
+				continue
+			}
+			if pixel != graphic.Blue {
+				t.Errorf("pixel (%d, %d) outside the strip changed to %v", x, y, pixel)
+			}
+		}
+	}
+}
+
+func TestLayoutCorners(t *testing.T) {
+	tests := []struct {
+		corner   Corner
+		expected Region
+	}{
+		{corner: TopLeft, expected: Region{X: 0, Y: 0, Width: 13, Height: 9}},
+		{corner: TopRight, expected: Region{X: 51, Y: 0, Width: 13, Height: 9}},
+		{corner: BottomLeft, expected: Region{X: 0, Y: 55, Width: 13, Height: 9}},
+		{corner: BottomRight, expected: Region{X: 51, Y: 55, Width: 13, Height: 9}},
+	}
+
+	for _, tt := range tests {
+		opts := DefaultOptions()
+		opts.Corner = tt.corner
+		if got := Layout("L2", opts); got != tt.expected {
+			t.Errorf("corner %d: expected %+v, got %+v", tt.corner, tt.expected, got)
+		}
+	}
+}
+
+func TestLayoutOffset(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Corner = BottomRight
+	opts.OffsetX = 2
+	opts.OffsetY = 3
+	expected := Region{X: 49, Y: 52, Width: 13, Height: 9}
+	if got := Layout("L2", opts); got != expected {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+}
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		label    string
+		maxChars int
+		expected []string
+	}{
+		{label: "12345", maxChars: 0, expected: []string{"12345"}},
+		{label: "12345", maxChars: 5, expected: []string{"12345"}},
+		{label: "12345", maxChars: 2, expected: []string{"12", "34", "5"}},
+		{label: "", maxChars: 2, expected: []string{""}},
+	}
+
+	for _, tt := range tests {
+		got := Lines(tt.label, tt.maxChars)
+		if len(got) != len(tt.expected) {
+			t.Errorf("Lines(%q, %d) = %q, expected %q", tt.label, tt.maxChars, got, tt.expected)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.expected[i] {
+				t.Errorf("Lines(%q, %d) = %q, expected %q", tt.label, tt.maxChars, got, tt.expected)
+				break
+			}
+		}
+	}
+}
+
+func TestDrawMultiLine(t *testing.T) {
+	opts := DefaultOptions()
+	opts.MaxChars = 2
+	buf := graphic.NewBufferWithColor(graphic.Blue)
+	region := Draw(buf, "12345", opts)
+
+	// 3 lines of 2 characters at most, plus 1 pixel of padding
+	expected := Region{X: 0, Y: 0, Width: 11 + 2, Height: 3*LineSpacing - 1 + 2}
+	if region != expected {
+		t.Fatalf("expected region %+v, got %+v", expected, region)
+	}
+
+	// Each line has pixels in its own row range, and the gap between lines is blank
+	for line := 0; line < 3; line++ {
+		top := opts.Padding + line*LineSpacing
+		inked := 0
+		for y := top; y < top+text.FontHeight; y++ {
+			for x := 0; x < region.Width; x++ {
+				if pixelAt(buf, x, y) == opts.TextColor {
+					inked++
+				}
+			}
+		}
+		if inked == 0 {
+			t.Errorf("expected pixels on line %d", line)
+		}
+		for x := 0; x < region.Width; x++ {
+			if pixelAt(buf, x, top+text.FontHeight) == opts.TextColor {
+				t.Errorf("expected a blank row below line %d", line)
+				break
+			}
+		}
+	}
+}
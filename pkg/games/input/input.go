@@ -0,0 +1,116 @@
+// Package input delivers the key presses controlling the games, read from the
+// terminal or sent by other code (e.g. tests or a remote controller).
+package input
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// bufferSize is how many key presses are queued before senders block
+const bufferSize = 10
+
+// Source delivers key presses to a game. Arrow keys are delivered as the
+// matching WASD key: w (up), a (left), s (down) and d (right).
+type Source interface {
+	// Keys returns the channel key presses are delivered on.
+	Keys() <-chan rune
+
+	// Start begins delivering key presses and returns a function to call
+	// when the game ends.
+	Start() (stop func())
+}
+
+// TerminalInput reads single key presses from stdin in raw mode.
+type TerminalInput struct {
+	keys chan rune
+}
+
+// NewTerminalInput creates a terminal input. The terminal is only switched to
+// raw mode on Start.
+func NewTerminalInput() *TerminalInput {
+	return &TerminalInput{keys: make(chan rune, bufferSize)}
+}
+
+// Keys returns the channel key presses are delivered on.
+func (t *TerminalInput) Keys() <-chan rune {
+	return t.keys
+}
+
+// Start switches the terminal to raw mode and starts reading key presses. The
+// returned function restores the terminal.
+func (t *TerminalInput) Start() func() {
+	oldState, err := term.MakeRaw(int(os.Stdin.Fd()))
+	if err != nil {
+		fmt.Printf("Warning: could not set raw mode: %v\n", err)
+		return func() {}
+	}
+
+	go func() {
+		buf := make([]byte, 3)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if err != nil {
+				return
+			}
+			if key, ok := TranslateKey(buf[:n]); ok {
+				t.keys <- key
+			}
+		}
+	}()
+
+	return func() {
+		term.Restore(int(os.Stdin.Fd()), oldState)
+	}
+}
+
+// TranslateKey converts a read from a raw mode terminal into a key press.
+// Arrow key escape sequences become the matching WASD key, other escape
+// sequences are ignored. Returns false if there is no key press.
+func TranslateKey(buf []byte) (rune, bool) {
+	if len(buf) == 0 {
+		return 0, false
+	}
+	if len(buf) == 3 && buf[0] == 27 && buf[1] == 91 {
+		switch buf[2] {
+		case 65: // up arrow
+			return 'w', true
+		case 66: // down arrow
+			return 's', true
+		case 67: // right arrow
+			return 'd', true
+		case 68: // left arrow
+			return 'a', true
+		}
+		return 0, false
+	}
+	return rune(buf[0]), true
+}
+
+// ChannelInput delivers the key presses sent to it, e.g. by tests or a remote
+// controller.
+type ChannelInput struct {
+	keys chan rune
+}
+
+// NewChannelInput creates a channel input.
+func NewChannelInput() *ChannelInput {
+	return &ChannelInput{keys: make(chan rune, bufferSize)}
+}
+
+// Send delivers a key press, blocking while bufferSize key presses are queued.
+func (c *ChannelInput) Send(key rune) {
+	c.keys <- key
+}
+
+// Keys returns the channel key presses are delivered on.
+func (c *ChannelInput) Keys() <-chan rune {
+	return c.keys
+}
+
+// Start does nothing: sent key presses are delivered right away.
+func (c *ChannelInput) Start() func() {
+	return func() {}
+}
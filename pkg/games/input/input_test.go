@@ -0,0 +1,50 @@
+package input
+
+import "testing"
+
+func TestTranslateKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		buf     []byte
+		wantKey rune
+		wantOK  bool
+	}{
+		{"letter", []byte{'p'}, 'p', true},
+		{"space", []byte{' '}, ' ', true},
+		{"up arrow", []byte{27, 91, 65}, 'w', true},
+		{"down arrow", []byte{27, 91, 66}, 's', true},
+		{"right arrow", []byte{27, 91, 67}, 'd', true},
+		{"left arrow", []byte{27, 91, 68}, 'a', true},
+		{"other escape sequence", []byte{27, 91, 70}, 0, false},
+		{"empty read", nil, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, ok := TranslateKey(tt.buf)
+			if key != tt.wantKey || ok != tt.wantOK {
+				t.Errorf("TranslateKey(%v) = %q, %v, want %q, %v", tt.buf, key, ok, tt.wantKey, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestChannelInput(t *testing.T) {
+	in := NewChannelInput()
+	stop := in.Start()
+	defer stop()
+
+	in.Send('a')
+	in.Send('d')
+
+	for _, want := range []rune{'a', 'd'} {
+		select {
+		case got := <-in.Keys():
+			if got != want {
+				t.Errorf("got key %q, want %q", got, want)
+			}
+		default:
+			t.Fatalf("no key delivered, want %q", want)
+		}
+	}
+}
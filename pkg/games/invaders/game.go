@@ -0,0 +1,500 @@
+package invaders
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/games/input"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
+)
+
+// Game timing constants
+const (
+	TickInterval      = 50 * time.Millisecond  // Game logic step
+	RenderInterval    = 100 * time.Millisecond // How often to render
+	AlienFireInterval = 700 * time.Millisecond // How often aliens try to shoot
+)
+
+// Playfield constants (in display pixels)
+const (
+	DisplaySize = graphic.DisplayWidth
+
+	PlayerWidth  = 5
+	PlayerHeight = 3
+	PlayerY      = 60 // Top row of the player cannon
+	PlayerStep   = 2  // Pixels moved per key press
+
+	AlienRows     = 4
+	AlienCols     = 7
+	AlienWidth    = 5
+	AlienHeight   = 4
+	AlienSpacingX = 8 // Horizontal distance between alien origins
+	AlienSpacingY = 6 // Vertical distance between alien origins
+	AlienStartX   = (DisplaySize - ((AlienCols-1)*AlienSpacingX + AlienWidth)) / 2
+	AlienStartY   = 4
+	AlienDropStep = 2 // Pixels the grid drops when it hits a wall
+	AlienPoints   = 10
+
+	BulletHeight       = 2
+	PlayerBulletSpeed  = 2 // Pixels per tick
+	MaxAlienBullets    = 3
+	BaseAlienStepTicks = 10 // Ticks between alien steps with a full grid
+	StartLives         = 3
+)
+
+// Bullet is a 1-pixel wide, BulletHeight tall projectile.
+// X, Y is the top pixel of the bullet.
+type Bullet struct {
+	X, Y int
+}
+
+// GameState contains all testable game state (no I/O dependencies)
+type GameState struct {
+	PlayerX      int // Left column of the player cannon
+	Aliens       [AlienRows][AlienCols]bool
+	AlienX       int // Grid origin (top-left of the first alien)
+	AlienY       int
+	AlienDir     int // Horizontal direction of the grid: 1 (right) or -1 (left)
+	PlayerBullet *Bullet
+	AlienBullets []Bullet
+	Score        int
+	Lives        int
+	Wave         int
+	GameOver     bool
+
+	alienTicks int // Ticks since the last alien step
+}
+
+// NewGameState creates a new game state with a full alien grid
+func NewGameState() *GameState {
+	s := &GameState{
+		PlayerX: (DisplaySize - PlayerWidth) / 2,
+		Lives:   StartLives,
+		Wave:    1,
+	}
+	s.resetAliens()
+	return s
+}
+
+// resetAliens fills the alien grid and moves it back to its start position
+func (s *GameState) resetAliens() {
+	for row := 0; row < AlienRows; row++ {
+		for col := 0; col < AlienCols; col++ {
+			s.Aliens[row][col] = true
+		}
+	}
+	s.AlienX = AlienStartX
+	s.AlienY = AlienStartY
+	s.AlienDir = 1
+	s.alienTicks = 0
+}
+
+// AlienPosition returns the top-left display position of the alien at row, col
+func (s *GameState) AlienPosition(row, col int) (int, int) {
+	return s.AlienX + col*AlienSpacingX, s.AlienY + row*AlienSpacingY
+}
+
+// AliveCount returns the number of aliens still alive
+func (s *GameState) AliveCount() int {
+	count := 0
+	for row := 0; row < AlienRows; row++ {
+		for col := 0; col < AlienCols; col++ {
+			if s.Aliens[row][col] {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// AlienStepInterval returns the number of ticks between alien steps.
+// The grid speeds up as aliens are destroyed, down to one step per tick
+// for the last alien.
+func (s *GameState) AlienStepInterval() int {
+	alive := s.AliveCount()
+	if alive <= 1 {
+		return 1
+	}
+	total := AlienRows * AlienCols
+	return 1 + (alive-1)*(BaseAlienStepTicks-1)/(total-1)
+}
+
+// TryMove moves the player cannon by dx pixels, clamped to the display.
+// Returns true if the cannon moved.
+func (s *GameState) TryMove(dx int) bool {
+	x := s.PlayerX + dx
+	if x < 0 {
+		x = 0
+	}
+	if x > DisplaySize-PlayerWidth {
+		x = DisplaySize - PlayerWidth
+	}
+	if x == s.PlayerX {
+		return false
+	}
+	s.PlayerX = x
+	return true
+}
+
+// Fire shoots a bullet from the player cannon.
+// Returns false if a player bullet is already in flight.
+func (s *GameState) Fire() bool {
+	if s.PlayerBullet != nil || s.GameOver {
+		return false
+	}
+	s.PlayerBullet = &Bullet{
+		X: s.PlayerX + PlayerWidth/2,
+		Y: PlayerY - BulletHeight,
+	}
+	return true
+}
+
+// AlienFire shoots a bullet from the bottom-most alien of the given column.
+// Returns false if the column is empty or too many alien bullets are in flight.
+func (s *GameState) AlienFire(col int) bool {
+	if col < 0 || col >= AlienCols || len(s.AlienBullets) >= MaxAlienBullets || s.GameOver {
+		return false
+	}
+	for row := AlienRows - 1; row >= 0; row-- {
+		if !s.Aliens[row][col] {
+			continue
+		}
+		x, y := s.AlienPosition(row, col)
+		s.AlienBullets = append(s.AlienBullets, Bullet{
+			X: x + AlienWidth/2,
+			Y: y + AlienHeight,
+		})
+		return true
+	}
+	return false
+}
+
+// Tick advances the game by one step: moves bullets, resolves collisions,
+// moves the alien grid and starts a new wave once all aliens are destroyed.
+func (s *GameState) Tick() {
+	if s.GameOver {
+		return
+	}
+
+	s.movePlayerBullet()
+	s.moveAlienBullets()
+	if s.GameOver {
+		return
+	}
+
+	s.alienTicks++
+	if s.alienTicks >= s.AlienStepInterval() {
+		s.alienTicks = 0
+		s.stepAliens()
+	}
+
+	if s.AliveCount() == 0 {
+		s.Wave++
+		s.PlayerBullet = nil
+		s.AlienBullets = nil
+		s.resetAliens()
+		return
+	}
+
+	if s.aliensBottom() >= PlayerY {
+		s.GameOver = true
+	}
+}
+
+// movePlayerBullet moves the player bullet up one pixel at a time, so it
+// can't skip over an alien, and destroys the first alien it hits.
+func (s *GameState) movePlayerBullet() {
+	if s.PlayerBullet == nil {
+		return
+	}
+	for i := 0; i < PlayerBulletSpeed; i++ {
+		s.PlayerBullet.Y--
+		if s.PlayerBullet.Y < 0 {
+			s.PlayerBullet = nil
+			return
+		}
+		if row, col, ok := s.alienAt(s.PlayerBullet.X, s.PlayerBullet.Y); ok {
+			s.Aliens[row][col] = false
+			s.Score += AlienPoints
+			s.PlayerBullet = nil
+			return
+		}
+	}
+}
+
+// moveAlienBullets moves alien bullets down one pixel and checks whether they
+// hit the player. A hit costs a life and clears all alien bullets.
+func (s *GameState) moveAlienBullets() {
+	remaining := s.AlienBullets[:0]
+	for _, b := range s.AlienBullets {
+		b.Y++
+		if b.Y >= DisplaySize {
+			continue
+		}
+		if s.hitsPlayer(b) {
+			s.Lives--
+			s.AlienBullets = nil
+			if s.Lives <= 0 {
+				s.GameOver = true
+			}
+			return
+		}
+		remaining = append(remaining, b)
+	}
+	s.AlienBullets = remaining
+}
+
+// hitsPlayer returns true if any pixel of the bullet overlaps the player cannon
+func (s *GameState) hitsPlayer(b Bullet) bool {
+	if b.X < s.PlayerX || b.X >= s.PlayerX+PlayerWidth {
+		return false
+	}
+	bottom := b.Y + BulletHeight - 1
+	return bottom >= PlayerY && b.Y < PlayerY+PlayerHeight
+}
+
+// alienAt returns the alive alien covering the given display pixel
+func (s *GameState) alienAt(x, y int) (int, int, bool) {
+	for row := 0; row < AlienRows; row++ {
+		for col := 0; col < AlienCols; col++ {
+			if !s.Aliens[row][col] {
+				continue
+			}
+			ax, ay := s.AlienPosition(row, col)
+			if x >= ax && x < ax+AlienWidth && y >= ay && y < ay+AlienHeight {
+				return row, col, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// stepAliens moves the grid one pixel sideways, or drops it and reverses
+// direction when the outermost alive alien would leave the display.
+func (s *GameState) stepAliens() {
+	minCol, maxCol := AlienCols, -1
+	for row := 0; row < AlienRows; row++ {
+		for col := 0; col < AlienCols; col++ {
+			if s.Aliens[row][col] {
+				if col < minCol {
+					minCol = col
+				}
+				if col > maxCol {
+					maxCol = col
+				}
+			}
+		}
+	}
+	if maxCol < 0 {
+		return
+	}
+
+	left := s.AlienX + minCol*AlienSpacingX + s.AlienDir
+	right := s.AlienX + maxCol*AlienSpacingX + AlienWidth - 1 + s.AlienDir
+	if left < 0 || right >= DisplaySize {
+		s.AlienY += AlienDropStep
+		s.AlienDir = -s.AlienDir
+		return
+	}
+	s.AlienX += s.AlienDir
+}
+
+// aliensBottom returns the display row just below the lowest alive alien
+func (s *GameState) aliensBottom() int {
+	for row := AlienRows - 1; row >= 0; row-- {
+		for col := 0; col < AlienCols; col++ {
+			if s.Aliens[row][col] {
+				_, y := s.AlienPosition(row, col)
+				return y + AlienHeight
+			}
+		}
+	}
+	return 0
+}
+
+// RandSource is an interface for random number generation (for testing)
+type RandSource interface {
+	Intn(n int) int
+}
+
+// defaultRand wraps math/rand for production use
+type defaultRand struct{}
+
+func (defaultRand) Intn(n int) int { return rand.Intn(n) }
+
+// Game orchestrates gameplay with I/O dependencies
+type Game struct {
+	state      *GameState
+	device     protocol.DeviceConnection
+	renderer   *Renderer
+	background []byte
+	input      input.Source
+	running    bool
+	randSource RandSource
+}
+
+// NewGame creates a new Space Invaders game
+func NewGame(device protocol.DeviceConnection) *Game {
+	return &Game{
+		device:     device,
+		renderer:   NewRenderer(device),
+		input:      input.NewTerminalInput(),
+		running:    true,
+		randSource: defaultRand{},
+	}
+}
+
+// SetUploadConfig sets the delays used when sending pixels to the device
+func (g *Game) SetUploadConfig(cfg protocol.UploadConfig) {
+	g.renderer.Upload = cfg
+}
+
+// SetInput replaces the terminal as the source of key presses, e.g. with an
+// input.ChannelInput in tests or for a remote controller
+func (g *Game) SetInput(src input.Source) {
+	g.input = src
+}
+
+// reset initializes the game state for a new game
+func (g *Game) reset() {
+	g.state = NewGameState()
+	g.background = GenerateGameBackground()
+}
+
+// handleInput processes keyboard input
+func (g *Game) handleInput() {
+	select {
+	case key := <-g.input.Keys():
+		switch key {
+		case 'a', 'A':
+			g.state.TryMove(-PlayerStep)
+		case 'd', 'D':
+			g.state.TryMove(PlayerStep)
+		case 'w', 'W', ' ':
+			g.state.Fire()
+		case 'q', 'Q':
+			g.running = false
+		}
+	default:
+	}
+}
+
+// render draws the current game state to the display
+func (g *Game) render() error {
+	g.renderer.RenderState(g.state, g.background)
+	return g.renderer.Flush()
+}
+
+// showImage displays a static image on the device
+func (g *Game) showImage(rgbData []byte) error {
+	if err := protocol.SetDrawMode(g.device, 1); err != nil {
+		return err
+	}
+	if err := protocol.SendImage(g.device, rgbData); err != nil {
+		return err
+	}
+	time.Sleep(500 * time.Millisecond)
+	return nil
+}
+
+// waitForKey blocks until a key is pressed
+func (g *Game) waitForKey() rune {
+	return <-g.input.Keys()
+}
+
+// runGame runs the main game loop until the game is over or the player quits.
+// Returns an error if the display can't be updated anymore (e.g. the BLE link
+// dropped)
+func (g *Game) runGame() error {
+	// Initialize renderer with background
+	g.renderer.SetPrevBuffer(g.background)
+	g.renderer.SetCurrBuffer(g.background)
+
+	// Display initial background
+	if err := g.showImage(g.background); err != nil {
+		return err
+	}
+
+	lastTick := time.Now()
+	lastAlienFire := time.Now()
+	lastRender := time.Now()
+
+	for g.running && !g.state.GameOver {
+		now := time.Now()
+
+		// Handle input
+		g.handleInput()
+
+		// Game logic
+		if now.Sub(lastTick) >= TickInterval {
+			g.state.Tick()
+			lastTick = now
+		}
+
+		// Aliens shoot from a random column
+		if now.Sub(lastAlienFire) >= AlienFireInterval {
+			g.state.AlienFire(g.randSource.Intn(AlienCols))
+			lastAlienFire = now
+		}
+
+		// Render
+		if now.Sub(lastRender) >= RenderInterval {
+			if err := g.render(); err != nil {
+				return fmt.Errorf("failed to draw on the display: %w", err)
+			}
+			lastRender = now
+		}
+
+		// Small sleep to avoid busy loop
+		time.Sleep(10 * time.Millisecond)
+	}
+	return nil
+}
+
+// Run starts the main game loop
+func (g *Game) Run() error {
+	fmt.Println("Starting Space Invaders!")
+	fmt.Println("Controls: A/Left=Left, D/Right=Right, W/Up/Space=Fire, Q=Quit")
+
+	stop := g.input.Start()
+	defer stop()
+
+	for g.running {
+		// Show cover image and wait for key to start
+		if err := g.showImage(GenerateCoverImage()); err != nil {
+			return err
+		}
+		fmt.Print("Press any key to start...")
+		key := g.waitForKey()
+		fmt.Println()
+		if key == 'q' || key == 'Q' {
+			break
+		}
+
+		// Reset and start game
+		g.reset()
+		if err := g.runGame(); err != nil {
+			return err
+		}
+
+		if !g.running {
+			break
+		}
+
+		// Show game over screen
+		if err := g.showImage(GenerateGameOverImage()); err != nil {
+			return err
+		}
+		fmt.Printf("Game Over! Score: %d, Wave: %d\n", g.state.Score, g.state.Wave)
+		fmt.Print("Press any key to restart (Q to quit)...")
+		key = g.waitForKey()
+		fmt.Println()
+		if key == 'q' || key == 'Q' {
+			break
+		}
+	}
+
+	return nil
+}
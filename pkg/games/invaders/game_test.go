@@ -0,0 +1,314 @@
+package invaders
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
+)
+
+func TestGameStateTryMove(t *testing.T) {
+	tests := []struct {
+		name      string
+		startX    int
+		dx        int
+		expectedX int
+		moved     bool
+	}{
+		{name: "move left", startX: 10, dx: -PlayerStep, expectedX: 10 - PlayerStep, moved: true},
+		{name: "move right", startX: 10, dx: PlayerStep, expectedX: 10 + PlayerStep, moved: true},
+		{name: "clamped at left edge", startX: 1, dx: -PlayerStep, expectedX: 0, moved: true},
+		{name: "blocked at left edge", startX: 0, dx: -PlayerStep, expectedX: 0, moved: false},
+		{name: "blocked at right edge", startX: DisplaySize - PlayerWidth, dx: PlayerStep, expectedX: DisplaySize - PlayerWidth, moved: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewGameState()
+			s.PlayerX = tt.startX
+
+			if moved := s.TryMove(tt.dx); moved != tt.moved {
+				t.Errorf("TryMove() = %v, want %v", moved, tt.moved)
+			}
+			if s.PlayerX != tt.expectedX {
+				t.Errorf("PlayerX = %d, want %d", s.PlayerX, tt.expectedX)
+			}
+		})
+	}
+}
+
+func TestGameStateFire(t *testing.T) {
+	s := NewGameState()
+
+	if !s.Fire() {
+		t.Fatal("expected first shot to fire")
+	}
+	if s.PlayerBullet == nil {
+		t.Fatal("expected a player bullet")
+	}
+	if s.PlayerBullet.X != s.PlayerX+PlayerWidth/2 {
+		t.Errorf("bullet X = %d, want %d", s.PlayerBullet.X, s.PlayerX+PlayerWidth/2)
+	}
+	if s.Fire() {
+		t.Error("expected second shot to be rejected while a bullet is in flight")
+	}
+}
+
+func TestBulletAlienCollision(t *testing.T) {
+	s := NewGameState()
+
+	// Place a bullet just below the bottom-left alien
+	x, y := s.AlienPosition(AlienRows-1, 0)
+	s.PlayerBullet = &Bullet{X: x + AlienWidth/2, Y: y + AlienHeight}
+
+	s.Tick()
+
+	if s.Aliens[AlienRows-1][0] {
+		t.Error("expected alien to be destroyed")
+	}
+	if s.PlayerBullet != nil {
+		t.Error("expected bullet to be removed after the hit")
+	}
+	if s.Score != AlienPoints {
+		t.Errorf("Score = %d, want %d", s.Score, AlienPoints)
+	}
+	if s.AliveCount() != AlienRows*AlienCols-1 {
+		t.Errorf("AliveCount() = %d, want %d", s.AliveCount(), AlienRows*AlienCols-1)
+	}
+}
+
+func TestBulletOnlyHitsFirstAlien(t *testing.T) {
+	s := NewGameState()
+
+	// A bullet between two rows of aliens only destroys the one above it
+	x, y := s.AlienPosition(1, 3)
+	s.PlayerBullet = &Bullet{X: x + AlienWidth/2, Y: y + AlienHeight}
+
+	s.Tick()
+
+	if s.Aliens[1][3] {
+		t.Error("expected alien above the bullet to be destroyed")
+	}
+	if !s.Aliens[0][3] {
+		t.Error("expected alien further up to survive")
+	}
+}
+
+func TestBulletMissesGap(t *testing.T) {
+	s := NewGameState()
+
+	// A bullet in the gap between two columns flies past the aliens
+	x, y := s.AlienPosition(AlienRows-1, 0)
+	s.PlayerBullet = &Bullet{X: x + AlienWidth, Y: y + AlienHeight}
+
+	s.Tick()
+
+	if s.AliveCount() != AlienRows*AlienCols {
+		t.Errorf("AliveCount() = %d, want %d", s.AliveCount(), AlienRows*AlienCols)
+	}
+	if s.PlayerBullet == nil {
+		t.Error("expected bullet to keep flying")
+	}
+}
+
+func TestAliensSpeedUpAsCountDrops(t *testing.T) {
+	s := NewGameState()
+
+	prev := s.AlienStepInterval()
+	if prev != BaseAlienStepTicks {
+		t.Errorf("full grid interval = %d, want %d", prev, BaseAlienStepTicks)
+	}
+
+	// Destroy aliens one by one: the step interval must never increase
+	// and must end at one step per tick
+	for row := 0; row < AlienRows; row++ {
+		for col := 0; col < AlienCols; col++ {
+			if s.AliveCount() == 1 {
+				break
+			}
+			s.Aliens[row][col] = false
+			interval := s.AlienStepInterval()
+			if interval > prev {
+				t.Fatalf("interval increased from %d to %d with %d aliens", prev, interval, s.AliveCount())
+			}
+			prev = interval
+		}
+	}
+
+	if s.AlienStepInterval() != 1 {
+		t.Errorf("last alien interval = %d, want 1", s.AlienStepInterval())
+	}
+}
+
+func TestAliensMoveFasterWithFewerAliens(t *testing.T) {
+	countSteps := func(s *GameState, ticks int) int {
+		steps := 0
+		for i := 0; i < ticks; i++ {
+			x, y := s.AlienX, s.AlienY
+			s.Tick()
+			if s.AlienX != x || s.AlienY != y {
+				steps++
+			}
+		}
+		return steps
+	}
+
+	full := NewGameState()
+	fullSteps := countSteps(full, 20)
+
+	few := NewGameState()
+	for row := 0; row < AlienRows; row++ {
+		for col := 0; col < AlienCols; col++ {
+			few.Aliens[row][col] = row == 0 && col < 2
+		}
+	}
+	fewSteps := countSteps(few, 20)
+
+	if fewSteps <= fullSteps {
+		t.Errorf("expected fewer aliens to move more often: %d steps vs %d with full grid", fewSteps, fullSteps)
+	}
+}
+
+func TestAliensReverseAtEdge(t *testing.T) {
+	s := NewGameState()
+
+	// Move the grid to the right edge
+	s.AlienX = DisplaySize - ((AlienCols-1)*AlienSpacingX + AlienWidth)
+	s.AlienDir = 1
+	startY := s.AlienY
+
+	s.stepAliens()
+
+	if s.AlienDir != -1 {
+		t.Errorf("AlienDir = %d, want -1", s.AlienDir)
+	}
+	if s.AlienY != startY+AlienDropStep {
+		t.Errorf("AlienY = %d, want %d", s.AlienY, startY+AlienDropStep)
+	}
+}
+
+func TestAlienFireHitsPlayer(t *testing.T) {
+	s := NewGameState()
+
+	s.AlienBullets = []Bullet{{X: s.PlayerX + 1, Y: PlayerY - BulletHeight}}
+	s.Tick()
+
+	if s.Lives != StartLives-1 {
+		t.Errorf("Lives = %d, want %d", s.Lives, StartLives-1)
+	}
+	if len(s.AlienBullets) != 0 {
+		t.Error("expected alien bullets to be cleared after a hit")
+	}
+
+	s.Lives = 1
+	s.AlienBullets = []Bullet{{X: s.PlayerX + 1, Y: PlayerY - BulletHeight}}
+	s.Tick()
+
+	if !s.GameOver {
+		t.Error("expected game over after losing the last life")
+	}
+}
+
+func TestAlienFire(t *testing.T) {
+	s := NewGameState()
+
+	if !s.AlienFire(2) {
+		t.Fatal("expected alien to fire")
+	}
+	x, y := s.AlienPosition(AlienRows-1, 2)
+	if s.AlienBullets[0].X != x+AlienWidth/2 || s.AlienBullets[0].Y != y+AlienHeight {
+		t.Errorf("bullet = %+v, want below bottom alien at %d,%d", s.AlienBullets[0], x, y)
+	}
+
+	// Empty column can't fire
+	for row := 0; row < AlienRows; row++ {
+		s.Aliens[row][4] = false
+	}
+	if s.AlienFire(4) {
+		t.Error("expected empty column not to fire")
+	}
+
+	// Limit on bullets in flight
+	s.AlienFire(0)
+	s.AlienFire(1)
+	if s.AlienFire(3) {
+		t.Errorf("expected at most %d alien bullets", MaxAlienBullets)
+	}
+}
+
+func TestNextWave(t *testing.T) {
+	s := NewGameState()
+	for row := 0; row < AlienRows; row++ {
+		for col := 0; col < AlienCols; col++ {
+			s.Aliens[row][col] = false
+		}
+	}
+	s.Aliens[0][0] = true
+
+	x, y := s.AlienPosition(0, 0)
+	s.PlayerBullet = &Bullet{X: x + AlienWidth/2, Y: y + AlienHeight}
+	s.Tick()
+
+	if s.Wave != 2 {
+		t.Errorf("Wave = %d, want 2", s.Wave)
+	}
+	if s.AliveCount() != AlienRows*AlienCols {
+		t.Errorf("AliveCount() = %d, want a full grid", s.AliveCount())
+	}
+}
+
+func TestAliensReachingPlayerEndGame(t *testing.T) {
+	s := NewGameState()
+	s.AlienY = PlayerY - (AlienRows-1)*AlienSpacingY - AlienHeight
+
+	s.Tick()
+
+	if !s.GameOver {
+		t.Error("expected game over when aliens reach the player")
+	}
+}
+
+var errDisconnected = errors.New("disconnected")
+
+// failingDevice accepts failAfter packets, then fails every write as if the
+// BLE link dropped
+type failingDevice struct {
+	writes    int
+	failAfter int
+}
+
+func (d *failingDevice) WritePacket(packet []byte) error {
+	d.writes++
+	if d.writes > d.failAfter {
+		return errDisconnected
+	}
+	return nil
+}
+func (d *failingDevice) ReadResponse() ([]byte, error) { return nil, nil }
+func (d *failingDevice) PollResponse() ([]byte, bool)  { return nil, false }
+func (d *failingDevice) DrainResponses()               {}
+
+func TestGameStopsOnDeviceError(t *testing.T) {
+	newGame := func(device protocol.DeviceConnection) *Game {
+		g := NewGame(device)
+		g.SetUploadConfig(protocol.UploadConfig{})
+		g.reset()
+		return g
+	}
+
+	// Count the writes showing the background, then drop the link on the first render
+	counter := &failingDevice{failAfter: math.MaxInt}
+	if err := newGame(counter).showImage(GenerateGameBackground()); err != nil {
+		t.Fatal(err)
+	}
+	device := &failingDevice{failAfter: counter.writes}
+
+	err := newGame(device).runGame()
+	if !errors.Is(err, errDisconnected) {
+		t.Fatalf("runGame() error = %v, want the device error", err)
+	}
+	if device.writes != device.failAfter+1 {
+		t.Errorf("%d writes after the failure, want the game to stop", device.writes-device.failAfter-1)
+	}
+}
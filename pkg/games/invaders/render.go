@@ -0,0 +1,80 @@
+package invaders
+
+import (
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/text"
+)
+
+// drawBigSprite draws a sprite scaled up for decoration
+func drawBigSprite(img []byte, sprite []string, x, y int, scale int, color graphic.Color) {
+	for sy, line := range sprite {
+		for sx, c := range line {
+			if c != '#' {
+				continue
+			}
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					graphic.SetPixel(img, x+sx*scale+dx, y+sy*scale+dy, color)
+				}
+			}
+		}
+	}
+}
+
+// GenerateCoverImage creates the title screen with "INVADERS" text and decorative aliens
+func GenerateCoverImage() []byte {
+	// Dark blue gradient background
+	img := graphic.NewBufferWithGradient(graphic.Color{0, 0, 5}, graphic.Color{0, 0, 8})
+
+	// Draw "INVADERS" title (8 chars * 6 pixels - 1 = 47 pixels wide, center at (64-47)/2 = 8)
+	// Draw shadow first
+	text.DrawText(img, "INVADERS", 9, 9, graphic.DarkWhite)
+	// Draw main text in green
+	text.DrawText(img, "INVADERS", 8, 8, graphic.Green)
+
+	// Draw a row of big aliens (3 aliens * 10 pixels, 8 pixels apart)
+	for i := 0; i < 3; i++ {
+		drawBigSprite(img, alienSprite[:], 8+i*18, 26, 2, alienColors[i])
+	}
+
+	// Draw the player cannon
+	drawBigSprite(img, playerSprite[:], 27, 50, 2, PlayerColor)
+
+	return img
+}
+
+// GenerateGameOverImage creates the game over screen
+func GenerateGameOverImage() []byte {
+	// Dark red tinted background
+	img := graphic.NewBufferWithGradient(graphic.Color{10, 0, 0}, graphic.Color{17, 0, 0})
+
+	// Draw "GAME" and "OVER" text centered
+	// "GAME" is 4 chars * 6 = 24 pixels, center at (64-24)/2 = 20
+	text.DrawText(img, "GAME", 21, 25, graphic.DarkRed)
+	text.DrawText(img, "GAME", 20, 24, graphic.Red)
+	text.DrawText(img, "OVER", 21, 35, graphic.DarkRed)
+	text.DrawText(img, "OVER", 20, 34, graphic.Red)
+
+	return img
+}
+
+// GenerateGameBackground creates the background for gameplay
+func GenerateGameBackground() []byte {
+	img := make([]byte, graphic.DisplayWidth*graphic.DisplayWidth*3)
+
+	// Sparse starfield on a black background
+	for y := 0; y < PlayerY-2; y++ {
+		for x := 0; x < graphic.DisplayWidth; x++ {
+			if (x*7+y*13)%97 == 0 {
+				graphic.SetPixel(img, x, y, graphic.Color{25, 25, 35})
+			}
+		}
+	}
+
+	// Ground line below the player cannon
+	for x := 0; x < graphic.DisplayWidth; x++ {
+		graphic.SetPixel(img, x, graphic.DisplayWidth-1, graphic.DimGray)
+	}
+
+	return img
+}
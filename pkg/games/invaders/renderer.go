@@ -0,0 +1,105 @@
+package invaders
+
+import (
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
+)
+
+// Sprite colors
+var (
+	PlayerColor       = graphic.Green
+	PlayerBulletColor = graphic.White
+	AlienBulletColor  = graphic.Orange
+	LifeColor         = graphic.Green
+)
+
+// Alien colors per row (top to bottom)
+var alienColors = [AlienRows]graphic.Color{graphic.Magenta, graphic.Cyan, graphic.Cyan, graphic.Yellow}
+
+// Alien sprite (AlienWidth x AlienHeight)
+var alienSprite = [AlienHeight]string{
+	".###.",
+	"#.#.#",
+	"#####",
+	"#...#",
+}
+
+// Player cannon sprite (PlayerWidth x PlayerHeight)
+var playerSprite = [PlayerHeight]string{
+	"..#..",
+	"#####",
+	"#####",
+}
+
+// Renderer handles diff-based rendering to the device
+type Renderer struct {
+	*protocol.DiffRenderer
+}
+
+// NewRenderer creates a new renderer
+func NewRenderer(device protocol.DeviceConnection) *Renderer {
+	return &Renderer{DiffRenderer: protocol.NewDiffRenderer(device)}
+}
+
+// RenderState converts game state to the pixel buffer
+// This is a pure function that updates the buffer without I/O
+func (r *Renderer) RenderState(state *GameState, background []byte) {
+	// Start with background
+	copy(r.Buffer(), background)
+
+	// Draw remaining lives as dots in the top-right corner
+	for i := 0; i < state.Lives; i++ {
+		r.setPixel(DisplaySize-2-i*3, 1, LifeColor)
+	}
+
+	// Draw aliens
+	for row := 0; row < AlienRows; row++ {
+		for col := 0; col < AlienCols; col++ {
+			if state.Aliens[row][col] {
+				x, y := state.AlienPosition(row, col)
+				r.drawSprite(alienSprite[:], x, y, alienColors[row])
+			}
+		}
+	}
+
+	// Draw player cannon
+	r.drawSprite(playerSprite[:], state.PlayerX, PlayerY, PlayerColor)
+
+	// Draw bullets
+	if state.PlayerBullet != nil {
+		r.drawBullet(*state.PlayerBullet, PlayerBulletColor)
+	}
+	for _, b := range state.AlienBullets {
+		r.drawBullet(b, AlienBulletColor)
+	}
+}
+
+// drawSprite draws a sprite with its top-left corner at x, y
+func (r *Renderer) drawSprite(sprite []string, x, y int, color graphic.Color) {
+	for dy, line := range sprite {
+		for dx, c := range line {
+			if c == '#' {
+				r.setPixel(x+dx, y+dy, color)
+			}
+		}
+	}
+}
+
+// drawBullet draws a bullet on the buffer
+func (r *Renderer) drawBullet(b Bullet, color graphic.Color) {
+	for dy := 0; dy < BulletHeight; dy++ {
+		r.setPixel(b.X, b.Y+dy, color)
+	}
+}
+
+// setPixel sets a single pixel on the buffer, ignoring out of bounds coordinates
+func (r *Renderer) setPixel(x, y int, color graphic.Color) {
+	if x < 0 || x >= graphic.DisplayWidth || y < 0 || y >= graphic.DisplayWidth {
+		return
+	}
+	buf := r.Buffer()
+	offset := (y*graphic.DisplayWidth + x) * 3
+	buf[offset] = color[0]
+	buf[offset+1] = color[1]
+	buf[offset+2] = color[2]
+}
@@ -0,0 +1,80 @@
+package invaders
+
+import (
+	"testing"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+)
+
+func pixelAt(buf []byte, x, y int) graphic.Color {
+	offset := (y*graphic.DisplayWidth + x) * 3
+	return graphic.Color{buf[offset], buf[offset+1], buf[offset+2]}
+}
+
+func TestRendererRenderState(t *testing.T) {
+	s := NewGameState()
+	s.PlayerBullet = &Bullet{X: 20, Y: 40}
+	s.AlienBullets = []Bullet{{X: 30, Y: 45}}
+
+	r := NewRenderer(nil)
+	r.RenderState(s, graphic.NewBuffer())
+	buf := r.GetCurrBuffer()
+
+	// Player cannon tip
+	if got := pixelAt(buf, s.PlayerX+2, PlayerY); got != PlayerColor {
+		t.Errorf("player tip = %v, want %v", got, PlayerColor)
+	}
+
+	// First alien's top row starts one pixel in
+	x, y := s.AlienPosition(0, 0)
+	if got := pixelAt(buf, x+1, y); got != alienColors[0] {
+		t.Errorf("alien pixel = %v, want %v", got, alienColors[0])
+	}
+	if got := pixelAt(buf, x, y); got != graphic.Black {
+		t.Errorf("alien corner = %v, want black", got)
+	}
+
+	// Bullets
+	for dy := 0; dy < BulletHeight; dy++ {
+		if got := pixelAt(buf, 20, 40+dy); got != PlayerBulletColor {
+			t.Errorf("player bullet pixel = %v, want %v", got, PlayerBulletColor)
+		}
+		if got := pixelAt(buf, 30, 45+dy); got != AlienBulletColor {
+			t.Errorf("alien bullet pixel = %v, want %v", got, AlienBulletColor)
+		}
+	}
+}
+
+func TestRendererDestroyedAlienDiff(t *testing.T) {
+	s := NewGameState()
+	background := graphic.NewBuffer()
+
+	r := NewRenderer(nil)
+	r.RenderState(s, background)
+	r.SetPrevBuffer(r.GetCurrBuffer())
+
+	// Destroying an alien only changes its own pixels, back to black
+	s.Aliens[0][0] = false
+	r.RenderState(s, background)
+
+	diff := r.ComputeDiff()
+	if len(diff) != 1 {
+		t.Fatalf("expected changes in a single color, got %d", len(diff))
+	}
+	points, ok := diff[graphic.Black]
+	if !ok {
+		t.Fatal("expected the alien pixels to be cleared to black")
+	}
+
+	lit := 0
+	for _, line := range alienSprite {
+		for _, c := range line {
+			if c == '#' {
+				lit++
+			}
+		}
+	}
+	if len(points) != lit {
+		t.Errorf("changed pixels = %d, want %d", len(points), lit)
+	}
+}
@@ -0,0 +1,94 @@
+// Package scores keeps per-game high scores in a JSON file, so they survive
+// across sessions.
+package scores
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/atomicfile"
+)
+
+// MaxScoresPerGame is how many of the highest scores are kept for each game
+const MaxScoresPerGame = 100
+
+// Store records high scores in a JSON file mapping each game name to its
+// scores, highest first. It is safe for concurrent use.
+type Store struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewStore creates a store backed by the JSON file at path. The file is
+// created on the first Record.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// Record adds a score for the game, keeping at most MaxScoresPerGame of the
+// highest scores.
+func (s *Store) Record(game string, score int) error {
+	if game == "" {
+		return fmt.Errorf("missing game name")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	scores := append(all[game], score)
+	sort.Sort(sort.Reverse(sort.IntSlice(scores)))
+	all[game] = scores[:min(len(scores), MaxScoresPerGame)]
+
+	return s.save(all)
+}
+
+// Top returns up to n of the highest scores for the game, highest first.
+func (s *Store) Top(game string, n int) ([]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	scores := all[game]
+	return scores[:min(len(scores), max(n, 0))], nil
+}
+
+// load reads all scores. A missing file has no scores.
+func (s *Store) load() (map[string][]int, error) {
+	all := map[string][]int{}
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return all, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scores: %w", err)
+	}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, fmt.Errorf("failed to parse scores: %w", err)
+	}
+	return all, nil
+}
+
+// save writes all scores, replacing the file atomically.
+func (s *Store) save(all map[string][]int) error {
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode scores: %w", err)
+	}
+	if err := atomicfile.WriteFile(s.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to save scores: %w", err)
+	}
+	return nil
+}
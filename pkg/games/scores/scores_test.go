@@ -0,0 +1,122 @@
+package scores
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func newTestStore(t *testing.T) *Store {
+	return NewStore(filepath.Join(t.TempDir(), "scores.json"))
+}
+
+func TestRecordAndTop(t *testing.T) {
+	s := newTestStore(t)
+
+	// No scores yet
+	top, err := s.Top("snake", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(top) != 0 {
+		t.Errorf("expected no scores, got %v", top)
+	}
+
+	for _, score := range []int{5, 12, 3, 12, 8} {
+		if err := s.Record("snake", score); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Record("tetris", 1000); err != nil {
+		t.Fatal(err)
+	}
+
+	// Highest first, per game
+	top, err = s.Top("snake", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{12, 12, 8, 5, 3}; !reflect.DeepEqual(top, want) {
+		t.Errorf("Top(snake, 10) = %v, want %v", top, want)
+	}
+
+	top, err = s.Top("snake", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{12, 12}; !reflect.DeepEqual(top, want) {
+		t.Errorf("Top(snake, 2) = %v, want %v", top, want)
+	}
+
+	// Scores survive a new store on the same file
+	top, err = NewStore(s.path).Top("tetris", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{1000}; !reflect.DeepEqual(top, want) {
+		t.Errorf("Top(tetris, 3) = %v, want %v", top, want)
+	}
+}
+
+func TestRecordKeepsMaxScoresPerGame(t *testing.T) {
+	s := newTestStore(t)
+	for score := 1; score <= MaxScoresPerGame+10; score++ {
+		if err := s.Record("snake", score); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	top, err := s.Top("snake", MaxScoresPerGame+10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(top) != MaxScoresPerGame {
+		t.Fatalf("expected %d scores, got %d", MaxScoresPerGame, len(top))
+	}
+	if top[0] != MaxScoresPerGame+10 || top[len(top)-1] != 11 {
+		t.Errorf("expected scores from %d down to 11, got %d down to %d", MaxScoresPerGame+10, top[0], top[len(top)-1])
+	}
+}
+
+func TestRecordConcurrent(t *testing.T) {
+	s := newTestStore(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(score int) {
+			defer wg.Done()
+			if err := s.Record("snake", score); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	top, err := s.Top("snake", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(top) != 20 {
+		t.Errorf("expected 20 scores, got %d", len(top))
+	}
+}
+
+func TestInvalidFile(t *testing.T) {
+	s := newTestStore(t)
+	if err := os.WriteFile(s.path, []byte(`{`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Top("snake", 1); err == nil {
+		t.Error("expected an error reading an invalid file")
+	}
+	if err := s.Record("snake", 1); err == nil {
+		t.Error("expected an error recording into an invalid file")
+	}
+	if err := s.Record("", 1); err == nil {
+		t.Error("expected an error without a game name")
+	}
+}
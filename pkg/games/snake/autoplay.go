@@ -0,0 +1,94 @@
+package snake
+
+import (
+	"math/rand"
+	"time"
+)
+
+// AutoPlayPause is how long the cover and game over screens are shown in
+// auto-play mode before the next game starts.
+const AutoPlayPause = 3 * time.Second
+
+// AutoInput is a bot steering the snake towards the food along the shortest
+// path avoiding walls, obstacles and the snake's body. Ties between equally
+// short paths are broken with a seeded random source, so the moves are
+// reproducible given the seed and the game state.
+type AutoInput struct {
+	rng *rand.Rand
+}
+
+// NewAutoInput creates a bot breaking ties with the given seed.
+func NewAutoInput(seed int64) *AutoInput {
+	return &AutoInput{rng: rand.New(rand.NewSource(seed))}
+}
+
+// Direction returns the direction the snake should move next. When no path to
+// the food exists it keeps the snake alive if it can, preferring the current
+// direction.
+func (a *AutoInput) Direction(g *Game) Direction {
+	dirs := []Direction{Up, Down, Left, Right}
+	a.rng.Shuffle(len(dirs), func(i, j int) { dirs[i], dirs[j] = dirs[j], dirs[i] })
+
+	// The tail moves away on the next tick, so it doesn't block
+	blocked := make(map[Point]bool, len(g.snake))
+	for _, p := range g.snake[:len(g.snake)-1] {
+		blocked[p] = true
+	}
+
+	// Breadth first search from the head, remembering the first move of each path
+	type node struct {
+		pos   Point
+		first Direction
+	}
+	head := g.snake[0]
+	visited := map[Point]bool{head: true}
+	var queue, safe []node
+	for _, dir := range dirs {
+		if pos, ok := a.next(g, head, dir, blocked); ok && !visited[pos] {
+			visited[pos] = true
+			queue = append(queue, node{pos, dir})
+		}
+	}
+	safe = append(safe, queue...)
+
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		if n.pos == g.food {
+			return n.first
+		}
+		for _, dir := range dirs {
+			if pos, ok := a.next(g, n.pos, dir, blocked); ok && !visited[pos] {
+				visited[pos] = true
+				queue = append(queue, node{pos, n.first})
+			}
+		}
+	}
+
+	// The food can't be reached: avoid crashing
+	for _, n := range safe {
+		if n.first == g.direction {
+			return n.first
+		}
+	}
+	if len(safe) > 0 {
+		return safe[0].first
+	}
+	return g.direction
+}
+
+// next returns where the head ends up moving from p in the given direction,
+// following portals, and false if that position is deadly.
+func (a *AutoInput) next(g *Game, p Point, dir Direction, blocked map[Point]bool) (Point, bool) {
+	p = g.step(p, dir)
+	if exit, ok := g.gameMap.PortalExit(p); ok {
+		p = exit
+	}
+	if p.X < 0 || p.X >= DisplaySize || p.Y < 0 || p.Y >= DisplaySize {
+		return p, false
+	}
+	if g.gameMap.IsObstacle(p.X, p.Y) || blocked[p] {
+		return p, false
+	}
+	return p, true
+}
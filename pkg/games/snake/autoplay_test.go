@@ -0,0 +1,79 @@
+package snake
+
+import "testing"
+
+func newAutoPlayGame(seed int64) *Game {
+	g := newTestGame(DefaultGameOptions())
+	g.background = GenerateBackgroundWithObstacles(g.gameMap)
+	g.SetAutoPlay(NewAutoInput(seed))
+	g.snake = []Point{{X: 10, Y: 10}, {X: 9, Y: 10}, {X: 8, Y: 10}}
+	g.direction = Right
+	return g
+}
+
+func TestAutoInputReachesAdjacentFood(t *testing.T) {
+	for _, food := range []Point{{X: 11, Y: 10}, {X: 10, Y: 9}, {X: 10, Y: 11}} {
+		g := newAutoPlayGame(1)
+		g.food = food
+
+		g.tick()
+
+		if g.snake[0] != food || g.score != 1 {
+			t.Errorf("food at %v: head = %v, score = %d, want the food eaten in one move", food, g.snake[0], g.score)
+		}
+	}
+}
+
+func TestAutoInputAvoidsRockAhead(t *testing.T) {
+	for seed := int64(0); seed < 10; seed++ {
+		g := newAutoPlayGame(seed)
+		g.gameMap.Tiles[10][11] = TileRock
+		g.food = Point{X: 20, Y: 10}
+
+		if dir := g.auto.Direction(g); dir == Right || dir == Left {
+			t.Errorf("seed %d: direction = %v, want to steer around the rock", seed, dir)
+		}
+		g.tick()
+		if g.gameOver {
+			t.Errorf("seed %d: expected the snake to avoid the rock", seed)
+		}
+	}
+}
+
+func TestAutoInputEatsFood(t *testing.T) {
+	g := newAutoPlayGame(42)
+	g.gameMap.Tiles[10][11] = TileRock
+	g.gameMap.Tiles[9][11] = TileRock
+	g.gameMap.Tiles[11][11] = TileRock
+	g.food = Point{X: 30, Y: 40}
+
+	for i := 0; i < 100 && g.score == 0; i++ {
+		g.tick()
+		if g.gameOver {
+			t.Fatalf("game over after %d moves", i+1)
+		}
+	}
+	if g.score != 1 {
+		t.Error("expected the bot to reach the food")
+	}
+}
+
+func TestAutoInputDeterministic(t *testing.T) {
+	path := func(seed int64) []Point {
+		g := newAutoPlayGame(seed)
+		g.food = Point{X: 30, Y: 40}
+		var heads []Point
+		for i := 0; i < 20; i++ {
+			g.tick()
+			heads = append(heads, g.snake[0])
+		}
+		return heads
+	}
+
+	a, b := path(7), path(7)
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("move %d: %v != %v with the same seed", i, a[i], b[i])
+		}
+	}
+}
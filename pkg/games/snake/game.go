@@ -3,11 +3,11 @@ package snake
 import (
 	"fmt"
 	"math/rand"
-	"os"
 	"time"
 
-	"golang.org/x/term"
-
+	"github.com/pracucci/idotmatrix-overclocked/pkg/games/gametime"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/games/input"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
 	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
 )
 
@@ -36,41 +36,80 @@ type Game struct {
 	score       int
 	running     bool
 	gameOver    bool
-	inputChan   chan rune
+	paused      bool
+	input       input.Source
 	background  []byte // Store background RGB data for pixel restoration
 
 	// Level system
 	startLevel   int         // Starting level (for testing)
+	options      GameOptions // Difficulty and rules
 	currentLevel int         // Current level (1-based)
 	applesEaten  int         // Apples eaten in current level
 	gameMap     *Map    // Current map with obstacles
 	levelConfig LevelConfig // Current level configuration
 	growthQueue int         // Pending growth segments
+
+	onGameOver func(score int)   // Called with the final score of each finished game
+	recorder   *graphic.Recorder // Captures the frames shown, if set
+	auto       *AutoInput        // Steers the snake in auto-play mode, if set
+	clock      gametime.Clock    // Wall clock unless replaced with SetClock
 }
 
 // NewGame creates a new snake game instance.
-func NewGame(device protocol.DeviceConnection, startLevel int) *Game {
+func NewGame(device protocol.DeviceConnection, startLevel int, options GameOptions) *Game {
 	if startLevel < 1 {
 		startLevel = 1
 	}
 	g := &Game{
 		device:       device,
 		running:      true,
-		inputChan:    make(chan rune, 10),
+		input:        input.NewTerminalInput(),
 		startLevel:   startLevel,
 		currentLevel: startLevel,
+		options:      options,
+		clock:        gametime.Real(),
 	}
 	return g
 }
 
+// SetClock replaces the wall clock used for timing, e.g. with a fake clock in
+// tests so the game loop runs without delays.
+func (g *Game) SetClock(c gametime.Clock) {
+	g.clock = c
+}
+
+// SetInput replaces the terminal as the source of key presses, e.g. with an
+// input.ChannelInput in tests or for a remote controller.
+func (g *Game) SetInput(src input.Source) {
+	g.input = src
+}
+
+// SetOnGameOver sets a function called with the final score when a game is
+// over, e.g. to record high scores. It isn't called when the player quits.
+func (g *Game) SetOnGameOver(fn func(score int)) {
+	g.onGameOver = fn
+}
+
+// SetAutoPlay lets the given bot steer the snake, e.g. for a hands-free demo.
+// Games start and restart on their own, and Q still quits.
+func (g *Game) SetAutoPlay(auto *AutoInput) {
+	g.auto = auto
+}
+
+// SetRecorder records the frames shown during the game, e.g. to replay a demo.
+func (g *Game) SetRecorder(r *graphic.Recorder) {
+	g.recorder = r
+}
+
 // reset initializes the game state for a new game.
 func (g *Game) reset() {
 	g.currentLevel = g.startLevel
 	g.applesEaten = 0
 	g.score = 0
 	g.gameOver = false
+	g.paused = false
 	g.growthQueue = 0
-	g.resetSnakePosition(InitialLength)
+	g.resetSnakePosition(g.options.InitialLength)
 }
 
 // resetSnakePosition resets the snake to the center with the given length.
@@ -95,11 +134,12 @@ func (g *Game) advanceLevel() {
 
 // setupLevel generates the map and prepares for the current level.
 func (g *Game) setupLevel() {
-	g.levelConfig = GetLevelConfig(g.currentLevel)
+	g.levelConfig = g.options.LevelConfig(g.currentLevel)
 
 	// Generate new map with obstacles
-	mapGen := NewMapGenerator(time.Now().UnixNano())
+	mapGen := NewMapGenerator(g.clock.Now().UnixNano())
 	g.gameMap = mapGen.Generate(g.levelConfig.NumRocks, g.levelConfig.NumLakes)
+	mapGen.PlaceSpecials(g.gameMap, g.levelConfig.NumPortalPairs, g.levelConfig.NumPowerUps)
 
 	// Generate and store background with obstacles
 	g.background = GenerateBackgroundWithObstacles(g.gameMap)
@@ -146,18 +186,27 @@ func (g *Game) getBackgroundPixel(x, y int) (uint8, uint8, uint8) {
 
 // calculateNewHead returns the new head position based on direction.
 func (g *Game) calculateNewHead() Point {
-	head := g.snake[0]
-	switch g.direction {
+	return g.step(g.snake[0], g.direction)
+}
+
+// step returns the position next to p in the given direction. With WrapWalls
+// positions past an edge reappear on the opposite edge.
+func (g *Game) step(p Point, dir Direction) Point {
+	switch dir {
 	case Up:
-		return Point{X: head.X, Y: head.Y - 1}
+		p.Y--
 	case Down:
-		return Point{X: head.X, Y: head.Y + 1}
+		p.Y++
 	case Left:
-		return Point{X: head.X - 1, Y: head.Y}
+		p.X--
 	case Right:
-		return Point{X: head.X + 1, Y: head.Y}
+		p.X++
 	}
-	return head
+	if g.options.WrapWalls {
+		p.X = (p.X + DisplaySize) % DisplaySize
+		p.Y = (p.Y + DisplaySize) % DisplaySize
+	}
+	return p
 }
 
 // isCollision checks if the given point causes a collision.
@@ -186,6 +235,11 @@ func (g *Game) move() ([]PixelChange, bool) {
 
 	newHead := g.calculateNewHead()
 
+	// Entering a portal moves the head to the paired portal, keeping the direction
+	if exit, ok := g.gameMap.PortalExit(newHead); ok {
+		newHead = exit
+	}
+
 	if g.isCollision(newHead) {
 		g.gameOver = true
 		return nil, false
@@ -199,10 +253,10 @@ func (g *Game) move() ([]PixelChange, bool) {
 	if newHead == g.food {
 		g.score++
 		g.applesEaten++
-		g.growthQueue += GrowthPerApple // Queue growth
+		g.growthQueue += g.options.GrowthPerApple // Queue growth
 
 		// Check for level advancement
-		if g.applesEaten >= ApplesPerLevel {
+		if g.applesEaten >= g.options.ApplesPerLevel {
 			return changes, true // Signal level advance
 		}
 
@@ -224,13 +278,37 @@ func (g *Game) move() ([]PixelChange, bool) {
 		g.snake = g.snake[:len(g.snake)-1]
 	}
 
+	// Check if eating a power-up
+	if g.gameMap.GetTile(newHead.X, newHead.Y) == TilePowerUp {
+		changes = append(changes, g.consumePowerUp(newHead)...)
+	}
+
 	return changes, false
 }
 
+// consumePowerUp removes the power-up at p, slows the snake down for the rest
+// of the level and shrinks it. Returns the pixel changes for the removed segments.
+func (g *Game) consumePowerUp(p Point) []PixelChange {
+	var changes []PixelChange
+
+	g.gameMap.Tiles[p.Y][p.X] = TileTerrain
+	g.background = GenerateBackgroundWithObstacles(g.gameMap)
+	g.levelConfig.TickDelay += PowerUpSlowdown
+
+	for i := 0; i < PowerUpShrink && len(g.snake) > g.options.InitialLength; i++ {
+		tail := g.snake[len(g.snake)-1]
+		r, gb, b := g.getBackgroundPixel(tail.X, tail.Y)
+		changes = append(changes, PixelChange{tail, r, gb, b})
+		g.snake = g.snake[:len(g.snake)-1]
+	}
+
+	return changes
+}
+
 // handleInput processes keyboard input.
 func (g *Game) handleInput() {
 	select {
-	case key := <-g.inputChan:
+	case key := <-g.input.Keys():
 		switch key {
 		case 'w', 'W':
 			if g.direction != Down {
@@ -248,6 +326,8 @@ func (g *Game) handleInput() {
 			if g.direction != Left {
 				g.direction = Right
 			}
+		case 'p', 'P':
+			g.paused = !g.paused
 		case 'q', 'Q':
 			g.running = false
 		}
@@ -255,132 +335,146 @@ func (g *Game) handleInput() {
 	}
 }
 
+// tick processes pending input and moves the snake, unless the game is paused
+// or over. Input is still processed while paused, so the game can be resumed
+// or quit. Returns the pixel changes to render and true if the level should
+// advance.
+func (g *Game) tick() ([]PixelChange, bool) {
+	g.handleInput()
+
+	if g.paused || g.gameOver {
+		return nil, false
+	}
+	if g.auto != nil {
+		g.direction = g.auto.Direction(g)
+	}
+	return g.move()
+}
+
 // renderInitial draws the initial snake and food on the display.
-func (g *Game) renderInitial() {
-	// Draw the snake
+func (g *Game) renderInitial() error {
+	changes := make([]PixelChange, 0, len(g.snake)+1)
 	for _, p := range g.snake {
-		protocol.SetPixel(g.device, p.X, p.Y, 0, 255, 0)
-		time.Sleep(20 * time.Millisecond)
+		changes = append(changes, PixelChange{p, 0, 255, 0})
+	}
+	changes = append(changes, PixelChange{g.food, 255, 0, 0})
+	return g.drawPixels(changes)
+}
+
+// drawPixels sends pixel changes to the display, stopping at the first
+// failed write (e.g. the BLE link dropped).
+func (g *Game) drawPixels(changes []PixelChange) error {
+	for _, c := range changes {
+		if err := protocol.SetPixel(g.device, c.pos.X, c.pos.Y, c.r, c.g, c.b); err != nil {
+			return fmt.Errorf("failed to draw on the display: %w", err)
+		}
+		g.clock.Sleep(g.options.PixelDelay)
+	}
+	return nil
+}
+
+// renderFrame returns the RGB buffer currently shown on the display: the
+// background with the snake and the food drawn on top.
+func (g *Game) renderFrame() []byte {
+	frame := make([]byte, len(g.background))
+	copy(frame, g.background)
+	for _, p := range g.snake {
+		graphic.SetPixel(frame, p.X, p.Y, graphic.Color{0, 255, 0})
+	}
+	graphic.SetPixel(frame, g.food.X, g.food.Y, graphic.Color{255, 0, 0})
+	return frame
+}
+
+// record captures a frame if the game is being recorded.
+func (g *Game) record(rgbData []byte) {
+	if g.recorder != nil {
+		g.recorder.Capture(rgbData, g.clock.Now())
 	}
-	// Draw the food
-	protocol.SetPixel(g.device, g.food.X, g.food.Y, 255, 0, 0)
-	time.Sleep(20 * time.Millisecond)
 }
 
 // showImage displays an image on the device.
 func (g *Game) showImage(rgbData []byte) error {
+	g.record(rgbData)
 	if err := protocol.SetDrawMode(g.device, 1); err != nil {
 		return err
 	}
 	if err := protocol.SendImage(g.device, rgbData); err != nil {
 		return err
 	}
-	time.Sleep(500 * time.Millisecond)
+	g.clock.Sleep(500 * time.Millisecond)
 	return nil
 }
 
-// waitForKey blocks until a key is pressed.
+// waitForKey blocks until a key is pressed. In auto-play mode it waits for
+// AutoPlayPause instead, returning 0 unless a key was pressed meanwhile.
 func (g *Game) waitForKey() rune {
-	return <-g.inputChan
-}
-
-// startInputReader starts the keyboard input goroutine.
-func (g *Game) startInputReader() func() {
-	oldState, err := term.MakeRaw(int(os.Stdin.Fd()))
-	if err != nil {
-		fmt.Printf("Warning: could not set raw mode: %v\n", err)
-		return func() {}
+	if g.auto == nil {
+		return <-g.input.Keys()
 	}
-
-	go func() {
-		buf := make([]byte, 3)
-		for g.running {
-			n, err := os.Stdin.Read(buf)
-			if err != nil {
-				return
-			}
-			if n > 0 {
-				// Handle escape sequences for arrow keys
-				if n == 3 && buf[0] == 27 && buf[1] == 91 {
-					switch buf[2] {
-					case 65: // up arrow
-						g.inputChan <- 'w'
-					case 66: // down arrow
-						g.inputChan <- 's'
-					case 67: // right arrow
-						g.inputChan <- 'd'
-					case 68: // left arrow
-						g.inputChan <- 'a'
-					}
-				} else {
-					g.inputChan <- rune(buf[0])
-				}
-			}
-		}
-	}()
-
-	return func() {
-		term.Restore(int(os.Stdin.Fd()), oldState)
+	select {
+	case key := <-g.input.Keys():
+		return key
+	case <-g.clock.After(AutoPlayPause):
+		return 0
 	}
 }
 
-// runLevel runs a single level and returns true if the game should continue.
-func (g *Game) runLevel() (continueGame bool, advanceLevel bool) {
+// runLevel runs a single level until the level is completed, the game is over
+// or the player quits. Returns true if the game should advance to the next
+// level, or an error if the display can't be updated anymore.
+func (g *Game) runLevel() (advanceLevel bool, err error) {
 	// Setup level
 	g.setupLevel()
 
 	// Show level interstitial
 	if err := g.showLevelInterstitial(); err != nil {
-		return false, false
+		return false, err
 	}
 
 	// Display background with obstacles
 	if err := g.showImage(g.background); err != nil {
-		return false, false
+		return false, err
 	}
 
 	// Spawn food and draw initial state
 	g.spawnFood()
-	g.renderInitial()
+	if err := g.renderInitial(); err != nil {
+		return false, err
+	}
+	g.record(g.renderFrame())
 
 	// Game tick loop
 	for g.running && !g.gameOver {
-		tickStart := time.Now()
+		tickStart := g.clock.Now()
 
-		g.handleInput()
-
-		if !g.gameOver {
-			changes, shouldAdvance := g.move()
-			if shouldAdvance {
-				return true, true // Continue game, advance level
-			}
-			for _, c := range changes {
-				protocol.SetPixel(g.device, c.pos.X, c.pos.Y, c.r, c.g, c.b)
-				time.Sleep(20 * time.Millisecond)
-			}
+		changes, shouldAdvance := g.tick()
+		if shouldAdvance {
+			return true, nil
+		}
+		if err := g.drawPixels(changes); err != nil {
+			return false, err
+		}
+		if len(changes) > 0 {
+			g.record(g.renderFrame())
 		}
 
-		elapsed := time.Since(tickStart)
+		elapsed := g.clock.Now().Sub(tickStart)
 		sleepTime := g.levelConfig.TickDelay - elapsed
 		if sleepTime > 0 {
-			time.Sleep(sleepTime)
+			g.clock.Sleep(sleepTime)
 		}
 	}
 
-	if !g.running {
-		return false, false // Quit
-	}
-
-	return true, false // Game over, don't advance
+	return false, nil
 }
 
 // Run starts the main game loop.
 func (g *Game) Run() error {
 	fmt.Println("Starting Snake!")
-	fmt.Println("Controls: WASD or Arrow keys to move, Q to quit, R to restart")
+	fmt.Println("Controls: WASD or Arrow keys to move, P to pause/resume, Q to quit, R to restart")
 
-	cleanup := g.startInputReader()
-	defer cleanup()
+	stop := g.input.Start()
+	defer stop()
 
 	for g.running {
 		// Show cover image and wait for key to start
@@ -399,9 +493,9 @@ func (g *Game) Run() error {
 
 		// Level loop
 		for g.running && !g.gameOver {
-			continueGame, advance := g.runLevel()
-			if !continueGame {
-				break
+			advance, err := g.runLevel()
+			if err != nil {
+				return err
 			}
 			if advance {
 				g.advanceLevel()
@@ -412,6 +506,10 @@ func (g *Game) Run() error {
 			break
 		}
 
+		if g.onGameOver != nil {
+			g.onGameOver(g.score)
+		}
+
 		// Game over - show game over image and wait for any key to restart (Q to quit)
 		if err := g.showImage(GenerateGameOverImage()); err != nil {
 			return err
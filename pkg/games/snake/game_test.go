@@ -0,0 +1,482 @@
+package snake
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/games/gametime"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/games/input"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+)
+
+func newTestGame(options GameOptions) *Game {
+	g := NewGame(nil, 1, options)
+	g.gameMap = NewMap()
+	return g
+}
+
+func TestWrapWalls(t *testing.T) {
+	tests := []struct {
+		name      string
+		head      Point
+		direction Direction
+		expected  Point
+	}{
+		{name: "exit right edge", head: Point{X: DisplaySize - 1, Y: 10}, direction: Right, expected: Point{X: 0, Y: 10}},
+		{name: "exit left edge", head: Point{X: 0, Y: 10}, direction: Left, expected: Point{X: DisplaySize - 1, Y: 10}},
+		{name: "exit top edge", head: Point{X: 10, Y: 0}, direction: Up, expected: Point{X: 10, Y: DisplaySize - 1}},
+		{name: "exit bottom edge", head: Point{X: 10, Y: DisplaySize - 1}, direction: Down, expected: Point{X: 10, Y: 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := DefaultGameOptions()
+			options.WrapWalls = true
+			g := newTestGame(options)
+			g.snake = []Point{tt.head}
+			g.direction = tt.direction
+
+			newHead := g.calculateNewHead()
+			if newHead != tt.expected {
+				t.Errorf("calculateNewHead() = %v, want %v", newHead, tt.expected)
+			}
+			if g.isCollision(newHead) {
+				t.Error("expected no collision when wrapping around the walls")
+			}
+		})
+	}
+}
+
+func TestWrapWallsMove(t *testing.T) {
+	options := DefaultGameOptions()
+	options.WrapWalls = true
+	g := newTestGame(options)
+	g.background = GenerateBackgroundWithObstacles(g.gameMap)
+	g.food = Point{X: 30, Y: 30}
+	g.snake = []Point{{X: DisplaySize - 1, Y: 5}, {X: DisplaySize - 2, Y: 5}, {X: DisplaySize - 3, Y: 5}}
+	g.direction = Right
+
+	g.move()
+
+	if g.gameOver {
+		t.Fatal("expected the snake to survive exiting the right edge")
+	}
+	if g.snake[0] != (Point{X: 0, Y: 5}) {
+		t.Errorf("head = %v, want {0 5}", g.snake[0])
+	}
+}
+
+func TestDeadlyWalls(t *testing.T) {
+	g := newTestGame(DefaultGameOptions())
+	g.snake = []Point{{X: DisplaySize - 1, Y: 10}}
+	g.direction = Right
+
+	newHead := g.calculateNewHead()
+	if newHead != (Point{X: DisplaySize, Y: 10}) {
+		t.Errorf("calculateNewHead() = %v, want {%d 10}", newHead, DisplaySize)
+	}
+	if !g.isCollision(newHead) {
+		t.Error("expected a wall collision with the default options")
+	}
+}
+
+func TestGameOptionsLevelConfig(t *testing.T) {
+	t.Run("defaults match the classic levels", func(t *testing.T) {
+		options := DefaultGameOptions()
+		for level := 1; level <= 10; level++ {
+			if got, want := options.LevelConfig(level), GetLevelConfig(level); got != want {
+				t.Errorf("level %d: LevelConfig() = %+v, want %+v", level, got, want)
+			}
+		}
+	})
+
+	t.Run("start speed and flat curve", func(t *testing.T) {
+		options := DefaultGameOptions()
+		options.StartSpeed = 50 * time.Millisecond
+		options.SpeedCurve = 0
+		for level := 1; level <= 5; level++ {
+			if got := options.LevelConfig(level).TickDelay; got != 50*time.Millisecond {
+				t.Errorf("level %d: TickDelay = %v, want 50ms", level, got)
+			}
+		}
+	})
+
+	t.Run("steeper curve", func(t *testing.T) {
+		options := DefaultGameOptions()
+		options.SpeedCurve = 2
+		if got := options.LevelConfig(2).TickDelay; got != 49*time.Millisecond {
+			t.Errorf("TickDelay = %v, want 49ms", got)
+		}
+	})
+
+	t.Run("obstacle density", func(t *testing.T) {
+		options := DefaultGameOptions()
+		options.ObstacleDensity = 0
+		config := options.LevelConfig(5)
+		if config.NumRocks != 0 || config.NumLakes != 0 {
+			t.Errorf("expected no obstacles, got %d rocks and %d lakes", config.NumRocks, config.NumLakes)
+		}
+
+		options.ObstacleDensity = 2
+		config = options.LevelConfig(3)
+		if config.NumRocks != 10 || config.NumLakes != 4 {
+			t.Errorf("expected 10 rocks and 4 lakes, got %d and %d", config.NumRocks, config.NumLakes)
+		}
+	})
+}
+
+func TestGameOptionsValidate(t *testing.T) {
+	if err := DefaultGameOptions().Validate(); err != nil {
+		t.Errorf("default options should be valid: %v", err)
+	}
+
+	invalid := []GameOptions{
+		{StartSpeed: 0, SpeedCurve: 1, ObstacleDensity: 1},
+		{StartSpeed: time.Millisecond, SpeedCurve: -1, ObstacleDensity: 1},
+		{StartSpeed: time.Millisecond, SpeedCurve: 1, ObstacleDensity: -1},
+		{StartSpeed: time.Millisecond, SpeedCurve: 1, ObstacleDensity: 1, GrowthPerApple: -1, ApplesPerLevel: 3, InitialLength: 3},
+		{StartSpeed: time.Millisecond, SpeedCurve: 1, ObstacleDensity: 1, GrowthPerApple: 3, ApplesPerLevel: 0, InitialLength: 3},
+		{StartSpeed: time.Millisecond, SpeedCurve: 1, ObstacleDensity: 1, GrowthPerApple: 3, ApplesPerLevel: 3, InitialLength: 0},
+		{StartSpeed: time.Millisecond, SpeedCurve: 1, ObstacleDensity: 1, GrowthPerApple: 3, ApplesPerLevel: 3, InitialLength: MaxInitialLength + 1},
+		{StartSpeed: time.Millisecond, SpeedCurve: 1, ObstacleDensity: 1, GrowthPerApple: 3, ApplesPerLevel: 3, InitialLength: 3, PixelDelay: -time.Millisecond},
+	}
+	for _, o := range invalid {
+		if err := o.Validate(); err == nil {
+			t.Errorf("expected %+v to be invalid", o)
+		}
+	}
+}
+
+func TestPortalTeleport(t *testing.T) {
+	options := DefaultGameOptions()
+	options.Specials = true
+	g := newTestGame(options)
+	g.gameMap.AddPortalPair(Point{X: 40, Y: 10}, Point{X: 10, Y: 50})
+	g.background = GenerateBackgroundWithObstacles(g.gameMap)
+	g.food = Point{X: 30, Y: 30}
+	g.snake = []Point{{X: 39, Y: 10}, {X: 38, Y: 10}, {X: 37, Y: 10}}
+	g.direction = Right
+
+	g.move()
+
+	if g.gameOver {
+		t.Fatal("expected the snake to survive entering a portal")
+	}
+	if g.snake[0] != (Point{X: 10, Y: 50}) {
+		t.Errorf("head = %v, want {10 50}", g.snake[0])
+	}
+	if g.direction != Right {
+		t.Errorf("direction = %v, want Right", g.direction)
+	}
+
+	// The snake keeps moving in the same direction out of the exit portal
+	g.move()
+	if g.snake[0] != (Point{X: 11, Y: 50}) {
+		t.Errorf("head = %v, want {11 50}", g.snake[0])
+	}
+}
+
+func TestPowerUpConsumption(t *testing.T) {
+	options := DefaultGameOptions()
+	options.Specials = true
+	g := newTestGame(options)
+	g.levelConfig = options.LevelConfig(1)
+	g.gameMap.Tiles[10][40] = TilePowerUp
+	g.background = GenerateBackgroundWithObstacles(g.gameMap)
+	g.food = Point{X: 30, Y: 30}
+	g.snake = []Point{{X: 39, Y: 10}, {X: 38, Y: 10}, {X: 37, Y: 10}, {X: 36, Y: 10}, {X: 35, Y: 10}, {X: 34, Y: 10}, {X: 33, Y: 10}}
+	g.direction = Right
+
+	g.move()
+
+	if want := SlowTickDelay + PowerUpSlowdown; g.levelConfig.TickDelay != want {
+		t.Errorf("TickDelay = %v, want %v", g.levelConfig.TickDelay, want)
+	}
+	// 7 segments + new head - moved tail - PowerUpShrink
+	if len(g.snake) != 4 {
+		t.Errorf("snake length = %d, want 4", len(g.snake))
+	}
+	if tile := g.gameMap.GetTile(40, 10); tile != TileTerrain {
+		t.Errorf("power-up tile = %v, want terrain after being eaten", tile)
+	}
+
+	// The snake never shrinks below its initial length
+	g.gameMap.Tiles[10][41] = TilePowerUp
+	g.move()
+	if len(g.snake) != InitialLength {
+		t.Errorf("snake length = %d, want %d", len(g.snake), InitialLength)
+	}
+}
+
+func TestPlaceSpecials(t *testing.T) {
+	m := NewMap()
+	NewMapGenerator(1).PlaceSpecials(m, 2, 3)
+
+	portals, powerUps := 0, 0
+	for y := 0; y < mapSize; y++ {
+		for x := 0; x < mapSize; x++ {
+			switch m.GetTile(x, y) {
+			case TilePortal:
+				portals++
+				exit, ok := m.PortalExit(Point{X: x, Y: y})
+				if !ok {
+					t.Fatalf("portal at (%d, %d) has no exit", x, y)
+				}
+				if back, _ := m.PortalExit(exit); back != (Point{X: x, Y: y}) {
+					t.Errorf("portal at (%d, %d) is not paired back", x, y)
+				}
+			case TilePowerUp:
+				powerUps++
+			}
+		}
+	}
+	if portals != 4 || powerUps != 3 {
+		t.Errorf("got %d portals and %d power-ups, want 4 and 3", portals, powerUps)
+	}
+}
+
+func TestSpecialsLevelConfig(t *testing.T) {
+	if config := DefaultGameOptions().LevelConfig(5); config.NumPortalPairs != 0 || config.NumPowerUps != 0 {
+		t.Errorf("expected no specials by default, got %+v", config)
+	}
+
+	options := DefaultGameOptions()
+	options.Specials = true
+	if config := options.LevelConfig(1); config.NumPortalPairs != 1 || config.NumPowerUps != 1 {
+		t.Errorf("level 1: got %d portal pairs and %d power-ups, want 1 and 1", config.NumPortalPairs, config.NumPowerUps)
+	}
+	if config := options.LevelConfig(20); config.NumPortalPairs != MaxPortalPairs || config.NumPowerUps != MaxPowerUps {
+		t.Errorf("level 20: got %d portal pairs and %d power-ups, want caps", config.NumPortalPairs, config.NumPowerUps)
+	}
+}
+
+func TestGrowthPerApple(t *testing.T) {
+	for _, growth := range []int{1, 3, 6} {
+		options := DefaultGameOptions()
+		options.GrowthPerApple = growth
+		g := newTestGame(options)
+		g.background = GenerateBackgroundWithObstacles(g.gameMap)
+		g.snake = []Point{{X: 10, Y: 10}, {X: 9, Y: 10}, {X: 8, Y: 10}}
+		g.food = Point{X: 11, Y: 10}
+		g.direction = Right
+
+		g.move()
+
+		// One queued segment is used right away by the move eating the apple
+		if g.growthQueue != growth-1 {
+			t.Errorf("growth %d: growthQueue = %d, want %d", growth, g.growthQueue, growth-1)
+		}
+
+		// The snake keeps growing until the queue is empty
+		for i := 0; i < growth+2; i++ {
+			g.move()
+		}
+		if want := 3 + growth; len(g.snake) != want {
+			t.Errorf("growth %d: snake length = %d, want %d", growth, len(g.snake), want)
+		}
+	}
+}
+
+func TestApplesPerLevel(t *testing.T) {
+	options := DefaultGameOptions()
+	options.ApplesPerLevel = 2
+	g := newTestGame(options)
+	g.background = GenerateBackgroundWithObstacles(g.gameMap)
+	g.snake = []Point{{X: 10, Y: 10}, {X: 9, Y: 10}, {X: 8, Y: 10}}
+	g.direction = Right
+
+	g.food = Point{X: 11, Y: 10}
+	if _, advance := g.move(); advance {
+		t.Fatal("expected no level advance after the first apple")
+	}
+	g.food = Point{X: 12, Y: 10}
+	if _, advance := g.move(); !advance {
+		t.Error("expected a level advance after the second apple")
+	}
+}
+
+func TestInitialLength(t *testing.T) {
+	options := DefaultGameOptions()
+	options.InitialLength = 8
+	g := newTestGame(options)
+
+	g.reset()
+
+	if len(g.snake) != 8 {
+		t.Errorf("snake length = %d, want 8", len(g.snake))
+	}
+}
+
+func TestArrowKeys(t *testing.T) {
+	g := newTestGame(DefaultGameOptions())
+	g.direction = Right
+	in := input.NewChannelInput()
+	g.SetInput(in)
+
+	// The terminal delivers the up arrow as the 3-byte sequence ESC [ A
+	key, ok := input.TranslateKey([]byte{27, 91, 65})
+	if !ok {
+		t.Fatal("up arrow sequence not translated to a key")
+	}
+	in.Send(key)
+	g.handleInput()
+	if g.direction != Up {
+		t.Errorf("direction = %v after up arrow, want Up", g.direction)
+	}
+
+	// WASD keeps working
+	in.Send('a')
+	g.handleInput()
+	if g.direction != Left {
+		t.Errorf("direction = %v after 'a', want Left", g.direction)
+	}
+}
+
+func TestPause(t *testing.T) {
+	g := newTestGame(DefaultGameOptions())
+	g.background = GenerateBackgroundWithObstacles(g.gameMap)
+	g.food = Point{X: 30, Y: 30}
+	g.snake = []Point{{X: 10, Y: 10}, {X: 9, Y: 10}, {X: 8, Y: 10}}
+	g.direction = Right
+	in := input.NewChannelInput()
+	g.SetInput(in)
+
+	g.tick()
+	if g.snake[0] != (Point{X: 11, Y: 10}) {
+		t.Fatalf("head = %v, want {11 10}", g.snake[0])
+	}
+
+	// The head doesn't advance while paused, but input is still processed
+	in.Send('p')
+	for i := 0; i < 3; i++ {
+		g.tick()
+	}
+	in.Send('s')
+	g.tick()
+	if g.snake[0] != (Point{X: 11, Y: 10}) {
+		t.Errorf("head = %v while paused, want {11 10}", g.snake[0])
+	}
+	if g.direction != Down {
+		t.Errorf("direction = %v while paused, want Down", g.direction)
+	}
+
+	// Resuming continues from where the snake was
+	in.Send('p')
+	g.tick()
+	if g.snake[0] != (Point{X: 11, Y: 11}) {
+		t.Errorf("head = %v after resume, want {11 11}", g.snake[0])
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snake.gif")
+	recorder := graphic.NewRecorder(path)
+
+	g := newTestGame(DefaultGameOptions())
+	g.SetRecorder(recorder)
+	g.background = GenerateBackgroundWithObstacles(g.gameMap)
+	g.food = Point{X: 30, Y: 30}
+	g.snake = []Point{{X: 10, Y: 10}, {X: 9, Y: 10}, {X: 8, Y: 10}}
+	g.direction = Right
+
+	// The initial frame, then one frame per move
+	g.record(g.renderFrame())
+	for i := 0; i < 5; i++ {
+		g.tick()
+		g.record(g.renderFrame())
+	}
+
+	if err := recorder.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta, err := graphic.GetGIFMetadata(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Frames != 6 {
+		t.Errorf("recorded %d frames, want 6", meta.Frames)
+	}
+}
+
+// nopDevice is a device connection that discards everything written to it
+type nopDevice struct{}
+
+func (nopDevice) WritePacket(packet []byte) error { return nil }
+func (nopDevice) ReadResponse() ([]byte, error)   { return nil, nil }
+func (nopDevice) PollResponse() ([]byte, bool)    { return nil, false }
+func (nopDevice) DrainResponses()                 {}
+
+func TestRunLevelOnFakeClock(t *testing.T) {
+	clock := gametime.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	start := clock.Now()
+
+	g := NewGame(nopDevice{}, 1, DefaultGameOptions())
+	g.SetClock(clock)
+	g.reset()
+
+	// Level 1 has no obstacles: without input the snake moves right from the
+	// center until it hits the wall
+	wallStart := time.Now()
+	advance, err := g.runLevel()
+
+	if err != nil || advance || !g.gameOver {
+		t.Fatalf("runLevel() = %v, %v with gameOver %v, want a game over", advance, err, g.gameOver)
+	}
+	if minTicks := DisplaySize/2 - 1; clock.Now().Sub(start) < time.Duration(minTicks)*SlowTickDelay {
+		t.Errorf("fake clock advanced by %v, want at least %d ticks", clock.Now().Sub(start), minTicks)
+	}
+	if time.Since(wallStart) > 5*time.Second {
+		t.Errorf("game loop took %v of wall clock time", time.Since(wallStart))
+	}
+}
+
+var errDisconnected = errors.New("disconnected")
+
+// failingDevice accepts failAfter packets, then fails every write as if the
+// BLE link dropped.
+type failingDevice struct {
+	nopDevice
+	writes    int
+	failAfter int
+}
+
+func (d *failingDevice) WritePacket(packet []byte) error {
+	d.writes++
+	if d.writes > d.failAfter {
+		return errDisconnected
+	}
+	return nil
+}
+
+func TestRunLevelStopsOnDeviceError(t *testing.T) {
+	runLevel := func(device *failingDevice) error {
+		g := NewGame(device, 1, DefaultGameOptions())
+		g.SetClock(gametime.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+		g.reset()
+		_, err := g.runLevel()
+		return err
+	}
+
+	// Count the writes of a whole level, then drop the link during play
+	counter := &failingDevice{failAfter: math.MaxInt}
+	if err := runLevel(counter); err != nil {
+		t.Fatal(err)
+	}
+	device := &failingDevice{failAfter: counter.writes - 20}
+
+	err := runLevel(device)
+	if !errors.Is(err, errDisconnected) {
+		t.Fatalf("runLevel() error = %v, want the device error", err)
+	}
+	if device.writes != device.failAfter+1 {
+		t.Errorf("%d writes after the failure, want the game to stop", device.writes-device.failAfter-1)
+	}
+}
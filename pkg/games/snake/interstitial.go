@@ -22,15 +22,16 @@ func (g *Game) showLevelInterstitial() error {
 
 	frames := text.GenerateAppearingFrames(levelText, opts)
 	for _, frame := range frames {
+		g.record(frame.Data)
 		if err := protocol.SendImage(g.device, frame.Data); err != nil {
 			return err
 		}
 		// Wait for frame delay plus extra buffer for device to process
-		time.Sleep(time.Duration(frame.Delay)*10*time.Millisecond + 100*time.Millisecond)
+		g.clock.Sleep(time.Duration(frame.Delay)*10*time.Millisecond + 100*time.Millisecond)
 	}
 
 	// Extra delay before transitioning to game
-	time.Sleep(500 * time.Millisecond)
+	g.clock.Sleep(500 * time.Millisecond)
 
 	return nil
 }
@@ -1,6 +1,10 @@
 package snake
 
-import "time"
+import (
+	"fmt"
+	"math"
+	"time"
+)
 
 // Game constants
 const (
@@ -14,17 +18,34 @@ const (
 	MediumTickDelay = 70 * time.Millisecond  // Level 2
 	FastTickDelay   = 35 * time.Millisecond  // Level 3+
 
+	// PixelDelay is the default delay after each pixel sent to the device
+	PixelDelay = 20 * time.Millisecond
+
 	// Obstacle caps
 	MaxRocks = 20
 	MaxLakes = 10
+
+	// Special tile caps
+	MaxPortalPairs = 3
+	MaxPowerUps    = 3
+
+	// Power-up effects
+	PowerUpSlowdown = 10 * time.Millisecond // Added to the tick delay for the rest of the level
+	PowerUpShrink   = 3                     // Segments removed (never shorter than the initial length)
+
+	// MaxInitialLength is the longest initial snake fitting left of the
+	// center of the display, where it starts
+	MaxInitialLength = DisplaySize/2 + 1
 )
 
 // LevelConfig holds the configuration for a specific level.
 type LevelConfig struct {
-	Level     int
-	TickDelay time.Duration
-	NumRocks  int
-	NumLakes  int
+	Level          int
+	TickDelay      time.Duration
+	NumRocks       int
+	NumLakes       int
+	NumPortalPairs int
+	NumPowerUps    int
 }
 
 // GetLevelConfig returns the configuration for the given level.
@@ -58,3 +79,78 @@ func GetLevelConfig(level int) LevelConfig {
 
 	return config
 }
+
+// GameOptions configures difficulty and rules of a snake game.
+type GameOptions struct {
+	StartSpeed      time.Duration // Tick delay at level 1 (lower is faster)
+	SpeedCurve      float64       // How quickly levels speed up: 0 keeps StartSpeed, 1 is the default curve, >1 is steeper
+	WrapWalls       bool          // Wrap around the display edges instead of dying on the walls
+	ObstacleDensity float64       // Multiplier for the number of rocks and lakes (0 disables obstacles)
+	Specials        bool          // Place portals and power-ups on the map
+	GrowthPerApple  int           // Segments the snake grows per apple eaten
+	ApplesPerLevel  int           // Apples needed to advance to the next level
+	InitialLength   int           // Snake length at the start of a game
+	PixelDelay      time.Duration // Delay after each pixel sent to the device (raise it on slow links)
+}
+
+// DefaultGameOptions returns the classic game options.
+func DefaultGameOptions() GameOptions {
+	return GameOptions{
+		StartSpeed:      SlowTickDelay,
+		SpeedCurve:      1,
+		WrapWalls:       false,
+		ObstacleDensity: 1,
+		Specials:        false,
+		GrowthPerApple:  GrowthPerApple,
+		ApplesPerLevel:  ApplesPerLevel,
+		InitialLength:   InitialLength,
+		PixelDelay:      PixelDelay,
+	}
+}
+
+// Validate checks that the options are within range.
+func (o GameOptions) Validate() error {
+	if o.StartSpeed <= 0 {
+		return fmt.Errorf("invalid start speed: %v (must be > 0)", o.StartSpeed)
+	}
+	if o.SpeedCurve < 0 {
+		return fmt.Errorf("invalid speed curve: %v (must be >= 0)", o.SpeedCurve)
+	}
+	if o.ObstacleDensity < 0 {
+		return fmt.Errorf("invalid obstacle density: %v (must be >= 0)", o.ObstacleDensity)
+	}
+	if o.GrowthPerApple < 0 {
+		return fmt.Errorf("invalid growth per apple: %d (must be >= 0)", o.GrowthPerApple)
+	}
+	if o.ApplesPerLevel < 1 {
+		return fmt.Errorf("invalid apples per level: %d (must be >= 1)", o.ApplesPerLevel)
+	}
+	if o.InitialLength < 1 || o.InitialLength > MaxInitialLength {
+		return fmt.Errorf("invalid initial length: %d (must be between 1 and %d)", o.InitialLength, MaxInitialLength)
+	}
+	if o.PixelDelay < 0 {
+		return fmt.Errorf("invalid pixel delay: %v (must be >= 0)", o.PixelDelay)
+	}
+	return nil
+}
+
+// LevelConfig returns the configuration for the given level with the options applied.
+// The tick delay follows the default level curve, scaled to StartSpeed and raised
+// to SpeedCurve, so the default options match GetLevelConfig.
+func (o GameOptions) LevelConfig(level int) LevelConfig {
+	config := GetLevelConfig(level)
+
+	ratio := float64(config.TickDelay) / float64(SlowTickDelay)
+	config.TickDelay = time.Duration(math.Round(float64(o.StartSpeed) * math.Pow(ratio, o.SpeedCurve)))
+
+	config.NumRocks = min(int(math.Round(float64(config.NumRocks)*o.ObstacleDensity)), MaxRocks)
+	config.NumLakes = min(int(math.Round(float64(config.NumLakes)*o.ObstacleDensity)), MaxLakes)
+
+	if o.Specials {
+		// One more portal pair every 2 levels and one more power-up every 3 levels
+		config.NumPortalPairs = min(1+(level-1)/2, MaxPortalPairs)
+		config.NumPowerUps = min(1+(level-1)/3, MaxPowerUps)
+	}
+
+	return config
+}
@@ -9,6 +9,8 @@ const (
 	TileTerrain TileType = iota
 	TileRock
 	TileLake
+	TilePortal  // Teleports the head to the paired portal
+	TilePowerUp // Slows the snake down and shrinks it when eaten
 )
 
 const (
@@ -24,12 +26,29 @@ const (
 
 // Map represents the game map with terrain and obstacles.
 type Map struct {
-	Tiles [mapSize][mapSize]TileType
+	Tiles   [mapSize][mapSize]TileType
+	Portals map[Point]Point // Maps each portal tile to its paired portal
 }
 
 // NewMap creates a new empty game map (all terrain).
 func NewMap() *Map {
-	return &Map{}
+	return &Map{
+		Portals: make(map[Point]Point),
+	}
+}
+
+// AddPortalPair places two linked portal tiles at a and b.
+func (m *Map) AddPortalPair(a, b Point) {
+	m.Tiles[a.Y][a.X] = TilePortal
+	m.Tiles[b.Y][b.X] = TilePortal
+	m.Portals[a] = b
+	m.Portals[b] = a
+}
+
+// PortalExit returns the paired portal of the portal at p, if any.
+func (m *Map) PortalExit(p Point) (Point, bool) {
+	exit, ok := m.Portals[p]
+	return exit, ok
 }
 
 // IsObstacle returns true if the tile at (x, y) is a rock or lake.
@@ -95,6 +114,41 @@ func (g *MapGenerator) Generate(numRocks, numLakes int) *Map {
 	return m
 }
 
+// PlaceSpecials adds the specified number of portal pairs and power-ups to the map.
+func (g *MapGenerator) PlaceSpecials(m *Map, numPortalPairs, numPowerUps int) {
+	for i := 0; i < numPortalPairs; i++ {
+		a, okA := g.placeSpecialTile(m, TilePortal)
+		if !okA {
+			break
+		}
+		b, okB := g.placeSpecialTile(m, TilePortal)
+		if !okB {
+			// No room for the pair, remove the unpaired portal
+			m.Tiles[a.Y][a.X] = TileTerrain
+			break
+		}
+		m.AddPortalPair(a, b)
+	}
+
+	for i := 0; i < numPowerUps; i++ {
+		g.placeSpecialTile(m, TilePowerUp)
+	}
+}
+
+// placeSpecialTile attempts to place a single special tile away from the
+// display edges, the safe zone and other obstacles.
+func (g *MapGenerator) placeSpecialTile(m *Map, tile TileType) (Point, bool) {
+	for attempt := 0; attempt < maxPlacementAttempts; attempt++ {
+		p := Point{X: 1 + g.rng.Intn(mapSize-2), Y: 1 + g.rng.Intn(mapSize-2)}
+
+		if g.canPlaceObstacle(m, []Point{p}) {
+			m.Tiles[p.Y][p.X] = tile
+			return p, true
+		}
+	}
+	return Point{}, false
+}
+
 // placeRock attempts to place a 4x4, 5x5 or 6x6 rock with rounded corners on the map.
 func (g *MapGenerator) placeRock(m *Map) bool {
 	for attempt := 0; attempt < maxPlacementAttempts; attempt++ {
@@ -16,6 +16,10 @@ var (
 	rockColorAlt = [3]uint8{50, 50, 55}  // Slightly darker variation
 	lakeColor    = [3]uint8{35, 70, 135} // Blue
 	lakeColorAlt = [3]uint8{40, 80, 145} // Lighter wave variation
+
+	// Special tile colors
+	portalColor  = [3]uint8{170, 0, 220} // Purple
+	powerUpColor = [3]uint8{255, 200, 0} // Gold
 )
 
 // Brown palette - limited to 2 colors due to display constraints
@@ -309,6 +313,10 @@ func GenerateBackgroundWithObstacles(gameMap *Map) []byte {
 					color = lakeColorAlt
 				}
 				setPixel(img, x, y, color)
+			case TilePortal:
+				setPixel(img, x, y, portalColor)
+			case TilePowerUp:
+				setPixel(img, x, y, powerUpColor)
 			}
 		}
 	}
@@ -0,0 +1,152 @@
+package tetris
+
+import "time"
+
+// Auto-play timing
+const (
+	AutoMoveInterval = 150 * time.Millisecond // Delay between the bot's key presses
+	AutoPlayPause    = 3 * time.Second        // Cover and game over screen duration in auto-play mode
+)
+
+// Placement evaluation weights: the board after a placement scores higher with
+// more cleared lines and lower, flatter stacks without holes
+const (
+	AggregateHeightWeight = -0.51
+	LinesClearedWeight    = 0.76
+	HolesWeight           = -0.36
+	BumpinessWeight       = -0.18
+)
+
+// Placement is a rotation and column where a piece can be dropped
+type Placement struct {
+	Rotation Rotation
+	X        int
+	Score    float64 // Evaluation of the board after the drop
+}
+
+// EvaluateBoard scores a board after a piece was locked and linesCleared lines
+// were removed. Higher is better.
+func EvaluateBoard(board *Board, linesCleared int) float64 {
+	heights := columnHeights(board)
+
+	aggregateHeight, bumpiness := 0, 0
+	for x, h := range heights {
+		aggregateHeight += h
+		if x > 0 {
+			bumpiness += max(h-heights[x-1], heights[x-1]-h)
+		}
+	}
+
+	return AggregateHeightWeight*float64(aggregateHeight) +
+		LinesClearedWeight*float64(linesCleared) +
+		HolesWeight*float64(countHoles(board)) +
+		BumpinessWeight*float64(bumpiness)
+}
+
+// columnHeights returns the height of the highest occupied cell of each column
+func columnHeights(board *Board) [BoardWidth]int {
+	var heights [BoardWidth]int
+	for x := 0; x < BoardWidth; x++ {
+		for y := 0; y < BoardHeight; y++ {
+			if board.Cells[y][x].Occupied {
+				heights[x] = BoardHeight - y
+				break
+			}
+		}
+	}
+	return heights
+}
+
+// countHoles returns the number of empty cells with an occupied cell above
+func countHoles(board *Board) int {
+	holes := 0
+	for x := 0; x < BoardWidth; x++ {
+		covered := false
+		for y := 0; y < BoardHeight; y++ {
+			if board.Cells[y][x].Occupied {
+				covered = true
+			} else if covered {
+				holes++
+			}
+		}
+	}
+	return holes
+}
+
+// BestPlacement evaluates dropping the piece with every rotation from every
+// column it fits in at its current height, and returns the best placement.
+// Returns false if the piece fits nowhere.
+func BestPlacement(board *Board, piece Tetromino) (Placement, bool) {
+	var best Placement
+	found := false
+
+	for rotation := Rotation0; rotation <= Rotation270; rotation++ {
+		for x := -3; x < BoardWidth; x++ {
+			candidate := Tetromino{Type: piece.Type, Rotation: rotation, X: x, Y: piece.Y}
+			if !board.IsValidPosition(candidate) {
+				continue
+			}
+
+			after := *board
+			after.Lock(board.DropPosition(candidate))
+			score := EvaluateBoard(&after, after.ClearLines())
+
+			if !found || score > best.Score {
+				best = Placement{Rotation: rotation, X: x, Score: score}
+				found = true
+			}
+		}
+	}
+	return best, found
+}
+
+// AutoKey returns the next key to press to bring the current piece to its best
+// placement: rotations first, then horizontal moves, then a hard drop. When the
+// piece can't rotate or move any further it is hard dropped where it is.
+// Returns 0 if there is no current piece.
+func AutoKey(state *GameState) rune {
+	if state.Current == nil || state.GameOver {
+		return 0
+	}
+	current := *state.Current
+	target, ok := BestPlacement(state.Board, current)
+	if !ok {
+		return ' '
+	}
+
+	if current.Rotation != target.Rotation {
+		if canRotate(state.Board, current) {
+			return 'w'
+		}
+		return ' '
+	}
+
+	dx := 0
+	switch {
+	case current.X < target.X:
+		dx = 1
+	case current.X > target.X:
+		dx = -1
+	default:
+		return ' '
+	}
+	if !state.Board.IsValidPosition(current.Move(dx, 0)) {
+		return ' '
+	}
+	if dx > 0 {
+		return 'd'
+	}
+	return 'a'
+}
+
+// canRotate returns true if the piece can be rotated clockwise, using the same
+// wall kicks as GameState.TryRotate
+func canRotate(board *Board, piece Tetromino) bool {
+	rotated := piece.RotateCW()
+	for _, kick := range piece.GetWallKicks(rotated.Rotation) {
+		if board.IsValidPosition(rotated.Move(kick.X, kick.Y)) {
+			return true
+		}
+	}
+	return false
+}
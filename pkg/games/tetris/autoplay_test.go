@@ -0,0 +1,91 @@
+package tetris
+
+import "testing"
+
+// fillRow occupies every cell of row y except the given columns
+func fillRow(b *Board, y int, except ...int) {
+	for x := 0; x < BoardWidth; x++ {
+		b.Cells[y][x].Occupied = true
+	}
+	for _, x := range except {
+		b.Cells[y][x].Occupied = false
+	}
+}
+
+func TestEvaluateBoard(t *testing.T) {
+	// Same cells locked: one board clears a line, the other covers a hole
+	cleared := NewBoard()
+	fillRow(cleared, BoardHeight-1)
+	clearedLines := cleared.ClearLines()
+
+	holes := NewBoard()
+	fillRow(holes, BoardHeight-1, 9)
+	holes.Cells[BoardHeight-2][9].Occupied = true
+
+	if EvaluateBoard(cleared, clearedLines) <= EvaluateBoard(holes, 0) {
+		t.Errorf("clearing a line (%.2f) should score higher than creating a hole (%.2f)",
+			EvaluateBoard(cleared, clearedLines), EvaluateBoard(holes, 0))
+	}
+
+	if got := countHoles(holes); got != 1 {
+		t.Errorf("countHoles() = %d, want 1", got)
+	}
+	if got := columnHeights(holes); got[0] != 1 || got[9] != 2 {
+		t.Errorf("columnHeights() = %v, want 1 in column 0 and 2 in column 9", got)
+	}
+}
+
+func TestBestPlacementClearsLine(t *testing.T) {
+	// Bottom row full except the last column: a vertical I piece there clears it
+	board := NewBoard()
+	fillRow(board, BoardHeight-1, 9)
+
+	placement, ok := BestPlacement(board, NewTetromino(TetrominoI))
+	if !ok {
+		t.Fatal("expected a placement")
+	}
+
+	after := *board
+	piece := Tetromino{Type: TetrominoI, Rotation: placement.Rotation, X: placement.X}
+	after.Lock(board.DropPosition(piece))
+	if lines := after.ClearLines(); lines != 1 {
+		t.Errorf("placement %+v cleared %d lines, want 1", placement, lines)
+	}
+	if holes := countHoles(&after); holes != 0 {
+		t.Errorf("placement %+v left %d holes, want 0", placement, holes)
+	}
+}
+
+func TestAutoKeyPlaysPiece(t *testing.T) {
+	state := NewGameState()
+	fillRow(state.Board, BoardHeight-1, 9)
+	state.Next = TetrominoI
+	state.SpawnPiece(TetrominoO)
+
+	// Apply the bot's keys until it hard drops the piece
+	for i := 0; i < 20; i++ {
+		switch key := AutoKey(state); key {
+		case 'w':
+			state.TryRotate()
+		case 'a':
+			state.TryMove(-1, 0)
+		case 'd':
+			state.TryMove(1, 0)
+		case ' ':
+			state.HardDrop()
+			if lines := state.LockAndClear(); lines != 1 {
+				t.Errorf("hard drop cleared %d lines, want 1", lines)
+			}
+			return
+		default:
+			t.Fatalf("unexpected key %q", key)
+		}
+	}
+	t.Fatal("the bot didn't drop the piece")
+}
+
+func TestAutoKeyWithoutPiece(t *testing.T) {
+	if key := AutoKey(NewGameState()); key != 0 {
+		t.Errorf("AutoKey() = %q without a current piece, want 0", key)
+	}
+}
@@ -0,0 +1,35 @@
+package tetris
+
+// Bag is a 7-bag randomizer: every piece type is dealt once, in random order,
+// before the bag is refilled
+type Bag struct {
+	rng    RandSource
+	pieces []TetrominoType
+}
+
+// NewBag creates a new empty bag using the given random source
+func NewBag(rng RandSource) *Bag {
+	return &Bag{rng: rng}
+}
+
+// Next returns the next piece type, refilling the bag when empty
+func (b *Bag) Next() TetrominoType {
+	if len(b.pieces) == 0 {
+		b.refill()
+	}
+	next := b.pieces[0]
+	b.pieces = b.pieces[1:]
+	return next
+}
+
+// refill fills the bag with one of each piece type in random order (Fisher-Yates shuffle)
+func (b *Bag) refill() {
+	b.pieces = make([]TetrominoType, TetrominoCount)
+	for i := range b.pieces {
+		b.pieces[i] = TetrominoType(i)
+	}
+	for i := len(b.pieces) - 1; i > 0; i-- {
+		j := b.rng.Intn(i + 1)
+		b.pieces[i], b.pieces[j] = b.pieces[j], b.pieces[i]
+	}
+}
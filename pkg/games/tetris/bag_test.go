@@ -0,0 +1,35 @@
+package tetris
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestBagDealsEachPieceOncePerBag(t *testing.T) {
+	bag := NewBag(rand.New(rand.NewSource(1)))
+
+	for round := 0; round < 10; round++ {
+		seen := make(map[TetrominoType]int)
+		for i := 0; i < int(TetrominoCount); i++ {
+			seen[bag.Next()]++
+		}
+
+		for piece := TetrominoType(0); piece < TetrominoCount; piece++ {
+			if seen[piece] != 1 {
+				t.Errorf("bag %d: piece %d dealt %d times, expected 1", round, piece, seen[piece])
+			}
+		}
+	}
+}
+
+func TestBagShuffle(t *testing.T) {
+	// Always swapping with index 0 rotates the initial order left by one
+	bag := NewBag(&mockRand{})
+
+	expected := []TetrominoType{TetrominoO, TetrominoT, TetrominoS, TetrominoZ, TetrominoJ, TetrominoL, TetrominoI}
+	for i, want := range expected {
+		if got := bag.Next(); got != want {
+			t.Errorf("piece %d: got %d, expected %d", i, got, want)
+		}
+	}
+}
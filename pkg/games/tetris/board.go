@@ -45,6 +45,15 @@ func (b *Board) IsValidPosition(t Tetromino) bool {
 	return true
 }
 
+// DropPosition returns the tetromino moved down as far as it can go
+// The given tetromino is not modified
+func (b *Board) DropPosition(t Tetromino) Tetromino {
+	for b.IsValidPosition(t.Move(0, 1)) {
+		t = t.Move(0, 1)
+	}
+	return t
+}
+
 // Lock places a tetromino on the board, marking its cells as occupied
 func (b *Board) Lock(t Tetromino) {
 	cells := t.GetCells()
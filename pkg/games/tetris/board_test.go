@@ -287,3 +287,22 @@ func TestBoardIsGameOver(t *testing.T) {
 		})
 	}
 }
+
+func TestBoardDropPosition(t *testing.T) {
+	board := NewBoard()
+	board.Cells[BoardHeight-1][4].Occupied = true
+
+	// T piece cells at (4,5) and (3..5,6) land on the block at (4,19)
+	piece := Tetromino{Type: TetrominoT, Rotation: Rotation0, X: 3, Y: 5}
+	dropped := board.DropPosition(piece)
+
+	if dropped.Y != BoardHeight-3 {
+		t.Errorf("expected drop Y %d, got %d", BoardHeight-3, dropped.Y)
+	}
+	if dropped.X != piece.X || dropped.Rotation != piece.Rotation {
+		t.Error("drop position should only change Y")
+	}
+	if piece.Y != 5 {
+		t.Error("original piece should not be modified")
+	}
+}
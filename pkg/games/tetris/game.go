@@ -3,20 +3,33 @@ package tetris
 import (
 	"fmt"
 	"math/rand"
-	"os"
 	"time"
 
-	"golang.org/x/term"
-
+	"github.com/pracucci/idotmatrix-overclocked/pkg/games/gametime"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/games/input"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
 	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
 )
 
 // Game timing constants
 const (
 	RenderInterval = 100 * time.Millisecond // How often to render
-	DropInterval   = 800 * time.Millisecond // Gravity speed (piece drops every interval)
+	DropInterval   = 800 * time.Millisecond // Gravity speed at level 1 (piece drops every interval)
+	PreviewSize    = 3                      // Number of upcoming pieces shown in the next queue
+)
+
+// Level progression constants
+const (
+	LinesPerLevel      = 10                     // Lines to clear to advance a level
+	DropIntervalStep   = 70 * time.Millisecond  // Drop interval reduction per level
+	MinDropInterval    = 100 * time.Millisecond // Fastest gravity speed
+	SoftDropPointsCell = 1                      // Points per cell moved with a soft drop
+	HardDropPointsCell = 2                      // Points per cell moved with a hard drop
 )
 
+// lineClearPoints is the base score for clearing 0-4 lines at once, multiplied by the level
+var lineClearPoints = [5]int{0, 100, 300, 500, 800}
+
 // RandSource is an interface for random number generation (for testing)
 type RandSource interface {
 	Intn(n int) int
@@ -32,7 +45,12 @@ type GameState struct {
 	Board    *Board
 	Current  *Tetromino
 	Next     TetrominoType
+	Queue    []TetrominoType // Upcoming pieces after Next, in order
+	Hold     TetrominoType
+	HasHold  bool // Whether Hold contains a piece
+	HoldUsed bool // Whether hold was already used for the current piece
 	Lines    int
+	Score    int
 	GameOver bool
 }
 
@@ -78,16 +96,26 @@ func (s *GameState) TryRotate() bool {
 	return false
 }
 
-// HardDrop instantly drops the piece to the bottom and locks it
+// SoftDrop moves the current piece down by one row, awarding soft drop points
+// Returns true if the piece moved
+func (s *GameState) SoftDrop() bool {
+	if !s.TryMove(0, 1) {
+		return false
+	}
+	s.Score += SoftDropPointsCell
+	return true
+}
+
+// HardDrop instantly drops the piece to the bottom, awarding hard drop points
+// for each row dropped
 func (s *GameState) HardDrop() {
 	if s.Current == nil {
 		return
 	}
 
-	// Move down until we can't
-	for s.Board.IsValidPosition(s.Current.Move(0, 1)) {
-		*s.Current = s.Current.Move(0, 1)
-	}
+	dropped := s.Board.DropPosition(*s.Current)
+	s.Score += (dropped.Y - s.Current.Y) * HardDropPointsCell
+	*s.Current = dropped
 }
 
 // Tick advances the game by one gravity step
@@ -105,30 +133,40 @@ func (s *GameState) Tick() bool {
 	// Can't move down - lock the piece
 	s.Board.Lock(*s.Current)
 	s.Current = nil
+	s.HoldUsed = false
 	return true
 }
 
-// LockAndClear locks the current piece and clears lines
+// LockAndClear locks the current piece, clears lines and updates the score
+// using the level before the lines are added
 // Returns the number of lines cleared
 func (s *GameState) LockAndClear() int {
 	if s.Current != nil {
 		s.Board.Lock(*s.Current)
 		s.Current = nil
+		s.HoldUsed = false
 	}
 
 	linesCleared := s.Board.ClearLines()
 	if linesCleared > 0 {
+		s.Score += LineClearScore(linesCleared, s.Level())
 		s.Lines += linesCleared
 	}
 
 	return linesCleared
 }
 
-// SpawnPiece creates a new piece at the top of the board
+// SpawnPiece creates a new piece at the top of the board from Next, and
+// advances the queue appending nextType to it
 // Returns false if the piece cannot be placed (game over)
 func (s *GameState) SpawnPiece(nextType TetrominoType) bool {
 	piece := NewTetromino(s.Next)
-	s.Next = nextType
+	if len(s.Queue) > 0 {
+		s.Next = s.Queue[0]
+		s.Queue = append(s.Queue[1:], nextType)
+	} else {
+		s.Next = nextType
+	}
 
 	if !s.Board.IsValidPosition(piece) {
 		s.GameOver = true
@@ -139,6 +177,63 @@ func (s *GameState) SpawnPiece(nextType TetrominoType) bool {
 	return true
 }
 
+// Level returns the current level, starting at 1 and increasing every LinesPerLevel lines
+func (s *GameState) Level() int {
+	return 1 + s.Lines/LinesPerLevel
+}
+
+// DropInterval returns the gravity interval for the current level
+func (s *GameState) DropInterval() time.Duration {
+	return DropIntervalForLevel(s.Level())
+}
+
+// LineClearScore returns the points for clearing the given number of lines at once
+func LineClearScore(lines, level int) int {
+	if lines < 0 || lines >= len(lineClearPoints) {
+		return 0
+	}
+	return lineClearPoints[lines] * level
+}
+
+// DropIntervalForLevel returns the gravity interval for the given level
+func DropIntervalForLevel(level int) time.Duration {
+	return max(DropInterval-time.Duration(level-1)*DropIntervalStep, MinDropInterval)
+}
+
+// Preview returns the upcoming pieces in order, starting with Next
+func (s *GameState) Preview() []TetrominoType {
+	return append([]TetrominoType{s.Next}, s.Queue...)
+}
+
+// HoldPiece swaps the current piece with the held one. When nothing is held
+// yet, the current piece is stored and Current is left nil so that the caller
+// spawns the next piece. Hold can only be used once until a piece is locked.
+// Returns true if the hold was performed
+func (s *GameState) HoldPiece() bool {
+	if s.Current == nil || s.HoldUsed || s.GameOver {
+		return false
+	}
+
+	held := s.Current.Type
+	s.HoldUsed = true
+
+	if !s.HasHold {
+		s.Hold = held
+		s.HasHold = true
+		s.Current = nil
+		return true
+	}
+
+	piece := NewTetromino(s.Hold)
+	s.Hold = held
+	if !s.Board.IsValidPosition(piece) {
+		s.GameOver = true
+		return true
+	}
+	s.Current = &piece
+	return true
+}
+
 // CheckGameOver checks if the board is in a game over state
 func (s *GameState) CheckGameOver() bool {
 	if s.Board.IsGameOver() {
@@ -148,15 +243,50 @@ func (s *GameState) CheckGameOver() bool {
 	return false
 }
 
+// Timing controls the game speed
+type Timing struct {
+	RenderInterval time.Duration // How often to render
+	DropInterval   time.Duration // Gravity speed at level 1, later levels are proportionally faster
+}
+
+// DefaultTiming returns the default render and gravity intervals
+func DefaultTiming() Timing {
+	return Timing{
+		RenderInterval: RenderInterval,
+		DropInterval:   DropInterval,
+	}
+}
+
+// Validate checks that both intervals are positive
+func (t Timing) Validate() error {
+	if t.RenderInterval <= 0 {
+		return fmt.Errorf("render interval must be positive, got %v", t.RenderInterval)
+	}
+	if t.DropInterval <= 0 {
+		return fmt.Errorf("drop interval must be positive, got %v", t.DropInterval)
+	}
+	return nil
+}
+
 // Game orchestrates gameplay with I/O dependencies
 type Game struct {
 	state      *GameState
 	device     protocol.DeviceConnection
 	renderer   *Renderer
 	background []byte
-	inputChan  chan rune
+	input      input.Source
 	running    bool
 	randSource RandSource
+	bag        *Bag
+	onGameOver func(score int)
+	autoPlay   bool
+	timing     Timing
+	clock      gametime.Clock // Wall clock unless replaced with SetClock
+
+	// Game loop timestamps
+	lastDrop     time.Time
+	lastRender   time.Time
+	lastAutoMove time.Time
 }
 
 // NewGame creates a new Tetris game
@@ -164,172 +294,235 @@ func NewGame(device protocol.DeviceConnection) *Game {
 	return &Game{
 		device:     device,
 		renderer:   NewRenderer(device),
-		inputChan:  make(chan rune, 10),
+		input:      input.NewTerminalInput(),
 		running:    true,
 		randSource: defaultRand{},
+		timing:     DefaultTiming(),
+		clock:      gametime.Real(),
 	}
 }
 
+// SetClock replaces the wall clock used for timing, e.g. with a fake clock in
+// tests so the game loop runs without delays
+func (g *Game) SetClock(c gametime.Clock) {
+	g.clock = c
+	g.renderer.Clock = c
+}
+
+// SetInput replaces the terminal as the source of key presses, e.g. with an
+// input.ChannelInput in tests or for a remote controller
+func (g *Game) SetInput(src input.Source) {
+	g.input = src
+}
+
+// SetTiming sets the render and gravity intervals, e.g. to slow the game down
+// on links that can't keep up
+func (g *Game) SetTiming(t Timing) {
+	g.timing = t
+}
+
+// dropInterval returns the gravity interval for the current level, scaling
+// the default level speeds to the configured drop interval
+func (g *Game) dropInterval() time.Duration {
+	// Multiplying two durations overflows int64 past a few seconds squared
+	return time.Duration(float64(g.state.DropInterval()) * float64(g.timing.DropInterval) / float64(DropInterval))
+}
+
+// SetUploadConfig sets the delays used when sending pixels to the device
+func (g *Game) SetUploadConfig(cfg protocol.UploadConfig) {
+	g.renderer.Upload = cfg
+}
+
+// SetAutoPlay lets a bot play, pressing the keys returned by AutoKey. Games
+// start and restart on their own, and Q still quits.
+func (g *Game) SetAutoPlay(enabled bool) {
+	g.autoPlay = enabled
+}
+
+// SetRecorder records the frames shown during the game, e.g. to replay a demo
+func (g *Game) SetRecorder(r *graphic.Recorder) {
+	g.renderer.Recorder = r
+}
+
+// SetOnGameOver sets a function called with the final score when a game is
+// over, e.g. to record high scores. It isn't called when the player quits.
+func (g *Game) SetOnGameOver(fn func(score int)) {
+	g.onGameOver = fn
+}
+
 // reset initializes the game state for a new game
 func (g *Game) reset() {
 	g.state = NewGameState()
-	g.state.Next = TetrominoType(g.randSource.Intn(int(TetrominoCount)))
+	g.bag = NewBag(g.randSource)
+	g.state.Next = g.bag.Next()
+	for i := 1; i < PreviewSize; i++ {
+		g.state.Queue = append(g.state.Queue, g.bag.Next())
+	}
 	g.background = GenerateGameBackground()
 }
 
 // spawnNextPiece spawns a new piece and generates the next piece type
 func (g *Game) spawnNextPiece() bool {
-	nextType := TetrominoType(g.randSource.Intn(int(TetrominoCount)))
-	return g.state.SpawnPiece(nextType)
+	return g.state.SpawnPiece(g.bag.Next())
 }
 
-// handleInput processes keyboard input
+// handleInput processes a pending key press, if any
 func (g *Game) handleInput() {
 	select {
-	case key := <-g.inputChan:
-		switch key {
-		case 'a', 'A':
-			g.state.TryMove(-1, 0)
-		case 'd', 'D':
-			g.state.TryMove(1, 0)
-		case 'w', 'W':
-			g.state.TryRotate()
-		case 's', 'S':
-			g.state.TryMove(0, 1) // Soft drop
-		case ' ':
-			g.state.HardDrop()
-			g.state.LockAndClear()
+	case key := <-g.input.Keys():
+		g.handleKey(key)
+	default:
+	}
+}
+
+// handleKey applies a key press to the game
+func (g *Game) handleKey(key rune) {
+	switch key {
+	case 'a', 'A':
+		g.state.TryMove(-1, 0)
+	case 'd', 'D':
+		g.state.TryMove(1, 0)
+	case 'w', 'W':
+		g.state.TryRotate()
+	case 's', 'S':
+		g.state.SoftDrop()
+	case ' ':
+		g.state.HardDrop()
+		g.state.LockAndClear()
+		g.spawnNextPiece()
+	case 'g', 'G':
+		g.renderer.ShowGhost = !g.renderer.ShowGhost
+	case 'c', 'C':
+		if g.state.HoldPiece() && g.state.Current == nil && !g.state.GameOver {
 			g.spawnNextPiece()
-		case 'q', 'Q':
-			g.running = false
 		}
-	default:
+	case 'q', 'Q':
+		g.running = false
 	}
 }
 
 // render draws the current game state to the display
 func (g *Game) render() error {
 	g.renderer.RenderState(g.state.Board, g.state.Current, g.background)
+	g.renderer.RenderPanels(g.state)
 	return g.renderer.Flush()
 }
 
 // showImage displays a static image on the device
 func (g *Game) showImage(rgbData []byte) error {
+	if g.renderer.Recorder != nil {
+		g.renderer.Recorder.Capture(rgbData, g.clock.Now())
+	}
 	if err := protocol.SetDrawMode(g.device, 1); err != nil {
 		return err
 	}
 	if err := protocol.SendImage(g.device, rgbData); err != nil {
 		return err
 	}
-	time.Sleep(500 * time.Millisecond)
+	g.clock.Sleep(500 * time.Millisecond)
 	return nil
 }
 
-// waitForKey blocks until a key is pressed
+// waitForKey blocks until a key is pressed. In auto-play mode it waits for
+// AutoPlayPause instead, returning 0 unless a key was pressed meanwhile.
 func (g *Game) waitForKey() rune {
-	return <-g.inputChan
-}
-
-// startInputReader starts the keyboard input goroutine
-func (g *Game) startInputReader() func() {
-	oldState, err := term.MakeRaw(int(os.Stdin.Fd()))
-	if err != nil {
-		fmt.Printf("Warning: could not set raw mode: %v\n", err)
-		return func() {}
+	if !g.autoPlay {
+		return <-g.input.Keys()
 	}
+	select {
+	case key := <-g.input.Keys():
+		return key
+	case <-g.clock.After(AutoPlayPause):
+		return 0
+	}
+}
 
-	go func() {
-		buf := make([]byte, 3)
-		for g.running {
-			n, err := os.Stdin.Read(buf)
-			if err != nil {
-				return
-			}
-			if n > 0 {
-				// Handle escape sequences for arrow keys
-				if n == 3 && buf[0] == 27 && buf[1] == 91 {
-					switch buf[2] {
-					case 65: // up arrow
-						g.inputChan <- 'w'
-					case 66: // down arrow
-						g.inputChan <- 's'
-					case 67: // right arrow
-						g.inputChan <- 'd'
-					case 68: // left arrow
-						g.inputChan <- 'a'
-					}
-				} else {
-					g.inputChan <- rune(buf[0])
-				}
-			}
-		}
-	}()
-
-	return func() {
-		term.Restore(int(os.Stdin.Fd()), oldState)
+// autoPress applies the key the bot presses next, if any
+func (g *Game) autoPress() {
+	if key := AutoKey(g.state); key != 0 {
+		g.handleKey(key)
 	}
 }
 
-// runGame runs the main game loop
-func (g *Game) runGame() {
+// runGame runs the main game loop until the game is over or the player quits.
+// Returns an error if the display can't be updated anymore (e.g. the BLE link
+// dropped)
+func (g *Game) runGame() error {
 	// Initialize renderer with background
 	g.renderer.SetPrevBuffer(g.background)
 	g.renderer.SetCurrBuffer(g.background)
 
 	// Display initial background
 	if err := g.showImage(g.background); err != nil {
-		return
+		return err
 	}
 
 	// Spawn first piece
 	if !g.spawnNextPiece() {
-		return
+		return nil
 	}
 
-	lastDrop := time.Now()
-	lastRender := time.Now()
+	now := g.clock.Now()
+	g.lastDrop = now
+	g.lastRender = now
+	g.lastAutoMove = now
 
 	for g.running && !g.state.GameOver {
-		now := time.Now()
-
-		// Handle input
-		g.handleInput()
-
-		// Gravity
-		if now.Sub(lastDrop) >= DropInterval {
-			locked := g.state.Tick()
-			if locked {
-				g.state.LockAndClear()
-				if g.state.CheckGameOver() {
-					break
-				}
-				if !g.spawnNextPiece() {
-					break
-				}
-			}
-			lastDrop = now
+		if err := g.update(g.clock.Now()); err != nil {
+			return err
 		}
 
-		// Render
-		if now.Sub(lastRender) >= RenderInterval {
-			if err := g.render(); err != nil {
-				fmt.Printf("Render error: %v\n", err)
+		// Small sleep to avoid busy loop
+		g.clock.Sleep(10 * time.Millisecond)
+	}
+	return nil
+}
+
+// update runs one iteration of the game loop at now: the bot's key press,
+// input, gravity and rendering, each at its own interval. Returns an error if
+// rendering failed
+func (g *Game) update(now time.Time) error {
+	// Let the bot press its next key
+	if g.autoPlay && now.Sub(g.lastAutoMove) >= AutoMoveInterval {
+		g.autoPress()
+		g.lastAutoMove = now
+	}
+
+	// Handle input
+	g.handleInput()
+
+	// Gravity
+	if now.Sub(g.lastDrop) >= g.dropInterval() {
+		locked := g.state.Tick()
+		if locked {
+			g.state.LockAndClear()
+			if g.state.CheckGameOver() {
+				return nil
+			}
+			if !g.spawnNextPiece() {
+				return nil
 			}
-			lastRender = now
 		}
+		g.lastDrop = now
+	}
 
-		// Small sleep to avoid busy loop
-		time.Sleep(10 * time.Millisecond)
+	// Render
+	if now.Sub(g.lastRender) >= g.timing.RenderInterval {
+		if err := g.render(); err != nil {
+			return fmt.Errorf("failed to draw on the display: %w", err)
+		}
+		g.lastRender = now
 	}
+	return nil
 }
 
 // Run starts the main game loop
 func (g *Game) Run() error {
 	fmt.Println("Starting Tetris!")
-	fmt.Println("Controls: A/Left=Left, D/Right=Right, W/Up=Rotate, S/Down=Soft drop, Space=Hard drop, Q=Quit")
+	fmt.Println("Controls: A/Left=Left, D/Right=Right, W/Up=Rotate, S/Down=Soft drop, Space=Hard drop, C=Hold, G=Toggle ghost, Q=Quit")
 
-	cleanup := g.startInputReader()
-	defer cleanup()
+	stop := g.input.Start()
+	defer stop()
 
 	for g.running {
 		// Show cover image and wait for key to start
@@ -345,7 +538,9 @@ func (g *Game) Run() error {
 
 		// Reset and start game
 		g.reset()
-		g.runGame()
+		if err := g.runGame(); err != nil {
+			return err
+		}
 
 		if !g.running {
 			break
@@ -355,7 +550,10 @@ func (g *Game) Run() error {
 		if err := g.showImage(GenerateGameOverImage()); err != nil {
 			return err
 		}
-		fmt.Printf("Game Over! Lines: %d\n", g.state.Lines)
+		fmt.Printf("Game Over! Score: %d, Lines: %d, Level: %d\n", g.state.Score, g.state.Lines, g.state.Level())
+		if g.onGameOver != nil {
+			g.onGameOver(g.state.Score)
+		}
 		fmt.Print("Press any key to restart (Q to quit)...")
 		key = g.waitForKey()
 		fmt.Println()
@@ -1,7 +1,17 @@
 package tetris
 
 import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/games/gametime"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/games/input"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
 )
 
 // mockRand provides deterministic random numbers for testing
@@ -399,3 +409,411 @@ func TestGameStateCheckGameOver(t *testing.T) {
 		t.Error("GameOver flag should be set")
 	}
 }
+
+func TestGameStateSpawnPieceAdvancesQueue(t *testing.T) {
+	state := NewGameState()
+	state.Next = TetrominoT
+	state.Queue = []TetrominoType{TetrominoO, TetrominoS}
+
+	if !state.SpawnPiece(TetrominoL) {
+		t.Fatal("expected piece to spawn")
+	}
+
+	if state.Current.Type != TetrominoT {
+		t.Errorf("expected current piece T, got %d", state.Current.Type)
+	}
+	expected := []TetrominoType{TetrominoO, TetrominoS, TetrominoL}
+	preview := state.Preview()
+	if len(preview) != len(expected) {
+		t.Fatalf("expected preview of %d pieces, got %d", len(expected), len(preview))
+	}
+	for i := range expected {
+		if preview[i] != expected[i] {
+			t.Errorf("preview[%d]: expected %d, got %d", i, expected[i], preview[i])
+		}
+	}
+}
+
+func TestGameStateHoldPiece(t *testing.T) {
+	state := NewGameState()
+	state.Next = TetrominoT
+	state.SpawnPiece(TetrominoO)
+
+	// First hold stores the current piece and leaves room for the next one
+	if !state.HoldPiece() {
+		t.Fatal("first hold should succeed")
+	}
+	if !state.HasHold || state.Hold != TetrominoT {
+		t.Errorf("expected T to be held, got hasHold=%v hold=%d", state.HasHold, state.Hold)
+	}
+	if state.Current != nil {
+		t.Error("current piece should be nil after the first hold")
+	}
+
+	// Hold can't be used again before the next piece locks
+	state.SpawnPiece(TetrominoS)
+	if state.HoldPiece() {
+		t.Error("hold should not be usable twice before locking")
+	}
+	if state.Current.Type != TetrominoO || state.Hold != TetrominoT {
+		t.Errorf("failed hold should not change pieces, got current=%d hold=%d", state.Current.Type, state.Hold)
+	}
+
+	// Locking the piece allows hold again, swapping with the held piece
+	state.HardDrop()
+	state.LockAndClear()
+	state.SpawnPiece(TetrominoZ)
+	if !state.HoldPiece() {
+		t.Fatal("hold should be usable again after locking")
+	}
+	if state.Current == nil || state.Current.Type != TetrominoT {
+		t.Error("expected held T to become the current piece")
+	}
+	if state.Hold != TetrominoS {
+		t.Errorf("expected S to be held, got %d", state.Hold)
+	}
+	if state.Current.Y != 0 {
+		t.Errorf("swapped piece should spawn at the top, got Y=%d", state.Current.Y)
+	}
+}
+
+func TestGameStateHoldPieceAfterTickLock(t *testing.T) {
+	state := NewGameState()
+	state.Next = TetrominoI
+	state.SpawnPiece(TetrominoO)
+	state.HoldUsed = true
+
+	state.HardDrop()
+	if !state.Tick() {
+		t.Fatal("expected piece to lock")
+	}
+	if state.HoldUsed {
+		t.Error("locking the piece should reset HoldUsed")
+	}
+}
+
+func TestLineClearScore(t *testing.T) {
+	tests := []struct {
+		lines    int
+		level    int
+		expected int
+	}{
+		{lines: 0, level: 1, expected: 0},
+		{lines: 1, level: 1, expected: 100},
+		{lines: 2, level: 1, expected: 300},
+		{lines: 3, level: 1, expected: 500},
+		{lines: 4, level: 1, expected: 800},
+		{lines: 4, level: 3, expected: 2400},
+	}
+
+	for _, tt := range tests {
+		if got := LineClearScore(tt.lines, tt.level); got != tt.expected {
+			t.Errorf("LineClearScore(%d, %d) = %d, expected %d", tt.lines, tt.level, got, tt.expected)
+		}
+	}
+
+	if tetris, singles := LineClearScore(4, 1), 4*LineClearScore(1, 1); tetris <= singles {
+		t.Errorf("a tetris (%d) should score more than four singles (%d)", tetris, singles)
+	}
+}
+
+func TestGameStateLockAndClearScore(t *testing.T) {
+	state := NewGameState()
+	state.Lines = 10 // Level 2
+
+	// Fill the bottom 4 rows except column 0, then drop a vertical I piece there
+	for y := BoardHeight - 4; y < BoardHeight; y++ {
+		for x := 1; x < BoardWidth; x++ {
+			state.Board.Cells[y][x].Occupied = true
+		}
+	}
+	piece := Tetromino{Type: TetrominoI, Rotation: Rotation270, X: -1, Y: BoardHeight - 4}
+	state.Current = &piece
+
+	if cleared := state.LockAndClear(); cleared != 4 {
+		t.Fatalf("expected 4 lines cleared, got %d", cleared)
+	}
+	if state.Score != 1600 {
+		t.Errorf("expected score 1600 for a tetris at level 2, got %d", state.Score)
+	}
+	if state.Level() != 2 {
+		t.Errorf("expected level 2 after 14 lines, got %d", state.Level())
+	}
+}
+
+func TestGameStateDropBonuses(t *testing.T) {
+	state := NewGameState()
+	piece := NewTetromino(TetrominoO)
+	state.Current = &piece
+
+	state.SoftDrop()
+	state.SoftDrop()
+	if state.Score != 2*SoftDropPointsCell {
+		t.Errorf("expected soft drop score %d, got %d", 2*SoftDropPointsCell, state.Score)
+	}
+
+	// O piece cells are on rows 0-1, so it lands at Y = BoardHeight-2
+	startY := state.Current.Y
+	state.HardDrop()
+	expected := 2*SoftDropPointsCell + (BoardHeight-2-startY)*HardDropPointsCell
+	if state.Score != expected {
+		t.Errorf("expected score %d after hard drop, got %d", expected, state.Score)
+	}
+}
+
+func TestGameStateLevelAndDropInterval(t *testing.T) {
+	state := NewGameState()
+	if state.Level() != 1 || state.DropInterval() != DropInterval {
+		t.Errorf("expected level 1 with drop interval %v, got level %d with %v", DropInterval, state.Level(), state.DropInterval())
+	}
+
+	prev := state.DropInterval()
+	for lines := LinesPerLevel; lines <= 5*LinesPerLevel; lines += LinesPerLevel {
+		state.Lines = lines
+		if state.DropInterval() >= prev {
+			t.Errorf("drop interval at level %d (%v) should be shorter than %v", state.Level(), state.DropInterval(), prev)
+		}
+		prev = state.DropInterval()
+	}
+
+	if got := DropIntervalForLevel(100); got != MinDropInterval {
+		t.Errorf("drop interval should be capped at %v, got %v", MinDropInterval, got)
+	}
+}
+
+func TestGameSpawnsPiecesFromBag(t *testing.T) {
+	g := NewGame(nil)
+	g.randSource = &mockRand{values: []int{3, 1, 4, 1, 5, 0, 2, 6, 5, 3, 5, 0}}
+	g.reset()
+
+	// Reset already dealt the preview, so the first 14 spawns come from the first two bags
+	counts := make(map[TetrominoType]int)
+	for i := 0; i < 2*int(TetrominoCount); i++ {
+		if !g.spawnNextPiece() {
+			t.Fatalf("spawn %d failed", i)
+		}
+		counts[g.state.Current.Type]++
+		g.state.Current = nil
+	}
+
+	for piece := TetrominoType(0); piece < TetrominoCount; piece++ {
+		if counts[piece] != 2 {
+			t.Errorf("piece %d spawned %d times in 14 spawns, expected 2", piece, counts[piece])
+		}
+	}
+}
+
+func TestGameChannelInput(t *testing.T) {
+	g := NewGame(nil)
+	in := input.NewChannelInput()
+	g.SetInput(in)
+	g.randSource = &mockRand{}
+	g.reset()
+	g.spawnNextPiece()
+	startX := g.state.Current.X
+
+	// No key pending leaves the piece where it is
+	g.handleInput()
+	if g.state.Current.X != startX {
+		t.Fatalf("piece moved to x=%d without input, want %d", g.state.Current.X, startX)
+	}
+
+	in.Send('d')
+	g.handleInput()
+	if g.state.Current.X != startX+1 {
+		t.Errorf("piece at x=%d after 'd', want %d", g.state.Current.X, startX+1)
+	}
+
+	in.Send('q')
+	g.handleInput()
+	if g.running {
+		t.Error("game still running after 'q'")
+	}
+}
+
+// nopDevice is a device connection that discards everything written to it
+type nopDevice struct{}
+
+func (nopDevice) WritePacket(packet []byte) error { return nil }
+func (nopDevice) ReadResponse() ([]byte, error)   { return nil, nil }
+func (nopDevice) PollResponse() ([]byte, bool)    { return nil, false }
+func (nopDevice) DrainResponses()                 {}
+
+func TestGameRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tetris.gif")
+	recorder := graphic.NewRecorder(path)
+
+	g := NewGame(nopDevice{})
+	g.SetUploadConfig(protocol.UploadConfig{})
+	g.SetRecorder(recorder)
+	g.randSource = &mockRand{}
+	g.reset()
+	g.spawnNextPiece()
+
+	// The first frame, then one frame per row the piece drops
+	if err := g.render(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		g.state.Tick()
+		if err := g.render(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Rendering an unchanged state doesn't add a frame
+	if err := g.render(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := recorder.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta, err := graphic.GetGIFMetadata(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Frames != 4 {
+		t.Errorf("recorded %d frames, want 4", meta.Frames)
+	}
+}
+
+func TestGameTimingDropInterval(t *testing.T) {
+	// drops simulates one second of game loop iterations every 10ms and
+	// returns how many rows the first piece fell
+	drops := func(timing Timing) int {
+		g := NewGame(nopDevice{})
+		g.SetUploadConfig(protocol.UploadConfig{})
+		g.SetTiming(timing)
+		g.randSource = &mockRand{}
+		g.reset()
+		g.spawnNextPiece()
+
+		start := time.Now()
+		g.lastDrop, g.lastRender = start, start
+		for elapsed := 10 * time.Millisecond; elapsed <= time.Second; elapsed += 10 * time.Millisecond {
+			if err := g.update(start.Add(elapsed)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return g.state.Current.Y
+	}
+
+	if got := drops(DefaultTiming()); got != 1 {
+		t.Errorf("default timing: piece fell %d rows in 1s, want 1", got)
+	}
+
+	fast := DefaultTiming()
+	fast.DropInterval = 100 * time.Millisecond
+	if got := drops(fast); got != 10 {
+		t.Errorf("100ms drop interval: piece fell %d rows in 1s, want 10", got)
+	}
+}
+
+func TestGameDropIntervalScalesWithLevel(t *testing.T) {
+	g := NewGame(nil)
+	g.SetTiming(Timing{RenderInterval: RenderInterval, DropInterval: DropInterval / 2})
+	g.reset()
+
+	if got := g.dropInterval(); got != DropInterval/2 {
+		t.Errorf("level 1 drop interval = %v, want %v", got, DropInterval/2)
+	}
+	g.state.Lines = 5 * LinesPerLevel
+	if got, want := g.dropInterval(), DropIntervalForLevel(6)/2; got != want {
+		t.Errorf("level 6 drop interval = %v, want %v", got, want)
+	}
+
+	// Long drop intervals must not overflow
+	g.SetTiming(Timing{RenderInterval: RenderInterval, DropInterval: time.Hour})
+	g.state.Lines = 0
+	if got := g.dropInterval(); got != time.Hour {
+		t.Errorf("level 1 drop interval = %v, want %v", got, time.Hour)
+	}
+}
+
+func TestTimingValidate(t *testing.T) {
+	if err := DefaultTiming().Validate(); err != nil {
+		t.Errorf("default timing should be valid: %v", err)
+	}
+	if err := (Timing{RenderInterval: RenderInterval}).Validate(); err == nil {
+		t.Error("expected an error for a zero drop interval")
+	}
+	if err := (Timing{DropInterval: DropInterval}).Validate(); err == nil {
+		t.Error("expected an error for a zero render interval")
+	}
+}
+
+func TestGameRunsOnFakeClock(t *testing.T) {
+	clock := gametime.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	start := clock.Now()
+
+	g := NewGame(nopDevice{})
+	g.SetUploadConfig(protocol.UploadConfig{})
+	g.SetClock(clock)
+	g.randSource = &mockRand{}
+	g.reset()
+
+	// Without input the pieces stack up in the middle until the game is over
+	wallStart := time.Now()
+	if err := g.runGame(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !g.state.GameOver {
+		t.Fatal("expected the game to be over")
+	}
+	// Every piece falls at least a few rows at the level 1 drop interval
+	if elapsed := clock.Now().Sub(start); elapsed < 10*DropInterval {
+		t.Errorf("fake clock advanced by %v, want at least %v", elapsed, 10*DropInterval)
+	}
+	if time.Since(wallStart) > 5*time.Second {
+		t.Errorf("game loop took %v of wall clock time", time.Since(wallStart))
+	}
+}
+
+var errDisconnected = errors.New("disconnected")
+
+// failingDevice accepts failAfter packets, then fails every write as if the
+// BLE link dropped
+type failingDevice struct {
+	nopDevice
+	writes    int
+	failAfter int
+}
+
+func (d *failingDevice) WritePacket(packet []byte) error {
+	d.writes++
+	if d.writes > d.failAfter {
+		return errDisconnected
+	}
+	return nil
+}
+
+func TestGameStopsOnDeviceError(t *testing.T) {
+	newGame := func(device protocol.DeviceConnection) *Game {
+		g := NewGame(device)
+		g.SetUploadConfig(protocol.UploadConfig{})
+		g.SetClock(gametime.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+		g.randSource = &mockRand{}
+		g.reset()
+		return g
+	}
+
+	// Count the writes showing the background, then drop the link on the first render
+	counter := &failingDevice{failAfter: math.MaxInt}
+	if err := newGame(counter).showImage(GenerateGameBackground()); err != nil {
+		t.Fatal(err)
+	}
+	device := &failingDevice{failAfter: counter.writes}
+
+	err := newGame(device).runGame()
+	if !errors.Is(err, errDisconnected) {
+		t.Fatalf("runGame() error = %v, want the device error", err)
+	}
+	if device.writes != device.failAfter+1 {
+		t.Errorf("%d writes after the failure, want the game to stop", device.writes-device.failAfter-1)
+	}
+}
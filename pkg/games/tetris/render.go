@@ -22,15 +22,8 @@ func drawTetromino(img []byte, pieceType TetrominoType, x, y int, scale int) {
 
 // GenerateCoverImage creates the title screen with "TETRIS" text and decorative pieces
 func GenerateCoverImage() []byte {
-	img := make([]byte, graphic.DisplayWidth*graphic.DisplayWidth*3)
-
 	// Dark purple gradient background
-	for y := 0; y < graphic.DisplayWidth; y++ {
-		for x := 0; x < graphic.DisplayWidth; x++ {
-			intensity := uint8(10 + y/8)
-			graphic.SetPixel(img, x, y, graphic.Color{intensity / 4, 0, intensity / 2})
-		}
-	}
+	img := graphic.NewBufferWithGradient(graphic.Color{2, 0, 5}, graphic.Color{4, 0, 8})
 
 	// Draw "TETRIS" title (6 chars * 6 pixels = 36 pixels wide, center at (64-36)/2 = 14)
 	// Draw shadow first
@@ -59,15 +52,8 @@ func GenerateCoverImage() []byte {
 
 // GenerateGameOverImage creates the game over screen
 func GenerateGameOverImage() []byte {
-	img := make([]byte, graphic.DisplayWidth*graphic.DisplayWidth*3)
-
 	// Dark red tinted background
-	for y := 0; y < graphic.DisplayWidth; y++ {
-		for x := 0; x < graphic.DisplayWidth; x++ {
-			intensity := uint8(20 + y/4)
-			graphic.SetPixel(img, x, y, graphic.Color{intensity / 2, 0, 0})
-		}
-	}
+	img := graphic.NewBufferWithGradient(graphic.Color{10, 0, 0}, graphic.Color{17, 0, 0})
 
 	// Draw "GAME" and "OVER" text centered
 	// "GAME" is 4 chars * 6 = 24 pixels, center at (64-24)/2 = 20
@@ -79,6 +65,9 @@ func GenerateGameOverImage() []byte {
 	return img
 }
 
+// BackgroundColor is the dark color filling the display around the board
+var BackgroundColor = graphic.Color{10, 10, 15}
+
 // GenerateGameBackground creates the background for gameplay
 func GenerateGameBackground() []byte {
 	img := make([]byte, graphic.DisplayWidth*graphic.DisplayWidth*3)
@@ -86,7 +75,7 @@ func GenerateGameBackground() []byte {
 	// Fill with dark background
 	for y := 0; y < graphic.DisplayWidth; y++ {
 		for x := 0; x < graphic.DisplayWidth; x++ {
-			graphic.SetPixel(img, x, y, graphic.Color{10, 10, 15})
+			graphic.SetPixel(img, x, y, BackgroundColor)
 		}
 	}
 
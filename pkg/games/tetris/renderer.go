@@ -1,8 +1,10 @@
 package tetris
 
 import (
-	"time"
+	"strconv"
 
+	"github.com/pracucci/idotmatrix-overclocked/pkg/games/gametime"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/games/hud"
 	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
 	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
 )
@@ -12,27 +14,55 @@ const (
 	BlockSize    = 3  // Each tetris cell = 3x3 display pixels
 	BoardOffsetX = 17 // X offset to center board: (64 - 10*3) / 2
 	BoardOffsetY = 2  // Y offset to center board: (64 - 20*3) / 2
+
+	// Side panels (pieces are drawn at scale 2, up to 8x4 pixels)
+	HoldPanelX    = 4  // Hold piece in the left margin
+	NextPanelX    = 52 // Next queue in the right margin
+	PanelY        = 4  // Y of the first piece in both panels
+	PanelSpacingY = 8  // Vertical distance between pieces in the next queue
+
+	// Score and level (5x7 font, 2 digits per row to fit the 16 pixel margins)
+	ScorePanelX  = 2  // Score in the left margin, below the hold piece
+	ScorePanelY  = 20 // Y of the first row of score digits
+	LevelPanelX  = 50 // Level in the right margin, below the next queue
+	LevelPanelY  = 40 // Y of the level digits
+	DigitsPerRow = 2  // Digits drawn on each row
+
+	GhostDimFactor = 4 // Ghost piece color is the piece color divided by this factor
 )
 
 // Renderer handles diff-based rendering to the device
 type Renderer struct {
-	device     protocol.DeviceConnection
-	prevBuffer [graphic.DisplayWidth * graphic.DisplayWidth * 3]byte
-	currBuffer [graphic.DisplayWidth * graphic.DisplayWidth * 3]byte
+	*protocol.DiffRenderer
+
+	// ShowGhost draws a dimmed outline where the current piece would land
+	ShowGhost bool
+
+	// Recorder, if set, captures every flushed frame
+	Recorder *graphic.Recorder
+
+	// Clock times the upload delays and recorded frames (nil uses the wall clock)
+	Clock gametime.Clock
 }
 
-// NewRenderer creates a new renderer
+// NewRenderer creates a new renderer with the ghost piece enabled
 func NewRenderer(device protocol.DeviceConnection) *Renderer {
 	return &Renderer{
-		device: device,
+		DiffRenderer: protocol.NewDiffRenderer(device),
+		ShowGhost:    true,
 	}
 }
 
+// GhostColor returns the dimmed color used to draw the ghost of a piece color
+func GhostColor(color graphic.Color) graphic.Color {
+	return graphic.Color{color[0] / GhostDimFactor, color[1] / GhostDimFactor, color[2] / GhostDimFactor}
+}
+
 // RenderState converts game state to the pixel buffer
-// This is a pure function that updates currBuffer without I/O
+// This is a pure function that updates the buffer without I/O
 func (r *Renderer) RenderState(board *Board, current *Tetromino, background []byte) {
 	// Start with background
-	copy(r.currBuffer[:], background)
+	copy(r.Buffer(), background)
 
 	// Draw locked pieces on the board
 	for y := 0; y < BoardHeight; y++ {
@@ -44,6 +74,17 @@ func (r *Renderer) RenderState(board *Board, current *Tetromino, background []by
 		}
 	}
 
+	// Draw the ghost piece at the hard-drop landing position, below the current piece
+	if current != nil && r.ShowGhost {
+		ghost := board.DropPosition(*current)
+		color := GhostColor(current.GetColor())
+		for _, cell := range ghost.GetCells() {
+			if cell.Y >= 0 {
+				r.drawGhostBlock(cell.X, cell.Y, color)
+			}
+		}
+	}
+
 	// Draw current piece
 	if current != nil {
 		cells := current.GetCells()
@@ -56,6 +97,39 @@ func (r *Renderer) RenderState(board *Board, current *Tetromino, background []by
 	}
 }
 
+// Score and level colors
+var (
+	ScoreColor = graphic.White
+	LevelColor = graphic.Cyan
+)
+
+// RenderPanels draws the held piece and the score in the left margin, and the
+// next queue and the level in the right margin
+func (r *Renderer) RenderPanels(state *GameState) {
+	if state.HasHold {
+		drawTetromino(r.Buffer(), state.Hold, HoldPanelX, PanelY, 2)
+	}
+	for i, pieceType := range state.Preview() {
+		drawTetromino(r.Buffer(), pieceType, NextPanelX, PanelY+i*PanelSpacingY, 2)
+	}
+
+	hud.Draw(r.Buffer(), strconv.Itoa(state.Score), panelHUD(ScorePanelX, ScorePanelY, ScoreColor))
+	hud.Draw(r.Buffer(), strconv.Itoa(state.Level()), panelHUD(LevelPanelX, LevelPanelY, LevelColor))
+}
+
+// panelHUD returns the options of a number strip at (x, y), wrapping every
+// DigitsPerRow digits
+func panelHUD(x, y int, color graphic.Color) hud.Options {
+	return hud.Options{
+		Corner:     hud.TopLeft,
+		OffsetX:    x,
+		OffsetY:    y,
+		TextColor:  color,
+		Background: BackgroundColor,
+		MaxChars:   DigitsPerRow,
+	}
+}
+
 // drawBlock draws a single tetris block (3x3 pixels) on the buffer
 func (r *Renderer) drawBlock(boardX, boardY int, color graphic.Color) {
 	// Convert board coordinates to display coordinates
@@ -63,80 +137,49 @@ func (r *Renderer) drawBlock(boardX, boardY int, color graphic.Color) {
 	displayY := BoardOffsetY + boardY*BlockSize
 
 	// Draw 3x3 block
+	buf := r.Buffer()
 	for dy := 0; dy < BlockSize; dy++ {
 		for dx := 0; dx < BlockSize; dx++ {
 			px := displayX + dx
 			py := displayY + dy
 			if px >= 0 && px < graphic.DisplayWidth && py >= 0 && py < graphic.DisplayWidth {
 				offset := (py*graphic.DisplayWidth + px) * 3
-				r.currBuffer[offset] = color[0]
-				r.currBuffer[offset+1] = color[1]
-				r.currBuffer[offset+2] = color[2]
+				buf[offset] = color[0]
+				buf[offset+1] = color[1]
+				buf[offset+2] = color[2]
 			}
 		}
 	}
 }
 
-// ComputeDiff finds changed pixels grouped by color
-// Returns a map of color to list of points that changed to that color
-func (r *Renderer) ComputeDiff() map[graphic.Color][]graphic.Point {
-	diff := make(map[graphic.Color][]graphic.Point)
-
-	for y := 0; y < graphic.DisplayWidth; y++ {
-		for x := 0; x < graphic.DisplayWidth; x++ {
-			offset := (y*graphic.DisplayWidth + x) * 3
-			prevR, prevG, prevB := r.prevBuffer[offset], r.prevBuffer[offset+1], r.prevBuffer[offset+2]
-			currR, currG, currB := r.currBuffer[offset], r.currBuffer[offset+1], r.currBuffer[offset+2]
+// drawGhostBlock draws the outline of a single tetris block (3x3 pixels with an empty center)
+func (r *Renderer) drawGhostBlock(boardX, boardY int, color graphic.Color) {
+	displayX := BoardOffsetX + boardX*BlockSize
+	displayY := BoardOffsetY + boardY*BlockSize
 
-			if prevR != currR || prevG != currG || prevB != currB {
-				color := graphic.Color{currR, currG, currB}
-				diff[color] = append(diff[color], graphic.Point{X: x, Y: y})
+	for dy := 0; dy < BlockSize; dy++ {
+		for dx := 0; dx < BlockSize; dx++ {
+			if dx > 0 && dx < BlockSize-1 && dy > 0 && dy < BlockSize-1 {
+				continue // Leave the center empty
 			}
+			graphic.SetPixel(r.Buffer(), displayX+dx, displayY+dy, color)
 		}
 	}
-
-	return diff
 }
 
 // Flush sends changed pixels to the device using multi-pixel packets
 func (r *Renderer) Flush() error {
-	diff := r.ComputeDiff()
-
-	for color, points := range diff {
-		// Split points into chunks of MaxPixelsPerPacket
-		for i := 0; i < len(points); i += protocol.MaxPixelsPerPacket {
-			end := i + protocol.MaxPixelsPerPacket
-			if end > len(points) {
-				end = len(points)
-			}
-			chunk := points[i:end]
-
-			if err := protocol.SetPixels(r.device, color, chunk); err != nil {
-				return err
-			}
-			time.Sleep(protocol.PacketDelay)
-		}
+	if r.Recorder != nil {
+		r.Recorder.Capture(r.Buffer(), r.clock().Now())
 	}
 
-	// Update previous buffer
-	copy(r.prevBuffer[:], r.currBuffer[:])
-
-	return nil
+	return r.FlushWith(r.clock().Sleep)
 }
 
-// SetPrevBuffer sets the previous buffer (used for initial state)
-func (r *Renderer) SetPrevBuffer(data []byte) {
-	copy(r.prevBuffer[:], data)
-}
-
-// SetCurrBuffer sets the current buffer (used for initial state)
-func (r *Renderer) SetCurrBuffer(data []byte) {
-	copy(r.currBuffer[:], data)
-}
-
-// GetCurrBuffer returns a copy of the current buffer (for testing)
-func (r *Renderer) GetCurrBuffer() []byte {
-	buf := make([]byte, len(r.currBuffer))
-	copy(buf, r.currBuffer[:])
-	return buf
+// clock returns the clock timing the renderer
+func (r *Renderer) clock() gametime.Clock {
+	if r.Clock == nil {
+		return gametime.Real()
+	}
+	return r.Clock
 }
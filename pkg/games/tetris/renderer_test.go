@@ -3,9 +3,18 @@ package tetris
 import (
 	"testing"
 
+	"github.com/pracucci/idotmatrix-overclocked/pkg/games/hud"
 	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/text"
 )
 
+// newTestRenderer returns a renderer with the ghost piece disabled, not
+// connected to a device
+func newTestRenderer() *Renderer {
+	return &Renderer{DiffRenderer: protocol.NewDiffRenderer(nil)}
+}
+
 func TestRendererComputeDiff(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -25,7 +34,7 @@ func TestRendererComputeDiff(t *testing.T) {
 			name:      "single pixel changed",
 			setupPrev: func(r *Renderer) {},
 			setupCurr: func(r *Renderer) {
-				r.currBuffer[0] = 255 // R at (0,0)
+				r.Buffer()[0] = 255 // R at (0,0)
 			},
 			expectedColors: 1,
 			expectedPixels: 1,
@@ -37,7 +46,7 @@ func TestRendererComputeDiff(t *testing.T) {
 				// Set 3 pixels to red
 				for i := 0; i < 3; i++ {
 					offset := i * 3
-					r.currBuffer[offset] = 255 // R
+					r.Buffer()[offset] = 255 // R
 				}
 			},
 			expectedColors: 1,
@@ -48,11 +57,11 @@ func TestRendererComputeDiff(t *testing.T) {
 			setupPrev: func(r *Renderer) {},
 			setupCurr: func(r *Renderer) {
 				// Pixel 0: red
-				r.currBuffer[0] = 255
+				r.Buffer()[0] = 255
 				// Pixel 1: green
-				r.currBuffer[4] = 255
+				r.Buffer()[4] = 255
 				// Pixel 2: blue
-				r.currBuffer[8] = 255
+				r.Buffer()[8] = 255
 			},
 			expectedColors: 3,
 			expectedPixels: 3,
@@ -61,7 +70,7 @@ func TestRendererComputeDiff(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			r := &Renderer{}
+			r := newTestRenderer()
 			tt.setupPrev(r)
 			tt.setupCurr(r)
 
@@ -83,7 +92,7 @@ func TestRendererComputeDiff(t *testing.T) {
 }
 
 func TestRendererRenderState(t *testing.T) {
-	r := &Renderer{}
+	r := newTestRenderer()
 	board := NewBoard()
 	background := make([]byte, graphic.DisplayWidth*graphic.DisplayWidth*3)
 
@@ -103,7 +112,7 @@ func TestRendererRenderState(t *testing.T) {
 }
 
 func TestRendererRenderLockedPieces(t *testing.T) {
-	r := &Renderer{}
+	r := newTestRenderer()
 	board := NewBoard()
 	background := make([]byte, graphic.DisplayWidth*graphic.DisplayWidth*3)
 
@@ -126,7 +135,7 @@ func TestRendererRenderLockedPieces(t *testing.T) {
 }
 
 func TestRendererRenderCurrentPiece(t *testing.T) {
-	r := &Renderer{}
+	r := newTestRenderer()
 	board := NewBoard()
 	background := make([]byte, graphic.DisplayWidth*graphic.DisplayWidth*3)
 
@@ -149,7 +158,7 @@ func TestRendererRenderCurrentPiece(t *testing.T) {
 }
 
 func TestRendererBlockSize(t *testing.T) {
-	r := &Renderer{}
+	r := newTestRenderer()
 	board := NewBoard()
 	background := make([]byte, graphic.DisplayWidth*graphic.DisplayWidth*3)
 
@@ -175,7 +184,7 @@ func TestRendererBlockSize(t *testing.T) {
 }
 
 func TestRendererSetPrevBuffer(t *testing.T) {
-	r := &Renderer{}
+	r := newTestRenderer()
 
 	// Set prev buffer to all 100s
 	prevData := make([]byte, graphic.DisplayWidth*graphic.DisplayWidth*3)
@@ -197,7 +206,7 @@ func TestRendererSetPrevBuffer(t *testing.T) {
 	}
 
 	// Now change one pixel in curr
-	r.currBuffer[0] = 200
+	r.Buffer()[0] = 200
 
 	diff = r.ComputeDiff()
 	if len(diff) != 1 {
@@ -206,7 +215,7 @@ func TestRendererSetPrevBuffer(t *testing.T) {
 }
 
 func TestRendererPieceAboveBoardNotRendered(t *testing.T) {
-	r := &Renderer{}
+	r := newTestRenderer()
 	board := NewBoard()
 	background := make([]byte, graphic.DisplayWidth*graphic.DisplayWidth*3)
 
@@ -218,3 +227,151 @@ func TestRendererPieceAboveBoardNotRendered(t *testing.T) {
 	// Cells at negative Y should not be rendered (would be at negative display coords)
 	// This test verifies no crash occurs
 }
+
+func TestRendererRenderPanels(t *testing.T) {
+	r := newTestRenderer()
+	background := make([]byte, graphic.DisplayWidth*graphic.DisplayWidth*3)
+	r.SetCurrBuffer(background)
+
+	state := NewGameState()
+	state.Next = TetrominoO
+	state.Queue = []TetrominoType{TetrominoO, TetrominoO}
+	state.Hold = TetrominoO
+	state.HasHold = true
+
+	r.RenderPanels(state)
+	buf := r.GetCurrBuffer()
+
+	// The O piece occupies offsets (1,0)-(2,1), so at scale 2 it starts 2 pixels right of the panel
+	isLit := func(x, y int) bool {
+		offset := (y*graphic.DisplayWidth + x) * 3
+		return buf[offset] != 0 || buf[offset+1] != 0 || buf[offset+2] != 0
+	}
+	if !isLit(HoldPanelX+2, PanelY) {
+		t.Error("held piece should be rendered in the left panel")
+	}
+	for i := 0; i < PreviewSize; i++ {
+		if !isLit(NextPanelX+2, PanelY+i*PanelSpacingY) {
+			t.Errorf("next piece %d should be rendered in the right panel", i)
+		}
+	}
+}
+
+func TestRendererRenderPanelsWithoutHold(t *testing.T) {
+	r := newTestRenderer()
+	r.RenderPanels(NewGameState())
+	buf := r.GetCurrBuffer()
+
+	// Only check the hold area, the score is drawn below it
+	for y := 0; y < ScorePanelY; y++ {
+		for x := 0; x < BoardOffsetX-1; x++ {
+			offset := (y*graphic.DisplayWidth + x) * 3
+			if buf[offset] != 0 || buf[offset+1] != 0 || buf[offset+2] != 0 {
+				t.Fatalf("left panel should be empty without a held piece, got pixel at (%d,%d)", x, y)
+			}
+		}
+	}
+}
+
+func TestRendererGhostPiece(t *testing.T) {
+	r := NewRenderer(nil)
+	board := NewBoard()
+	background := make([]byte, graphic.DisplayWidth*graphic.DisplayWidth*3)
+
+	// O piece cells are at offsets (1,0)-(2,1), so it lands with Y = BoardHeight-2
+	tetro := Tetromino{Type: TetrominoO, Rotation: Rotation0, X: 3, Y: 2}
+	color := tetro.GetColor()
+	ghostColor := GhostColor(color)
+
+	if ghostColor == color {
+		t.Fatal("ghost color should differ from the piece color")
+	}
+
+	r.RenderState(board, &tetro, background)
+	buf := r.GetCurrBuffer()
+
+	pixelAt := func(x, y int) graphic.Color {
+		offset := (y*graphic.DisplayWidth + x) * 3
+		return graphic.Color{buf[offset], buf[offset+1], buf[offset+2]}
+	}
+
+	ghost := board.DropPosition(tetro)
+	if ghost.Y != BoardHeight-2 {
+		t.Fatalf("expected ghost at the hard-drop Y %d, got %d", BoardHeight-2, ghost.Y)
+	}
+	for _, cell := range ghost.GetCells() {
+		displayX := BoardOffsetX + cell.X*BlockSize
+		displayY := BoardOffsetY + cell.Y*BlockSize
+
+		if got := pixelAt(displayX, displayY); got != ghostColor {
+			t.Errorf("ghost outline at board (%d,%d) should be %v, got %v", cell.X, cell.Y, ghostColor, got)
+		}
+		if got := pixelAt(displayX+1, displayY+1); got != (graphic.Color{}) {
+			t.Errorf("ghost center at board (%d,%d) should be background, got %v", cell.X, cell.Y, got)
+		}
+	}
+
+	// The active piece is still drawn at its own position
+	displayX := BoardOffsetX + 4*BlockSize
+	displayY := BoardOffsetY + 2*BlockSize
+	if got := pixelAt(displayX, displayY); got != color {
+		t.Errorf("active piece should be drawn with %v, got %v", color, got)
+	}
+}
+
+func TestRendererGhostPieceDisabled(t *testing.T) {
+	r := newTestRenderer()
+	board := NewBoard()
+	background := make([]byte, graphic.DisplayWidth*graphic.DisplayWidth*3)
+
+	tetro := Tetromino{Type: TetrominoO, Rotation: Rotation0, X: 3, Y: 2}
+	r.RenderState(board, &tetro, background)
+	buf := r.GetCurrBuffer()
+
+	displayX := BoardOffsetX + 4*BlockSize
+	displayY := BoardOffsetY + (BoardHeight-1)*BlockSize
+	offset := (displayY*graphic.DisplayWidth + displayX) * 3
+	if buf[offset] != 0 || buf[offset+1] != 0 || buf[offset+2] != 0 {
+		t.Error("ghost piece should not be drawn when ShowGhost is false")
+	}
+}
+
+func TestRendererRenderScoreAndLevel(t *testing.T) {
+	r := newTestRenderer()
+	state := NewGameState()
+	state.Score = 12345
+	state.Lines = 25
+
+	r.RenderPanels(state)
+	buf := r.GetCurrBuffer()
+
+	// countColor counts the pixels of the given color inside a region
+	countColor := func(x0, y0, x1, y1 int, color graphic.Color) int {
+		count := 0
+		for y := y0; y < y1; y++ {
+			for x := x0; x < x1; x++ {
+				offset := (y*graphic.DisplayWidth + x) * 3
+				if buf[offset] == color[0] && buf[offset+1] == color[1] && buf[offset+2] == color[2] {
+					count++
+				}
+			}
+		}
+		return count
+	}
+
+	// 5 digits are drawn on 3 rows in the left margin
+	for row := 0; row < 3; row++ {
+		y := ScorePanelY + row*hud.LineSpacing
+		if countColor(0, y, BoardOffsetX-1, y+text.FontHeight, ScoreColor) == 0 {
+			t.Errorf("score row %d should be drawn", row)
+		}
+	}
+	if countColor(0, ScorePanelY+3*hud.LineSpacing, BoardOffsetX-1, graphic.DisplayWidth, ScoreColor) != 0 {
+		t.Error("score should not use more than 3 rows")
+	}
+
+	// Level 3 is drawn in the right margin
+	if countColor(LevelPanelX, LevelPanelY, graphic.DisplayWidth, LevelPanelY+text.FontHeight, LevelColor) == 0 {
+		t.Error("level should be drawn in the right margin")
+	}
+}
@@ -0,0 +1,104 @@
+package graphic
+
+import (
+	"fmt"
+	"math"
+)
+
+// ChartOptions configures the bar and line charts.
+type ChartOptions struct {
+	Color  Color // Bars or line color
+	BarGap int   // Pixels between bars, left out when bars get too narrow
+}
+
+// DefaultChartOptions returns green charts with 1 pixel between bars.
+func DefaultChartOptions() ChartOptions {
+	return ChartOptions{
+		Color:  Green,
+		BarGap: 1,
+	}
+}
+
+// ChartRange returns the smallest and largest values of a series.
+func ChartRange(values []float64) (float64, float64) {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	return lo, hi
+}
+
+// ChartY returns the row of a value scaled to the display height: hi on the
+// top row and lo on the bottom row. When lo and hi are equal every value is on
+// the middle row, so a constant series is a flat line.
+func ChartY(value, lo, hi float64) int {
+	if hi <= lo {
+		return DisplayHeight / 2
+	}
+	t := (value - lo) / (hi - lo)
+	return DisplayHeight - 1 - int(math.Round(t*float64(DisplayHeight-1)))
+}
+
+// validateChartValues checks there are between 1 and DisplayWidth finite
+// values, so each gets at least one column.
+func validateChartValues(values []float64) error {
+	if len(values) < 1 || len(values) > DisplayWidth {
+		return fmt.Errorf("invalid number of values: %d (valid: 1-%d)", len(values), DisplayWidth)
+	}
+	for i, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("invalid value at position %d: %v", i, v)
+		}
+	}
+	return nil
+}
+
+// DrawBarChart draws one bar per value across the display width, rising from
+// the bottom to the value row scaled between the smallest (1 pixel tall) and
+// largest (full height) values. Returns an error if there are no values, more
+// values than columns, or values that aren't finite.
+func DrawBarChart(buf []byte, values []float64, opts ChartOptions) error {
+	if err := validateChartValues(values); err != nil {
+		return err
+	}
+
+	lo, hi := ChartRange(values)
+	for i, v := range values {
+		x := i * DisplayWidth / len(values)
+		w := (i+1)*DisplayWidth/len(values) - x
+		if w > opts.BarGap {
+			w -= opts.BarGap
+		}
+		y := ChartY(v, lo, hi)
+		FillRect(buf, x, y, w, DisplayHeight-y, opts.Color)
+	}
+	return nil
+}
+
+// DrawLineChart draws a line through the values spread evenly from the left to
+// the right edge, scaled between the smallest (bottom row) and largest (top
+// row) values. A single value is drawn as a horizontal line. Returns an error
+// if there are no values, more values than columns, or values that aren't
+// finite.
+func DrawLineChart(buf []byte, values []float64, opts ChartOptions) error {
+	if err := validateChartValues(values); err != nil {
+		return err
+	}
+
+	lo, hi := ChartRange(values)
+	if len(values) == 1 {
+		y := ChartY(values[0], lo, hi)
+		DrawLine(buf, 0, y, DisplayWidth-1, y, opts.Color)
+		return nil
+	}
+
+	prevX, prevY := 0, ChartY(values[0], lo, hi)
+	for i := 1; i < len(values); i++ {
+		x := i * (DisplayWidth - 1) / (len(values) - 1)
+		y := ChartY(values[i], lo, hi)
+		DrawLine(buf, prevX, prevY, x, y, opts.Color)
+		prevX, prevY = x, y
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package graphic
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChartY(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    float64
+		lo, hi   float64
+		expected int
+	}{
+		{name: "max on the top row", value: 10, lo: 0, hi: 10, expected: 0},
+		{name: "min on the bottom row", value: 0, lo: 0, hi: 10, expected: 63},
+		{name: "half way", value: 5, lo: 0, hi: 10, expected: 31},
+		{name: "negative range", value: -5, lo: -10, hi: 0, expected: 31},
+		{name: "offset range", value: 150, lo: 100, hi: 163, expected: 13},
+		{name: "equal bounds on the middle row", value: 7, lo: 7, hi: 7, expected: 32},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ChartY(tt.value, tt.lo, tt.hi))
+		})
+	}
+}
+
+func TestChartRange(t *testing.T) {
+	lo, hi := ChartRange([]float64{3, -2, 8, 0})
+	assert.Equal(t, -2.0, lo)
+	assert.Equal(t, 8.0, hi)
+}
+
+func TestDrawBarChart(t *testing.T) {
+	buf := NewBuffer()
+	require.NoError(t, DrawBarChart(buf, []float64{0, 10, 5, 10}, DefaultChartOptions()))
+
+	// Four 16 pixel wide bars with a 1 pixel gap, from the bottom to the value
+	heights := []int{1, 64, 33, 64}
+	lit := litPixels(buf)
+	for i, height := range heights {
+		for x := i * 16; x < (i+1)*16; x++ {
+			for y := 0; y < DisplayHeight; y++ {
+				expected := x < (i+1)*16-1 && y >= DisplayHeight-height
+				require.Equal(t, expected, lit[Point{x, y}], "bar %d pixel %d,%d", i, x, y)
+			}
+		}
+	}
+}
+
+func TestDrawBarChartNarrowBars(t *testing.T) {
+	// One column per value leaves no room for gaps
+	values := make([]float64, DisplayWidth)
+	for i := range values {
+		values[i] = float64(i)
+	}
+	buf := NewBuffer()
+	require.NoError(t, DrawBarChart(buf, values, DefaultChartOptions()))
+
+	lit := litPixels(buf)
+	for x := 0; x < DisplayWidth; x++ {
+		assert.True(t, lit[Point{x, DisplayHeight - 1}], "column %d", x)
+	}
+	assert.True(t, lit[Point{63, 0}])
+	assert.False(t, lit[Point{0, 62}])
+}
+
+func TestDrawLineChart(t *testing.T) {
+	buf := NewBuffer()
+	require.NoError(t, DrawLineChart(buf, []float64{0, 10, 0}, DefaultChartOptions()))
+
+	lit := litPixels(buf)
+	assert.True(t, lit[Point{0, 63}])
+	assert.True(t, lit[Point{31, 0}])
+	assert.True(t, lit[Point{63, 63}])
+
+	// The line reaches every column from the left to the right edge
+	for x := 0; x < DisplayWidth; x++ {
+		found := false
+		for y := 0; y < DisplayHeight; y++ {
+			found = found || lit[Point{x, y}]
+		}
+		assert.True(t, found, "column %d", x)
+	}
+}
+
+func TestDrawChartsFlatSeries(t *testing.T) {
+	values := []float64{4.2, 4.2, 4.2, 4.2, 4.2}
+
+	// The line is a single row across the display
+	buf := NewBuffer()
+	require.NoError(t, DrawLineChart(buf, values, DefaultChartOptions()))
+	lit := litPixels(buf)
+	assert.Len(t, lit, DisplayWidth)
+	for p := range lit {
+		assert.Equal(t, DisplayHeight/2, p.Y)
+	}
+
+	// The bars all reach the same row
+	buf = NewBuffer()
+	require.NoError(t, DrawBarChart(buf, values, DefaultChartOptions()))
+	lit = litPixels(buf)
+	for x := 0; x < DisplayWidth; x++ {
+		if lit[Point{x, DisplayHeight - 1}] {
+			assert.True(t, lit[Point{x, DisplayHeight / 2}], "column %d", x)
+			assert.False(t, lit[Point{x, DisplayHeight/2 - 1}], "column %d", x)
+		}
+	}
+
+	// A single value is a flat line too
+	buf = NewBuffer()
+	require.NoError(t, DrawLineChart(buf, []float64{-3}, DefaultChartOptions()))
+	assert.Len(t, litPixels(buf), DisplayWidth)
+}
+
+func TestDrawChartsInvalidValues(t *testing.T) {
+	buf := NewBuffer()
+	for _, values := range [][]float64{nil, make([]float64, DisplayWidth+1), {1, math.NaN()}, {math.Inf(1)}} {
+		assert.Error(t, DrawBarChart(buf, values, DefaultChartOptions()))
+		assert.Error(t, DrawLineChart(buf, values, DefaultChartOptions()))
+	}
+	assert.Empty(t, litPixels(buf))
+}
@@ -1,5 +1,12 @@
 package graphic
 
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strings"
+)
+
 // Color represents an RGB color.
 type Color [3]uint8
 
@@ -76,7 +83,122 @@ func ShadowFor(c Color) Color {
 	return Color{c[0] / 5, c[1] / 5, c[2] / 5}
 }
 
+// HSVToColor converts a hue (degrees, wrapped to 0-360), saturation and value
+// (both 0-1) to an RGB Color.
+func HSVToColor(h, s, v float64) Color {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+
+	c := v * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := v - c
+
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = c, x, 0
+	case h < 120:
+		r, g, b = x, c, 0
+	case h < 180:
+		r, g, b = 0, c, x
+	case h < 240:
+		r, g, b = 0, x, c
+	case h < 300:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+
+	return Color{
+		uint8(math.Round((r + m) * 255)),
+		uint8(math.Round((g + m) * 255)),
+		uint8(math.Round((b + m) * 255)),
+	}
+}
+
+// MixColors returns the color moved from a towards b by t, from 0 (a) to 1
+// (b). t is clamped to 0..1.
+func MixColors(a, b Color, t float64) Color {
+	t = max(0, min(t, 1))
+	var mixed Color
+	for i := range mixed {
+		mixed[i] = uint8(float64(a[i]) + (float64(b[i])-float64(a[i]))*t + 0.5)
+	}
+	return mixed
+}
+
+// dominantColorBits is how many high bits of each channel DominantColor
+// buckets colors by, so similar shades count as the same color
+const dominantColorBits = 3
+
+// DominantColor returns the most common color of an RGB buffer: pixels are
+// bucketed by similar shade and the average color of the fullest bucket is
+// returned. An empty buffer returns Black.
+func DominantColor(buf []byte) Color {
+	const shift = 8 - dominantColorBits
+	type bucket struct {
+		count   int
+		r, g, b int
+	}
+	buckets := make(map[[3]uint8]*bucket)
+
+	var best *bucket
+	for i := 0; i+2 < len(buf); i += 3 {
+		key := [3]uint8{buf[i] >> shift, buf[i+1] >> shift, buf[i+2] >> shift}
+		bk := buckets[key]
+		if bk == nil {
+			bk = &bucket{}
+			buckets[key] = bk
+		}
+		bk.count++
+		bk.r += int(buf[i])
+		bk.g += int(buf[i+1])
+		bk.b += int(buf[i+2])
+		if best == nil || bk.count > best.count {
+			best = bk
+		}
+	}
+	if best == nil {
+		return Black
+	}
+	return Color{
+		uint8((best.r + best.count/2) / best.count),
+		uint8((best.g + best.count/2) / best.count),
+		uint8((best.b + best.count/2) / best.count),
+	}
+}
+
+// Hex returns the color as "#rrggbb".
+func (c Color) Hex() string {
+	return "#" + hex.EncodeToString(c[:])
+}
+
 // ColorNames returns a list of available color names.
 func ColorNames() []string {
 	return []string{"white", "red", "green", "blue", "yellow", "cyan", "magenta", "orange", "gray", "purple", "pink"}
 }
+
+// ColorHelp describes the values accepted by ParseColor, for flag help and
+// error messages.
+func ColorHelp() string {
+	return strings.Join(ColorNames(), ", ") + ", or hex #RRGGBB"
+}
+
+// ParseColor parses a color name from ColorPalette (case insensitive) or a
+// hex color as "#RRGGBB" or "RRGGBB".
+func ParseColor(s string) (Color, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	if color, ok := ColorPalette[name]; ok {
+		return color, nil
+	}
+
+	digits := strings.TrimPrefix(name, "#")
+	if len(digits) == 6 {
+		if rgb, err := hex.DecodeString(digits); err == nil {
+			return Color{rgb[0], rgb[1], rgb[2]}, nil
+		}
+	}
+	return Color{}, fmt.Errorf("unknown color: %s (valid: %s)", name, ColorHelp())
+}
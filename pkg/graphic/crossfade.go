@@ -0,0 +1,79 @@
+package graphic
+
+import (
+	"fmt"
+	"image"
+	"image/gif"
+)
+
+// crossfadeFrameDelay is the delay between crossfade frames (10ms units)
+const crossfadeFrameDelay = 5
+
+// MixBuffers returns a copy of the from RGB buffer moved towards the to buffer
+// by t, from 0 (from) to 1 (to). t is clamped to 0..1 and both buffers must
+// have the same size.
+func MixBuffers(from, to []byte, t float64) []byte {
+	t = max(0, min(t, 1))
+	out := make([]byte, len(from))
+	for i := range out {
+		out[i] = uint8(float64(from[i]) + (float64(to[i])-float64(from[i]))*t + 0.5)
+	}
+	return out
+}
+
+// Crossfade creates an animation that fades from one RGB buffer to another
+// over the given number of frames, the first showing from and the last
+// showing to. The animation plays once and holds on the last frame.
+func (d Display) Crossfade(from, to []byte, frames int) *Image {
+	frames = max(frames, 2)
+	g := &gif.GIF{
+		Image:     make([]*image.Paletted, frames),
+		Delay:     make([]int, frames),
+		LoopCount: -1, // Play once
+	}
+	for i := 0; i < frames; i++ {
+		g.Image[i] = d.RGBToPaletted(MixBuffers(from, to, float64(i)/float64(frames-1)))
+		g.Delay[i] = crossfadeFrameDelay
+	}
+
+	return &Image{
+		Type:    ImageTypeAnimated,
+		GIFData: g,
+	}
+}
+
+// Crossfade creates an animation that fades from one 64x64 RGB buffer to
+// another over the given number of frames.
+func Crossfade(from, to []byte, frames int) *Image {
+	return Display64.Crossfade(from, to, frames)
+}
+
+// GenerateColorCycle creates an animation that loops forever through the
+// colors, crossfading from each color to the next one and from the last color
+// back to the first. Each color takes framesPerColor frames, the first of
+// which shows the color itself.
+func (d Display) GenerateColorCycle(colors []Color, framesPerColor int) (*Image, error) {
+	if len(colors) == 0 {
+		return nil, fmt.Errorf("no colors to cycle through")
+	}
+	framesPerColor = max(framesPerColor, 1)
+
+	g := &gif.GIF{LoopCount: 0} // Loop forever
+	for i, c := range colors {
+		next := colors[(i+1)%len(colors)]
+		// The crossfade ends on the next color, which starts the next fade
+		fade := d.Crossfade(d.NewBufferWithColor(c), d.NewBufferWithColor(next), framesPerColor+1)
+		g.Image = append(g.Image, fade.GIFData.Image[:framesPerColor]...)
+		g.Delay = append(g.Delay, fade.GIFData.Delay[:framesPerColor]...)
+	}
+
+	return &Image{
+		Type:    ImageTypeAnimated,
+		GIFData: g,
+	}, nil
+}
+
+// GenerateColorCycle creates a 64x64 animation looping through the colors.
+func GenerateColorCycle(colors []Color, framesPerColor int) (*Image, error) {
+	return Display64.GenerateColorCycle(colors, framesPerColor)
+}
@@ -0,0 +1,61 @@
+package graphic
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMixBuffers(t *testing.T) {
+	from := []byte{0, 100, 255}
+	to := []byte{200, 100, 55}
+
+	assert.Equal(t, from, MixBuffers(from, to, 0))
+	assert.Equal(t, to, MixBuffers(from, to, 1))
+	assert.Equal(t, []byte{100, 100, 155}, MixBuffers(from, to, 0.5))
+	assert.Equal(t, to, MixBuffers(from, to, 2))
+}
+
+func TestCrossfade(t *testing.T) {
+	// Colors whose average is also in the Plan9 palette
+	from := NewBufferWithColor(Color{0, 0, 0})
+	to := NewBufferWithColor(Color{0x88, 0x88, 0x88})
+
+	img := Crossfade(from, to, 5)
+	require.Equal(t, ImageTypeAnimated, img.Type)
+	require.Len(t, img.GIFData.Image, 5)
+	assert.Equal(t, -1, img.GIFData.LoopCount)
+
+	colorAt := func(frame int) color.RGBA {
+		return color.RGBAModel.Convert(img.GIFData.Image[frame].At(10, 10)).(color.RGBA)
+	}
+	assert.Equal(t, color.RGBA{0, 0, 0, 255}, colorAt(0))
+	assert.Equal(t, color.RGBA{0x44, 0x44, 0x44, 255}, colorAt(2))
+	assert.Equal(t, color.RGBA{0x88, 0x88, 0x88, 255}, colorAt(4))
+}
+
+func TestGenerateColorCycle(t *testing.T) {
+	colors := []Color{Red, Green, Blue}
+	img, err := GenerateColorCycle(colors, 4)
+	require.NoError(t, err)
+	require.Equal(t, ImageTypeAnimated, img.Type)
+	require.Len(t, img.GIFData.Image, 12)
+	assert.Zero(t, img.GIFData.LoopCount)
+
+	colorAt := func(frame int) color.RGBA {
+		return color.RGBAModel.Convert(img.GIFData.Image[frame].At(10, 10)).(color.RGBA)
+	}
+	for i, c := range colors {
+		assert.Equal(t, color.RGBA{c[0], c[1], c[2], 255}, colorAt(i*4), "color %d", i)
+	}
+	// Frames in between are blends
+	assert.NotEqual(t, colorAt(0), colorAt(2))
+	assert.NotEqual(t, colorAt(4), colorAt(2))
+}
+
+func TestGenerateColorCycleNoColors(t *testing.T) {
+	_, err := GenerateColorCycle(nil, 4)
+	assert.Error(t, err)
+}
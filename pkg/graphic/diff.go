@@ -0,0 +1,25 @@
+package graphic
+
+// DiffBuffers finds the pixels of curr that differ from prev, grouped by their
+// color in curr. Colors are returned in the order they are first found,
+// scanning row by row, so diffs are sent in a deterministic order.
+func DiffBuffers(prev, curr []byte) ([]Color, map[Color][]Point) {
+	var colors []Color
+	groups := make(map[Color][]Point)
+
+	for y := 0; y < DisplayHeight; y++ {
+		for x := 0; x < DisplayWidth; x++ {
+			offset := (y*DisplayWidth + x) * 3
+			if prev[offset] == curr[offset] && prev[offset+1] == curr[offset+1] && prev[offset+2] == curr[offset+2] {
+				continue
+			}
+			color := Color{curr[offset], curr[offset+1], curr[offset+2]}
+			if _, ok := groups[color]; !ok {
+				colors = append(colors, color)
+			}
+			groups[color] = append(groups[color], Point{X: x, Y: y})
+		}
+	}
+
+	return colors, groups
+}
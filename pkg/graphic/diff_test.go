@@ -0,0 +1,27 @@
+package graphic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffBuffers(t *testing.T) {
+	prev := NewBuffer()
+	curr := NewBuffer()
+
+	colors, groups := DiffBuffers(prev, curr)
+	assert.Empty(t, colors)
+	assert.Empty(t, groups)
+
+	SetPixel(curr, 5, 1, Red)
+	SetPixel(curr, 2, 0, Blue)
+	SetPixel(curr, 3, 1, Blue)
+	SetPixel(prev, 9, 9, Green)
+
+	colors, groups = DiffBuffers(prev, curr)
+	assert.Equal(t, []Color{Blue, Red, Black}, colors)
+	assert.Equal(t, []Point{{X: 2, Y: 0}, {X: 3, Y: 1}}, groups[Blue])
+	assert.Equal(t, []Point{{X: 5, Y: 1}}, groups[Red])
+	assert.Equal(t, []Point{{X: 9, Y: 9}}, groups[Black])
+}
@@ -0,0 +1,243 @@
+package graphic
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"math"
+)
+
+// Display describes the pixel size of a panel. Buffers for a display hold
+// Width*Height*3 raw RGB bytes, row by row from the top-left corner.
+type Display struct {
+	Width  int
+	Height int
+}
+
+// Supported panel sizes
+var (
+	Display64 = Display{Width: DisplayWidth, Height: DisplayHeight}
+	Display32 = Display{Width: 32, Height: 32}
+)
+
+// DisplayForSize returns the display for a square panel size (32 or 64).
+func DisplayForSize(size int) (Display, error) {
+	switch size {
+	case 32:
+		return Display32, nil
+	case 64:
+		return Display64, nil
+	default:
+		return Display{}, fmt.Errorf("invalid display size: %d (must be 32 or 64)", size)
+	}
+}
+
+// BufferSize returns the size in bytes of a raw RGB buffer for the display.
+func (d Display) BufferSize() int {
+	return d.Width * d.Height * 3
+}
+
+// NewBuffer creates a new black buffer for the display.
+func (d Display) NewBuffer() []byte {
+	return make([]byte, d.BufferSize())
+}
+
+// NewBufferWithColor creates a new buffer for the display filled with the given color.
+func (d Display) NewBufferWithColor(color Color) []byte {
+	buf := d.NewBuffer()
+	for i := 0; i < d.Width*d.Height; i++ {
+		offset := i * 3
+		buf[offset] = color[0]
+		buf[offset+1] = color[1]
+		buf[offset+2] = color[2]
+	}
+	return buf
+}
+
+// GradientDirection is the direction of a gradient fill.
+type GradientDirection int
+
+// Gradient directions
+const (
+	GradientVertical   GradientDirection = iota // Top to bottom
+	GradientHorizontal                          // Left to right
+	GradientDiagonal                            // Top-left to bottom-right
+)
+
+// NewBufferWithGradient creates a new buffer for the display filled with a
+// linear gradient from one color to another in the given direction. The
+// first row (or column, or corner) has exactly the from color and the last
+// one the to color.
+func (d Display) NewBufferWithGradient(from, to Color, direction GradientDirection) []byte {
+	buf := d.NewBuffer()
+	for y := 0; y < d.Height; y++ {
+		for x := 0; x < d.Width; x++ {
+			var pos, steps int
+			switch direction {
+			case GradientHorizontal:
+				pos, steps = x, d.Width-1
+			case GradientDiagonal:
+				pos, steps = x+y, d.Width+d.Height-2
+			default:
+				pos, steps = y, d.Height-1
+			}
+
+			t := 0.0
+			if steps > 0 {
+				t = float64(pos) / float64(steps)
+			}
+			d.SetPixel(buf, x, y, MixColors(from, to, t))
+		}
+	}
+	return buf
+}
+
+// SetPixel sets a single pixel in the RGB buffer.
+// Coordinates outside the display bounds are silently ignored.
+func (d Display) SetPixel(buf []byte, x, y int, color Color) {
+	if x < 0 || x >= d.Width || y < 0 || y >= d.Height {
+		return
+	}
+	offset := (y*d.Width + x) * 3
+	buf[offset] = color[0]
+	buf[offset+1] = color[1]
+	buf[offset+2] = color[2]
+}
+
+// BlendPixel mixes color into the pixel already in the RGB buffer, with alpha
+// from 0 (keep the existing pixel) to 1 (same as SetPixel). Alpha is clamped
+// to 0..1. Coordinates outside the display bounds are silently ignored.
+func (d Display) BlendPixel(buf []byte, x, y int, color Color, alpha float64) {
+	if x < 0 || x >= d.Width || y < 0 || y >= d.Height {
+		return
+	}
+	alpha = max(0, min(alpha, 1))
+	offset := (y*d.Width + x) * 3
+	for i := 0; i < 3; i++ {
+		blended := float64(buf[offset+i])*(1-alpha) + float64(color[i])*alpha
+		buf[offset+i] = uint8(max(0, min(math.Round(blended), 255)))
+	}
+}
+
+// ValidateFrame checks that buf is a full raw RGB frame for the display.
+func (d Display) ValidateFrame(buf []byte) error {
+	if len(buf) != d.BufferSize() {
+		return fmt.Errorf("invalid frame size: %d bytes (expected %d raw RGB bytes, %dx%dx3)", len(buf), d.BufferSize(), d.Width, d.Height)
+	}
+	return nil
+}
+
+// MirrorHorizontal returns a copy of the RGB buffer flipped left to right.
+func (d Display) MirrorHorizontal(buf []byte) []byte {
+	out := d.NewBuffer()
+	for y := 0; y < d.Height; y++ {
+		for x := 0; x < d.Width; x++ {
+			src := (y*d.Width + x) * 3
+			dst := (y*d.Width + d.Width - 1 - x) * 3
+			copy(out[dst:dst+3], buf[src:src+3])
+		}
+	}
+	return out
+}
+
+// MirrorVertical returns a copy of the RGB buffer flipped top to bottom.
+func (d Display) MirrorVertical(buf []byte) []byte {
+	out := d.NewBuffer()
+	rowSize := d.Width * 3
+	for y := 0; y < d.Height; y++ {
+		src := y * rowSize
+		dst := (d.Height - 1 - y) * rowSize
+		copy(out[dst:dst+rowSize], buf[src:src+rowSize])
+	}
+	return out
+}
+
+// ImageToRGB converts an image.Image to an RGB buffer for the display,
+// copying the top-left corner of the image without scaling.
+func (d Display) ImageToRGB(img image.Image) []byte {
+	buf := d.NewBuffer()
+	bounds := img.Bounds()
+	for y := 0; y < d.Height; y++ {
+		for x := 0; x < d.Width; x++ {
+			srcX := bounds.Min.X + x
+			srcY := bounds.Min.Y + y
+			if srcX < bounds.Max.X && srcY < bounds.Max.Y {
+				r, g, b, _ := img.At(srcX, srcY).RGBA()
+				offset := (y*d.Width + x) * 3
+				buf[offset] = uint8(r >> 8)
+				buf[offset+1] = uint8(g >> 8)
+				buf[offset+2] = uint8(b >> 8)
+			}
+		}
+	}
+	return buf
+}
+
+// ResizeRGB scales an image of any size to an RGB buffer for the display using
+// nearest-neighbor sampling.
+func (d Display) ResizeRGB(img image.Image) []byte {
+	buf := d.NewBuffer()
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width == 0 || height == 0 {
+		return buf
+	}
+	for y := 0; y < d.Height; y++ {
+		for x := 0; x < d.Width; x++ {
+			srcX := bounds.Min.X + x*width/d.Width
+			srcY := bounds.Min.Y + y*height/d.Height
+			r, g, b, _ := img.At(srcX, srcY).RGBA()
+			offset := (y*d.Width + x) * 3
+			buf[offset] = uint8(r >> 8)
+			buf[offset+1] = uint8(g >> 8)
+			buf[offset+2] = uint8(b >> 8)
+		}
+	}
+	return buf
+}
+
+// RGBToPaletted converts an RGB buffer for the display to a paletted image for
+// GIF encoding, using the fixed Plan9 palette.
+func (d Display) RGBToPaletted(rgbBuf []byte) *image.Paletted {
+	rgba := d.RGBToRGBA(rgbBuf)
+	paletted := image.NewPaletted(rgba.Bounds(), palette.Plan9)
+	draw.Draw(paletted, paletted.Bounds(), rgba, image.Point{}, draw.Src)
+	return paletted
+}
+
+// RGBToPalettedDithered converts an RGB buffer for the display to a paletted
+// image for GIF encoding, using the fixed Plan9 palette with Floyd-Steinberg
+// dithering. Gradients look smoother than with RGBToPaletted, at the cost of
+// some noise, which makes it a poor fit for crisp text.
+func (d Display) RGBToPalettedDithered(rgbBuf []byte) *image.Paletted {
+	rgba := d.RGBToRGBA(rgbBuf)
+	paletted := image.NewPaletted(rgba.Bounds(), palette.Plan9)
+	draw.FloydSteinberg.Draw(paletted, paletted.Bounds(), rgba, image.Point{})
+	return paletted
+}
+
+// RGBToPalettedMedianCut converts an RGB buffer for the display to a paletted
+// image for GIF encoding, using a median cut palette of at most maxColors
+// colors built from the buffer.
+func (d Display) RGBToPalettedMedianCut(rgbBuf []byte, maxColors int) *image.Paletted {
+	return QuantizeToPaletted(d.RGBToRGBA(rgbBuf), maxColors)
+}
+
+// RGBToRGBA converts an RGB buffer for the display to an RGBA image.
+func (d Display) RGBToRGBA(rgbBuf []byte) *image.RGBA {
+	rgba := image.NewRGBA(image.Rect(0, 0, d.Width, d.Height))
+	for y := 0; y < d.Height; y++ {
+		for x := 0; x < d.Width; x++ {
+			offset := (y*d.Width + x) * 3
+			rgba.Set(x, y, color.RGBA{
+				R: rgbBuf[offset],
+				G: rgbBuf[offset+1],
+				B: rgbBuf[offset+2],
+				A: 255,
+			})
+		}
+	}
+	return rgba
+}
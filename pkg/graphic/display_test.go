@@ -0,0 +1,155 @@
+package graphic
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisplayForSize(t *testing.T) {
+	display, err := DisplayForSize(32)
+	require.NoError(t, err)
+	assert.Equal(t, Display32, display)
+
+	display, err = DisplayForSize(64)
+	require.NoError(t, err)
+	assert.Equal(t, Display64, display)
+
+	_, err = DisplayForSize(16)
+	assert.Error(t, err)
+}
+
+func TestDisplayBufferSize(t *testing.T) {
+	assert.Equal(t, BufferSize, Display64.BufferSize())
+	assert.Equal(t, 32*32*3, Display32.BufferSize())
+	assert.Len(t, Display32.NewBuffer(), 32*32*3)
+	assert.Len(t, Display32.NewBufferWithColor(Color{1, 2, 3}), 32*32*3)
+}
+
+func TestDisplaySetPixel32(t *testing.T) {
+	c := Color{10, 20, 30}
+
+	tests := []struct {
+		name   string
+		x, y   int
+		offset int
+	}{
+		{name: "top-left", x: 0, y: 0, offset: 0},
+		{name: "end of first row", x: 31, y: 0, offset: 31 * 3},
+		{name: "start of second row", x: 0, y: 1, offset: 32 * 3},
+		{name: "bottom-right", x: 31, y: 31, offset: (31*32 + 31) * 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := Display32.NewBuffer()
+			Display32.SetPixel(buf, tt.x, tt.y, c)
+			assert.Equal(t, []byte{10, 20, 30}, buf[tt.offset:tt.offset+3])
+		})
+	}
+
+	t.Run("out of bounds is ignored", func(t *testing.T) {
+		buf := Display32.NewBuffer()
+		Display32.SetPixel(buf, 32, 0, c)
+		Display32.SetPixel(buf, 0, 32, c)
+		assert.Equal(t, Display32.NewBuffer(), buf)
+	})
+}
+
+func TestDisplayMirror32(t *testing.T) {
+	red := Color{255, 0, 0}
+	buf := Display32.NewBuffer()
+	Display32.SetPixel(buf, 0, 0, red)
+
+	horizontal := Display32.NewBuffer()
+	Display32.SetPixel(horizontal, 31, 0, red)
+	assert.Equal(t, horizontal, Display32.MirrorHorizontal(buf))
+
+	vertical := Display32.NewBuffer()
+	Display32.SetPixel(vertical, 0, 31, red)
+	assert.Equal(t, vertical, Display32.MirrorVertical(buf))
+}
+
+func TestDisplayRGBToPaletted32(t *testing.T) {
+	buf := Display32.NewBufferWithColor(Color{255, 255, 255})
+	img := Display32.RGBToPaletted(buf)
+
+	assert.Equal(t, image.Rect(0, 0, 32, 32), img.Bounds())
+	assert.Equal(t, color.RGBA{255, 255, 255, 255}, color.RGBAModel.Convert(img.At(31, 31)))
+}
+
+func TestDisplayRGBToPalettedDithered(t *testing.T) {
+	// A flat color that isn't in the Plan9 palette: dithering mixes nearby
+	// palette colors so the average stays closer to the source
+	src := Color{100, 30, 200}
+	buf := Display32.NewBufferWithColor(src)
+
+	plain := Display32.RGBToPaletted(buf)
+	dithered := Display32.RGBToPalettedDithered(buf)
+	require.Equal(t, image.Rect(0, 0, 32, 32), dithered.Bounds())
+
+	averageError := func(img *image.Paletted) int {
+		var r, g, b int
+		for y := 0; y < 32; y++ {
+			for x := 0; x < 32; x++ {
+				c := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+				r, g, b = r+int(c.R), g+int(c.G), b+int(c.B)
+			}
+		}
+		n := 32 * 32
+		return abs(r/n-int(src[0])) + abs(g/n-int(src[1])) + abs(b/n-int(src[2]))
+	}
+
+	assert.Less(t, averageError(dithered), averageError(plain))
+	assert.Equal(t, plain.ColorIndexAt(0, 0), plain.ColorIndexAt(31, 31))
+}
+
+func TestDisplayNewBufferWithGradient(t *testing.T) {
+	from := Color{0, 100, 200}
+	to := Color{200, 100, 0}
+
+	pixelAt := func(d Display, buf []byte, x, y int) Color {
+		offset := (y*d.Width + x) * 3
+		return Color{buf[offset], buf[offset+1], buf[offset+2]}
+	}
+
+	t.Run("vertical", func(t *testing.T) {
+		// An odd height has an exact middle row
+		d := Display{Width: 4, Height: 5}
+		buf := d.NewBufferWithGradient(from, to, GradientVertical)
+		for x := 0; x < d.Width; x++ {
+			assert.Equal(t, from, pixelAt(d, buf, x, 0))
+			assert.Equal(t, Color{100, 100, 100}, pixelAt(d, buf, x, 2))
+			assert.Equal(t, to, pixelAt(d, buf, x, 4))
+		}
+	})
+
+	t.Run("horizontal", func(t *testing.T) {
+		d := Display{Width: 5, Height: 4}
+		buf := d.NewBufferWithGradient(from, to, GradientHorizontal)
+		for y := 0; y < d.Height; y++ {
+			assert.Equal(t, from, pixelAt(d, buf, 0, y))
+			assert.Equal(t, Color{100, 100, 100}, pixelAt(d, buf, 2, y))
+			assert.Equal(t, to, pixelAt(d, buf, 4, y))
+		}
+	})
+
+	t.Run("diagonal", func(t *testing.T) {
+		d := Display{Width: 3, Height: 3}
+		buf := d.NewBufferWithGradient(from, to, GradientDiagonal)
+		assert.Equal(t, from, pixelAt(d, buf, 0, 0))
+		assert.Equal(t, Color{100, 100, 100}, pixelAt(d, buf, 2, 0))
+		assert.Equal(t, Color{100, 100, 100}, pixelAt(d, buf, 0, 2))
+		assert.Equal(t, to, pixelAt(d, buf, 2, 2))
+	})
+
+	t.Run("64x64 vertical", func(t *testing.T) {
+		buf := NewBufferWithGradient(from, to)
+		require.Len(t, buf, BufferSize)
+		assert.Equal(t, from, pixelAt(Display64, buf, 63, 0))
+		assert.Equal(t, to, pixelAt(Display64, buf, 0, 63))
+	})
+}
@@ -0,0 +1,105 @@
+package graphic
+
+// DrawLine draws a line between two points (both included) using Bresenham's
+// algorithm. Points outside the display are skipped, like SetPixel.
+func DrawLine(buf []byte, x0, y0, x1, y1 int, color Color) {
+	dx := abs(x1 - x0)
+	dy := -abs(y1 - y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	err := dx + dy
+
+	for {
+		SetPixel(buf, x0, y0, color)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// DrawCircle draws the outline of a circle centered at (cx, cy) using the
+// midpoint algorithm. Points outside the display are skipped, like SetPixel.
+func DrawCircle(buf []byte, cx, cy, radius int, color Color) {
+	if radius < 0 {
+		return
+	}
+
+	x, y := radius, 0
+	err := 1 - radius
+	for x >= y {
+		// Each computed point is mirrored into all eight octants
+		SetPixel(buf, cx+x, cy+y, color)
+		SetPixel(buf, cx+y, cy+x, color)
+		SetPixel(buf, cx-y, cy+x, color)
+		SetPixel(buf, cx-x, cy+y, color)
+		SetPixel(buf, cx-x, cy-y, color)
+		SetPixel(buf, cx-y, cy-x, color)
+		SetPixel(buf, cx+y, cy-x, color)
+		SetPixel(buf, cx+x, cy-y, color)
+
+		y++
+		if err < 0 {
+			err += 2*y + 1
+		} else {
+			x--
+			err += 2*(y-x) + 1
+		}
+	}
+}
+
+// FillCircle draws a filled circle centered at (cx, cy), covering the same
+// outline as DrawCircle. Points outside the display are skipped, like SetPixel.
+func FillCircle(buf []byte, cx, cy, radius int, color Color) {
+	if radius < 0 {
+		return
+	}
+
+	x, y := radius, 0
+	err := 1 - radius
+	for x >= y {
+		// Horizontal spans between the mirrored outline points
+		DrawLine(buf, cx-x, cy+y, cx+x, cy+y, color)
+		DrawLine(buf, cx-x, cy-y, cx+x, cy-y, color)
+		DrawLine(buf, cx-y, cy+x, cx+y, cy+x, color)
+		DrawLine(buf, cx-y, cy-x, cx+y, cy-x, color)
+
+		y++
+		if err < 0 {
+			err += 2*y + 1
+		} else {
+			x--
+			err += 2*(y-x) + 1
+		}
+	}
+}
+
+// FillRect draws a filled w x h rectangle with its top left corner at (x, y).
+// Points outside the display are skipped, like SetPixel.
+func FillRect(buf []byte, x, y, w, h int, color Color) {
+	for row := y; row < y+h; row++ {
+		for col := x; col < x+w; col++ {
+			SetPixel(buf, col, row, color)
+		}
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,153 @@
+package graphic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var drawColor = Color{255, 255, 255}
+
+// litPixels returns the set of pixels with a non-black color in buf
+func litPixels(buf []byte) map[Point]bool {
+	lit := make(map[Point]bool)
+	for y := 0; y < DisplayHeight; y++ {
+		for x := 0; x < DisplayWidth; x++ {
+			offset := (y*DisplayWidth + x) * 3
+			if buf[offset] != 0 || buf[offset+1] != 0 || buf[offset+2] != 0 {
+				lit[Point{X: x, Y: y}] = true
+			}
+		}
+	}
+	return lit
+}
+
+func TestDrawLine(t *testing.T) {
+	tests := []struct {
+		name           string
+		x0, y0, x1, y1 int
+		expected       []Point
+	}{
+		{
+			name: "single point",
+			x0:   5, y0: 5, x1: 5, y1: 5,
+			expected: []Point{{5, 5}},
+		},
+		{
+			name: "horizontal",
+			x0:   1, y0: 2, x1: 4, y1: 2,
+			expected: []Point{{1, 2}, {2, 2}, {3, 2}, {4, 2}},
+		},
+		{
+			name: "vertical upward",
+			x0:   3, y0: 3, x1: 3, y1: 0,
+			expected: []Point{{3, 0}, {3, 1}, {3, 2}, {3, 3}},
+		},
+		{
+			name: "diagonal",
+			x0:   0, y0: 0, x1: 3, y1: 3,
+			expected: []Point{{0, 0}, {1, 1}, {2, 2}, {3, 3}},
+		},
+		{
+			name: "shallow slope",
+			x0:   0, y0: 0, x1: 4, y1: 2,
+			expected: []Point{{0, 0}, {1, 1}, {2, 1}, {3, 2}, {4, 2}},
+		},
+		{
+			name: "clipped at the display edge",
+			x0:   62, y0: 0, x1: 65, y1: 0,
+			expected: []Point{{62, 0}, {63, 0}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := NewBuffer()
+			DrawLine(buf, tt.x0, tt.y0, tt.x1, tt.y1, drawColor)
+
+			expected := make(map[Point]bool)
+			for _, p := range tt.expected {
+				expected[p] = true
+			}
+			assert.Equal(t, expected, litPixels(buf))
+		})
+	}
+}
+
+func TestDrawCircle(t *testing.T) {
+	const cx, cy = 32, 32
+
+	for _, radius := range []int{1, 5, 10, 20} {
+		buf := NewBuffer()
+		DrawCircle(buf, cx, cy, radius, drawColor)
+		lit := litPixels(buf)
+
+		// The extremes are on the circle and the center is empty
+		assert.True(t, lit[Point{cx + radius, cy}], "radius %d", radius)
+		assert.True(t, lit[Point{cx - radius, cy}], "radius %d", radius)
+		assert.True(t, lit[Point{cx, cy + radius}], "radius %d", radius)
+		assert.True(t, lit[Point{cx, cy - radius}], "radius %d", radius)
+		assert.False(t, lit[Point{cx, cy}], "radius %d", radius)
+
+		// Symmetric about the center, both axes and the diagonal
+		for p := range lit {
+			dx, dy := p.X-cx, p.Y-cy
+			assert.True(t, lit[Point{cx - dx, cy + dy}], "radius %d: mirror of %v", radius, p)
+			assert.True(t, lit[Point{cx + dx, cy - dy}], "radius %d: mirror of %v", radius, p)
+			assert.True(t, lit[Point{cx + dy, cy + dx}], "radius %d: mirror of %v", radius, p)
+		}
+	}
+}
+
+func TestDrawCircleZeroRadius(t *testing.T) {
+	buf := NewBuffer()
+	DrawCircle(buf, 10, 10, 0, drawColor)
+	assert.Equal(t, map[Point]bool{{10, 10}: true}, litPixels(buf))
+}
+
+func TestFillCircle(t *testing.T) {
+	const cx, cy, radius = 32, 32, 6
+
+	outline := NewBuffer()
+	DrawCircle(outline, cx, cy, radius, drawColor)
+	filled := NewBuffer()
+	FillCircle(filled, cx, cy, radius, drawColor)
+
+	outlineLit := litPixels(outline)
+	filledLit := litPixels(filled)
+
+	// The filled circle covers the outline and everything inside it
+	for p := range outlineLit {
+		assert.True(t, filledLit[p], "outline point %v not filled", p)
+	}
+	assert.True(t, filledLit[Point{cx, cy}])
+	for p := range filledLit {
+		dx, dy := p.X-cx, p.Y-cy
+		assert.LessOrEqual(t, dx*dx+dy*dy, (radius+1)*(radius+1), "point %v outside the circle", p)
+	}
+}
+
+func TestDrawCircleClipped(t *testing.T) {
+	buf := NewBuffer()
+	assert.NotPanics(t, func() {
+		DrawCircle(buf, 0, 0, 10, drawColor)
+		FillCircle(buf, 63, 63, 10, drawColor)
+	})
+}
+
+func TestFillRect(t *testing.T) {
+	buf := NewBuffer()
+	FillRect(buf, 2, 3, 3, 2, drawColor)
+
+	assert.Equal(t, map[Point]bool{
+		{2, 3}: true, {3, 3}: true, {4, 3}: true,
+		{2, 4}: true, {3, 4}: true, {4, 4}: true,
+	}, litPixels(buf))
+
+	// Empty and clipped rectangles
+	buf = NewBuffer()
+	FillRect(buf, 10, 10, 0, 5, drawColor)
+	assert.Empty(t, litPixels(buf))
+	assert.NotPanics(t, func() { FillRect(buf, 60, 60, 10, 10, drawColor) })
+	assert.Len(t, litPixels(buf), 16)
+}
@@ -0,0 +1,107 @@
+package graphic
+
+import "math"
+
+// ValidateFrame checks that buf is a full 64x64 raw RGB frame.
+func ValidateFrame(buf []byte) error {
+	return Display64.ValidateFrame(buf)
+}
+
+// MirrorHorizontal returns a copy of the RGB buffer flipped left to right.
+func MirrorHorizontal(buf []byte) []byte {
+	return Display64.MirrorHorizontal(buf)
+}
+
+// MirrorVertical returns a copy of the RGB buffer flipped top to bottom.
+func MirrorVertical(buf []byte) []byte {
+	return Display64.MirrorVertical(buf)
+}
+
+// ScaleBrightness returns a copy of the RGB buffer with every channel scaled
+// to the given percentage (0-100).
+func ScaleBrightness(buf []byte, percent int) []byte {
+	percent = max(0, min(percent, 100))
+	out := make([]byte, len(buf))
+	for i, v := range buf {
+		out[i] = uint8(int(v) * percent / 100)
+	}
+	return out
+}
+
+// ApplyGamma returns a copy of the RGB buffer with gamma correction applied to
+// every channel. Values above 1 darken mid tones, values below 1 brighten them.
+func ApplyGamma(buf []byte, gamma float64) []byte {
+	var table [256]uint8
+	for i := range table {
+		table[i] = uint8(math.Round(255 * math.Pow(float64(i)/255, gamma)))
+	}
+
+	out := make([]byte, len(buf))
+	for i, v := range buf {
+		out[i] = table[v]
+	}
+	return out
+}
+
+// LevelsMode selects how AutoLevels measures the tonal range of an image.
+type LevelsMode int
+
+// Levels modes
+const (
+	// LevelsLuminance stretches all channels by the same amount, based on the
+	// darkest and brightest pixel luminance. It keeps the colors balanced.
+	LevelsLuminance LevelsMode = iota
+
+	// LevelsPerChannel stretches each channel on its own, which also corrects
+	// a color cast.
+	LevelsPerChannel
+)
+
+// AutoLevels returns a copy of the RGB buffer with its histogram stretched,
+// so the darkest pixel maps to 0 and the brightest to 255, based on the pixel
+// luminance. It improves washed out or dark images.
+func AutoLevels(buf []byte) []byte {
+	return AutoLevelsWithMode(buf, LevelsLuminance)
+}
+
+// AutoLevelsWithMode is like AutoLevels, measuring the tonal range as set by
+// mode. Buffers without any contrast are returned unchanged.
+func AutoLevelsWithMode(buf []byte, mode LevelsMode) []byte {
+	var lo, hi [3]float64
+	for c := range lo {
+		lo[c], hi[c] = 255, 0
+	}
+	for i := 0; i+2 < len(buf); i += 3 {
+		if mode == LevelsPerChannel {
+			for c := 0; c < 3; c++ {
+				lo[c] = min(lo[c], float64(buf[i+c]))
+				hi[c] = max(hi[c], float64(buf[i+c]))
+			}
+			continue
+		}
+		l := luminance(buf[i], buf[i+1], buf[i+2])
+		lo[0], hi[0] = min(lo[0], l), max(hi[0], l)
+	}
+	if mode == LevelsLuminance {
+		lo[1], lo[2], hi[1], hi[2] = lo[0], lo[0], hi[0], hi[0]
+	}
+
+	out := make([]byte, len(buf))
+	copy(out, buf)
+	for c := 0; c < 3; c++ {
+		if hi[c] <= lo[c] {
+			continue
+		}
+		scale := 255 / (hi[c] - lo[c])
+		for i := c; i < len(buf)-len(buf)%3; i += 3 {
+			v := math.Round((float64(buf[i]) - lo[c]) * scale)
+			out[i] = uint8(max(0, min(v, 255)))
+		}
+	}
+	return out
+}
+
+// luminance returns the perceived brightness (0-255) of an RGB color.
+func luminance(r, g, b uint8) float64 {
+	return 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+}
@@ -0,0 +1,93 @@
+package graphic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateFrame(t *testing.T) {
+	assert.NoError(t, ValidateFrame(NewBuffer()))
+	assert.Error(t, ValidateFrame(nil))
+	assert.Error(t, ValidateFrame(make([]byte, BufferSize-1)))
+	assert.Error(t, ValidateFrame(make([]byte, BufferSize+1)))
+}
+
+func TestMirror(t *testing.T) {
+	red := Color{255, 0, 0}
+	buf := NewBuffer()
+	SetPixel(buf, 0, 0, red)
+
+	horizontal := NewBuffer()
+	SetPixel(horizontal, DisplayWidth-1, 0, red)
+	assert.Equal(t, horizontal, MirrorHorizontal(buf))
+
+	vertical := NewBuffer()
+	SetPixel(vertical, 0, DisplayHeight-1, red)
+	assert.Equal(t, vertical, MirrorVertical(buf))
+
+	// The input is left untouched
+	expected := NewBuffer()
+	SetPixel(expected, 0, 0, red)
+	assert.Equal(t, expected, buf)
+}
+
+func TestScaleBrightness(t *testing.T) {
+	buf := []byte{0, 100, 255}
+
+	assert.Equal(t, []byte{0, 100, 255}, ScaleBrightness(buf, 100))
+	assert.Equal(t, []byte{0, 50, 127}, ScaleBrightness(buf, 50))
+	assert.Equal(t, []byte{0, 0, 0}, ScaleBrightness(buf, 0))
+	assert.Equal(t, []byte{0, 100, 255}, ScaleBrightness(buf, 150), "clamped to 100%")
+}
+
+func TestApplyGamma(t *testing.T) {
+	buf := []byte{0, 128, 255}
+
+	assert.Equal(t, buf, ApplyGamma(buf, 1))
+	assert.Equal(t, []byte{0, 64, 255}, ApplyGamma(buf, 2))
+	assert.Equal(t, []byte{0, 181, 255}, ApplyGamma(buf, 0.5))
+}
+
+func TestAutoLevels(t *testing.T) {
+	// A gray gradient with values in [50, 200]
+	buf := NewBuffer()
+	for y := 0; y < DisplayHeight; y++ {
+		v := uint8(50 + 150*y/(DisplayHeight-1))
+		for x := 0; x < DisplayWidth; x++ {
+			SetPixel(buf, x, y, Color{v, v, v})
+		}
+	}
+
+	for _, mode := range []LevelsMode{LevelsLuminance, LevelsPerChannel} {
+		out := AutoLevelsWithMode(buf, mode)
+		lo, hi := uint8(255), uint8(0)
+		for _, v := range out {
+			lo, hi = min(lo, v), max(hi, v)
+		}
+		assert.Equal(t, uint8(0), lo, "mode %d", mode)
+		assert.Equal(t, uint8(255), hi, "mode %d", mode)
+		assert.Equal(t, uint8(50), buf[0], "the input is not modified")
+	}
+	assert.Equal(t, AutoLevelsWithMode(buf, LevelsLuminance), AutoLevels(buf))
+}
+
+func TestAutoLevelsModes(t *testing.T) {
+	// A reddish image: red spans [100, 200], green and blue [50, 100]
+	buf := []byte{100, 50, 50, 200, 100, 100}
+
+	// Per channel, every channel is stretched to the full range
+	assert.Equal(t, []byte{0, 0, 0, 255, 255, 255}, AutoLevelsWithMode(buf, LevelsPerChannel))
+
+	// By luminance, all channels are stretched alike, keeping the red cast
+	out := AutoLevelsWithMode(buf, LevelsLuminance)
+	assert.Greater(t, out[0], out[1])
+	assert.Greater(t, out[3], out[4])
+	assert.Equal(t, out[1], out[2])
+}
+
+func TestAutoLevelsWithoutContrast(t *testing.T) {
+	buf := NewBufferWithColor(Color{80, 80, 80})
+	assert.Equal(t, buf, AutoLevels(buf))
+	assert.Equal(t, buf, AutoLevelsWithMode(buf, LevelsPerChannel))
+}
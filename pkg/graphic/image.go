@@ -5,9 +5,9 @@ import (
 	"fmt"
 	"image"
 	"image/color"
-	"image/color/palette"
 	"image/draw"
 	"image/gif"
+	"image/png"
 )
 
 // Display constants
@@ -54,70 +54,279 @@ func (img *Image) GIFBytes() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// PNGBytes encodes the image as a PNG, e.g. for previews. Static images are
+// encoded as is, animated images are encoded using their first frame.
+func (img *Image) PNGBytes() ([]byte, error) {
+	var still image.Image
+	switch img.Type {
+	case ImageTypeStatic:
+		if len(img.StaticData) != BufferSize {
+			return nil, fmt.Errorf("invalid static image size: %d bytes (expected %d)", len(img.StaticData), BufferSize)
+		}
+		still = Display64.RGBToRGBA(img.StaticData)
+	case ImageTypeAnimated:
+		if img.GIFData == nil || len(img.GIFData.Image) == 0 {
+			return nil, fmt.Errorf("PNGBytes called on an animated image without frames")
+		}
+		still = img.GIFData.Image[0]
+	default:
+		return nil, fmt.Errorf("unknown image type: %d", img.Type)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, still); err != nil {
+		return nil, fmt.Errorf("failed to encode PNG: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Frame returns frame i of an animated image as a static image. Frames are
+// composited over the previous ones honoring their disposal method, since GIF
+// frames may only cover part of the canvas. Images that are not 64x64 are
+// resized.
+func (img *Image) Frame(i int) (*Image, error) {
+	if img.Type != ImageTypeAnimated || img.GIFData == nil {
+		return nil, fmt.Errorf("Frame called on non-animated image")
+	}
+	g := img.GIFData
+	if i < 0 || i >= len(g.Image) {
+		return nil, fmt.Errorf("frame %d out of range (the image has %d frames)", i, len(g.Image))
+	}
+
+	var still []byte
+	compositeGIF(g, image.Transparent, i+1, func(_ int, canvas *image.RGBA) {
+		still = ResizeRGB(canvas)
+	})
+	return &Image{
+		Type:       ImageTypeStatic,
+		StaticData: still,
+	}, nil
+}
+
+// CompositeGIF renders every frame of g over the previous ones, honoring
+// their disposal methods, on a canvas filled with the background color.
+// Transparent regions, and regions disposed to the background, show the
+// background color. Returns a full canvas per frame.
+func CompositeGIF(g *gif.GIF, background Color) []*image.RGBA {
+	bg := image.NewUniform(color.RGBA{background[0], background[1], background[2], 255})
+	frames := make([]*image.RGBA, 0, len(g.Image))
+	compositeGIF(g, bg, len(g.Image), func(_ int, canvas *image.RGBA) {
+		frames = append(frames, image.NewRGBA(canvas.Bounds()))
+		copy(frames[len(frames)-1].Pix, canvas.Pix)
+	})
+	return frames
+}
+
+// DownsampleGIF returns g with at most maxFrames frames, evenly sampled across
+// the whole animation. Each kept frame is shown for the summed delays of the
+// frames it replaces, so the animation keeps its duration and full motion at
+// a lower frame rate. Frames are dropped, so they should be full canvases
+// (e.g. composited with CompositeGIF). g is returned as is if it has no more
+// than maxFrames frames.
+func DownsampleGIF(g *gif.GIF, maxFrames int) *gif.GIF {
+	n := len(g.Image)
+	if maxFrames < 1 || n <= maxFrames {
+		return g
+	}
+
+	sampled := &gif.GIF{
+		Image:           make([]*image.Paletted, maxFrames),
+		Delay:           make([]int, maxFrames),
+		LoopCount:       g.LoopCount,
+		Config:          g.Config,
+		BackgroundIndex: g.BackgroundIndex,
+	}
+	if len(g.Disposal) == n {
+		sampled.Disposal = make([]byte, maxFrames)
+	}
+	for i := 0; i < maxFrames; i++ {
+		start, end := sampleRange(i, n, maxFrames)
+		sampled.Image[i] = g.Image[start]
+		for _, delay := range g.Delay[start:end] {
+			sampled.Delay[i] += delay
+		}
+		if sampled.Disposal != nil {
+			sampled.Disposal[i] = g.Disposal[start]
+		}
+	}
+	return sampled
+}
+
+// DownsampleFrames is like DownsampleGIF for frames that aren't quantized yet,
+// returning at most maxFrames frames with their delays. Sampling them before
+// quantizing skips the dropped ones. frames and delays must have the same
+// length. They are returned as is if there are no more than maxFrames frames.
+func DownsampleFrames(frames []*image.RGBA, delays []int, maxFrames int) ([]*image.RGBA, []int) {
+	n := len(frames)
+	if maxFrames < 1 || n <= maxFrames {
+		return frames, delays
+	}
+
+	sampledFrames := make([]*image.RGBA, maxFrames)
+	sampledDelays := make([]int, maxFrames)
+	for i := 0; i < maxFrames; i++ {
+		start, end := sampleRange(i, n, maxFrames)
+		sampledFrames[i] = frames[start]
+		for _, delay := range delays[start:end] {
+			sampledDelays[i] += delay
+		}
+	}
+	return sampledFrames, sampledDelays
+}
+
+// sampleRange returns the [start, end) range of the n frames replaced by kept
+// frame i, when keeping maxFrames frames. The kept frame is frames[start].
+func sampleRange(i, n, maxFrames int) (int, int) {
+	return i * n / maxFrames, (i + 1) * n / maxFrames
+}
+
+// Shortest frame the device shows (~60 FPS)
+const (
+	MinFrameTimeMs = 16
+	MinFrameDelay  = (MinFrameTimeMs + 9) / 10 // As a GIF delay in 1/100s, rounded up
+)
+
+// FitGIFDuration returns g with its frame delays scaled down proportionally, so
+// one loop lasts at most maxDurationMs: the animation plays faster, keeping
+// the relative timing of its frames. Every frame keeps a delay of at least
+// MinFrameDelay, so a GIF with many frames may still last longer than
+// maxDurationMs. g is returned as is if it is already short enough.
+func FitGIFDuration(g *gif.GIF, maxDurationMs int) *gif.GIF {
+	total := 0
+	for _, delay := range g.Delay {
+		total += delay
+	}
+	maxTotal := maxDurationMs / 10 // Delays are in 1/100s
+	if total <= maxTotal || total == 0 {
+		return g
+	}
+
+	fitted := *g
+	fitted.Delay = make([]int, len(g.Delay))
+	for i, delay := range g.Delay {
+		fitted.Delay[i] = max(MinFrameDelay, delay*maxTotal/total)
+	}
+	return &fitted
+}
+
+// compositeGIF composites the first n frames of g on a canvas filled with
+// background, calling fn with the canvas as each frame is shown.
+func compositeGIF(g *gif.GIF, background image.Image, n int, fn func(i int, canvas *image.RGBA)) {
+	bounds := image.Rect(0, 0, g.Config.Width, g.Config.Height)
+	if bounds.Empty() && len(g.Image) > 0 {
+		bounds = g.Image[0].Bounds()
+	}
+	canvas := image.NewRGBA(bounds)
+	draw.Draw(canvas, bounds, background, image.Point{}, draw.Src)
+
+	for i, frame := range g.Image[:n] {
+		var disposal byte
+		if i < len(g.Disposal) {
+			disposal = g.Disposal[i]
+		}
+		var previous []byte
+		if disposal == gif.DisposalPrevious {
+			previous = append([]byte(nil), canvas.Pix...)
+		}
+
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+		fn(i, canvas)
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Bounds(), background, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			copy(canvas.Pix, previous)
+		}
+	}
+}
+
+// GIFMetadata describes an encoded GIF.
+type GIFMetadata struct {
+	Frames     int // Number of frames
+	DurationMs int // Total duration of one loop in milliseconds
+	Size       int // Encoded size in bytes
+}
+
+// GetGIFMetadata decodes an encoded GIF and returns its frame count, total
+// duration and size.
+func GetGIFMetadata(data []byte) (GIFMetadata, error) {
+	g, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil {
+		return GIFMetadata{}, fmt.Errorf("failed to decode GIF: %w", err)
+	}
+
+	durationMs := 0
+	for _, delay := range g.Delay {
+		durationMs += delay * 10 // GIF delays are in 1/100s
+	}
+
+	return GIFMetadata{
+		Frames:     len(g.Image),
+		DurationMs: durationMs,
+		Size:       len(data),
+	}, nil
+}
+
 // NewBuffer creates a new 64x64x3 black buffer.
 func NewBuffer() []byte {
-	return make([]byte, BufferSize)
+	return Display64.NewBuffer()
 }
 
 // NewBufferWithColor creates a new 64x64x3 buffer filled with the given color.
 func NewBufferWithColor(color Color) []byte {
-	buf := make([]byte, BufferSize)
-	for i := 0; i < DisplayWidth*DisplayHeight; i++ {
-		offset := i * 3
-		buf[offset] = color[0]
-		buf[offset+1] = color[1]
-		buf[offset+2] = color[2]
-	}
-	return buf
+	return Display64.NewBufferWithColor(color)
+}
+
+// NewBufferWithGradient creates a new 64x64x3 buffer filled with a vertical
+// gradient from the top color to the bottom color.
+func NewBufferWithGradient(top, bottom Color) []byte {
+	return Display64.NewBufferWithGradient(top, bottom, GradientVertical)
+}
+
+// NewBufferWithGradientDirection creates a new 64x64x3 buffer filled with a
+// gradient in the given direction.
+func NewBufferWithGradientDirection(from, to Color, direction GradientDirection) []byte {
+	return Display64.NewBufferWithGradient(from, to, direction)
 }
 
 // SetPixel sets a single pixel in the RGB image buffer.
 // Coordinates outside the display bounds are silently ignored.
 func SetPixel(buf []byte, x, y int, color Color) {
-	if x < 0 || x >= DisplayWidth || y < 0 || y >= DisplayHeight {
-		return
-	}
-	offset := (y*DisplayWidth + x) * 3
-	buf[offset] = color[0]
-	buf[offset+1] = color[1]
-	buf[offset+2] = color[2]
+	Display64.SetPixel(buf, x, y, color)
+}
+
+// BlendPixel mixes color into the pixel already in the RGB image buffer, with
+// alpha from 0 (keep the existing pixel) to 1 (same as SetPixel).
+// Coordinates outside the display bounds are silently ignored.
+func BlendPixel(buf []byte, x, y int, color Color, alpha float64) {
+	Display64.BlendPixel(buf, x, y, color, alpha)
 }
 
 // ImageToRGB converts an image.Image to a 64x64x3 RGB buffer.
 func ImageToRGB(img image.Image) []byte {
-	buf := make([]byte, BufferSize)
-	bounds := img.Bounds()
-	for y := 0; y < DisplayHeight; y++ {
-		for x := 0; x < DisplayWidth; x++ {
-			srcX := bounds.Min.X + x
-			srcY := bounds.Min.Y + y
-			if srcX < bounds.Max.X && srcY < bounds.Max.Y {
-				r, g, b, _ := img.At(srcX, srcY).RGBA()
-				offset := (y*DisplayWidth + x) * 3
-				buf[offset] = uint8(r >> 8)
-				buf[offset+1] = uint8(g >> 8)
-				buf[offset+2] = uint8(b >> 8)
-			}
-		}
-	}
-	return buf
+	return Display64.ImageToRGB(img)
+}
+
+// ResizeRGB scales an image of any size to a 64x64x3 RGB buffer using
+// nearest-neighbor sampling. A 64x64 image is converted as is.
+func ResizeRGB(img image.Image) []byte {
+	return Display64.ResizeRGB(img)
 }
 
 // RGBToPaletted converts an RGB buffer to a paletted image for GIF encoding.
 func RGBToPaletted(rgbBuf []byte) *image.Paletted {
-	rgba := image.NewRGBA(image.Rect(0, 0, DisplayWidth, DisplayHeight))
-	for y := 0; y < DisplayHeight; y++ {
-		for x := 0; x < DisplayWidth; x++ {
-			offset := (y*DisplayWidth + x) * 3
-			rgba.Set(x, y, color.RGBA{
-				R: rgbBuf[offset],
-				G: rgbBuf[offset+1],
-				B: rgbBuf[offset+2],
-				A: 255,
-			})
-		}
-	}
-	paletted := image.NewPaletted(rgba.Bounds(), palette.Plan9)
-	draw.Draw(paletted, paletted.Bounds(), rgba, image.Point{}, draw.Src)
-	return paletted
+	return Display64.RGBToPaletted(rgbBuf)
+}
+
+// RGBToPalettedDithered converts an RGB buffer to a paletted image for GIF
+// encoding, using the Plan9 palette with Floyd-Steinberg dithering.
+func RGBToPalettedDithered(rgbBuf []byte) *image.Paletted {
+	return Display64.RGBToPalettedDithered(rgbBuf)
+}
+
+// RGBToPalettedMedianCut converts an RGB buffer to a paletted image for GIF
+// encoding, using a median cut palette of at most maxColors colors.
+func RGBToPalettedMedianCut(rgbBuf []byte, maxColors int) *image.Paletted {
+	return Display64.RGBToPalettedMedianCut(rgbBuf, maxColors)
 }
@@ -1,9 +1,11 @@
 package graphic
 
 import (
+	"bytes"
 	"image"
 	"image/color"
 	"image/gif"
+	"image/png"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -103,6 +105,89 @@ func TestShadowFor(t *testing.T) {
 	})
 }
 
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Color
+	}{
+		{input: "red", expected: Red},
+		{input: " Orange ", expected: Orange},
+		{input: "#ff8800", expected: Color{255, 136, 0}},
+		{input: "FF8800", expected: Color{255, 136, 0}},
+		{input: "#000000", expected: Black},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			color, err := ParseColor(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, color)
+		})
+	}
+
+	for _, input := range []string{"", "rainbow", "#ff88", "#ff88000", "#gg8800", "##ff8800"} {
+		t.Run("invalid "+input, func(t *testing.T) {
+			_, err := ParseColor(input)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDominantColor(t *testing.T) {
+	t.Run("90% red", func(t *testing.T) {
+		buf := NewBufferWithColor(Red)
+		for y := 0; y < DisplayHeight; y++ {
+			for x := 0; x < DisplayWidth; x++ {
+				switch {
+				case (y*DisplayWidth+x)%20 == 0:
+					SetPixel(buf, x, y, Blue)
+				case (y*DisplayWidth+x)%20 == 1:
+					SetPixel(buf, x, y, Green)
+				}
+			}
+		}
+		assert.Equal(t, Red, DominantColor(buf))
+	})
+
+	t.Run("similar shades are averaged", func(t *testing.T) {
+		buf := []byte{200, 0, 0, 202, 0, 0, 0, 0, 255}
+		assert.Equal(t, Color{201, 0, 0}, DominantColor(buf))
+	})
+
+	t.Run("empty buffer", func(t *testing.T) {
+		assert.Equal(t, Black, DominantColor(nil))
+	})
+}
+
+func TestColorHex(t *testing.T) {
+	assert.Equal(t, "#ff8800", Color{255, 136, 0}.Hex())
+	assert.Equal(t, "#000000", Black.Hex())
+}
+
+func TestHSVToColor(t *testing.T) {
+	tests := []struct {
+		name     string
+		h, s, v  float64
+		expected Color
+	}{
+		{name: "red", h: 0, s: 1, v: 1, expected: Red},
+		{name: "yellow", h: 60, s: 1, v: 1, expected: Yellow},
+		{name: "green", h: 120, s: 1, v: 1, expected: Green},
+		{name: "cyan", h: 180, s: 1, v: 1, expected: Cyan},
+		{name: "blue", h: 240, s: 1, v: 1, expected: Blue},
+		{name: "magenta", h: 300, s: 1, v: 1, expected: Magenta},
+		{name: "hue wraps around", h: 360, s: 1, v: 1, expected: Red},
+		{name: "negative hue wraps around", h: -120, s: 1, v: 1, expected: Blue},
+		{name: "zero saturation is gray", h: 200, s: 0, v: 0.5, expected: Color{128, 128, 128}},
+		{name: "zero value is black", h: 90, s: 1, v: 0, expected: Black},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, HSVToColor(tt.h, tt.s, tt.v))
+		})
+	}
+}
+
 func TestImageRawBytes(t *testing.T) {
 	t.Run("static image returns StaticData", func(t *testing.T) {
 		staticData := NewBufferWithColor(Red)
@@ -157,3 +242,382 @@ func TestImageGIFBytes(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestResizeRGB(t *testing.T) {
+	t.Run("downscales a larger image", func(t *testing.T) {
+		// 128x128 image: left half red, right half blue
+		src := image.NewRGBA(image.Rect(0, 0, 128, 128))
+		for y := 0; y < 128; y++ {
+			for x := 0; x < 128; x++ {
+				c := color.RGBA{255, 0, 0, 255}
+				if x >= 64 {
+					c = color.RGBA{0, 0, 255, 255}
+				}
+				src.Set(x, y, c)
+			}
+		}
+
+		buf := ResizeRGB(src)
+		require.Len(t, buf, BufferSize)
+		assert.Equal(t, Red[:], buf[0:3])
+		offset := (10*DisplayWidth + 31) * 3
+		assert.Equal(t, Red[:], buf[offset:offset+3])
+		offset = (10*DisplayWidth + 32) * 3
+		assert.Equal(t, Blue[:], buf[offset:offset+3])
+	})
+
+	t.Run("upscales a smaller image", func(t *testing.T) {
+		// 2x2 image scaled to 32x32 pixel quadrants
+		src := image.NewRGBA(image.Rect(0, 0, 2, 2))
+		src.Set(1, 1, color.RGBA{0, 255, 0, 255})
+
+		buf := ResizeRGB(src)
+		offset := (31*DisplayWidth + 31) * 3
+		assert.Equal(t, Black[:], buf[offset:offset+3])
+		offset = (32*DisplayWidth + 32) * 3
+		assert.Equal(t, Green[:], buf[offset:offset+3])
+	})
+
+	t.Run("64x64 image matches ImageToRGB", func(t *testing.T) {
+		src := image.NewRGBA(image.Rect(0, 0, DisplayWidth, DisplayHeight))
+		src.Set(5, 7, color.RGBA{1, 2, 3, 255})
+		assert.Equal(t, ImageToRGB(src), ResizeRGB(src))
+	})
+}
+
+func TestGetGIFMetadata(t *testing.T) {
+	img := &Image{
+		Type: ImageTypeAnimated,
+		GIFData: &gif.GIF{
+			Image: []*image.Paletted{
+				RGBToPaletted(NewBuffer()),
+				RGBToPaletted(NewBufferWithColor(Red)),
+				RGBToPaletted(NewBufferWithColor(Green)),
+			},
+			Delay: []int{10, 20, 5},
+		},
+	}
+	data, err := img.GIFBytes()
+	require.NoError(t, err)
+
+	metadata, err := GetGIFMetadata(data)
+	require.NoError(t, err)
+	assert.Equal(t, GIFMetadata{Frames: 3, DurationMs: 350, Size: len(data)}, metadata)
+
+	_, err = GetGIFMetadata([]byte("not a gif"))
+	assert.Error(t, err)
+}
+
+func TestImagePNGBytes(t *testing.T) {
+	t.Run("static image", func(t *testing.T) {
+		img := &Image{Type: ImageTypeStatic, StaticData: NewBufferWithColor(Red)}
+		data, err := img.PNGBytes()
+		require.NoError(t, err)
+
+		decoded, err := png.Decode(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, image.Rect(0, 0, DisplayWidth, DisplayHeight), decoded.Bounds())
+		assert.Equal(t, color.RGBA{Red[0], Red[1], Red[2], 255}, color.RGBAModel.Convert(decoded.At(DisplayWidth-1, DisplayHeight-1)))
+	})
+
+	t.Run("animated image uses the first frame", func(t *testing.T) {
+		img := &Image{
+			Type: ImageTypeAnimated,
+			GIFData: &gif.GIF{
+				Image: []*image.Paletted{
+					RGBToPaletted(NewBufferWithColor(Red)),
+					RGBToPaletted(NewBufferWithColor(Green)),
+				},
+				Delay: []int{10, 10},
+			},
+		}
+		data, err := img.PNGBytes()
+		require.NoError(t, err)
+
+		decoded, err := png.Decode(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, image.Rect(0, 0, DisplayWidth, DisplayHeight), decoded.Bounds())
+		assert.Equal(t, color.RGBA{Red[0], Red[1], Red[2], 255}, color.RGBAModel.Convert(decoded.At(0, 0)))
+	})
+
+	t.Run("invalid images", func(t *testing.T) {
+		_, err := (&Image{Type: ImageTypeStatic, StaticData: []byte{1, 2, 3}}).PNGBytes()
+		assert.Error(t, err)
+
+		_, err = (&Image{Type: ImageTypeAnimated, GIFData: &gif.GIF{}}).PNGBytes()
+		assert.Error(t, err)
+	})
+}
+
+func TestImageFrame(t *testing.T) {
+	first := NewBufferWithColor(Red)
+	SetPixel(first, 10, 20, Blue)
+
+	// The second frame only covers a 4x4 square in the top-left corner
+	pal := color.Palette{color.RGBA{Green[0], Green[1], Green[2], 255}}
+	patch := image.NewPaletted(image.Rect(0, 0, 4, 4), pal)
+
+	twoFrames := func(disposal byte) *Image {
+		return &Image{
+			Type: ImageTypeAnimated,
+			GIFData: &gif.GIF{
+				Image:    []*image.Paletted{RGBToPaletted(first), patch},
+				Delay:    []int{10, 10},
+				Disposal: []byte{disposal, 0},
+				Config:   image.Config{Width: DisplayWidth, Height: DisplayHeight},
+			},
+		}
+	}
+
+	t.Run("first frame", func(t *testing.T) {
+		frame, err := twoFrames(0).Frame(0)
+		require.NoError(t, err)
+		assert.Equal(t, ImageTypeStatic, frame.Type)
+		assert.Equal(t, first, frame.StaticData)
+	})
+
+	t.Run("partial frame is composited over the previous ones", func(t *testing.T) {
+		frame, err := twoFrames(gif.DisposalNone).Frame(1)
+		require.NoError(t, err)
+
+		expected := append([]byte(nil), first...)
+		for y := 0; y < 4; y++ {
+			for x := 0; x < 4; x++ {
+				SetPixel(expected, x, y, Green)
+			}
+		}
+		assert.Equal(t, expected, frame.StaticData)
+	})
+
+	t.Run("background disposal clears the previous frame", func(t *testing.T) {
+		frame, err := twoFrames(gif.DisposalBackground).Frame(1)
+		require.NoError(t, err)
+
+		expected := NewBuffer()
+		for y := 0; y < 4; y++ {
+			for x := 0; x < 4; x++ {
+				SetPixel(expected, x, y, Green)
+			}
+		}
+		assert.Equal(t, expected, frame.StaticData)
+	})
+
+	t.Run("invalid frames", func(t *testing.T) {
+		_, err := twoFrames(0).Frame(2)
+		assert.Error(t, err)
+		_, err = twoFrames(0).Frame(-1)
+		assert.Error(t, err)
+		_, err = (&Image{Type: ImageTypeStatic, StaticData: first}).Frame(0)
+		assert.Error(t, err)
+	})
+}
+
+func TestCompositeGIF(t *testing.T) {
+	darkBlue := Color{0, 0, 80}
+	red := color.RGBA{Red[0], Red[1], Red[2], 255}
+
+	// The first frame is red on the left half and transparent on the right
+	pal := color.Palette{color.Transparent, red}
+	first := image.NewPaletted(image.Rect(0, 0, DisplayWidth, DisplayHeight), pal)
+	for y := 0; y < DisplayHeight; y++ {
+		for x := 0; x < DisplayWidth/2; x++ {
+			first.SetColorIndex(x, y, 1)
+		}
+	}
+	// The second frame only covers a 4x4 square on the right half
+	second := image.NewPaletted(image.Rect(40, 0, 44, 4), pal)
+	for i := range second.Pix {
+		second.Pix[i] = 1
+	}
+
+	g := &gif.GIF{
+		Image:    []*image.Paletted{first, second},
+		Delay:    []int{10, 10},
+		Disposal: []byte{gif.DisposalBackground, gif.DisposalNone},
+		Config:   image.Config{Width: DisplayWidth, Height: DisplayHeight},
+	}
+	frames := CompositeGIF(g, darkBlue)
+	require.Len(t, frames, 2)
+
+	bg := color.RGBA{darkBlue[0], darkBlue[1], darkBlue[2], 255}
+
+	// The transparent region shows the background color
+	assert.Equal(t, red, frames[0].RGBAAt(0, 0))
+	assert.Equal(t, bg, frames[0].RGBAAt(DisplayWidth-1, DisplayHeight-1))
+
+	// The first frame was disposed to the background before the second
+	assert.Equal(t, bg, frames[1].RGBAAt(0, 0))
+	assert.Equal(t, red, frames[1].RGBAAt(40, 0))
+	assert.Equal(t, bg, frames[1].RGBAAt(DisplayWidth-1, DisplayHeight-1))
+}
+
+func TestDownsampleGIF(t *testing.T) {
+	pal := color.Palette{color.Black, color.White}
+	g := &gif.GIF{LoopCount: 3}
+	for i := 0; i < 128; i++ {
+		g.Image = append(g.Image, image.NewPaletted(image.Rect(0, 0, DisplayWidth, DisplayHeight), pal))
+		g.Delay = append(g.Delay, 5)
+		g.Disposal = append(g.Disposal, gif.DisposalBackground)
+	}
+
+	sampled := DownsampleGIF(g, 64)
+	require.Len(t, sampled.Image, 64)
+	require.Len(t, sampled.Delay, 64)
+	require.Len(t, sampled.Disposal, 64)
+	assert.Equal(t, 3, sampled.LoopCount)
+
+	// Frames are sampled across the whole animation, keeping its duration
+	assert.Same(t, g.Image[0], sampled.Image[0])
+	assert.Same(t, g.Image[2], sampled.Image[1])
+	assert.Same(t, g.Image[126], sampled.Image[63])
+	total := 0
+	for _, delay := range sampled.Delay {
+		assert.Equal(t, 10, delay)
+		total += delay
+	}
+	assert.Equal(t, 128*5, total)
+
+	// Uneven ratios spread the kept frames and still keep the duration
+	sampled = DownsampleGIF(g, 50)
+	require.Len(t, sampled.Image, 50)
+	total = 0
+	for _, delay := range sampled.Delay {
+		total += delay
+	}
+	assert.Equal(t, 128*5, total)
+
+	// GIFs within the limit are returned as is
+	assert.Same(t, g, DownsampleGIF(g, 128))
+}
+
+func TestDownsampleFrames(t *testing.T) {
+	var frames []*image.RGBA
+	var delays []int
+	for i := 0; i < 128; i++ {
+		frames = append(frames, image.NewRGBA(image.Rect(0, 0, DisplayWidth, DisplayHeight)))
+		delays = append(delays, 5)
+	}
+
+	sampledFrames, sampledDelays := DownsampleFrames(frames, delays, 64)
+	require.Len(t, sampledFrames, 64)
+	require.Len(t, sampledDelays, 64)
+
+	// Frames are sampled across the whole animation, keeping its duration
+	assert.Same(t, frames[0], sampledFrames[0])
+	assert.Same(t, frames[2], sampledFrames[1])
+	assert.Same(t, frames[126], sampledFrames[63])
+	total := 0
+	for _, delay := range sampledDelays {
+		assert.Equal(t, 10, delay)
+		total += delay
+	}
+	assert.Equal(t, 128*5, total)
+
+	// Uneven ratios spread the kept frames and still keep the duration
+	sampledFrames, sampledDelays = DownsampleFrames(frames, delays, 50)
+	require.Len(t, sampledFrames, 50)
+	total = 0
+	for _, delay := range sampledDelays {
+		total += delay
+	}
+	assert.Equal(t, 128*5, total)
+
+	// Animations within the limit are returned as is
+	sampledFrames, sampledDelays = DownsampleFrames(frames, delays, 128)
+	assert.Equal(t, frames, sampledFrames)
+	assert.Equal(t, delays, sampledDelays)
+}
+
+func TestFitGIFDuration(t *testing.T) {
+	frame := image.NewPaletted(image.Rect(0, 0, DisplayWidth, DisplayHeight), color.Palette{color.Black})
+	newGIF := func(delays ...int) *gif.GIF {
+		g := &gif.GIF{Delay: delays, LoopCount: 2}
+		for range delays {
+			g.Image = append(g.Image, frame)
+		}
+		return g
+	}
+	totalMs := func(g *gif.GIF) int {
+		total := 0
+		for _, delay := range g.Delay {
+			total += delay * 10
+		}
+		return total
+	}
+
+	t.Run("4000ms GIF is sped up to 2000ms", func(t *testing.T) {
+		g := newGIF(20, 40, 100, 240)
+		require.Equal(t, 4000, totalMs(g))
+
+		fitted := FitGIFDuration(g, 2000)
+		assert.LessOrEqual(t, totalMs(fitted), 2000)
+		assert.Equal(t, []int{10, 20, 50, 120}, fitted.Delay)
+		assert.Len(t, fitted.Image, 4)
+		assert.Equal(t, 2, fitted.LoopCount)
+		assert.Equal(t, []int{20, 40, 100, 240}, g.Delay, "the input is not modified")
+	})
+
+	t.Run("uneven delays stay within the limit", func(t *testing.T) {
+		fitted := FitGIFDuration(newGIF(7, 13, 29, 51, 3), 500)
+		assert.LessOrEqual(t, totalMs(fitted), 500)
+		for _, delay := range fitted.Delay {
+			assert.GreaterOrEqual(t, delay, MinFrameDelay)
+		}
+	})
+
+	t.Run("short frames are kept at the device minimum", func(t *testing.T) {
+		fitted := FitGIFDuration(newGIF(2, 2, 2, 194), 1000)
+		assert.Equal(t, []int{2, 2, 2, 97}, fitted.Delay)
+		assert.Equal(t, 2, MinFrameDelay, "16ms rounds up to 20ms")
+	})
+
+	t.Run("short GIFs are returned as is", func(t *testing.T) {
+		g := newGIF(50, 50)
+		assert.Same(t, g, FitGIFDuration(g, 2000))
+	})
+}
+
+func TestBlendPixel(t *testing.T) {
+	background := Color{100, 50, 200}
+	c := Color{200, 150, 0}
+
+	t.Run("half alpha yields the midpoint", func(t *testing.T) {
+		buf := NewBufferWithColor(background)
+		BlendPixel(buf, 10, 20, c, 0.5)
+		offset := (20*DisplayWidth + 10) * 3
+		assert.Equal(t, []byte{150, 100, 100}, buf[offset:offset+3])
+	})
+
+	t.Run("full alpha equals SetPixel", func(t *testing.T) {
+		blended := NewBufferWithColor(background)
+		BlendPixel(blended, 10, 20, c, 1.0)
+		set := NewBufferWithColor(background)
+		SetPixel(set, 10, 20, c)
+		assert.Equal(t, set, blended)
+	})
+
+	t.Run("zero alpha keeps the existing pixel", func(t *testing.T) {
+		buf := NewBufferWithColor(background)
+		BlendPixel(buf, 10, 20, c, 0)
+		assert.Equal(t, NewBufferWithColor(background), buf)
+	})
+
+	t.Run("alpha is clamped", func(t *testing.T) {
+		above := NewBufferWithColor(background)
+		BlendPixel(above, 10, 20, c, 2)
+		set := NewBufferWithColor(background)
+		SetPixel(set, 10, 20, c)
+		assert.Equal(t, set, above)
+
+		below := NewBufferWithColor(background)
+		BlendPixel(below, 10, 20, c, -1)
+		assert.Equal(t, NewBufferWithColor(background), below)
+	})
+
+	t.Run("out of bounds is ignored", func(t *testing.T) {
+		buf := NewBufferWithColor(background)
+		BlendPixel(buf, -1, 0, c, 0.5)
+		BlendPixel(buf, 0, DisplayHeight, c, 0.5)
+		assert.Equal(t, NewBufferWithColor(background), buf)
+	})
+}
@@ -0,0 +1,65 @@
+package graphic
+
+import (
+	"image"
+	"image/gif"
+	"math"
+)
+
+// PulseOptions configures a pulse ("breathing") brightness animation.
+type PulseOptions struct {
+	MinPercent int // Brightness at the start and end of each cycle (0-100)
+	MaxPercent int // Brightness in the middle of each cycle (0-100)
+	Frames     int // Frames per cycle
+	FrameDelay int // Delay per frame (10ms units)
+	Loops      int // GIF loop count (0 loops forever)
+}
+
+// DefaultPulseOptions returns a slow pulse between 20% and 100% brightness.
+func DefaultPulseOptions() PulseOptions {
+	return PulseOptions{
+		MinPercent: 20,
+		MaxPercent: 100,
+		Frames:     16,
+		FrameDelay: 10, // 100ms, 1.6s per cycle
+		Loops:      0,  // Loop forever
+	}
+}
+
+// PulsePercent returns the brightness of the given frame of a pulse cycle. It
+// follows a sine curve from MinPercent at frame 0 to MaxPercent half way
+// through the cycle, and back.
+func (opts PulseOptions) PulsePercent(frame int) int {
+	if opts.Frames <= 1 {
+		return opts.MaxPercent
+	}
+	phase := float64(frame) / float64(opts.Frames)
+	eased := (1 - math.Cos(2*math.Pi*phase)) / 2
+	return opts.MinPercent + int(math.Round(eased*float64(opts.MaxPercent-opts.MinPercent)))
+}
+
+// GeneratePulse creates a looping animation of the static RGB buffer pulsing
+// in brightness, scaling each frame with ScaleBrightness.
+func (d Display) GeneratePulse(buf []byte, opts PulseOptions) *Image {
+	frames := max(opts.Frames, 1)
+	g := &gif.GIF{
+		Image:     make([]*image.Paletted, frames),
+		Delay:     make([]int, frames),
+		LoopCount: opts.Loops,
+	}
+	for i := 0; i < frames; i++ {
+		g.Image[i] = d.RGBToPaletted(ScaleBrightness(buf, opts.PulsePercent(i)))
+		g.Delay[i] = opts.FrameDelay
+	}
+
+	return &Image{
+		Type:    ImageTypeAnimated,
+		GIFData: g,
+	}
+}
+
+// GeneratePulse creates a looping animation of the static 64x64 RGB buffer
+// pulsing in brightness.
+func GeneratePulse(buf []byte, opts PulseOptions) *Image {
+	return Display64.GeneratePulse(buf, opts)
+}
@@ -0,0 +1,61 @@
+package graphic
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// frameBrightness returns the sum of all RGB channels of a frame
+func frameBrightness(frame *image.Paletted) int {
+	total := 0
+	bounds := frame.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.RGBAModel.Convert(frame.At(x, y)).(color.RGBA)
+			total += int(c.R) + int(c.G) + int(c.B)
+		}
+	}
+	return total
+}
+
+func TestPulsePercent(t *testing.T) {
+	opts := PulseOptions{MinPercent: 20, MaxPercent: 100, Frames: 8}
+
+	assert.Equal(t, 20, opts.PulsePercent(0))
+	assert.Equal(t, 60, opts.PulsePercent(2))
+	assert.Equal(t, 100, opts.PulsePercent(4))
+	assert.Equal(t, opts.PulsePercent(1), opts.PulsePercent(7))
+	assert.Equal(t, opts.PulsePercent(0), opts.PulsePercent(8))
+}
+
+func TestGeneratePulse(t *testing.T) {
+	opts := DefaultPulseOptions()
+	img := GeneratePulse(NewBufferWithColor(White), opts)
+
+	require.Equal(t, ImageTypeAnimated, img.Type)
+	frames := img.GIFData.Image
+	require.Len(t, frames, opts.Frames)
+	assert.Equal(t, opts.Loops, img.GIFData.LoopCount)
+	for _, delay := range img.GIFData.Delay {
+		assert.Equal(t, opts.FrameDelay, delay)
+	}
+
+	first := frameBrightness(frames[0])
+	middle := frameBrightness(frames[len(frames)/2])
+	assert.Greater(t, middle, first)
+
+	// Brightness rises up to the middle, then falls back towards the start
+	for i := 1; i < len(frames); i++ {
+		assert.Equal(t, frameBrightness(frames[i]), frameBrightness(frames[len(frames)-i]), "frame %d", i)
+	}
+	assert.Less(t, frameBrightness(frames[len(frames)-1]), middle)
+}
+
+func TestGeneratePulse32(t *testing.T) {
+	img := Display32.GeneratePulse(Display32.NewBufferWithColor(Red), DefaultPulseOptions())
+	assert.Equal(t, image.Rect(0, 0, 32, 32), img.GIFData.Image[0].Bounds())
+}
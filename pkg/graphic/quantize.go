@@ -0,0 +1,180 @@
+package graphic
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"slices"
+	"sort"
+)
+
+// MaxPaletteColors is the maximum number of colors in a GIF palette
+const MaxPaletteColors = 256
+
+// colorBox is a set of pixel colors for median cut quantization
+type colorBox []color.RGBA
+
+// widestChannel returns the channel (0=R, 1=G, 2=B) with the widest range of
+// values in the box, and the range.
+func (b colorBox) widestChannel() (int, int) {
+	lo := [3]uint8{255, 255, 255}
+	hi := [3]uint8{0, 0, 0}
+	for _, c := range b {
+		for i, v := range [3]uint8{c.R, c.G, c.B} {
+			lo[i] = min(lo[i], v)
+			hi[i] = max(hi[i], v)
+		}
+	}
+
+	channel, width := 0, -1
+	for i := 0; i < 3; i++ {
+		if w := int(hi[i]) - int(lo[i]); w > width {
+			channel, width = i, w
+		}
+	}
+	return channel, width
+}
+
+// average returns the mean color of the box
+func (b colorBox) average() color.RGBA {
+	var r, g, bl int
+	for _, c := range b {
+		r += int(c.R)
+		g += int(c.G)
+		bl += int(c.B)
+	}
+	n := len(b)
+	return color.RGBA{uint8((r + n/2) / n), uint8((g + n/2) / n), uint8((bl + n/2) / n), 255}
+}
+
+// MedianCutPalette builds a palette of at most maxColors colors for the image
+// using median cut: the set of pixel colors is repeatedly split near the
+// median of its widest channel, and each final set contributes its average
+// color.
+func MedianCutPalette(rgba *image.RGBA, maxColors int) color.Palette {
+	return medianCutPalette(appendPixels(nil, rgba), maxColors)
+}
+
+// MedianCutPaletteFrames builds a single median cut palette of at most
+// maxColors colors shared by all the frames, so the colors of an animation
+// stay consistent from frame to frame.
+func MedianCutPaletteFrames(frames []*image.RGBA, maxColors int) color.Palette {
+	var pixels colorBox
+	for _, frame := range frames {
+		pixels = appendPixels(pixels, frame)
+	}
+	return medianCutPalette(pixels, maxColors)
+}
+
+// appendPixels appends the opaque colors of the image pixels to the box
+func appendPixels(pixels colorBox, rgba *image.RGBA) colorBox {
+	bounds := rgba.Bounds()
+	pixels = slices.Grow(pixels, bounds.Dx()*bounds.Dy())
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := rgba.RGBAAt(x, y)
+			c.A = 255
+			pixels = append(pixels, c)
+		}
+	}
+	return pixels
+}
+
+func medianCutPalette(pixels colorBox, maxColors int) color.Palette {
+	maxColors = max(1, min(maxColors, MaxPaletteColors))
+	if len(pixels) == 0 {
+		return color.Palette{color.RGBA{0, 0, 0, 255}}
+	}
+
+	boxes := []colorBox{pixels}
+	for len(boxes) < maxColors {
+		// Split the box with the widest channel range
+		best, bestChannel, bestWidth := -1, 0, 0
+		for i, box := range boxes {
+			if len(box) < 2 {
+				continue
+			}
+			if channel, width := box.widestChannel(); width > bestWidth {
+				best, bestChannel, bestWidth = i, channel, width
+			}
+		}
+		if best < 0 {
+			break // Every box holds a single color
+		}
+
+		box := boxes[best]
+		sort.Slice(box, func(i, j int) bool {
+			return channelValue(box[i], bestChannel) < channelValue(box[j], bestChannel)
+		})
+		split := splitIndex(box, bestChannel)
+		boxes[best] = box[:split]
+		boxes = append(boxes, box[split:])
+	}
+
+	palette := make(color.Palette, len(boxes))
+	for i, box := range boxes {
+		palette[i] = box.average()
+	}
+	return palette
+}
+
+// splitIndex returns the index closest to the median of the box, sorted by
+// channel, where the channel value changes, so no color ends up in both halves.
+func splitIndex(box colorBox, channel int) int {
+	median := len(box) / 2
+	for offset := 0; offset < len(box); offset++ {
+		for _, i := range []int{median - offset, median + offset} {
+			if i > 0 && i < len(box) && channelValue(box[i-1], channel) != channelValue(box[i], channel) {
+				return i
+			}
+		}
+	}
+	return median
+}
+
+func channelValue(c color.RGBA, channel int) uint8 {
+	switch channel {
+	case 0:
+		return c.R
+	case 1:
+		return c.G
+	default:
+		return c.B
+	}
+}
+
+// QuantizeToPaletted converts the image to a paletted image using a median
+// cut palette of at most maxColors colors, which matches the source colors
+// more closely than a fixed palette.
+func QuantizeToPaletted(rgba *image.RGBA, maxColors int) *image.Paletted {
+	return quantizeToPaletted(rgba, maxColors, draw.Src)
+}
+
+// QuantizeToPalettedDithered is like QuantizeToPaletted, with Floyd-Steinberg dithering.
+func QuantizeToPalettedDithered(rgba *image.RGBA, maxColors int) *image.Paletted {
+	return quantizeToPaletted(rgba, maxColors, draw.FloydSteinberg)
+}
+
+func quantizeToPaletted(rgba *image.RGBA, maxColors int, drawer draw.Drawer) *image.Paletted {
+	paletted := image.NewPaletted(rgba.Bounds(), MedianCutPalette(rgba, maxColors))
+	drawer.Draw(paletted, paletted.Bounds(), rgba, rgba.Bounds().Min)
+	return paletted
+}
+
+// QuantizeFrames converts the frames to paletted images sharing a median cut
+// palette of at most maxColors colors built from all of them. dither applies
+// Floyd-Steinberg dithering.
+func QuantizeFrames(frames []*image.RGBA, maxColors int, dither bool) []*image.Paletted {
+	var drawer draw.Drawer = draw.Src
+	if dither {
+		drawer = draw.FloydSteinberg
+	}
+
+	pal := MedianCutPaletteFrames(frames, maxColors)
+	paletted := make([]*image.Paletted, len(frames))
+	for i, frame := range frames {
+		paletted[i] = image.NewPaletted(frame.Bounds(), pal)
+		drawer.Draw(paletted[i], paletted[i].Bounds(), frame, frame.Bounds().Min)
+	}
+	return paletted
+}
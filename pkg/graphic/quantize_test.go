@@ -0,0 +1,110 @@
+package graphic
+
+import (
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// gradientImage returns a 64x64 image with a red/green gradient over a fixed blue
+func gradientImage() *image.RGBA {
+	rgba := image.NewRGBA(image.Rect(0, 0, DisplayWidth, DisplayHeight))
+	for y := 0; y < DisplayHeight; y++ {
+		for x := 0; x < DisplayWidth; x++ {
+			rgba.SetRGBA(x, y, color.RGBA{uint8(x * 4), uint8(y * 4), 100, 255})
+		}
+	}
+	return rgba
+}
+
+// colorError returns the total squared RGB error between two images
+func colorError(a *image.RGBA, b image.Image) int {
+	total := 0
+	bounds := a.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			ca := a.RGBAAt(x, y)
+			cb := color.RGBAModel.Convert(b.At(x, y)).(color.RGBA)
+			for _, d := range []int{int(ca.R) - int(cb.R), int(ca.G) - int(cb.G), int(ca.B) - int(cb.B)} {
+				total += d * d
+			}
+		}
+	}
+	return total
+}
+
+func TestQuantizeToPalettedGradient(t *testing.T) {
+	src := gradientImage()
+
+	plan9 := image.NewPaletted(src.Bounds(), palette.Plan9)
+	draw.Draw(plan9, plan9.Bounds(), src, image.Point{}, draw.Src)
+
+	medianCut := QuantizeToPaletted(src, MaxPaletteColors)
+
+	assert.LessOrEqual(t, len(medianCut.Palette), MaxPaletteColors)
+	assert.Less(t, colorError(src, medianCut), colorError(src, plan9))
+}
+
+func TestMedianCutPaletteFewColors(t *testing.T) {
+	// An image with fewer colors than allowed gets exactly its colors back
+	buf := NewBufferWithColor(Red)
+	for x := 0; x < DisplayWidth; x++ {
+		SetPixel(buf, x, 0, Blue)
+	}
+	src := Display64.RGBToRGBA(buf)
+
+	pal := MedianCutPalette(src, 16)
+	assert.ElementsMatch(t, color.Palette{
+		color.RGBA{Red[0], Red[1], Red[2], 255},
+		color.RGBA{Blue[0], Blue[1], Blue[2], 255},
+	}, pal)
+	assert.Zero(t, colorError(src, QuantizeToPaletted(src, 16)))
+}
+
+func TestMedianCutPaletteMaxColors(t *testing.T) {
+	src := gradientImage()
+	assert.Len(t, MedianCutPalette(src, 8), 8)
+	assert.Len(t, MedianCutPalette(src, 1), 1)
+	assert.Len(t, MedianCutPalette(src, 1000), MaxPaletteColors)
+}
+
+func TestQuantizeFrames(t *testing.T) {
+	// A synthetic animation: the gradient with its blue channel shifting per frame
+	frames := make([]*image.RGBA, 4)
+	for i := range frames {
+		frames[i] = gradientImage()
+		for p := 2; p < len(frames[i].Pix); p += 4 {
+			frames[i].Pix[p] = uint8(50 * i)
+		}
+	}
+
+	quantized := QuantizeFrames(frames, MaxPaletteColors, false)
+	assert.Len(t, quantized, len(frames))
+
+	medianCutError, plan9Error := 0, 0
+	for i, frame := range frames {
+		// Every frame shares the same palette
+		assert.Equal(t, quantized[0].Palette, quantized[i].Palette)
+		medianCutError += colorError(frame, quantized[i])
+
+		plan9 := image.NewPaletted(frame.Bounds(), palette.Plan9)
+		draw.Draw(plan9, plan9.Bounds(), frame, image.Point{}, draw.Src)
+		plan9Error += colorError(frame, plan9)
+	}
+	assert.Less(t, medianCutError, plan9Error)
+}
+
+func TestMedianCutPaletteFramesFewColors(t *testing.T) {
+	// Colors appearing in different frames all end up in the shared palette
+	red := Display64.RGBToRGBA(NewBufferWithColor(Red))
+	blue := Display64.RGBToRGBA(NewBufferWithColor(Blue))
+
+	assert.ElementsMatch(t, color.Palette{
+		color.RGBA{Red[0], Red[1], Red[2], 255},
+		color.RGBA{Blue[0], Blue[1], Blue[2], 255},
+	}, MedianCutPaletteFrames([]*image.RGBA{red, blue}, 16))
+}
@@ -0,0 +1,78 @@
+package graphic
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/gif"
+	"os"
+	"time"
+)
+
+// RecorderFinalDelay is how long the last recorded frame is shown (10ms units)
+const RecorderFinalDelay = 200
+
+// Recorder captures the RGB frames shown on the display, e.g. while playing a
+// game, and encodes them as an animated GIF that can be replayed with showgif.
+// It doesn't read the clock itself: callers pass the time each frame is shown,
+// which sets how long the previous frame is played back.
+type Recorder struct {
+	path   string
+	frames []*image.Paletted
+	delays []int
+	last   []byte    // RGB data of the last captured frame
+	lastAt time.Time // When the last captured frame was shown
+}
+
+// NewRecorder creates a recorder writing the GIF to path on Stop.
+func NewRecorder(path string) *Recorder {
+	return &Recorder{path: path}
+}
+
+// Capture records a 64x64 RGB frame shown at now. A frame identical to the
+// previous one is skipped, so the previous frame is played back for longer.
+func (r *Recorder) Capture(buf []byte, now time.Time) {
+	if r.last != nil && bytes.Equal(buf, r.last) {
+		return
+	}
+	if len(r.frames) > 0 {
+		r.delays[len(r.delays)-1] = max(1, int(now.Sub(r.lastAt)/(10*time.Millisecond)))
+	}
+
+	r.last = append(r.last[:0], buf...)
+	r.lastAt = now
+	r.frames = append(r.frames, RGBToPaletted(buf))
+	r.delays = append(r.delays, RecorderFinalDelay)
+}
+
+// Frames returns the number of frames captured so far.
+func (r *Recorder) Frames() int {
+	return len(r.frames)
+}
+
+// Image returns the captured frames as an animation that plays once.
+func (r *Recorder) Image() *Image {
+	return &Image{
+		Type: ImageTypeAnimated,
+		GIFData: &gif.GIF{
+			Image:     r.frames,
+			Delay:     r.delays,
+			LoopCount: -1, // Play once
+		},
+	}
+}
+
+// Stop encodes the captured frames and writes the GIF file.
+func (r *Recorder) Stop() error {
+	if len(r.frames) == 0 {
+		return fmt.Errorf("no frames recorded")
+	}
+	data, err := r.Image().GIFBytes()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(r.path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write recording: %w", err)
+	}
+	return nil
+}
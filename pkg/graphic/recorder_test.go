@@ -0,0 +1,35 @@
+package graphic
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "game.gif")
+	r := NewRecorder(path)
+
+	// Stopping without frames fails
+	assert.Error(t, r.Stop())
+
+	start := time.Now()
+	r.Capture(NewBufferWithColor(Red), start)
+	r.Capture(NewBufferWithColor(Red), start.Add(100*time.Millisecond)) // Identical, skipped
+	r.Capture(NewBufferWithColor(Green), start.Add(300*time.Millisecond))
+	r.Capture(NewBufferWithColor(Blue), start.Add(400*time.Millisecond))
+	require.Equal(t, 3, r.Frames())
+
+	require.NoError(t, r.Stop())
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	meta, err := GetGIFMetadata(data)
+	require.NoError(t, err)
+	assert.Equal(t, 3, meta.Frames)
+	assert.Equal(t, 300+100+RecorderFinalDelay*10, meta.DurationMs)
+}
@@ -0,0 +1,176 @@
+package graphic
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"os"
+)
+
+// Limits of the GIFs shown on the device
+const (
+	MaxGIFFrames     = 64   // Longer GIFs are downsampled
+	MaxGIFDurationMs = 2000 // Longer GIFs are sped up with ReencodeOptions.FitDuration
+)
+
+// Quantization modes of ReencodeGIF
+const (
+	QuantizeGIF       = "gif"        // One median cut palette for the whole GIF
+	QuantizeMedianCut = "median-cut" // One median cut palette per frame
+	QuantizePlan9     = "plan9"      // Fixed Plan9 palette
+)
+
+// ReencodeOptions configures how ReencodeGIF re-encodes a GIF for the device.
+type ReencodeOptions struct {
+	LoopCount   int    // GIF loop count of the re-encoded GIF (0 loops forever)
+	Quantize    string // Frame palettes: QuantizeGIF, QuantizeMedianCut or QuantizePlan9
+	Dither      bool   // Apply Floyd-Steinberg dithering while quantizing
+	Background  Color  // Shown through transparent regions
+	FitDuration bool   // Speed up GIFs longer than MaxGIFDurationMs instead of only reporting them
+}
+
+// DefaultReencodeOptions returns options looping forever with one palette for
+// the whole GIF, on a black background.
+func DefaultReencodeOptions() ReencodeOptions {
+	return ReencodeOptions{
+		Quantize:   QuantizeGIF,
+		Background: Black,
+	}
+}
+
+// ReencodedGIF is a GIF re-encoded by ReencodeGIF, with what was changed to
+// fit the device limits.
+type ReencodedGIF struct {
+	Data             []byte
+	Frames           int  // Frames of the re-encoded GIF
+	SourceFrames     int  // Frames of the source GIF, more than Frames if it was downsampled
+	DurationMs       int  // Duration of one loop of the re-encoded GIF
+	SourceDurationMs int  // Duration before FitDuration sped the GIF up
+	SpedUp           bool // FitDuration sped the GIF up
+}
+
+// LoadGIF reads a GIF file and re-encodes it with ReencodeGIF.
+func LoadGIF(path string, opts ReencodeOptions) (*ReencodedGIF, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	g, err := gif.DecodeAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode GIF: %w", err)
+	}
+	return ReencodeGIF(g, opts)
+}
+
+// ReencodeGIF re-composites the frames of a 64x64 GIF and re-encodes them for
+// the device. Frames shorter than 10ms are slowed down, GIFs with more than
+// MaxGIFFrames frames are downsampled, and frames are quantized as selected
+// by opts.Quantize.
+func ReencodeGIF(g *gif.GIF, opts ReencodeOptions) (*ReencodedGIF, error) {
+	// Validate dimensions
+	if g.Config.Width != DisplayWidth || g.Config.Height != DisplayHeight {
+		return nil, fmt.Errorf("GIF is %dx%d, expected %dx%d", g.Config.Width, g.Config.Height, DisplayWidth, DisplayHeight)
+	}
+
+	numFrames := len(g.Image)
+	if numFrames == 0 {
+		return nil, fmt.Errorf("GIF has no frames")
+	}
+
+	switch opts.Quantize {
+	case QuantizeGIF, QuantizeMedianCut, QuantizePlan9:
+	default:
+		return nil, fmt.Errorf("invalid quantization %q (valid: %s, %s, %s)", opts.Quantize, QuantizeGIF, QuantizeMedianCut, QuantizePlan9)
+	}
+
+	// Adjust frame delays
+	delays := make([]int, numFrames)
+	for i := 0; i < numFrames; i++ {
+		delay := g.Delay[i]
+		if delay < MinFrameTimeMs/10 {
+			delay = MinFrameTimeMs / 10 // Minimum 16ms (delay is in 1/100s)
+		}
+		delays[i] = delay
+	}
+
+	// Re-composite frames
+	canvases := CompositeGIF(g, opts.Background)
+
+	// Keep long GIFs within the frame limit by sampling frames across the
+	// whole animation, rather than cutting its tail. Sampling the canvases
+	// skips quantizing frames that are dropped anyway.
+	canvases, delays = DownsampleFrames(canvases, delays, MaxGIFFrames)
+	numFrames = len(canvases)
+
+	// Re-encode frames
+	var newFrames []*image.Paletted
+
+	switch opts.Quantize {
+	case QuantizeGIF:
+		newFrames = QuantizeFrames(canvases, MaxPaletteColors, opts.Dither)
+	default:
+		newFrames = make([]*image.Paletted, numFrames)
+		for i, canvas := range canvases {
+			// Create new paletted frame from canvas
+			var drawer draw.Drawer = draw.Src
+			if opts.Dither {
+				drawer = draw.FloydSteinberg
+			}
+			switch {
+			case opts.Quantize == QuantizeMedianCut && opts.Dither:
+				newFrames[i] = QuantizeToPalettedDithered(canvas, MaxPaletteColors)
+			case opts.Quantize == QuantizeMedianCut:
+				newFrames[i] = QuantizeToPaletted(canvas, MaxPaletteColors)
+			default:
+				palettedFrame := image.NewPaletted(image.Rect(0, 0, DisplayWidth, DisplayHeight), palette.Plan9)
+				drawer.Draw(palettedFrame, palettedFrame.Bounds(), canvas, image.Point{})
+				newFrames[i] = palettedFrame
+			}
+		}
+	}
+
+	// Re-encode GIF with the requested loop count and disposal=2 (restore to background)
+	newGIF := &gif.GIF{
+		Image:     newFrames,
+		Delay:     delays,
+		LoopCount: opts.LoopCount,
+		Disposal:  make([]byte, numFrames),
+	}
+	// Set disposal=2 (DisposalBackground) for all frames
+	for i := range newGIF.Disposal {
+		newGIF.Disposal[i] = gif.DisposalBackground
+	}
+
+	result := &ReencodedGIF{
+		Frames:           numFrames,
+		SourceFrames:     len(g.Image),
+		SourceDurationMs: gifDurationMs(newGIF),
+	}
+	if opts.FitDuration && result.SourceDurationMs > MaxGIFDurationMs {
+		newGIF = FitGIFDuration(newGIF, MaxGIFDurationMs)
+		result.SpedUp = true
+	}
+	result.DurationMs = gifDurationMs(newGIF)
+
+	// Encode to bytes
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, newGIF); err != nil {
+		return nil, fmt.Errorf("failed to re-encode GIF: %w", err)
+	}
+	result.Data = buf.Bytes()
+	return result, nil
+}
+
+// gifDurationMs returns how long one loop of g lasts
+func gifDurationMs(g *gif.GIF) int {
+	total := 0
+	for _, delay := range g.Delay {
+		total += delay * 10
+	}
+	return total
+}
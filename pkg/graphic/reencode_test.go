@@ -0,0 +1,89 @@
+package graphic
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testGIF returns a GIF with frames of the given size, each lasting delay
+func testGIF(size, frames, delay int) *gif.GIF {
+	pal := color.Palette{color.Black, color.White}
+	g := &gif.GIF{Config: image.Config{Width: size, Height: size, ColorModel: pal}}
+	for i := 0; i < frames; i++ {
+		frame := image.NewPaletted(image.Rect(0, 0, size, size), pal)
+		frame.SetColorIndex(i%size, 0, 1)
+		g.Image = append(g.Image, frame)
+		g.Delay = append(g.Delay, delay)
+	}
+	return g
+}
+
+func TestReencodeGIF(t *testing.T) {
+	t.Run("downsamples GIFs over the frame limit", func(t *testing.T) {
+		result, err := ReencodeGIF(testGIF(DisplayWidth, 100, 5), DefaultReencodeOptions())
+		require.NoError(t, err)
+		assert.Equal(t, 100, result.SourceFrames)
+		assert.Equal(t, MaxGIFFrames, result.Frames)
+		assert.Equal(t, 100*50, result.DurationMs) // Downsampling keeps the duration
+
+		g, err := gif.DecodeAll(bytes.NewReader(result.Data))
+		require.NoError(t, err)
+		assert.Len(t, g.Image, MaxGIFFrames)
+	})
+
+	t.Run("keeps frames at least 10ms long", func(t *testing.T) {
+		result, err := ReencodeGIF(testGIF(DisplayWidth, 4, 0), DefaultReencodeOptions())
+		require.NoError(t, err)
+		assert.Equal(t, 40, result.DurationMs)
+	})
+
+	t.Run("speeds up long GIFs only with FitDuration", func(t *testing.T) {
+		opts := DefaultReencodeOptions()
+		result, err := ReencodeGIF(testGIF(DisplayWidth, 10, 50), opts)
+		require.NoError(t, err)
+		assert.False(t, result.SpedUp)
+		assert.Equal(t, 5000, result.DurationMs)
+
+		opts.FitDuration = true
+		result, err = ReencodeGIF(testGIF(DisplayWidth, 10, 50), opts)
+		require.NoError(t, err)
+		assert.True(t, result.SpedUp)
+		assert.Equal(t, 5000, result.SourceDurationMs)
+		assert.LessOrEqual(t, result.DurationMs, MaxGIFDurationMs)
+	})
+
+	t.Run("rejects GIFs that aren't 64x64", func(t *testing.T) {
+		_, err := ReencodeGIF(testGIF(32, 1, 10), DefaultReencodeOptions())
+		assert.ErrorContains(t, err, "GIF is 32x32, expected 64x64")
+	})
+
+	t.Run("rejects unknown quantization modes", func(t *testing.T) {
+		opts := DefaultReencodeOptions()
+		opts.Quantize = "octree"
+		_, err := ReencodeGIF(testGIF(DisplayWidth, 1, 10), opts)
+		assert.Error(t, err)
+	})
+}
+
+func TestLoadGIF(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "anim.gif")
+	var buf bytes.Buffer
+	require.NoError(t, gif.EncodeAll(&buf, testGIF(DisplayWidth, 3, 10)))
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o644))
+
+	result, err := LoadGIF(path, DefaultReencodeOptions())
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Frames)
+	assert.Equal(t, 300, result.DurationMs)
+
+	_, err = LoadGIF(filepath.Join(t.TempDir(), "missing.gif"), DefaultReencodeOptions())
+	assert.Error(t, err)
+}
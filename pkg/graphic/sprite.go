@@ -0,0 +1,31 @@
+package graphic
+
+// Sprite is a small bitmap that can be drawn at any position on a buffer.
+type Sprite struct {
+	Width  int
+	Height int
+	Pixels []Color // Width*Height colors, row by row from the top-left corner
+
+	// Transparent is the color of the pixels skipped when drawing, leaving
+	// the buffer untouched. Nil draws every pixel.
+	Transparent *Color
+}
+
+// DrawSprite draws the sprite with its top-left corner at (x, y). Transparent
+// pixels are skipped and pixels outside the display are clipped, so sprites
+// can be partially off screen.
+func DrawSprite(buf []byte, s Sprite, x, y int) {
+	for row := 0; row < s.Height; row++ {
+		for col := 0; col < s.Width; col++ {
+			idx := row*s.Width + col
+			if idx >= len(s.Pixels) {
+				return
+			}
+			color := s.Pixels[idx]
+			if s.Transparent != nil && color == *s.Transparent {
+				continue
+			}
+			SetPixel(buf, x+col, y+row, color)
+		}
+	}
+}
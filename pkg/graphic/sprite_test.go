@@ -0,0 +1,84 @@
+package graphic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	spriteRed   = Color{255, 0, 0}
+	spriteGreen = Color{0, 255, 0}
+	spriteBlack = Color{0, 0, 0}
+)
+
+// checkerSprite is a 2x2 sprite: red, green on the first row, green, red on the second
+var checkerSprite = Sprite{
+	Width:  2,
+	Height: 2,
+	Pixels: []Color{spriteRed, spriteGreen, spriteGreen, spriteRed},
+}
+
+func pixelAt(buf []byte, x, y int) Color {
+	offset := (y*DisplayWidth + x) * 3
+	return Color{buf[offset], buf[offset+1], buf[offset+2]}
+}
+
+func TestDrawSprite(t *testing.T) {
+	buf := NewBuffer()
+	DrawSprite(buf, checkerSprite, 10, 20)
+
+	assert.Equal(t, spriteRed, pixelAt(buf, 10, 20))
+	assert.Equal(t, spriteGreen, pixelAt(buf, 11, 20))
+	assert.Equal(t, spriteGreen, pixelAt(buf, 10, 21))
+	assert.Equal(t, spriteRed, pixelAt(buf, 11, 21))
+	assert.Len(t, litPixels(buf), 4)
+}
+
+func TestDrawSpriteClipping(t *testing.T) {
+	t.Run("negative coordinates", func(t *testing.T) {
+		buf := NewBuffer()
+		DrawSprite(buf, checkerSprite, -1, -1)
+
+		// Only the bottom-right pixel is on screen
+		assert.Equal(t, map[Point]bool{{0, 0}: true}, litPixels(buf))
+		assert.Equal(t, spriteRed, pixelAt(buf, 0, 0))
+	})
+
+	t.Run("past the bottom-right edge", func(t *testing.T) {
+		buf := NewBuffer()
+		DrawSprite(buf, checkerSprite, DisplayWidth-1, DisplayHeight-1)
+
+		// Only the top-left pixel is on screen
+		assert.Equal(t, map[Point]bool{{DisplayWidth - 1, DisplayHeight - 1}: true}, litPixels(buf))
+		assert.Equal(t, spriteRed, pixelAt(buf, DisplayWidth-1, DisplayHeight-1))
+	})
+
+	t.Run("fully off screen", func(t *testing.T) {
+		buf := NewBuffer()
+		DrawSprite(buf, checkerSprite, -5, DisplayHeight+5)
+		assert.Empty(t, litPixels(buf))
+	})
+}
+
+func TestDrawSpriteTransparency(t *testing.T) {
+	background := Color{0, 0, 100}
+	sprite := Sprite{
+		Width:       3,
+		Height:      1,
+		Pixels:      []Color{spriteRed, spriteBlack, spriteGreen},
+		Transparent: &spriteBlack,
+	}
+
+	buf := NewBufferWithColor(background)
+	DrawSprite(buf, sprite, 0, 0)
+	assert.Equal(t, spriteRed, pixelAt(buf, 0, 0))
+	assert.Equal(t, background, pixelAt(buf, 1, 0), "transparent pixel should keep the background")
+	assert.Equal(t, spriteGreen, pixelAt(buf, 2, 0))
+
+	// Without a transparent color every pixel is drawn
+	sprite.Transparent = nil
+	buf = NewBufferWithColor(background)
+	DrawSprite(buf, sprite, 0, 0)
+	assert.Equal(t, spriteBlack, pixelAt(buf, 1, 0))
+}
@@ -2,21 +2,24 @@ package grot
 
 import (
 	"bytes"
+	"fmt"
 	"image"
 	"image/gif"
 	"image/png"
 	"math"
 	"math/rand"
+	"strings"
 
 	"github.com/pracucci/idotmatrix-overclocked/pkg/assets"
 	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/text"
 )
 
 // Matrix animation constants
 const (
 	matrixFrameCount = 64 // More frames for smoother looping
-	matrixFrameDelay = 3  // 30ms per frame (faster to compensate)
-	matrixColumns    = 12
+	matrixFrameDelay = 3  // Default 30ms per frame (faster to compensate)
+	matrixColumns    = 12 // Default number of columns
 	matrixCharWidth  = 4 // 3 pixels + 1 spacing
 	matrixCharHeight = 6 // 5 pixels + 1 spacing
 	matrixTailLen    = 4 // Number of characters in tail
@@ -29,19 +32,10 @@ const (
 	blockSeed   = 123 // Seed for block selection
 )
 
-// Tiny 3x5 pixel font for Matrix characters (katakana-inspired + digits)
-var matrixChars = [][]string{
+// Tiny 3x5 pixel font for Matrix characters: the digits of the small text
+// font followed by katakana-inspired shapes
+var matrixChars = append(messageGlyphs("0123456789"), [][]string{
 	// Simple geometric shapes that evoke katakana
-	{"###", "#.#", "###", "#.#", "#.#"}, // 0
-	{".#.", "##.", ".#.", ".#.", "###"}, // 1
-	{"###", "..#", "###", "#..", "###"}, // 2
-	{"###", "..#", "###", "..#", "###"}, // 3
-	{"#.#", "#.#", "###", "..#", "..#"}, // 4
-	{"###", "#..", "###", "..#", "###"}, // 5
-	{"#..", "#..", "###", "#.#", "###"}, // 6
-	{"###", "..#", ".#.", ".#.", ".#."}, // 7
-	{"###", "#.#", "###", "#.#", "###"}, // 8
-	{"###", "#.#", "###", "..#", "..#"}, // 9
 	{".#.", "#.#", "###", "#.#", "#.#"}, // A-like
 	{"##.", "#.#", "##.", "#.#", "##."}, // B-like
 	{"###", "#..", "#..", "#..", "###"}, // C-like
@@ -52,15 +46,32 @@ var matrixChars = [][]string{
 	{"###", "#..", "##.", "#..", "###"}, // E-like
 	{".#.", ".#.", ".#.", ".#.", ".#."}, // I-like
 	{"#..", "#..", "#..", "#..", "###"}, // L-like
+}...)
+
+// Brightness levels of the tail characters (out of 255), from the character
+// right behind the head to the end of the tail
+var matrixTailLevels = []int{255, 180, 120, 60}
+
+// MatrixOptions configures the matrix animation.
+type MatrixOptions struct {
+	Columns    int           // Number of falling columns (1-60)
+	Density    int           // Falling streams per column
+	FrameDelay int           // Delay between frames (in 1/100s)
+	HeadColor  graphic.Color // Leading character color
+	TailColor  graphic.Color // Tail color (fades out toward the end of the tail)
+	Dissolve   bool          // Detach blocks from the base image and let them fall
 }
 
-// Matrix green colors (bright head to dark tail)
-var matrixGreens = []graphic.Color{
-	{180, 255, 180}, // White-green (head highlight)
-	{0, 255, 0},     // Bright green
-	{0, 180, 0},     // Medium green
-	{0, 120, 0},     // Dim green
-	{0, 60, 0},      // Very dim green
+// DefaultMatrixOptions returns the options used by GenerateMatrix.
+func DefaultMatrixOptions() MatrixOptions {
+	return MatrixOptions{
+		Columns:    matrixColumns,
+		Density:    1,
+		FrameDelay: matrixFrameDelay,
+		HeadColor:  graphic.Color{180, 255, 180}, // White-green highlight
+		TailColor:  graphic.Color{0, 255, 0},
+		Dissolve:   true,
+	}
 }
 
 // matrixColumn represents a falling column of characters
@@ -82,6 +93,20 @@ type imageBlock struct {
 
 // GenerateMatrix creates a matrix-style animation over the base image.
 func GenerateMatrix() (*graphic.Image, error) {
+	return GenerateMatrixWithOptions(DefaultMatrixOptions())
+}
+
+// GenerateMatrixWithOptions creates a matrix-style animation over the base image
+// with custom columns, speed and colors.
+func GenerateMatrixWithOptions(opts MatrixOptions) (*graphic.Image, error) {
+	return GenerateMatrixText("", opts)
+}
+
+// GenerateMatrixText creates a matrix-style animation over the base image where
+// the falling columns spell out the characters of message, rendered with the
+// 3x5 matrix font. Characters without a glyph (including spaces) are skipped.
+// An empty message rains down the default katakana-like glyphs.
+func GenerateMatrixText(message string, opts MatrixOptions) (*graphic.Image, error) {
 	// Load base image
 	baseData, err := assets.Grot.ReadFile("grot/matrix-base.png")
 	if err != nil {
@@ -94,10 +119,41 @@ func GenerateMatrix() (*graphic.Image, error) {
 	}
 
 	// Convert base image to RGB buffer for easy manipulation
-	baseRGB := graphic.ImageToRGB(baseImg)
+	return generateMatrix(graphic.ImageToRGB(baseImg), message, opts)
+}
+
+// GenerateMatrixFromImage creates a matrix-style animation over a custom base
+// image, which dissolves and rains down like the default one. Images that are
+// not 64x64 are resized to fit the display.
+func GenerateMatrixFromImage(base image.Image, opts MatrixOptions) (*graphic.Image, error) {
+	if base == nil {
+		return nil, fmt.Errorf("missing base image")
+	}
+	return generateMatrix(graphic.ResizeRGB(base), "", opts)
+}
+
+// generateMatrix renders the matrix animation over the given 64x64 RGB base.
+func generateMatrix(baseRGB []byte, message string, opts MatrixOptions) (*graphic.Image, error) {
+	if err := validateMatrixOptions(opts); err != nil {
+		return nil, err
+	}
+
+	glyphs := matrixChars
+	sequential := false
+	if message != "" {
+		glyphs = messageGlyphs(message)
+		if len(glyphs) == 0 {
+			return nil, fmt.Errorf("message has no supported characters: %q", message)
+		}
+		sequential = true
+	}
+	shades := matrixShades(opts.HeadColor, opts.TailColor)
 
 	// Initialize image blocks for dissolution effect
-	imageBlocks := initImageBlocks(baseRGB)
+	var imageBlocks []*imageBlock
+	if opts.Dissolve {
+		imageBlocks = initImageBlocks(baseRGB)
+	}
 
 	// Initialize random generator with fixed seed for deterministic animation
 	rng := rand.New(rand.NewSource(matrixRngSeed))
@@ -105,14 +161,17 @@ func GenerateMatrix() (*graphic.Image, error) {
 	numCharRows := graphic.DisplayHeight / matrixCharHeight
 
 	// Calculate cycle length and speed for seamless looping
-	// Columns need to travel exactly cycleLength positions in matrixFrameCount frames
+	// Columns need to travel exactly cycleLength positions in matrixFrameCount frames,
+	// regardless of how many columns there are
 	cycleLength := numCharRows + matrixTailLen
 	speed := float64(cycleLength) / float64(matrixFrameCount)
 
 	// Initialize columns with staggered starting positions
-	columns := make([]*matrixColumn, matrixColumns)
-	for i := 0; i < matrixColumns; i++ {
-		columns[i] = newMatrixColumn(rng, i, cycleLength, speed)
+	columns := make([]*matrixColumn, 0, opts.Columns*opts.Density)
+	for i := 0; i < opts.Columns; i++ {
+		for j := 0; j < opts.Density; j++ {
+			columns = append(columns, newMatrixColumn(rng, i, opts.Columns, cycleLength, speed, len(glyphs), sequential))
+		}
 	}
 
 	var frames []*image.Paletted
@@ -129,11 +188,11 @@ func GenerateMatrix() (*graphic.Image, error) {
 
 		// Draw matrix columns
 		for _, col := range columns {
-			drawMatrixColumn(buf, baseRGB, col, frame, numCharRows, cycleLength)
+			drawMatrixColumn(buf, baseRGB, col, frame, numCharRows, cycleLength, glyphs, shades)
 		}
 
 		frames = append(frames, graphic.RGBToPaletted(buf))
-		delays = append(delays, matrixFrameDelay)
+		delays = append(delays, opts.FrameDelay)
 	}
 
 	return &graphic.Image{
@@ -146,18 +205,70 @@ func GenerateMatrix() (*graphic.Image, error) {
 	}, nil
 }
 
-// newMatrixColumn creates a new falling column with deterministic properties
-func newMatrixColumn(rng *rand.Rand, colIndex int, cycleLength int, speed float64) *matrixColumn {
+// validateMatrixOptions checks that the matrix options are within range.
+func validateMatrixOptions(opts MatrixOptions) error {
+	maxColumns := graphic.DisplayWidth - matrixCharWidth
+	if opts.Columns < 1 || opts.Columns > maxColumns {
+		return fmt.Errorf("invalid columns: %d (valid: 1-%d)", opts.Columns, maxColumns)
+	}
+	if opts.Density < 1 {
+		return fmt.Errorf("invalid density: %d (must be >= 1)", opts.Density)
+	}
+	if opts.FrameDelay < 1 {
+		return fmt.Errorf("invalid frame delay: %d (must be >= 1)", opts.FrameDelay)
+	}
+	return nil
+}
+
+// messageGlyphs maps each character of message to its glyph in the 3x5 text
+// font, skipping characters that have no glyph.
+func messageGlyphs(message string) [][]string {
+	var glyphs [][]string
+	for _, r := range strings.ToUpper(message) {
+		if glyph, ok := text.SmallGlyph(r); ok {
+			glyphs = append(glyphs, glyph[:])
+		}
+	}
+	return glyphs
+}
+
+// matrixShades returns the column colors from head to tail: the head color
+// followed by progressively dimmer shades of the tail color.
+func matrixShades(head, tail graphic.Color) []graphic.Color {
+	shades := make([]graphic.Color, 0, len(matrixTailLevels)+1)
+	shades = append(shades, head)
+
+	for _, level := range matrixTailLevels {
+		var shade graphic.Color
+		for i, c := range tail {
+			shade[i] = uint8(int(c) * level / 255)
+		}
+		shades = append(shades, shade)
+	}
+	return shades
+}
+
+// newMatrixColumn creates a new falling column with deterministic properties.
+// When sequential is true the column cycles through the glyphs in order (starting
+// at a random offset) so that a message can be read top to bottom.
+func newMatrixColumn(rng *rand.Rand, colIndex int, numColumns int, cycleLength int, speed float64, glyphCount int, sequential bool) *matrixColumn {
 	// Fixed x position (evenly spaced across the screen)
-	x := colIndex*5 + 1
+	x := colIndex*(graphic.DisplayWidth-matrixCharWidth)/numColumns + 1
 
 	// Randomize starting Y position across the full cycle
 	startY := rng.Float64() * float64(cycleLength)
 
 	// Pre-generate base characters for deterministic character selection
 	baseChars := make([]int, cycleLength+matrixTailLen+2)
-	for i := range baseChars {
-		baseChars[i] = rng.Intn(len(matrixChars))
+	if sequential {
+		offset := rng.Intn(glyphCount)
+		for i := range baseChars {
+			baseChars[i] = (offset + i) % glyphCount
+		}
+	} else {
+		for i := range baseChars {
+			baseChars[i] = rng.Intn(glyphCount)
+		}
 	}
 
 	return &matrixColumn{
@@ -169,7 +280,7 @@ func newMatrixColumn(rng *rand.Rand, colIndex int, cycleLength int, speed float6
 }
 
 // drawMatrixColumn draws a column of characters with fading tail
-func drawMatrixColumn(buf, baseRGB []byte, col *matrixColumn, frame int, numCharRows int, cycleLength int) {
+func drawMatrixColumn(buf, baseRGB []byte, col *matrixColumn, frame int, numCharRows int, cycleLength int, glyphs [][]string, shades []graphic.Color) {
 	// Calculate head position using cyclic modulo arithmetic for seamless looping
 	headY := math.Mod(col.startY+float64(frame)*col.speed, float64(cycleLength))
 	headCharY := int(headY)
@@ -188,25 +299,20 @@ func drawMatrixColumn(buf, baseRGB []byte, col *matrixColumn, frame int, numChar
 
 		// Choose color based on position (head is brightest)
 		var charColor graphic.Color
-		if i < len(matrixGreens) {
-			charColor = matrixGreens[i]
+		if i < len(shades) {
+			charColor = shades[i]
 		} else {
-			charColor = matrixGreens[len(matrixGreens)-1]
+			charColor = shades[len(shades)-1]
 		}
 
 		// Draw the character
 		pixelY := charY * matrixCharHeight
-		drawMatrixChar(buf, baseRGB, col.x, pixelY, charIdx, charColor)
+		drawMatrixChar(buf, baseRGB, col.x, pixelY, glyphs[charIdx], charColor)
 	}
 }
 
 // drawMatrixChar draws a single 3x5 character at the given position
-func drawMatrixChar(buf, baseRGB []byte, x, y, charIdx int, charColor graphic.Color) {
-	if charIdx < 0 || charIdx >= len(matrixChars) {
-		return
-	}
-
-	char := matrixChars[charIdx]
+func drawMatrixChar(buf, baseRGB []byte, x, y int, char []string, charColor graphic.Color) {
 	for row := 0; row < 5; row++ {
 		if row >= len(char) {
 			continue
@@ -225,21 +331,16 @@ func drawMatrixChar(buf, baseRGB []byte, x, y, charIdx int, charColor graphic.Co
 
 				// Check base image brightness
 				offset := (py*graphic.DisplayWidth + px) * 3
-				baseR := baseRGB[offset]
-				baseG := baseRGB[offset+1]
-				baseB := baseRGB[offset+2]
-				brightness := int(baseR) + int(baseG) + int(baseB)
+				base := graphic.Color{baseRGB[offset], baseRGB[offset+1], baseRGB[offset+2]}
+				brightness := int(base[0]) + int(base[1]) + int(base[2])
 
 				if brightness < 100 {
 					// Dark area: show full character
 					graphic.SetPixel(buf, px, py, charColor)
 				} else {
 					// Light area: blend with base
-					blendFactor := 0.5
-					blendedR := uint8(float64(baseR)*(1-blendFactor) + float64(charColor[0])*blendFactor)
-					blendedG := uint8(float64(baseG)*(1-blendFactor) + float64(charColor[1])*blendFactor)
-					blendedB := uint8(float64(baseB)*(1-blendFactor) + float64(charColor[2])*blendFactor)
-					graphic.SetPixel(buf, px, py, graphic.Color{blendedR, blendedG, blendedB})
+					graphic.SetPixel(buf, px, py, base)
+					graphic.BlendPixel(buf, px, py, charColor, 0.5)
 				}
 			}
 		}
@@ -0,0 +1,245 @@
+package grot
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/text"
+)
+
+func TestMessageGlyphs(t *testing.T) {
+	glyphs := messageGlyphs("Hi there!")
+	require.Len(t, glyphs, 8, "space should be skipped")
+	h, _ := text.SmallGlyph('H')
+	i, _ := text.SmallGlyph('I')
+	bang, _ := text.SmallGlyph('!')
+	assert.Equal(t, h[:], glyphs[0])
+	assert.Equal(t, i[:], glyphs[1], "lowercase should map to uppercase glyphs")
+	assert.Equal(t, bang[:], glyphs[7])
+
+	assert.Empty(t, messageGlyphs("   "))
+}
+
+func TestMatrixShades(t *testing.T) {
+	// The default colors reproduce the classic matrix greens
+	opts := DefaultMatrixOptions()
+	assert.Equal(t, []graphic.Color{
+		{180, 255, 180},
+		{0, 255, 0},
+		{0, 180, 0},
+		{0, 120, 0},
+		{0, 60, 0},
+	}, matrixShades(opts.HeadColor, opts.TailColor))
+}
+
+func TestMatrixTextGlyphsAppearInColumns(t *testing.T) {
+	message := "GO"
+	glyphs := messageGlyphs(message)
+	shades := matrixShades(graphic.White, graphic.Red)
+
+	numCharRows := graphic.DisplayHeight / matrixCharHeight
+	cycleLength := numCharRows + matrixTailLen
+	speed := float64(cycleLength) / float64(matrixFrameCount)
+
+	rng := rand.New(rand.NewSource(matrixRngSeed))
+	baseRGB := graphic.NewBuffer()
+
+	drawn := 0
+	for i := 0; i < matrixColumns; i++ {
+		col := newMatrixColumn(rng, i, matrixColumns, cycleLength, speed, len(glyphs), true)
+
+		for frame := 0; frame < matrixFrameCount; frame += 8 {
+			buf := graphic.NewBuffer()
+			drawMatrixColumn(buf, baseRGB, col, frame, numCharRows, cycleLength, glyphs, shades)
+
+			// Every non-empty character cell in the column must be one of the message glyphs
+			for charY := 0; charY < numCharRows; charY++ {
+				cell := readMatrixCell(buf, col.x, charY*matrixCharHeight)
+				if cell == nil {
+					continue
+				}
+				assert.Contains(t, glyphs, cell)
+				drawn++
+			}
+		}
+	}
+	assert.Positive(t, drawn, "expected some message characters to be rendered")
+}
+
+func TestGenerateMatrixText(t *testing.T) {
+	opts := DefaultMatrixOptions()
+	opts.TailColor = graphic.Blue
+	opts.Dissolve = false
+
+	img, err := GenerateMatrixText("hello", opts)
+	require.NoError(t, err)
+	require.NotNil(t, img.GIFData)
+	assert.Len(t, img.GIFData.Image, matrixFrameCount)
+
+	_, err = GenerateMatrixText("   ", opts)
+	assert.Error(t, err)
+}
+
+func TestGenerateMatrixWithOptions(t *testing.T) {
+	magenta := graphic.Color{255, 0, 255}
+
+	opts := DefaultMatrixOptions()
+	opts.Columns = 20
+	opts.Density = 2
+	opts.FrameDelay = 10
+	opts.HeadColor = magenta
+	opts.TailColor = graphic.Red
+	opts.Dissolve = false
+
+	img, err := GenerateMatrixWithOptions(opts)
+	require.NoError(t, err)
+	require.NotNil(t, img.GIFData)
+	assert.Len(t, img.GIFData.Image, matrixFrameCount)
+	for _, delay := range img.GIFData.Delay {
+		assert.Equal(t, 10, delay)
+	}
+
+	// The custom head color must be used by the rendered frames
+	found := false
+	for _, frame := range img.GIFData.Image {
+		idx := uint8(frame.Palette.Index(color.RGBA{magenta[0], magenta[1], magenta[2], 255}))
+		if bytes.IndexByte(frame.Pix, idx) >= 0 {
+			found = true
+			break
+		}
+	}
+	assert.True(t, found, "expected the custom head color in the output")
+}
+
+func TestGenerateMatrixWithOptionsInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*MatrixOptions)
+	}{
+		{name: "no columns", modify: func(o *MatrixOptions) { o.Columns = 0 }},
+		{name: "too many columns", modify: func(o *MatrixOptions) { o.Columns = 61 }},
+		{name: "no density", modify: func(o *MatrixOptions) { o.Density = 0 }},
+		{name: "no frame delay", modify: func(o *MatrixOptions) { o.FrameDelay = 0 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultMatrixOptions()
+			tt.modify(&opts)
+			_, err := GenerateMatrixWithOptions(opts)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestMatrixColumnsLoopSeamlessly(t *testing.T) {
+	numCharRows := graphic.DisplayHeight / matrixCharHeight
+	cycleLength := numCharRows + matrixTailLen
+	speed := float64(cycleLength) / float64(matrixFrameCount)
+
+	for _, numColumns := range []int{1, 7, 12, 30, 60} {
+		rng := rand.New(rand.NewSource(matrixRngSeed))
+		for i := 0; i < numColumns; i++ {
+			col := newMatrixColumn(rng, i, numColumns, cycleLength, speed, len(matrixChars), false)
+			assert.GreaterOrEqual(t, col.x, 0)
+			assert.LessOrEqual(t, col.x+3, graphic.DisplayWidth)
+
+			// After a full animation cycle the head is back where it started
+			start := math.Mod(col.startY, float64(cycleLength))
+			end := math.Mod(col.startY+float64(matrixFrameCount)*col.speed, float64(cycleLength))
+			assert.InDelta(t, start, end, 1e-9, "columns=%d column=%d", numColumns, i)
+		}
+	}
+}
+
+func TestInitImageBlocksFromCheckerboard(t *testing.T) {
+	const square = 8
+
+	// Checkerboard of white and black 8x8 squares
+	base := image.NewRGBA(image.Rect(0, 0, graphic.DisplayWidth, graphic.DisplayHeight))
+	isBright := func(x, y int) bool {
+		return (x/square+y/square)%2 == 0
+	}
+	for y := 0; y < graphic.DisplayHeight; y++ {
+		for x := 0; x < graphic.DisplayWidth; x++ {
+			if isBright(x, y) {
+				base.Set(x, y, color.White)
+			} else {
+				base.Set(x, y, color.Black)
+			}
+		}
+	}
+
+	blocks := initImageBlocks(graphic.ResizeRGB(base))
+	require.NotEmpty(t, blocks)
+
+	for _, b := range blocks {
+		// Every block must be taken (at least partially) from a bright square,
+		// and its pixels must match the base image.
+		brightPixels := 0
+		for dy := 0; dy < blockHeight; dy++ {
+			for dx := 0; dx < blockWidth; dx++ {
+				if isBright(b.srcX+dx, b.srcY+dy) {
+					brightPixels++
+					assert.Equal(t, graphic.White, b.pixels[dy][dx])
+				} else {
+					assert.Equal(t, graphic.Black, b.pixels[dy][dx])
+				}
+			}
+		}
+		assert.Positive(t, brightPixels, "block at %d,%d is entirely in a dark square", b.srcX, b.srcY)
+	}
+}
+
+func TestGenerateMatrixFromImage(t *testing.T) {
+	// A non 64x64 base is resized to fit the display
+	base := image.NewRGBA(image.Rect(0, 0, 32, 32))
+	for y := 0; y < 32; y++ {
+		for x := 0; x < 32; x++ {
+			base.Set(x, y, color.White)
+		}
+	}
+
+	img, err := GenerateMatrixFromImage(base, DefaultMatrixOptions())
+	require.NoError(t, err)
+	require.NotNil(t, img.GIFData)
+	assert.Len(t, img.GIFData.Image, matrixFrameCount)
+
+	_, err = GenerateMatrixFromImage(nil, DefaultMatrixOptions())
+	assert.Error(t, err)
+}
+
+// readMatrixCell returns the 3x5 glyph pattern drawn at the given position,
+// or nil if no pixel is lit.
+func readMatrixCell(buf []byte, x, y int) []string {
+	lit := false
+	cell := make([]string, 5)
+	for row := 0; row < 5; row++ {
+		line := make([]byte, 3)
+		for col := 0; col < 3; col++ {
+			line[col] = '.'
+			py := y + row
+			if py >= graphic.DisplayHeight {
+				continue
+			}
+			offset := (py*graphic.DisplayWidth + x + col) * 3
+			if buf[offset] != 0 || buf[offset+1] != 0 || buf[offset+2] != 0 {
+				line[col] = '#'
+				lit = true
+			}
+		}
+		cell[row] = string(line)
+	}
+	if !lit {
+		return nil
+	}
+	return cell
+}
@@ -0,0 +1,169 @@
+// Package particles generates seamlessly looping animations of falling snow
+// or drifting stars.
+package particles
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/gif"
+	"math"
+	"math/rand"
+	"strings"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+)
+
+const (
+	frameDelay = 8 // 80ms per frame (delay is in 1/100s)
+	maxWind    = 4 // Most horizontal display crossings per loop
+	maxSpeed   = 4 // Most display crossings per loop
+	maxCount   = 512
+)
+
+// Particle kinds
+const (
+	KindSnow  = "snow"  // Flakes falling down, blown sideways by the wind
+	KindStars = "stars" // Twinkling stars drifting to the left
+)
+
+// Options configures the particle animation.
+type Options struct {
+	Kind   string        // KindSnow or KindStars
+	Count  int           // Number of particles
+	Color  graphic.Color // Color of the closest, brightest particles
+	Speed  int           // Display crossings per loop of the fastest particles (1-4)
+	Wind   int           // Horizontal display crossings per loop of snow, negative to the left (-4 to 4)
+	Frames int           // Frames per loop
+	Loops  int           // GIF loop count (0 loops forever)
+	Seed   int64         // Seed of the random particle placement
+}
+
+// DefaultOptions returns white snow falling straight down, looping forever.
+func DefaultOptions() Options {
+	return Options{
+		Kind:   KindSnow,
+		Count:  60,
+		Color:  graphic.White,
+		Speed:  1,
+		Wind:   0,
+		Frames: 32,
+		Loops:  0,
+	}
+}
+
+// particle moves a whole number of times across the display per loop, so it
+// is back where it started after the last frame and the animation loops
+// without a jump.
+type particle struct {
+	x, y         float64 // Position at frame 0
+	lapsX, lapsY int     // Display crossings per loop
+	brightness   int     // Percentage of the color (0-100)
+	twinkles     int     // Brightness cycles per loop (stars only)
+	phase        float64 // Twinkle phase at frame 0
+}
+
+// position returns the pixel the particle is on at the given frame.
+func (p particle) position(frame, frames int) graphic.Point {
+	t := float64(frame) / float64(frames)
+	x := p.x + t*float64(p.lapsX*graphic.DisplayWidth)
+	y := p.y + t*float64(p.lapsY*graphic.DisplayHeight)
+	return graphic.Point{
+		X: wrap(int(math.Floor(x)), graphic.DisplayWidth),
+		Y: wrap(int(math.Floor(y)), graphic.DisplayHeight),
+	}
+}
+
+// brightnessAt returns the brightness of the particle at the given frame.
+func (p particle) brightnessAt(frame, frames int) int {
+	if p.twinkles == 0 {
+		return p.brightness
+	}
+	t := float64(frame) / float64(frames)
+	wave := (1 + math.Sin(2*math.Pi*(float64(p.twinkles)*t+p.phase))) / 2
+	return int(math.Round(float64(p.brightness) * (0.3 + 0.7*wave)))
+}
+
+func wrap(v, size int) int {
+	return ((v % size) + size) % size
+}
+
+// validate checks the options are in range.
+func (opts Options) validate() error {
+	kind := strings.ToLower(opts.Kind)
+	if kind != KindSnow && kind != KindStars {
+		return fmt.Errorf("unknown kind: %s (valid: %s, %s)", opts.Kind, KindSnow, KindStars)
+	}
+	if opts.Count < 1 || opts.Count > maxCount {
+		return fmt.Errorf("invalid count: %d (valid: 1-%d)", opts.Count, maxCount)
+	}
+	if opts.Speed < 1 || opts.Speed > maxSpeed {
+		return fmt.Errorf("invalid speed: %d (valid: 1-%d)", opts.Speed, maxSpeed)
+	}
+	if opts.Wind < -maxWind || opts.Wind > maxWind {
+		return fmt.Errorf("invalid wind: %d (valid: -%d to %d)", opts.Wind, maxWind, maxWind)
+	}
+	if opts.Frames < 2 {
+		return fmt.Errorf("invalid frames: %d (must be >= 2)", opts.Frames)
+	}
+	return nil
+}
+
+// newParticles places the particles at random, closer ones moving faster and
+// shining brighter.
+func newParticles(opts Options) []particle {
+	rng := rand.New(rand.NewSource(opts.Seed))
+	stars := strings.ToLower(opts.Kind) == KindStars
+
+	particles := make([]particle, opts.Count)
+	for i := range particles {
+		laps := 1 + rng.Intn(opts.Speed)
+		p := particle{
+			x:          rng.Float64() * graphic.DisplayWidth,
+			y:          rng.Float64() * graphic.DisplayHeight,
+			brightness: 40 + 60*laps/opts.Speed,
+		}
+		if stars {
+			p.lapsX = -laps
+			p.twinkles = 1 + rng.Intn(3)
+			p.phase = rng.Float64()
+		} else {
+			p.lapsX = opts.Wind
+			p.lapsY = laps
+		}
+		particles[i] = p
+	}
+	return particles
+}
+
+// GenerateGIF generates the particle animation GIF. The particles at the last
+// frame are one step away from where they are at the first frame, so the
+// animation loops seamlessly. Returns an error if an option is out of range.
+func GenerateGIF(opts Options) ([]byte, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	particles := newParticles(opts)
+	g := &gif.GIF{
+		Image:     make([]*image.Paletted, opts.Frames),
+		Delay:     make([]int, opts.Frames),
+		LoopCount: opts.Loops,
+	}
+	for f := 0; f < opts.Frames; f++ {
+		buf := graphic.NewBuffer()
+		for _, p := range particles {
+			pos := p.position(f, opts.Frames)
+			c := graphic.Color(graphic.ScaleBrightness(opts.Color[:], p.brightnessAt(f, opts.Frames)))
+			graphic.SetPixel(buf, pos.X, pos.Y, c)
+		}
+		g.Image[f] = graphic.RGBToPalettedMedianCut(buf, graphic.MaxPaletteColors)
+		g.Delay[f] = frameDelay
+	}
+
+	var out bytes.Buffer
+	if err := gif.EncodeAll(&out, g); err != nil {
+		return nil, fmt.Errorf("failed to encode GIF: %w", err)
+	}
+	return out.Bytes(), nil
+}
@@ -0,0 +1,88 @@
+package particles
+
+import (
+	"bytes"
+	"image/gif"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateGIF(t *testing.T) {
+	for _, kind := range []string{KindSnow, KindStars} {
+		t.Run(kind, func(t *testing.T) {
+			opts := DefaultOptions()
+			opts.Kind = kind
+			opts.Seed = 1
+
+			data, err := GenerateGIF(opts)
+			require.NoError(t, err)
+
+			g, err := gif.DecodeAll(bytes.NewReader(data))
+			require.NoError(t, err)
+			require.Len(t, g.Image, opts.Frames)
+			assert.Equal(t, 64, g.Config.Width)
+			assert.Equal(t, 64, g.Config.Height)
+		})
+	}
+}
+
+func TestGenerateGIFSeeded(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Seed = 42
+
+	first, err := GenerateGIF(opts)
+	require.NoError(t, err)
+	second, err := GenerateGIF(opts)
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+}
+
+func TestParticlesLoopSeamlessly(t *testing.T) {
+	for _, kind := range []string{KindSnow, KindStars} {
+		for _, frames := range []int{16, 24, 32} {
+			opts := DefaultOptions()
+			opts.Kind = kind
+			opts.Speed = 3
+			opts.Wind = -2
+			opts.Frames = frames
+			opts.Seed = 7
+
+			for i, p := range newParticles(opts) {
+				// One step after the last frame is the first frame again
+				assert.Equal(t, p.position(0, frames), p.position(frames, frames), "%s particle %d with %d frames", kind, i, frames)
+				assert.Equal(t, p.brightnessAt(0, frames), p.brightnessAt(frames, frames), "%s particle %d with %d frames", kind, i, frames)
+
+				// And the last frame is one step away from it, without a jump
+				last, first := p.position(frames-1, frames), p.position(0, frames)
+				assert.LessOrEqual(t, wrappedDistance(last.X, first.X), 64*4/frames+1)
+				assert.LessOrEqual(t, wrappedDistance(last.Y, first.Y), 64*4/frames+1)
+			}
+		}
+	}
+}
+
+// wrappedDistance returns the distance between two coordinates on the
+// display, wrapping around its edges
+func wrappedDistance(a, b int) int {
+	d := wrap(a-b, 64)
+	return min(d, 64-d)
+}
+
+func TestGenerateGIFInvalidOptions(t *testing.T) {
+	for name, modify := range map[string]func(*Options){
+		"unknown kind":  func(o *Options) { o.Kind = "rain" },
+		"no particles":  func(o *Options) { o.Count = 0 },
+		"zero speed":    func(o *Options) { o.Speed = 0 },
+		"too much wind": func(o *Options) { o.Wind = 5 },
+		"single frame":  func(o *Options) { o.Frames = 1 },
+	} {
+		t.Run(name, func(t *testing.T) {
+			opts := DefaultOptions()
+			modify(&opts)
+			_, err := GenerateGIF(opts)
+			assert.Error(t, err)
+		})
+	}
+}
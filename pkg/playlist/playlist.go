@@ -0,0 +1,271 @@
+// Package playlist loops through an ordered list of display items, each
+// shown for a fixed duration.
+package playlist
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/gif"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/emoji"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/fire"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/grot"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/text"
+)
+
+// Item types
+const (
+	TypeEmoji = "emoji"
+	TypeGrot  = "grot"
+	TypeFire  = "fire"
+	TypeText  = "text"
+	TypeGIF   = "gif"
+)
+
+// Item is a single entry of a playlist.
+//
+// Params depend on the type:
+//   - emoji, grot: "name"
+//   - fire: none
+//   - text: "text", optional "animation" (default "none") and "color" (name or
+//     hex "#RRGGBB", default "white")
+//   - gif: "path" of a 64x64 GIF file, re-encoded like showgif does, and sped
+//     up if longer than graphic.MaxGIFDurationMs
+type Item struct {
+	Type     string            `json:"type"`
+	Params   map[string]string `json:"params,omitempty"`
+	Duration Duration          `json:"duration,omitempty"`
+}
+
+// Duration is a time.Duration encoded in JSON as a string (e.g. "5s").
+type Duration time.Duration
+
+// MarshalJSON encodes the duration as a string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON decodes a duration string such as "5s" or "1m30s".
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid duration %s: must be a string like \"5s\"", string(data))
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %w", s, err)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Validate checks that the item has a known type, its required params and a
+// positive duration.
+func (i Item) Validate() error {
+	if err := i.ValidateContent(); err != nil {
+		return err
+	}
+	if i.Duration <= 0 {
+		return fmt.Errorf("%s item requires a positive duration", i.Type)
+	}
+	return nil
+}
+
+// ValidateContent checks that the item has a known type and its required
+// params, ignoring the duration.
+func (i Item) ValidateContent() error {
+	switch i.Type {
+	case TypeEmoji, TypeGrot:
+		if i.Params["name"] == "" {
+			return fmt.Errorf("%s item requires a \"name\" param", i.Type)
+		}
+	case TypeFire:
+	case TypeText:
+		if i.Params["text"] == "" {
+			return fmt.Errorf("text item requires a \"text\" param")
+		}
+	case TypeGIF:
+		if i.Params["path"] == "" {
+			return fmt.Errorf("gif item requires a \"path\" param")
+		}
+	default:
+		return fmt.Errorf("unknown item type: %q (valid: %s)", i.Type, strings.Join([]string{TypeEmoji, TypeGrot, TypeFire, TypeText, TypeGIF}, ", "))
+	}
+	return nil
+}
+
+// Load reads a playlist from a JSON file containing an array of items.
+func Load(path string) ([]Item, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read playlist: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse decodes and validates a playlist from JSON.
+func Parse(data []byte) ([]Item, error) {
+	var items []Item
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("failed to parse playlist: %w", err)
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("playlist is empty")
+	}
+	for idx, item := range items {
+		if err := item.Validate(); err != nil {
+			return nil, fmt.Errorf("item %d: %w", idx, err)
+		}
+	}
+	return items, nil
+}
+
+// Generate returns the GIF bytes to display for the item.
+func Generate(item Item) ([]byte, error) {
+	switch item.Type {
+	case TypeEmoji:
+		img, err := emoji.Generate(item.Params["name"])
+		if err != nil {
+			return nil, err
+		}
+		return img.GIFBytes()
+
+	case TypeGrot:
+		img, err := grot.Generate(item.Params["name"])
+		if err != nil {
+			return nil, err
+		}
+		return img.GIFBytes()
+
+	case TypeFire:
+		return fire.GenerateGIF(0), nil
+
+	case TypeText:
+		animation := item.Params["animation"]
+		if animation == "" {
+			animation = "none"
+		}
+		colorName := item.Params["color"]
+		if strings.TrimSpace(colorName) == "" {
+			colorName = "white"
+		}
+		color, err := graphic.ParseColor(colorName)
+		if err != nil {
+			return nil, err
+		}
+		return GenerateTextGIF(item.Params["text"], animation, color)
+
+	case TypeGIF:
+		// Nobody sees a warning about a GIF over the duration limit while the
+		// playlist runs, so long GIFs are sped up to fit
+		opts := graphic.DefaultReencodeOptions()
+		opts.FitDuration = true
+		result, err := graphic.LoadGIF(item.Params["path"], opts)
+		if err != nil {
+			return nil, err
+		}
+		return result.Data, nil
+
+	default:
+		return nil, fmt.Errorf("unknown item type: %q", item.Type)
+	}
+}
+
+// GenerateTextGIF renders a text message as GIF bytes. Static text (the "none"
+// animation) is encoded as a single frame GIF.
+func GenerateTextGIF(msg, animation string, color graphic.Color) ([]byte, error) {
+	opts := text.DefaultAnimationOptions()
+	opts.TextColor = color
+	opts.ShadowColor = graphic.ShadowFor(color)
+	img, errMsg := text.GenerateAnimation(animation, msg, opts)
+	if errMsg != "" {
+		return nil, fmt.Errorf("%s", errMsg)
+	}
+
+	if img.Type == graphic.ImageTypeStatic {
+		img = &graphic.Image{
+			Type: graphic.ImageTypeAnimated,
+			GIFData: &gif.GIF{
+				Image: []*image.Paletted{graphic.RGBToPaletted(img.StaticData)},
+				Delay: []int{0},
+			},
+		}
+	}
+	return img.GIFBytes()
+}
+
+// Player tracks which item of a playlist is showing. It doesn't read the
+// clock itself: callers pass the current time, so it can be driven by a fake
+// clock in tests.
+type Player struct {
+	items    []Item
+	index    int
+	started  time.Time // When the current item started showing
+	running  bool
+	paused   bool
+	pausedAt time.Time
+}
+
+// NewPlayer creates a player for the given items.
+func NewPlayer(items []Item) *Player {
+	return &Player{items: items}
+}
+
+// Start begins playback from the first item.
+func (p *Player) Start(now time.Time) {
+	p.index = 0
+	p.started = now
+	p.running = true
+	p.paused = false
+}
+
+// Current returns the index and the item currently showing.
+func (p *Player) Current() (int, Item) {
+	return p.index, p.items[p.index]
+}
+
+// Paused returns true if playback is paused.
+func (p *Player) Paused() bool {
+	return p.paused
+}
+
+// Update advances to the next item once the current one has been shown for
+// its duration, looping back to the start after the last item. The next item
+// starts at now, so no item is skipped if the caller falls behind. Returns
+// true if the current item changed.
+func (p *Player) Update(now time.Time) bool {
+	if !p.running || p.paused {
+		return false
+	}
+	if now.Sub(p.started) < time.Duration(p.items[p.index].Duration) {
+		return false
+	}
+
+	p.index = (p.index + 1) % len(p.items)
+	p.started = now
+	return true
+}
+
+// Pause freezes playback on the current item.
+func (p *Player) Pause(now time.Time) {
+	if p.paused {
+		return
+	}
+	p.paused = true
+	p.pausedAt = now
+}
+
+// Resume continues playback, keeping the time the current item had left when
+// paused.
+func (p *Player) Resume(now time.Time) {
+	if !p.paused {
+		return
+	}
+	p.paused = false
+	p.started = p.started.Add(now.Sub(p.pausedAt))
+}
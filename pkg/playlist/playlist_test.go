@@ -0,0 +1,194 @@
+package playlist
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+)
+
+func testItems() []Item {
+	return []Item{
+		{Type: TypeEmoji, Params: map[string]string{"name": "rocket"}, Duration: Duration(3 * time.Second)},
+		{Type: TypeFire, Duration: Duration(5 * time.Second)},
+		{Type: TypeText, Params: map[string]string{"text": "HI"}, Duration: Duration(2 * time.Second)},
+	}
+}
+
+func TestPlayerAdvancesAndLoops(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	p := NewPlayer(testItems())
+	p.Start(start)
+
+	steps := []struct {
+		offset   time.Duration
+		changed  bool
+		expected int
+	}{
+		{offset: 0, changed: false, expected: 0},
+		{offset: 2999 * time.Millisecond, changed: false, expected: 0},
+		{offset: 3 * time.Second, changed: true, expected: 1},
+		{offset: 7 * time.Second, changed: false, expected: 1},
+		{offset: 8 * time.Second, changed: true, expected: 2},
+		{offset: 10 * time.Second, changed: true, expected: 0}, // Loops back to the start
+		{offset: 13 * time.Second, changed: true, expected: 1},
+	}
+
+	for _, step := range steps {
+		changed := p.Update(start.Add(step.offset))
+		index, _ := p.Current()
+		assert.Equal(t, step.changed, changed, "changed at %v", step.offset)
+		assert.Equal(t, step.expected, index, "index at %v", step.offset)
+	}
+}
+
+func TestPlayerDoesNotSkipItemsWhenBehind(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	p := NewPlayer(testItems())
+	p.Start(start)
+
+	// Way past the whole playlist (e.g. after a slow upload): only advance by one
+	assert.True(t, p.Update(start.Add(time.Minute)))
+	index, item := p.Current()
+	assert.Equal(t, 1, index)
+	assert.Equal(t, TypeFire, item.Type)
+
+	// The next item gets its full duration from that point
+	assert.False(t, p.Update(start.Add(time.Minute+4*time.Second)))
+	assert.True(t, p.Update(start.Add(time.Minute+5*time.Second)))
+}
+
+func TestPlayerPauseResume(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	p := NewPlayer(testItems())
+	p.Start(start)
+
+	// Pause 1s into the first item (2s left)
+	p.Pause(start.Add(time.Second))
+	assert.True(t, p.Paused())
+	assert.False(t, p.Update(start.Add(time.Hour)), "paused player should not advance")
+
+	// Resume 10s later: the first item still has 2s left
+	p.Resume(start.Add(11 * time.Second))
+	assert.False(t, p.Paused())
+	assert.False(t, p.Update(start.Add(12*time.Second+999*time.Millisecond)))
+	assert.True(t, p.Update(start.Add(13*time.Second)))
+
+	index, _ := p.Current()
+	assert.Equal(t, 1, index)
+}
+
+func TestPlayerNotStarted(t *testing.T) {
+	p := NewPlayer(testItems())
+	assert.False(t, p.Update(time.Now()))
+}
+
+func TestParse(t *testing.T) {
+	t.Run("valid playlist", func(t *testing.T) {
+		items, err := Parse([]byte(`[
+			{"type": "emoji", "params": {"name": "rocket"}, "duration": "3s"},
+			{"type": "fire", "duration": "1m"}
+		]`))
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+		assert.Equal(t, TypeEmoji, items[0].Type)
+		assert.Equal(t, "rocket", items[0].Params["name"])
+		assert.Equal(t, Duration(3*time.Second), items[0].Duration)
+		assert.Equal(t, Duration(time.Minute), items[1].Duration)
+	})
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "invalid JSON", input: `[{`},
+		{name: "empty playlist", input: `[]`},
+		{name: "unknown type", input: `[{"type": "video", "duration": "3s"}]`},
+		{name: "missing name", input: `[{"type": "emoji", "duration": "3s"}]`},
+		{name: "missing text", input: `[{"type": "text", "duration": "3s"}]`},
+		{name: "missing path", input: `[{"type": "gif", "duration": "3s"}]`},
+		{name: "missing duration", input: `[{"type": "fire"}]`},
+		{name: "numeric duration", input: `[{"type": "fire", "duration": 3}]`},
+		{name: "invalid duration", input: `[{"type": "fire", "duration": "soon"}]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.input))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "playlist.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[{"type": "fire", "duration": "2s"}]`), 0o644))
+
+	items, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, []Item{{Type: TypeFire, Duration: Duration(2 * time.Second)}}, items)
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestGenerate(t *testing.T) {
+	for _, item := range testItems() {
+		t.Run(item.Type, func(t *testing.T) {
+			data, err := Generate(item)
+			require.NoError(t, err)
+			assert.Equal(t, "GIF", string(data[:3]))
+		})
+	}
+
+	t.Run("unknown emoji", func(t *testing.T) {
+		_, err := Generate(Item{Type: TypeEmoji, Params: map[string]string{"name": "does-not-exist"}})
+		assert.Error(t, err)
+	})
+
+	t.Run("hex color", func(t *testing.T) {
+		data, err := Generate(Item{Type: TypeText, Params: map[string]string{"text": "HI", "color": "#ff8800"}})
+		require.NoError(t, err)
+		assert.Equal(t, "GIF", string(data[:3]))
+	})
+
+	t.Run("unknown color", func(t *testing.T) {
+		_, err := Generate(Item{Type: TypeText, Params: map[string]string{"text": "HI", "color": "plaid"}})
+		assert.Error(t, err)
+	})
+	t.Run("gif is re-encoded for the device", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "big.gif")
+		writeGIF(t, path, 128, 100)
+
+		_, err := Generate(Item{Type: TypeGIF, Params: map[string]string{"path": path}})
+		assert.ErrorContains(t, err, "expected 64x64")
+
+		writeGIF(t, path, graphic.DisplayWidth, 100)
+		data, err := Generate(Item{Type: TypeGIF, Params: map[string]string{"path": path}})
+		require.NoError(t, err)
+		g, err := gif.DecodeAll(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Len(t, g.Image, graphic.MaxGIFFrames)
+	})
+}
+
+// writeGIF writes a size x size GIF with the given number of 100ms frames
+func writeGIF(t *testing.T, path string, size, frames int) {
+	pal := color.Palette{color.Black, color.White}
+	g := &gif.GIF{Config: image.Config{Width: size, Height: size, ColorModel: pal}}
+	for i := 0; i < frames; i++ {
+		g.Image = append(g.Image, image.NewPaletted(image.Rect(0, 0, size, size), pal))
+		g.Delay = append(g.Delay, 10)
+	}
+	var buf bytes.Buffer
+	require.NoError(t, gif.EncodeAll(&buf, g))
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o644))
+}
@@ -0,0 +1,100 @@
+// Package progress renders a progress bar with a percentage label, updating the
+// display with only the pixels that changed between values.
+package progress
+
+import (
+	"fmt"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/text"
+)
+
+// Bar layout: the bar is filled from the left inside a 1 pixel border frame,
+// with a 1 pixel gap between the frame and the fill
+const (
+	BarX      = 4  // Left column of the fill
+	BarY      = 30 // Top row of the fill
+	BarWidth  = 56 // Fill width at 100%
+	BarHeight = 8  // Fill height
+	LabelY    = 18 // Top row of the percentage label
+)
+
+// Options configures the progress bar colors.
+type Options struct {
+	Color       graphic.Color // Fill and label color
+	BorderColor graphic.Color // Frame color
+}
+
+// DefaultOptions returns a green bar in a gray frame.
+func DefaultOptions() Options {
+	return Options{
+		Color:       graphic.Green,
+		BorderColor: graphic.Gray,
+	}
+}
+
+// ValidateValue checks the value is a percentage.
+func ValidateValue(value int) error {
+	if value < 0 || value > 100 {
+		return fmt.Errorf("invalid value: %d (valid: 0-100)", value)
+	}
+	return nil
+}
+
+// FillWidth returns how many columns of the bar a value fills, rounded to the
+// closest column. Values out of range are clamped.
+func FillWidth(value int) int {
+	value = max(0, min(value, 100))
+	return (value*BarWidth + 50) / 100
+}
+
+// Label returns the percentage label of a value, such as "50%".
+func Label(value int) string {
+	return fmt.Sprintf("%d%%", value)
+}
+
+// LabelX returns the left column of the centered label of a value.
+func LabelX(value int) int {
+	return (graphic.DisplayWidth - text.TextWidth(Label(value))) / 2
+}
+
+// Draw draws the framed bar filled proportionally to value, with the
+// percentage centered above it.
+func Draw(buf []byte, value int, opts Options) {
+	left, right := BarX-2, BarX+BarWidth+1
+	top, bottom := BarY-2, BarY+BarHeight+1
+	graphic.DrawLine(buf, left, top, right, top, opts.BorderColor)
+	graphic.DrawLine(buf, left, bottom, right, bottom, opts.BorderColor)
+	graphic.DrawLine(buf, left, top, left, bottom, opts.BorderColor)
+	graphic.DrawLine(buf, right, top, right, bottom, opts.BorderColor)
+
+	for x := BarX; x < BarX+FillWidth(value); x++ {
+		for y := BarY; y < BarY+BarHeight; y++ {
+			graphic.SetPixel(buf, x, y, opts.Color)
+		}
+	}
+
+	text.DrawText(buf, Label(value), LabelX(value), LabelY, opts.Color)
+}
+
+// Renderer handles diff-based rendering of the progress bar to the device
+type Renderer struct {
+	*protocol.DiffRenderer
+	opts Options
+}
+
+// NewRenderer creates a new renderer
+func NewRenderer(device protocol.DeviceConnection, opts Options) *Renderer {
+	return &Renderer{
+		DiffRenderer: protocol.NewDiffRenderer(device),
+		opts:         opts,
+	}
+}
+
+// Render draws the bar for the given value on the current buffer.
+// This is a pure function that updates the buffer without I/O
+func (r *Renderer) Render(value int) {
+	copy(r.Buffer(), graphic.NewBuffer())
+	Draw(r.Buffer(), value, r.opts)
+}
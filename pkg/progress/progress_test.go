@@ -0,0 +1,117 @@
+package progress
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/text"
+)
+
+func pixelAt(buf []byte, x, y int) graphic.Color {
+	offset := (y*graphic.DisplayWidth + x) * 3
+	return graphic.Color{buf[offset], buf[offset+1], buf[offset+2]}
+}
+
+func TestFillWidth(t *testing.T) {
+	tests := []struct {
+		value    int
+		expected int
+	}{
+		{value: 0, expected: 0},
+		{value: 1, expected: 1},
+		{value: 50, expected: BarWidth / 2},
+		{value: 99, expected: BarWidth - 1},
+		{value: 100, expected: BarWidth},
+		{value: -10, expected: 0},
+		{value: 150, expected: BarWidth},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, FillWidth(tt.value), "value %d", tt.value)
+	}
+}
+
+func TestValidateValue(t *testing.T) {
+	assert.NoError(t, ValidateValue(0))
+	assert.NoError(t, ValidateValue(100))
+	assert.Error(t, ValidateValue(-1))
+	assert.Error(t, ValidateValue(101))
+}
+
+func TestDrawHalfFillsHalfTheBar(t *testing.T) {
+	opts := DefaultOptions()
+	buf := graphic.NewBuffer()
+	Draw(buf, 50, opts)
+
+	for y := BarY; y < BarY+BarHeight; y++ {
+		for x := BarX; x < BarX+BarWidth; x++ {
+			expected := graphic.Black
+			if x < BarX+BarWidth/2 {
+				expected = opts.Color
+			}
+			require.Equal(t, expected, pixelAt(buf, x, y), "pixel %d,%d", x, y)
+		}
+	}
+
+	// The frame surrounds the bar with a 1 pixel gap
+	assert.Equal(t, opts.BorderColor, pixelAt(buf, BarX-2, BarY-2))
+	assert.Equal(t, opts.BorderColor, pixelAt(buf, BarX+BarWidth+1, BarY+BarHeight+1))
+	assert.Equal(t, graphic.Black, pixelAt(buf, BarX-1, BarY))
+	assert.Equal(t, graphic.Black, pixelAt(buf, BarX+BarWidth, BarY))
+}
+
+func TestDrawLabel(t *testing.T) {
+	opts := DefaultOptions()
+
+	for _, value := range []int{0, 7, 50, 100} {
+		buf := graphic.NewBuffer()
+		Draw(buf, value, opts)
+
+		// The label area matches the percentage drawn on its own
+		expected := graphic.NewBuffer()
+		text.DrawText(expected, Label(value), LabelX(value), LabelY, opts.Color)
+		for y := LabelY; y < LabelY+text.FontHeight; y++ {
+			start := y * graphic.DisplayWidth * 3
+			require.Equal(t, expected[start:start+graphic.DisplayWidth*3], buf[start:start+graphic.DisplayWidth*3], "value %d row %d", value, y)
+		}
+	}
+
+	// "50%" is the 5, 0 and % glyphs side by side
+	buf := graphic.NewBuffer()
+	Draw(buf, 50, opts)
+	x := LabelX(50)
+	for i, char := range "50%" {
+		glyph := graphic.NewBuffer()
+		text.DrawChar(glyph, char, x+i*text.FontSpacing, LabelY, opts.Color)
+		for gy := LabelY; gy < LabelY+text.FontHeight; gy++ {
+			for gx := x + i*text.FontSpacing; gx < x+i*text.FontSpacing+text.FontWidth; gx++ {
+				assert.Equal(t, pixelAt(glyph, gx, gy), pixelAt(buf, gx, gy), "glyph %q pixel %d,%d", char, gx, gy)
+			}
+		}
+	}
+}
+
+func TestRendererComputeDiff(t *testing.T) {
+	r := NewRenderer(nil, DefaultOptions())
+
+	r.Render(40)
+	r.SetPrevBuffer(r.Buffer())
+	r.Render(40)
+	assert.Empty(t, r.ComputeDiff())
+
+	// Going from 40% to 41% only touches the new fill column and the label
+	r.Render(41)
+	diff := r.ComputeDiff()
+	require.NotEmpty(t, diff)
+	newColumn := BarX + FillWidth(40)
+	for _, points := range diff {
+		for _, p := range points {
+			inLabel := p.Y >= LabelY && p.Y < LabelY+text.FontHeight
+			inNewColumn := p.Y >= BarY && p.Y < BarY+BarHeight && p.X >= newColumn && p.X < BarX+FillWidth(41)
+			assert.True(t, inLabel || inNewColumn, "unexpected change at %d,%d", p.X, p.Y)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+package protocol
+
+import "fmt"
+
+// SetBrightness sets the display backlight brightness (0-100 percent).
+func SetBrightness(d DeviceConnection, level int) error {
+	if level < 0 || level > 100 {
+		return fmt.Errorf("invalid brightness %d (valid: 0-100)", level)
+	}
+	return WriteData(d, []byte{5, 0, 4, 128, byte(level)})
+}
@@ -0,0 +1,51 @@
+package protocol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetBrightness(t *testing.T) {
+	tests := []struct {
+		name     string
+		level    int
+		expected []byte
+	}{
+		{
+			name:     "minimum",
+			level:    0,
+			expected: []byte{5, 0, 4, 128, 0},
+		},
+		{
+			name:     "half",
+			level:    50,
+			expected: []byte{5, 0, 4, 128, 50},
+		},
+		{
+			name:     "maximum",
+			level:    100,
+			expected: []byte{5, 0, 4, 128, 100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &DeviceConnectionMock{}
+			err := SetBrightness(mock, tt.level)
+			require.NoError(t, err)
+			require.Len(t, mock.WrittenPackets, 1)
+			assert.Equal(t, tt.expected, mock.WrittenPackets[0])
+		})
+	}
+}
+
+func TestSetBrightnessOutOfRange(t *testing.T) {
+	for _, level := range []int{-1, 101} {
+		mock := &DeviceConnectionMock{}
+		err := SetBrightness(mock, level)
+		require.Error(t, err)
+		assert.Empty(t, mock.WrittenPackets)
+	}
+}
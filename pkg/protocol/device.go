@@ -1,6 +1,8 @@
 package protocol
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -24,6 +26,12 @@ var readCharacteristicUUID = bluetooth.New16BitUUID(readCharacteristicId)
 
 var btAdapter = bluetooth.DefaultAdapter
 
+// ErrResponseTimeout is returned by ReadResponse when the device doesn't respond in time.
+var ErrResponseTimeout = errors.New("timeout waiting for response")
+
+// scanStopRetryInterval is how often a scan is asked to stop until it does.
+const scanStopRetryInterval = 100 * time.Millisecond
+
 // DeviceNamePrefix is the prefix for iDotMatrix device names.
 const DeviceNamePrefix = "IDM-"
 
@@ -47,16 +55,34 @@ func NewDevice(logger log.Logger) *Device {
 // If targetAddr is empty, it auto-discovers the first device with name prefix "IDM-".
 // If targetAddr is specified, it connects to that specific MAC address.
 func (d *Device) Connect(targetAddr string) error {
+	return d.ConnectContext(context.Background(), targetAddr)
+}
+
+// ConnectContext is like Connect, giving up once ctx is done, e.g. when a
+// connect timeout expires because the device is missing or out of range.
+func (d *Device) ConnectContext(ctx context.Context, targetAddr string) error {
 	if err := btAdapter.Enable(); err != nil {
 		return err
 	}
 
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("scan aborted: %w", err)
+	}
+
+	// Stop scanning once ctx is done
+	scanDone := stopScanWhenDone(ctx)
+
 	// Scan for device
 	if targetAddr == "" {
 		level.Info(d.logger).Log("msg", "Scanning for iDotMatrix devices")
 	}
 
 	err := btAdapter.Scan(func(adapter *bluetooth.Adapter, result bluetooth.ScanResult) {
+		if ctx.Err() != nil {
+			adapter.StopScan()
+			return
+		}
+
 		name := result.LocalName()
 		level.Debug(d.logger).Log("msg", "Found device", "address", result.Address.String(), "rssi", result.RSSI, "name", name)
 
@@ -75,18 +101,31 @@ func (d *Device) Connect(targetAddr string) error {
 			}
 		}
 	})
+	scanDone()
 	if err != nil {
 		return err
 	}
 
 	if d.scanResult.Address.String() == "" {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("scan aborted: %w", err)
+		}
 		if targetAddr != "" {
 			return fmt.Errorf("device with address %q not found", targetAddr)
 		}
 		return fmt.Errorf("no iDotMatrix device found (looking for devices with name starting with %q)", DeviceNamePrefix)
 	}
 
-	btd, err := btAdapter.Connect(d.scanResult.Address, bluetooth.ConnectionParams{})
+	// Connecting can't be cancelled: stop waiting on ctx and disconnect the
+	// device if the connection completes later
+	connected := make(chan result[*bluetooth.Device], 1)
+	go func() {
+		btd, err := btAdapter.Connect(d.scanResult.Address, bluetooth.ConnectionParams{})
+		connected <- result[*bluetooth.Device]{btd, err}
+	}()
+	btd, err := awaitConnect(ctx, connected, func(btd *bluetooth.Device) {
+		btd.Disconnect()
+	})
 	if err != nil {
 		return err
 	}
@@ -148,11 +187,68 @@ func (d *Device) Connect(targetAddr string) error {
 	return nil
 }
 
+// result is the outcome of a blocking BLE operation running in the background
+type result[T any] struct {
+	value T
+	err   error
+}
+
+// awaitConnect waits for the connection attempt sending its outcome on results,
+// or for ctx to be done, whichever comes first. When ctx wins, a connection
+// completing later is passed to abandon, so it doesn't stay open.
+func awaitConnect[T any](ctx context.Context, results <-chan result[T], abandon func(T)) (T, error) {
+	select {
+	case r := <-results:
+		return r.value, r.err
+	case <-ctx.Done():
+		go func() {
+			if r := <-results; r.err == nil {
+				abandon(r.value)
+			}
+		}()
+		var zero T
+		return zero, fmt.Errorf("connect aborted: %w", ctx.Err())
+	}
+}
+
 // Disconnect closes the BLE connection to the device.
 func (d *Device) Disconnect() error {
 	return d.btDevice.Disconnect()
 }
 
+// stopScanWhenDone stops the BLE scan once ctx is done. StopScan has no effect
+// if it runs before the scan started, so it's repeated every
+// scanStopRetryInterval until the returned function is called, once Scan
+// returned.
+func stopScanWhenDone(ctx context.Context) func() {
+	scanDone := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		select {
+		case <-scanDone:
+			return
+		case <-ctx.Done():
+		}
+
+		ticker := time.NewTicker(scanStopRetryInterval)
+		defer ticker.Stop()
+		for {
+			btAdapter.StopScan()
+			select {
+			case <-scanDone:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return func() {
+		close(scanDone)
+		<-stopped
+	}
+}
+
 // ReadResponse waits for a response from the device via BLE notifications.
 // This is used after sending data chunks to wait for the device to process them.
 // Returns the response bytes and any error. Times out after 2 seconds.
@@ -164,7 +260,7 @@ func (d *Device) ReadResponse() ([]byte, error) {
 	case data := <-d.responseChan:
 		return data, nil
 	case <-time.After(2 * time.Second):
-		return nil, fmt.Errorf("timeout waiting for response")
+		return nil, ErrResponseTimeout
 	}
 }
 
@@ -175,6 +271,20 @@ func (d *Device) WritePacket(packet []byte) error {
 	return err
 }
 
+// PollResponse returns a response the device already sent, without waiting
+// for one. It returns false if no response is pending.
+func (d *Device) PollResponse() ([]byte, bool) {
+	if d.responseChan == nil {
+		return nil, false
+	}
+	select {
+	case data := <-d.responseChan:
+		return data, true
+	default:
+		return nil, false
+	}
+}
+
 // DrainResponses clears any stale notifications from the response channel.
 // Call this before starting a new multi-chunk upload to ensure clean state.
 func (d *Device) DrainResponses() {
@@ -201,6 +311,10 @@ type DeviceConnection interface {
 	// ReadResponse waits for a response from the device via BLE notifications.
 	ReadResponse() ([]byte, error)
 
+	// PollResponse returns a response the device already sent, without waiting
+	// for one. It returns false if no response is pending.
+	PollResponse() ([]byte, bool)
+
 	// DrainResponses clears any stale notifications from the response channel.
 	DrainResponses()
 }
@@ -208,15 +322,40 @@ type DeviceConnection interface {
 // WriteData writes data to the device in up to MTU sized chunks.
 // The iDotMatrix device has a 514-byte MTU limit.
 func WriteData(d DeviceConnection, data []byte) error {
+	return WriteDataContext(context.Background(), d, data)
+}
+
+// WriteDataContext is like WriteData, stopping between chunks once ctx is
+// done and returning the context error.
+func WriteDataContext(ctx context.Context, d DeviceConnection, data []byte) error {
+	return WriteDataWithStats(ctx, d, data, nil)
+}
+
+// WriteDataWithStats is like WriteDataContext, adding the bytes and packets
+// written and the time spent writing them to stats, if not nil. Stats add up
+// over calls, so they can describe an upload made of several writes.
+func WriteDataWithStats(ctx context.Context, d DeviceConnection, data []byte, stats *UploadStats) error {
 	const maxMTU = 514
 
+	if stats != nil {
+		start := time.Now()
+		defer func() { stats.Duration += time.Since(start) }()
+	}
+
 	cursor := 0
 	remaining := len(data)
 	for remaining > 0 {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("write aborted: %w", err)
+		}
 		chunkLen := min(maxMTU, remaining)
 		if err := d.WritePacket(data[cursor : cursor+chunkLen]); err != nil {
 			return err
 		}
+		if stats != nil {
+			stats.Bytes += chunkLen
+			stats.Packets++
+		}
 		cursor += chunkLen
 		remaining -= chunkLen
 	}
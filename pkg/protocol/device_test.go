@@ -0,0 +1,61 @@
+package protocol
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAwaitConnect(t *testing.T) {
+	noAbandon := func(int) { t.Error("unexpected abandoned connection") }
+
+	t.Run("returns the connection", func(t *testing.T) {
+		results := make(chan result[int], 1)
+		results <- result[int]{value: 42}
+
+		value, err := awaitConnect(context.Background(), results, noAbandon)
+		require.NoError(t, err)
+		assert.Equal(t, 42, value)
+	})
+
+	t.Run("returns the connection error", func(t *testing.T) {
+		results := make(chan result[int], 1)
+		results <- result[int]{err: errors.New("connection refused")}
+
+		_, err := awaitConnect(context.Background(), results, noAbandon)
+		assert.EqualError(t, err, "connection refused")
+	})
+
+	t.Run("gives up when the context expires", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		abandoned := make(chan int, 1)
+		results := make(chan result[int], 1)
+		_, err := awaitConnect(ctx, results, func(v int) { abandoned <- v })
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		// A connection completing after the timeout is abandoned
+		results <- result[int]{value: 7}
+		select {
+		case v := <-abandoned:
+			assert.Equal(t, 7, v)
+		case <-time.After(time.Second):
+			t.Fatal("late connection was not abandoned")
+		}
+	})
+
+	t.Run("failed late connections are not abandoned", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		results := make(chan result[int], 1)
+		_, err := awaitConnect(ctx, results, noAbandon)
+		require.ErrorIs(t, err, context.Canceled)
+		results <- result[int]{err: errors.New("timeout")}
+	})
+}
@@ -0,0 +1,83 @@
+package protocol
+
+import (
+	"time"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+)
+
+// DiffRenderer keeps the frame shown on the device and the frame being drawn,
+// and sends only the pixels that changed between them. Renderers embed it,
+// draw their state into Buffer() and call Flush.
+type DiffRenderer struct {
+	device     DeviceConnection
+	prevBuffer []byte
+	currBuffer []byte
+
+	// Upload holds the delays used when flushing pixels to the device
+	Upload UploadConfig
+}
+
+// NewDiffRenderer creates a diff renderer whose shown and drawn frames start
+// black.
+func NewDiffRenderer(device DeviceConnection) *DiffRenderer {
+	return &DiffRenderer{
+		device:     device,
+		prevBuffer: graphic.NewBuffer(),
+		currBuffer: graphic.NewBuffer(),
+		Upload:     DefaultPixelUploadConfig(),
+	}
+}
+
+// Buffer returns the frame being drawn, sent to the device by the next Flush.
+func (r *DiffRenderer) Buffer() []byte {
+	return r.currBuffer
+}
+
+// ComputeDiff finds changed pixels grouped by color
+// Returns a map of color to list of points that changed to that color
+func (r *DiffRenderer) ComputeDiff() map[graphic.Color][]graphic.Point {
+	_, diff := graphic.DiffBuffers(r.prevBuffer, r.currBuffer)
+	return diff
+}
+
+// Show uploads the current buffer as a full image and marks it as displayed.
+// Used for the first frame, subsequent frames are sent with Flush.
+func (r *DiffRenderer) Show() error {
+	if err := SetDrawMode(r.device, 1); err != nil {
+		return err
+	}
+	if err := SendImage(r.device, r.currBuffer); err != nil {
+		return err
+	}
+	copy(r.prevBuffer, r.currBuffer)
+	return nil
+}
+
+// Flush sends changed pixels to the device using multi-pixel packets
+func (r *DiffRenderer) Flush() error {
+	return r.FlushWith(time.Sleep)
+}
+
+// FlushWith is like Flush, waiting between packets with sleep (e.g. the Sleep
+// of a fake clock in tests).
+func (r *DiffRenderer) FlushWith(sleep func(time.Duration)) error {
+	return FlushDiff(r.device, r.prevBuffer, r.currBuffer, r.Upload, sleep)
+}
+
+// SetPrevBuffer sets the previous buffer (used for initial state)
+func (r *DiffRenderer) SetPrevBuffer(data []byte) {
+	copy(r.prevBuffer, data)
+}
+
+// SetCurrBuffer sets the current buffer (used for initial state)
+func (r *DiffRenderer) SetCurrBuffer(data []byte) {
+	copy(r.currBuffer, data)
+}
+
+// GetCurrBuffer returns a copy of the current buffer (for testing)
+func (r *DiffRenderer) GetCurrBuffer() []byte {
+	buf := make([]byte, len(r.currBuffer))
+	copy(buf, r.currBuffer)
+	return buf
+}
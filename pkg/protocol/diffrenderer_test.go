@@ -0,0 +1,48 @@
+package protocol
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+)
+
+func TestDiffRenderer(t *testing.T) {
+	mock := &DeviceConnectionMock{}
+	r := NewDiffRenderer(mock)
+	r.Upload = UploadConfig{}
+
+	// Nothing drawn yet: nothing to send
+	assert.Empty(t, r.ComputeDiff())
+	require.NoError(t, r.Flush())
+	assert.Empty(t, mock.WrittenPackets)
+
+	// Only the drawn pixels are sent, once
+	graphic.SetPixel(r.Buffer(), 3, 4, graphic.Red)
+	graphic.SetPixel(r.Buffer(), 5, 6, graphic.Red)
+	assert.Equal(t, map[graphic.Color][]graphic.Point{graphic.Red: {{X: 3, Y: 4}, {X: 5, Y: 6}}}, r.ComputeDiff())
+
+	var sleeps int
+	require.NoError(t, r.FlushWith(func(time.Duration) { sleeps++ }))
+	require.Len(t, mock.WrittenPackets, 1)
+	assert.Equal(t, []byte{3, 4, 5, 6}, mock.WrittenPackets[0][8:12])
+	assert.Zero(t, sleeps) // No wait after the last packet
+	assert.Empty(t, r.ComputeDiff())
+
+	// GetCurrBuffer returns a copy
+	buf := r.GetCurrBuffer()
+	buf[0] = 255
+	assert.Empty(t, r.ComputeDiff())
+}
+
+func TestDiffRendererInitialBuffers(t *testing.T) {
+	r := NewDiffRenderer(&DeviceConnectionMock{})
+	background := graphic.NewBufferWithColor(graphic.Blue)
+	r.SetPrevBuffer(background)
+	r.SetCurrBuffer(background)
+	assert.Empty(t, r.ComputeDiff())
+	assert.Equal(t, background, r.GetCurrBuffer())
+}
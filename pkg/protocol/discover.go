@@ -0,0 +1,81 @@
+package protocol
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"tinygo.org/x/bluetooth"
+)
+
+// DiscoveredDevice is an iDotMatrix device found while scanning.
+type DiscoveredDevice struct {
+	Address string `json:"address"`
+	Name    string `json:"name"`
+	RSSI    int16  `json:"rssi"`
+}
+
+// Discover scans for nearby devices for the given duration, without
+// connecting to any of them, and returns the iDotMatrix devices found.
+func Discover(timeout time.Duration) ([]DiscoveredDevice, error) {
+	if err := btAdapter.Enable(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	scanDone := stopScanWhenDone(ctx)
+
+	var (
+		mx    sync.Mutex
+		found []DiscoveredDevice
+	)
+	err := btAdapter.Scan(func(adapter *bluetooth.Adapter, result bluetooth.ScanResult) {
+		if ctx.Err() != nil {
+			adapter.StopScan()
+			return
+		}
+
+		mx.Lock()
+		defer mx.Unlock()
+		found = append(found, DiscoveredDevice{
+			Address: result.Address.String(),
+			Name:    result.LocalName(),
+			RSSI:    result.RSSI,
+		})
+	})
+	scanDone()
+	if err != nil {
+		return nil, err
+	}
+
+	mx.Lock()
+	defer mx.Unlock()
+	return FilterDevices(found), nil
+}
+
+// FilterDevices keeps the devices whose name starts with DeviceNamePrefix,
+// one per address with its most recent RSSI, sorted by name and address.
+func FilterDevices(found []DiscoveredDevice) []DiscoveredDevice {
+	byAddress := make(map[string]DiscoveredDevice)
+	for _, device := range found {
+		if !strings.HasPrefix(device.Name, DeviceNamePrefix) {
+			continue
+		}
+		byAddress[strings.ToUpper(device.Address)] = device
+	}
+
+	devices := make([]DiscoveredDevice, 0, len(byAddress))
+	for _, device := range byAddress {
+		devices = append(devices, device)
+	}
+	sort.Slice(devices, func(i, j int) bool {
+		if devices[i].Name != devices[j].Name {
+			return devices[i].Name < devices[j].Name
+		}
+		return devices[i].Address < devices[j].Address
+	})
+	return devices
+}
@@ -0,0 +1,27 @@
+package protocol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterDevices(t *testing.T) {
+	found := []DiscoveredDevice{
+		{Address: "AA:BB:CC:DD:EE:02", Name: "IDM-B", RSSI: -70},
+		{Address: "11:22:33:44:55:66", Name: "Headphones", RSSI: -40},
+		{Address: "AA:BB:CC:DD:EE:01", Name: "IDM-A", RSSI: -80},
+		{Address: "22:33:44:55:66:77", Name: "", RSSI: -50},
+		{Address: "aa:bb:cc:dd:ee:02", Name: "IDM-B", RSSI: -60}, // Seen again, closer
+	}
+
+	assert.Equal(t, []DiscoveredDevice{
+		{Address: "AA:BB:CC:DD:EE:01", Name: "IDM-A", RSSI: -80},
+		{Address: "aa:bb:cc:dd:ee:02", Name: "IDM-B", RSSI: -60},
+	}, FilterDevices(found))
+}
+
+func TestFilterDevicesEmpty(t *testing.T) {
+	assert.Empty(t, FilterDevices(nil))
+	assert.Empty(t, FilterDevices([]DiscoveredDevice{{Address: "11:22:33:44:55:66", Name: "Headphones"}}))
+}
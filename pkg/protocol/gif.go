@@ -1,13 +1,19 @@
 package protocol
 
 import (
+	"bytes"
+	"context"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"hash/crc32"
+	"image/gif"
 	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
 )
 
 const (
@@ -19,24 +25,84 @@ const (
 
 // SendGIF sends an animated GIF to the display.
 // gifData should be the raw GIF file bytes (re-encoded GIF).
-func SendGIF(d DeviceConnection, gifData []byte, logger log.Logger) error {
+func SendGIF(d DeviceConnection, gifData []byte, cfg UploadConfig, logger log.Logger) error {
+	return SendGIFContext(context.Background(), d, gifData, cfg, logger)
+}
+
+// SendGIFContext is like SendGIF, aborting the upload between BLE packets
+// once ctx is done and returning the context error.
+func SendGIFContext(ctx context.Context, d DeviceConnection, gifData []byte, cfg UploadConfig, logger log.Logger) error {
+	return sendGIF(ctx, d, gifData, cfg, logger)
+}
+
+// SendGIFOnce sends an animated GIF to the display that plays a single pass
+// and then stops on its last frame, instead of looping forever. The GIF is
+// re-encoded with a loop count of -1 (no looping extension), so it relies on
+// the loop count stored in the GIF. Returns an error if gifData isn't a
+// valid GIF.
+func SendGIFOnce(d DeviceConnection, gifData []byte, cfg UploadConfig, logger log.Logger) error {
+	return SendGIFOnceContext(context.Background(), d, gifData, cfg, logger)
+}
+
+// SendGIFOnceContext is like SendGIFOnce, aborting the upload between BLE
+// packets once ctx is done and returning the context error.
+func SendGIFOnceContext(ctx context.Context, d DeviceConnection, gifData []byte, cfg UploadConfig, logger log.Logger) error {
+	once, err := playOnce(gifData)
+	if err != nil {
+		return err
+	}
+	return sendGIF(ctx, d, once, cfg, logger)
+}
+
+// playOnce returns gifData re-encoded to show each frame a single time.
+func playOnce(gifData []byte) ([]byte, error) {
+	g, err := gif.DecodeAll(bytes.NewReader(gifData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode GIF: %w", err)
+	}
+	g.LoopCount = -1
+
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, g); err != nil {
+		return nil, fmt.Errorf("failed to encode GIF: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// sendGIF uploads the GIF in chunks, each with its header.
+func sendGIF(ctx context.Context, d DeviceConnection, gifData []byte, cfg UploadConfig, logger log.Logger) error {
 	// Drain any stale notifications from previous operations
 	d.DrainResponses()
 
 	// Brief stabilization delay after connection
-	time.Sleep(100 * time.Millisecond)
+	if err := sleepContext(ctx, cfg.StabilizeDelay); err != nil {
+		return fmt.Errorf("upload aborted: %w", err)
+	}
 
 	// Calculate CRC32 of entire GIF data (same value used in all chunk headers)
 	crc := crc32.ChecksumIEEE(gifData)
 
-	level.Debug(logger).Log("msg", "GIF upload starting", "size", len(gifData), "crc32", fmt.Sprintf("0x%08X", crc))
+	// Frame count and duration help explain slow uploads
+	level.Debug(logger).Log("msg", "GIF upload starting", "size", len(gifData), "gif", gifSummary(gifData), "crc32", fmt.Sprintf("0x%08X", crc))
 
 	// Split GIF data into 4096-byte chunks
 	chunks := chunkBuffer(gifData, gifChunkSize)
 
 	level.Debug(logger).Log("msg", "Split into chunks", "chunks", len(chunks))
 
+	var stats UploadStats
+	start := time.Now()
+	defer func() {
+		stats.Duration = time.Since(start)
+		level.Debug(logger).Log("msg", "GIF upload finished", "bytes", stats.Bytes, "chunks", stats.Chunks, "packets", stats.Packets, "retries", stats.Retries, "elapsed", stats.Duration, "bytes_per_sec", fmt.Sprintf("%.0f", stats.Throughput()))
+		if cfg.Stats != nil {
+			*cfg.Stats = stats
+		}
+	}()
+
 	for ci, chunk := range chunks {
+		chunkStart := time.Now()
+
 		// Build 16-byte header for this chunk
 		header := make([]byte, gifHeaderSize)
 
@@ -65,7 +131,7 @@ func SendGIF(d DeviceConnection, gifData []byte, logger log.Logger) error {
 		// Bytes 9-12: CRC32 - little-endian
 		binary.LittleEndian.PutUint32(header[9:13], crc)
 
-		// Bytes 13-14: Time signature = 0 (for gif_type=12)
+		// Bytes 13-14: Time signature = 0 (no time signature)
 		header[13] = 0
 		header[14] = 0
 
@@ -82,23 +148,46 @@ func SendGIF(d DeviceConnection, gifData []byte, logger log.Logger) error {
 
 		level.Debug(logger).Log("msg", "BLE packets to send", "chunk", ci+1, "packets", len(blePackets))
 
-		// Send all BLE packets for this chunk
-		for pi, pkt := range blePackets {
-			level.Debug(logger).Log("msg", "Sending BLE packet", "packet", pi+1, "total", len(blePackets), "bytes", len(pkt))
-			if err := d.WritePacket(pkt); err != nil {
-				return fmt.Errorf("failed to send BLE packet %d: %w", pi+1, err)
+		// Send all BLE packets for this chunk and read the response. If the
+		// device doesn't respond in time, it hasn't acknowledged the chunk, so
+		// the chunk is re-sent up to cfg.MaxRetries times. A response arriving
+		// during the backoff acknowledges the chunk, which is then not re-sent:
+		// chunk headers carry no offset, so a duplicate would corrupt the GIF.
+		var response []byte
+		backoff := cfg.RetryBackoff
+		for retry := 0; ; retry++ {
+			if err := writeGIFPackets(ctx, d, blePackets, cfg, logger); err != nil {
+				return err
+			}
+			stats.Packets += len(blePackets)
+
+			level.Debug(logger).Log("msg", "Waiting for response", "chunk", ci+1)
+			var err error
+			response, err = d.ReadResponse()
+			if err == nil {
+				break
+			}
+			if !errors.Is(err, ErrResponseTimeout) || retry >= cfg.MaxRetries {
+				return fmt.Errorf("chunk %d: read response failed: %w", ci+1, err)
+			}
+
+			if err := sleepContext(ctx, backoff); err != nil {
+				return fmt.Errorf("chunk %d: upload aborted: %w", ci+1, err)
+			}
+			backoff *= 2
+
+			var late bool
+			if response, late = d.PollResponse(); late {
+				level.Debug(logger).Log("msg", "Late response received, not re-sending chunk", "chunk", ci+1)
+				break
 			}
-			// Small delay between packets to let device process
-			time.Sleep(10 * time.Millisecond)
-		}
 
-		// Read response after sending all packets for this chunk
-		level.Debug(logger).Log("msg", "Waiting for response", "chunk", ci+1)
-		response, err := d.ReadResponse()
-		if err != nil {
-			return fmt.Errorf("chunk %d: read response failed: %w", ci+1, err)
+			stats.Retries++
+			level.Warn(logger).Log("msg", "Chunk response timed out, retrying", "chunk", ci+1, "retry", retry+1, "max_retries", cfg.MaxRetries)
 		}
-		level.Debug(logger).Log("msg", "Response received", "response", fmt.Sprintf("%v", response), "hex", fmt.Sprintf("%X", response))
+		level.Debug(logger).Log("msg", "Response received", "response", fmt.Sprintf("%v", response), "hex", fmt.Sprintf("%X", response), "chunk", ci+1, "elapsed", time.Since(chunkStart))
+		stats.Bytes += len(chunk)
+		stats.Chunks++
 
 		// Expected responses:
 		// [5 0 1 0 1] = chunk OK, continue
@@ -129,3 +218,50 @@ func SendGIF(d DeviceConnection, gifData []byte, logger log.Logger) error {
 
 	return nil
 }
+
+// gifSummary describes a GIF in log lines by its frame count and duration. The
+// GIF is only decoded when the line is written, so uploads don't pay for it
+// unless debug logging is enabled.
+type gifSummary []byte
+
+// String returns e.g. "12 frames, 1200ms".
+func (g gifSummary) String() string {
+	metadata, err := graphic.GetGIFMetadata(g)
+	if err != nil {
+		return "invalid GIF"
+	}
+	return fmt.Sprintf("%d frames, %dms", metadata.Frames, metadata.DurationMs)
+}
+
+// writeGIFPackets sends the BLE packets of a GIF chunk, waiting cfg.PacketDelay
+// after each packet to let the device process it. It stops once ctx is done.
+func writeGIFPackets(ctx context.Context, d DeviceConnection, blePackets [][]byte, cfg UploadConfig, logger log.Logger) error {
+	for pi, pkt := range blePackets {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("upload aborted before BLE packet %d: %w", pi+1, err)
+		}
+		level.Debug(logger).Log("msg", "Sending BLE packet", "packet", pi+1, "total", len(blePackets), "bytes", len(pkt))
+		if err := d.WritePacket(pkt); err != nil {
+			return fmt.Errorf("failed to send BLE packet %d: %w", pi+1, err)
+		}
+		if err := sleepContext(ctx, cfg.PacketDelay); err != nil {
+			return fmt.Errorf("upload aborted after BLE packet %d: %w", pi+1, err)
+		}
+	}
+	return nil
+}
+
+// sleepContext waits for d, returning the context error early once ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
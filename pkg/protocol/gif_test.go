@@ -1,13 +1,22 @@
 package protocol
 
 import (
+	"bytes"
+	"context"
 	"encoding/binary"
+	"errors"
 	"hash/crc32"
+	"image/gif"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
 )
 
 func TestSendGIF(t *testing.T) {
@@ -24,7 +33,7 @@ func TestSendGIF(t *testing.T) {
 			gifData[i] = byte(i % 256)
 		}
 
-		err := SendGIF(mock, gifData, log.NewNopLogger())
+		err := SendGIF(mock, gifData, DefaultGIFUploadConfig(), log.NewNopLogger())
 		require.NoError(t, err)
 		assert.True(t, mock.DrainCalled, "DrainResponses should be called")
 		require.NotEmpty(t, mock.WrittenPackets, "expected BLE packets to be sent")
@@ -53,7 +62,7 @@ func TestSendGIF(t *testing.T) {
 			gifData[i] = byte(i % 256)
 		}
 
-		err := SendGIF(mock, gifData, log.NewNopLogger())
+		err := SendGIF(mock, gifData, DefaultGIFUploadConfig(), log.NewNopLogger())
 		require.NoError(t, err)
 
 		// Count chunks by looking for packet sequence indicators
@@ -83,7 +92,7 @@ func TestSendGIF(t *testing.T) {
 			gifData[i] = byte(i % 256)
 		}
 
-		err := SendGIF(mock, gifData, log.NewNopLogger())
+		err := SendGIF(mock, gifData, DefaultGIFUploadConfig(), log.NewNopLogger())
 		require.NoError(t, err)
 
 		// Should only have packets for first chunk since device said complete
@@ -94,4 +103,222 @@ func TestSendGIF(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("play once uploads the GIF without looping", func(t *testing.T) {
+		img, err := graphic.GenerateColorCycle([]graphic.Color{graphic.Red, graphic.Blue}, 2)
+		require.NoError(t, err)
+		gifData, err := img.GIFBytes()
+		require.NoError(t, err)
+
+		once, err := playOnce(gifData)
+		require.NoError(t, err)
+		g, err := gif.DecodeAll(bytes.NewReader(once))
+		require.NoError(t, err)
+		assert.Equal(t, -1, g.LoopCount)
+		assert.Equal(t, img.GIFData.Delay, g.Delay)
+
+		// Same gif type as looping uploads, the loop count is in the GIF
+		onceMock := &DeviceConnectionMock{}
+		onceMock.AddResponse(responseComplete)
+		require.NoError(t, SendGIFOnce(onceMock, gifData, DefaultGIFUploadConfig(), log.NewNopLogger()))
+		require.NotEmpty(t, onceMock.WrittenPackets)
+		assert.Equal(t, byte(gifTypeNoTimeSignature), onceMock.WrittenPackets[0][15])
+		assert.Equal(t, uint32(len(once)), binary.LittleEndian.Uint32(onceMock.WrittenPackets[0][5:9]))
+
+		assert.Error(t, SendGIFOnce(&DeviceConnectionMock{}, []byte("not a GIF"), DefaultGIFUploadConfig(), log.NewNopLogger()))
+	})
+	t.Run("configured delays send the same packets", func(t *testing.T) {
+		gifData := make([]byte, 5000)
+		for i := range gifData {
+			gifData[i] = byte(i % 256)
+		}
+
+		defaultMock := &DeviceConnectionMock{}
+		defaultMock.AddResponse(responseOKContinue)
+		defaultMock.AddResponse(responseComplete)
+		require.NoError(t, SendGIF(defaultMock, gifData, DefaultGIFUploadConfig(), log.NewNopLogger()))
+
+		configuredMock := &DeviceConnectionMock{}
+		configuredMock.AddResponse(responseOKContinue)
+		configuredMock.AddResponse(responseComplete)
+		cfg := UploadConfig{PacketDelay: time.Millisecond, StabilizeDelay: 0}
+		require.NoError(t, SendGIF(configuredMock, gifData, cfg, log.NewNopLogger()))
+
+		assert.Equal(t, defaultMock.WrittenPackets, configuredMock.WrittenPackets)
+	})
+	t.Run("chunk is re-sent after a response timeout", func(t *testing.T) {
+		gifData := make([]byte, 100)
+		cfg := UploadConfig{MaxRetries: 2}
+
+		mock := &DeviceConnectionMock{}
+		mock.AddReadError(ErrResponseTimeout)
+		mock.AddResponse(responseComplete)
+		require.NoError(t, SendGIF(mock, gifData, cfg, log.NewNopLogger()))
+
+		// The single BLE packet of the chunk is sent twice
+		require.Len(t, mock.WrittenPackets, 2)
+		assert.Equal(t, mock.WrittenPackets[0], mock.WrittenPackets[1])
+	})
+
+	t.Run("late response acknowledges the chunk without re-sending it", func(t *testing.T) {
+		gifData := make([]byte, 5000)
+		var stats UploadStats
+		cfg := UploadConfig{MaxRetries: 2, Stats: &stats}
+
+		mock := &DeviceConnectionMock{}
+		mock.AddReadError(ErrResponseTimeout)
+		mock.AddLateResponse(responseOKContinue)
+		mock.AddResponse(responseComplete)
+		require.NoError(t, SendGIF(mock, gifData, cfg, log.NewNopLogger()))
+
+		// Each chunk is sent once: 4112 bytes in 9 packets, then 920 bytes in 2
+		assert.Len(t, mock.WrittenPackets, 11)
+		assert.Zero(t, stats.Retries)
+		assert.Equal(t, 2, stats.Chunks)
+	})
+
+	t.Run("upload fails after max retries", func(t *testing.T) {
+		gifData := make([]byte, 100)
+		cfg := UploadConfig{MaxRetries: 2}
+
+		mock := &DeviceConnectionMock{}
+		for i := 0; i < 3; i++ {
+			mock.AddReadError(ErrResponseTimeout)
+		}
+		mock.AddResponse(responseComplete)
+		err := SendGIF(mock, gifData, cfg, log.NewNopLogger())
+		require.ErrorIs(t, err, ErrResponseTimeout)
+		assert.Len(t, mock.WrittenPackets, 3)
+	})
+
+	t.Run("other read errors are not retried", func(t *testing.T) {
+		gifData := make([]byte, 100)
+		cfg := UploadConfig{MaxRetries: 2}
+
+		mock := &DeviceConnectionMock{}
+		mock.AddReadError(errors.New("notifications not enabled"))
+		mock.AddResponse(responseComplete)
+		require.Error(t, SendGIF(mock, gifData, cfg, log.NewNopLogger()))
+		assert.Len(t, mock.WrittenPackets, 1)
+	})
+}
+
+func TestSendGIFLogsSummaryOnlyAtDebug(t *testing.T) {
+	img, err := graphic.GenerateColorCycle([]graphic.Color{graphic.Red, graphic.Blue}, 2)
+	require.NoError(t, err)
+	gifData, err := img.GIFBytes()
+	require.NoError(t, err)
+
+	for _, debug := range []bool{false, true} {
+		var buf bytes.Buffer
+		allow := level.AllowInfo()
+		if debug {
+			allow = level.AllowDebug()
+		}
+		logger := level.NewFilter(log.NewLogfmtLogger(&buf), allow)
+
+		mock := &DeviceConnectionMock{}
+		mock.AddResponse([]byte{5, 0, 1, 0, 3})
+		require.NoError(t, SendGIF(mock, gifData, UploadConfig{}, logger))
+		assert.Equal(t, debug, strings.Contains(buf.String(), `gif="4 frames, 200ms"`), buf.String())
+	}
+
+	assert.Equal(t, "invalid GIF", gifSummary("not a gif").String())
+}
+
+func TestSendGIFStats(t *testing.T) {
+	responseOKContinue := []byte{5, 0, 1, 0, 1}
+	responseComplete := []byte{5, 0, 1, 0, 3}
+
+	t.Run("reports the uploaded bytes, chunks and packets", func(t *testing.T) {
+		var stats UploadStats
+		cfg := UploadConfig{Stats: &stats}
+
+		mock := &DeviceConnectionMock{}
+		mock.AddResponse(responseOKContinue)
+		mock.AddResponse(responseComplete)
+		require.NoError(t, SendGIF(mock, make([]byte, 5000), cfg, log.NewNopLogger()))
+
+		assert.Equal(t, 5000, stats.Bytes)
+		assert.Equal(t, 2, stats.Chunks)
+		assert.Equal(t, len(mock.WrittenPackets), stats.Packets)
+		assert.Zero(t, stats.Retries)
+		assert.Positive(t, stats.Duration)
+	})
+
+	t.Run("counts re-sent chunks once", func(t *testing.T) {
+		var stats UploadStats
+		cfg := UploadConfig{MaxRetries: 2, Stats: &stats}
+
+		mock := &DeviceConnectionMock{}
+		mock.AddReadError(ErrResponseTimeout)
+		mock.AddResponse(responseComplete)
+		require.NoError(t, SendGIF(mock, make([]byte, 100), cfg, log.NewNopLogger()))
+
+		assert.Equal(t, 100, stats.Bytes)
+		assert.Equal(t, 1, stats.Retries)
+		assert.Equal(t, 2, stats.Packets)
+	})
+}
+
+func TestUploadStats(t *testing.T) {
+	stats := UploadStats{Bytes: 12288, Duration: 3 * time.Second}
+	assert.Equal(t, 4096.0, stats.Throughput())
+	assert.Equal(t, "uploaded 12.0KB in 3.0s (4.0KB/s)", stats.String())
+	assert.Zero(t, UploadStats{Bytes: 100}.Throughput())
+}
+
+// cancelOnReadMock cancels a context when the device responds to a chunk
+type cancelOnReadMock struct {
+	*DeviceConnectionMock
+	cancel context.CancelFunc
+}
+
+func (m *cancelOnReadMock) ReadResponse() ([]byte, error) {
+	m.cancel()
+	return m.DeviceConnectionMock.ReadResponse()
+}
+
+func TestSendGIFContext(t *testing.T) {
+	responseOKContinue := []byte{5, 0, 1, 0, 1}
+	responseComplete := []byte{5, 0, 1, 0, 3}
+	gifData := make([]byte, 3*gifChunkSize) // 3 chunks
+
+	t.Run("cancelled context sends nothing", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		mock := &DeviceConnectionMock{}
+		err := SendGIFContext(ctx, mock, gifData, UploadConfig{}, log.NewNopLogger())
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, mock.WrittenPackets)
+	})
+
+	t.Run("cancelling aborts before the remaining chunks", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mock := &cancelOnReadMock{DeviceConnectionMock: &DeviceConnectionMock{}, cancel: cancel}
+		for i := 0; i < 2; i++ {
+			mock.AddResponse(responseOKContinue)
+		}
+		mock.AddResponse(responseComplete)
+
+		err := SendGIFContext(ctx, mock, gifData, UploadConfig{}, log.NewNopLogger())
+		require.ErrorIs(t, err, context.Canceled)
+
+		// Only the packets of the first chunk were sent
+		firstChunkPackets := len(chunkBuffer(make([]byte, gifHeaderSize+gifChunkSize), gifBLEPacketSize))
+		assert.Len(t, mock.WrittenPackets, firstChunkPackets)
+	})
+
+	t.Run("cancelling interrupts the packet delay", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		err := SendGIFContext(ctx, &DeviceConnectionMock{}, gifData, UploadConfig{PacketDelay: time.Minute}, log.NewNopLogger())
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), 10*time.Second)
+	})
 }
@@ -1,6 +1,7 @@
 package protocol
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
@@ -61,3 +62,110 @@ func SetPixels(d DeviceConnection, color graphic.Color, points []graphic.Point)
 
 	return d.WritePacket(payload)
 }
+
+// RectPoints returns the points of the w x h rectangle with its top-left
+// corner at (x, y), row by row. Points outside the display are skipped.
+func RectPoints(x, y, w, h int) []graphic.Point {
+	var points []graphic.Point
+	for py := max(y, 0); py < min(y+h, graphic.DisplayHeight); py++ {
+		for px := max(x, 0); px < min(x+w, graphic.DisplayWidth); px++ {
+			points = append(points, graphic.Point{X: px, Y: py})
+		}
+	}
+	return points
+}
+
+// FillRect fills the w x h rectangle with its top-left corner at (x, y) with color.
+// Points are sent in MaxPixelsPerPacket-sized SetPixels packets, waiting
+// PacketDelay between consecutive packets.
+func FillRect(d DeviceConnection, color graphic.Color, x, y, w, h int) error {
+	groups := map[graphic.Color][]graphic.Point{color: RectPoints(x, y, w, h)}
+	return SendPixelGroups(d, []graphic.Color{color}, groups, UploadConfig{PacketDelay: PacketDelay}, time.Sleep)
+}
+
+// MaxDrawPixels is the maximum number of pixels accepted by DrawPixels (one full frame)
+const MaxDrawPixels = graphic.DisplayWidth * graphic.DisplayHeight
+
+// Pixel is a single colored pixel.
+type Pixel struct {
+	X int   `json:"x"`
+	Y int   `json:"y"`
+	R uint8 `json:"r"`
+	G uint8 `json:"g"`
+	B uint8 `json:"b"`
+}
+
+// ValidatePixels checks that there are at most MaxDrawPixels pixels and that
+// all of them are within the display.
+func ValidatePixels(pixels []Pixel) error {
+	if len(pixels) > MaxDrawPixels {
+		return fmt.Errorf("too many pixels: %d (max %d)", len(pixels), MaxDrawPixels)
+	}
+	for i, p := range pixels {
+		if p.X < 0 || p.X >= graphic.DisplayWidth || p.Y < 0 || p.Y >= graphic.DisplayHeight {
+			return fmt.Errorf("pixel %d out of bounds: (%d, %d) (must be 0-%d)", i, p.X, p.Y, graphic.DisplayWidth-1)
+		}
+	}
+	return nil
+}
+
+// GroupPixelsByColor groups the pixel coordinates by color. Colors are returned
+// in the order they first appear, so the packets sent are deterministic.
+func GroupPixelsByColor(pixels []Pixel) ([]graphic.Color, map[graphic.Color][]graphic.Point) {
+	var colors []graphic.Color
+	groups := make(map[graphic.Color][]graphic.Point)
+	for _, p := range pixels {
+		color := graphic.Color{p.R, p.G, p.B}
+		if _, ok := groups[color]; !ok {
+			colors = append(colors, color)
+		}
+		groups[color] = append(groups[color], graphic.Point{X: p.X, Y: p.Y})
+	}
+	return colors, groups
+}
+
+// DrawPixels validates the pixels and draws them, grouped by color, in
+// MaxPixelsPerPacket-sized SetPixels packets, waiting cfg.PacketDelay between
+// consecutive packets.
+func DrawPixels(d DeviceConnection, pixels []Pixel, cfg UploadConfig) error {
+	if err := ValidatePixels(pixels); err != nil {
+		return err
+	}
+
+	colors, groups := GroupPixelsByColor(pixels)
+	return SendPixelGroups(d, colors, groups, cfg, time.Sleep)
+}
+
+// SendPixelGroups sends the points of each color, in the order of colors, in
+// MaxPixelsPerPacket-sized SetPixels packets, calling sleep with
+// cfg.PacketDelay between consecutive packets.
+func SendPixelGroups(d DeviceConnection, colors []graphic.Color, groups map[graphic.Color][]graphic.Point, cfg UploadConfig, sleep func(time.Duration)) error {
+	sent := 0
+	for _, color := range colors {
+		points := groups[color]
+		for i := 0; i < len(points); i += MaxPixelsPerPacket {
+			if sent > 0 {
+				sleep(cfg.PacketDelay)
+			}
+			end := min(i+MaxPixelsPerPacket, len(points))
+			if err := SetPixels(d, color, points[i:end]); err != nil {
+				return err
+			}
+			sent++
+		}
+	}
+
+	return nil
+}
+
+// FlushDiff sends the pixels of curr that differ from prev with
+// SendPixelGroups, then copies curr into prev so the next flush only sends
+// what changed since this one. prev is left untouched if sending fails.
+func FlushDiff(d DeviceConnection, prev, curr []byte, cfg UploadConfig, sleep func(time.Duration)) error {
+	colors, groups := graphic.DiffBuffers(prev, curr)
+	if err := SendPixelGroups(d, colors, groups, cfg, sleep); err != nil {
+		return err
+	}
+	copy(prev, curr)
+	return nil
+}
@@ -1,7 +1,9 @@
 package protocol
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -119,3 +121,141 @@ func TestSetPixelsTruncatesAt255(t *testing.T) {
 	// Packet should have 8 header bytes + 255*2 coordinate bytes = 518 bytes
 	assert.Len(t, packet, 518)
 }
+
+func TestRectPoints(t *testing.T) {
+	tests := []struct {
+		name     string
+		x, y     int
+		w, h     int
+		expected []graphic.Point
+	}{
+		{
+			name:     "2x2 rect enumerated row by row",
+			x:        10, y: 20,
+			w:        2, h: 2,
+			expected: []graphic.Point{{X: 10, Y: 20}, {X: 11, Y: 20}, {X: 10, Y: 21}, {X: 11, Y: 21}},
+		},
+		{
+			name:     "rect clipped to the display",
+			x:        62, y: -1,
+			w:        4, h: 2,
+			expected: []graphic.Point{{X: 62, Y: 0}, {X: 63, Y: 0}},
+		},
+		{
+			name:     "empty rect",
+			x:        5, y: 5,
+			w:        0, h: 3,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, RectPoints(tt.x, tt.y, tt.w, tt.h))
+		})
+	}
+}
+
+func TestFillRect(t *testing.T) {
+	// 20x20 = 400 points, split into 255 + 145
+	mock := &DeviceConnectionMock{}
+	err := FillRect(mock, graphic.Color{0, 255, 0}, 5, 5, 20, 20)
+	require.NoError(t, err)
+	require.Len(t, mock.WrittenPackets, 2)
+
+	assert.Len(t, mock.WrittenPackets[0], 8+2*255)
+	assert.Len(t, mock.WrittenPackets[1], 8+2*145)
+
+	// First point is the top-left corner, last point is the bottom-right corner
+	assert.Equal(t, []byte{5, 5}, mock.WrittenPackets[0][8:10])
+	last := mock.WrittenPackets[1]
+	assert.Equal(t, []byte{24, 24}, last[len(last)-2:])
+}
+
+func TestDrawPixels(t *testing.T) {
+	red := Pixel{R: 255}
+	green := Pixel{G: 255}
+
+	// 300 red pixels (255 + 45) and 2 green pixels, interleaved
+	var pixels []Pixel
+	for i := 0; i < 300; i++ {
+		p := red
+		p.X, p.Y = i%64, i/64
+		pixels = append(pixels, p)
+		if i == 10 || i == 20 {
+			p := green
+			p.X, p.Y = i, 63
+			pixels = append(pixels, p)
+		}
+	}
+
+	mock := &DeviceConnectionMock{}
+	require.NoError(t, DrawPixels(mock, pixels, DefaultPixelUploadConfig()))
+	require.Len(t, mock.WrittenPackets, 3)
+
+	// Red first (first seen), split into two packets, then green
+	assert.Len(t, mock.WrittenPackets[0], 8+2*255)
+	assert.Equal(t, []byte{255, 0, 0}, mock.WrittenPackets[0][5:8])
+	assert.Len(t, mock.WrittenPackets[1], 8+2*45)
+	assert.Equal(t, []byte{255, 0, 0}, mock.WrittenPackets[1][5:8])
+	assert.Equal(t, []byte{0x0C, 0x00, 0x05, 0x01, 0x00, 0, 255, 0, 10, 63, 20, 63}, mock.WrittenPackets[2])
+}
+
+func TestFlushDiff(t *testing.T) {
+	prev := graphic.NewBuffer()
+	curr := graphic.NewBuffer()
+	// 300 red pixels (255 + 45) and 1 blue pixel
+	for i := 0; i < 300; i++ {
+		graphic.SetPixel(curr, i%64, 1+i/64, graphic.Red)
+	}
+	graphic.SetPixel(curr, 0, 0, graphic.Blue)
+
+	var sleeps []time.Duration
+	sleep := func(d time.Duration) { sleeps = append(sleeps, d) }
+	cfg := UploadConfig{PacketDelay: 7 * time.Millisecond}
+
+	t.Run("failed flush keeps the previous buffer", func(t *testing.T) {
+		mock := &DeviceConnectionMock{WritePacketErr: errors.New("disconnected")}
+		require.Error(t, FlushDiff(mock, prev, curr, cfg, sleep))
+		assert.Equal(t, graphic.NewBuffer(), prev)
+	})
+
+	t.Run("sends the changed pixels grouped by color", func(t *testing.T) {
+		mock := &DeviceConnectionMock{}
+		require.NoError(t, FlushDiff(mock, prev, curr, cfg, sleep))
+		require.Len(t, mock.WrittenPackets, 3)
+
+		// Blue first (first seen), then red split into two packets
+		assert.Equal(t, []byte{0, 0, 255, 0, 0}, mock.WrittenPackets[0][5:10])
+		assert.Len(t, mock.WrittenPackets[1], 8+2*255)
+		assert.Len(t, mock.WrittenPackets[2], 8+2*45)
+		assert.Equal(t, []time.Duration{cfg.PacketDelay, cfg.PacketDelay}, sleeps)
+		assert.Equal(t, curr, prev)
+	})
+
+	t.Run("unchanged buffers send nothing", func(t *testing.T) {
+		mock := &DeviceConnectionMock{}
+		require.NoError(t, FlushDiff(mock, prev, curr, cfg, sleep))
+		assert.Empty(t, mock.WrittenPackets)
+	})
+}
+
+func TestDrawPixelsValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		pixels []Pixel
+	}{
+		{name: "negative x", pixels: []Pixel{{X: -1, Y: 0}}},
+		{name: "x too large", pixels: []Pixel{{X: 64, Y: 0}}},
+		{name: "y too large", pixels: []Pixel{{X: 0, Y: 64}}},
+		{name: "too many pixels", pixels: make([]Pixel, MaxDrawPixels+1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &DeviceConnectionMock{}
+			assert.Error(t, DrawPixels(mock, tt.pixels, DefaultPixelUploadConfig()))
+			assert.Empty(t, mock.WrittenPackets)
+		})
+	}
+}
@@ -2,7 +2,10 @@ package protocol
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
 )
 
 // SetDrawMode sends set draw mode to display.
@@ -13,8 +16,19 @@ func SetDrawMode(d DeviceConnection, mode int) error {
 // SendImage sends an image to the display. Only makes sense after a call to SetDrawMode(1).
 // imageData should be raw RGB data (3 bytes per pixel).
 func SendImage(d DeviceConnection, imageData []byte) error {
+	return SendImageWithStats(d, imageData, nil)
+}
+
+// SendImageWithStats is like SendImage, filling in stats, if not nil, with the
+// bytes (chunk headers included), image chunks and packets written and the
+// time spent, including a failed upload up to the failure.
+func SendImageWithStats(d DeviceConnection, imageData []byte, stats *UploadStats) error {
 	const headerSize = 9
 
+	if stats != nil {
+		*stats = UploadStats{}
+	}
+
 	// Split image data into 4096-byte chunks
 	chunks := chunkBuffer(imageData, 4096)
 
@@ -38,14 +52,40 @@ func SendImage(d DeviceConnection, imageData []byte) error {
 		// Chunk data
 		binary.Write(packet, binary.LittleEndian, ch)
 
-		if err := WriteData(d, packet.Bytes()); err != nil {
+		if err := WriteDataWithStats(context.Background(), d, packet.Bytes(), stats); err != nil {
 			return err
 		}
+		if stats != nil {
+			stats.Chunks++
+		}
 	}
 
 	return nil
 }
 
+// SendFrame switches the display to draw mode and sends a full 64x64 raw RGB
+// frame. Returns an error without writing anything if the frame size is wrong.
+func SendFrame(d DeviceConnection, frame []byte) error {
+	if err := graphic.ValidateFrame(frame); err != nil {
+		return err
+	}
+	if err := SetDrawMode(d, 1); err != nil {
+		return err
+	}
+	return SendImage(d, frame)
+}
+
+// ClearScreen blanks the display to black while keeping it on, by sending an
+// all-black frame.
+func ClearScreen(d DeviceConnection) error {
+	return SendFrame(d, graphic.NewBuffer())
+}
+
+// FillScreen sets the whole display to a single color.
+func FillScreen(d DeviceConnection, c graphic.Color) error {
+	return SendFrame(d, graphic.NewBufferWithColor(c))
+}
+
 // chunkBuffer chunks the supplied data buffer to chunkSize slices.
 func chunkBuffer(data []byte, chunkSize int) [][]byte {
 	chunks := make([][]byte, 0)
@@ -2,11 +2,15 @@ package protocol
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
 )
 
 func TestSetDrawMode(t *testing.T) {
@@ -97,3 +101,102 @@ func TestSendImage(t *testing.T) {
 		assert.Equal(t, uint32(5000), totalLen2)
 	})
 }
+
+func TestSendFrame(t *testing.T) {
+	t.Run("sends draw mode and the frame as is", func(t *testing.T) {
+		frame := make([]byte, graphic.BufferSize)
+		for i := range frame {
+			frame[i] = byte(i % 251)
+		}
+
+		mock := &DeviceConnectionMock{}
+		require.NoError(t, SendFrame(mock, frame))
+
+		expected := &DeviceConnectionMock{}
+		require.NoError(t, SetDrawMode(expected, 1))
+		require.NoError(t, SendImage(expected, frame))
+		assert.Equal(t, expected.WrittenPackets, mock.WrittenPackets)
+	})
+
+	t.Run("rejects a frame of the wrong size", func(t *testing.T) {
+		for _, size := range []int{0, graphic.BufferSize - 1, graphic.BufferSize + 1} {
+			mock := &DeviceConnectionMock{}
+			assert.Error(t, SendFrame(mock, make([]byte, size)))
+			assert.Empty(t, mock.WrittenPackets)
+		}
+	})
+}
+
+// sentFrame returns the frame sent with SendFrame, checking the draw mode is
+// set first and stripping the 9-byte header of each image packet
+func sentFrame(t *testing.T, mock *DeviceConnectionMock) []byte {
+	t.Helper()
+	require.NotEmpty(t, mock.WrittenPackets)
+	assert.Equal(t, []byte{5, 0, 4, 1, 1}, mock.WrittenPackets[0])
+
+	stream := bytes.Join(mock.WrittenPackets[1:], nil)
+	var sent []byte
+	for len(stream) > 0 {
+		packetLen := int(binary.LittleEndian.Uint16(stream[0:2]))
+		sent = append(sent, stream[9:packetLen]...)
+		stream = stream[packetLen:]
+	}
+	return sent
+}
+
+func TestClearScreen(t *testing.T) {
+	mock := &DeviceConnectionMock{}
+	require.NoError(t, ClearScreen(mock))
+	assert.Equal(t, make([]byte, graphic.BufferSize), sentFrame(t, mock))
+}
+
+func TestFillScreen(t *testing.T) {
+	mock := &DeviceConnectionMock{}
+	require.NoError(t, FillScreen(mock, graphic.Red))
+
+	sent := sentFrame(t, mock)
+	require.Len(t, sent, graphic.BufferSize)
+	assert.Equal(t, []byte{255, 0, 0}, sent[:3])
+	assert.Equal(t, graphic.NewBufferWithColor(graphic.Red), sent)
+}
+
+func TestWriteDataWithStats(t *testing.T) {
+	mock := &DeviceConnectionMock{}
+	var stats UploadStats
+
+	// 1200 bytes are written in 3 packets of up to 514 bytes, stats add up
+	require.NoError(t, WriteDataWithStats(context.Background(), mock, make([]byte, 1200), &stats))
+	require.NoError(t, WriteDataWithStats(context.Background(), mock, make([]byte, 5), &stats))
+	assert.Equal(t, 1205, stats.Bytes)
+	assert.Equal(t, 4, stats.Packets)
+	assert.Positive(t, stats.Duration)
+
+	// Writing without stats still works
+	require.NoError(t, WriteDataWithStats(context.Background(), mock, []byte{1}, nil))
+	assert.Len(t, mock.WrittenPackets, 5)
+}
+
+func TestSendImageWithStats(t *testing.T) {
+	t.Run("full frame", func(t *testing.T) {
+		mock := &DeviceConnectionMock{}
+		stats := UploadStats{Retries: 3} // Overwritten
+
+		// 12288 bytes in 3 chunks of 4096 bytes plus a 9 byte header, each
+		// written in 8 packets of up to 514 bytes
+		require.NoError(t, SendImageWithStats(mock, graphic.NewBuffer(), &stats))
+		assert.Equal(t, 12288+3*9, stats.Bytes)
+		assert.Equal(t, 3, stats.Chunks)
+		assert.Equal(t, 3*8, stats.Packets)
+		assert.Equal(t, len(mock.WrittenPackets), stats.Packets)
+		assert.Zero(t, stats.Retries)
+		assert.Positive(t, stats.Duration)
+	})
+
+	t.Run("failed upload", func(t *testing.T) {
+		mock := &DeviceConnectionMock{WritePacketErr: errors.New("disconnected")}
+		var stats UploadStats
+		require.Error(t, SendImageWithStats(mock, graphic.NewBuffer(), &stats))
+		assert.Zero(t, stats.Bytes)
+		assert.Zero(t, stats.Chunks)
+	})
+}
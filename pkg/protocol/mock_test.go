@@ -11,10 +11,12 @@ type DeviceConnectionMock struct {
 	// Mock responses for ReadResponse()
 	Responses     [][]byte
 	responseIndex int
+	lateResponses [][]byte // Returned by PollResponse, after a ReadResponse timeout
 
 	// Error injection
 	WritePacketErr error
 	ReadErr        error
+	readErrs       []error // Returned, one per call, before any response
 }
 
 func (m *DeviceConnectionMock) WritePacket(packet []byte) error {
@@ -32,6 +34,11 @@ func (m *DeviceConnectionMock) ReadResponse() ([]byte, error) {
 	if m.ReadErr != nil {
 		return nil, m.ReadErr
 	}
+	if len(m.readErrs) > 0 {
+		err := m.readErrs[0]
+		m.readErrs = m.readErrs[1:]
+		return nil, err
+	}
 	if m.responseIndex >= len(m.Responses) {
 		return nil, fmt.Errorf("no more mock responses available")
 	}
@@ -40,6 +47,15 @@ func (m *DeviceConnectionMock) ReadResponse() ([]byte, error) {
 	return response, nil
 }
 
+func (m *DeviceConnectionMock) PollResponse() ([]byte, bool) {
+	if len(m.lateResponses) == 0 {
+		return nil, false
+	}
+	response := m.lateResponses[0]
+	m.lateResponses = m.lateResponses[1:]
+	return response, true
+}
+
 func (m *DeviceConnectionMock) DrainResponses() {
 	m.DrainCalled = true
 }
@@ -48,3 +64,14 @@ func (m *DeviceConnectionMock) DrainResponses() {
 func (m *DeviceConnectionMock) AddResponse(response []byte) {
 	m.Responses = append(m.Responses, response)
 }
+
+// AddReadError queues an error for ReadResponse to return before the queued responses.
+func (m *DeviceConnectionMock) AddReadError(err error) {
+	m.readErrs = append(m.readErrs, err)
+}
+
+// AddLateResponse queues a response for PollResponse to return, as if it
+// arrived after ReadResponse timed out.
+func (m *DeviceConnectionMock) AddLateResponse(response []byte) {
+	m.lateResponses = append(m.lateResponses, response)
+}
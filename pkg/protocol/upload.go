@@ -0,0 +1,74 @@
+package protocol
+
+import (
+	"fmt"
+	"time"
+)
+
+// Default GIF upload timings
+const (
+	gifPacketDelay    = 10 * time.Millisecond
+	gifStabilizeDelay = 100 * time.Millisecond
+	gifMaxRetries     = 2
+	gifRetryBackoff   = 250 * time.Millisecond
+)
+
+// UploadConfig holds the delays used while uploading data over BLE. Some
+// devices or firmwares need longer delays to receive data reliably, while
+// others tolerate shorter ones for faster uploads.
+type UploadConfig struct {
+	PacketDelay    time.Duration // Delay after each BLE packet
+	StabilizeDelay time.Duration // Delay before the upload starts
+
+	// MaxRetries is how many times a GIF chunk is re-sent when the device
+	// doesn't respond to it in time. A response arriving during the backoff
+	// before a retry counts as the chunk's response, and the chunk isn't
+	// re-sent.
+	MaxRetries   int
+	RetryBackoff time.Duration // Delay before the first retry, doubled on each retry
+
+	// Stats, if set, is filled in with the statistics of the GIF upload,
+	// including a failed one up to the failure.
+	Stats *UploadStats
+}
+
+// UploadStats describes a GIF upload, or an upload made with WriteData, to
+// help diagnose slow uploads.
+type UploadStats struct {
+	Bytes    int           // Bytes sent; for GIFs, not counting chunk headers and re-sent chunks
+	Chunks   int           // Chunks sent; for GIFs, acknowledged by the device
+	Packets  int           // BLE packets written, including re-sent ones
+	Retries  int           // Chunks re-sent because the device didn't respond in time
+	Duration time.Duration // Time from the start of the upload to the last response, or write without responses
+}
+
+// Throughput returns the effective upload speed in bytes per second.
+func (s UploadStats) Throughput() float64 {
+	if s.Duration <= 0 {
+		return 0
+	}
+	return float64(s.Bytes) / s.Duration.Seconds()
+}
+
+// String summarizes the upload, e.g. "uploaded 12.0KB in 3.2s (3.8KB/s)".
+func (s UploadStats) String() string {
+	return fmt.Sprintf("uploaded %.1fKB in %.1fs (%.1fKB/s)", float64(s.Bytes)/1024, s.Duration.Seconds(), s.Throughput()/1024)
+}
+
+// DefaultGIFUploadConfig returns the default timings for GIF uploads.
+func DefaultGIFUploadConfig() UploadConfig {
+	return UploadConfig{
+		PacketDelay:    gifPacketDelay,
+		StabilizeDelay: gifStabilizeDelay,
+		MaxRetries:     gifMaxRetries,
+		RetryBackoff:   gifRetryBackoff,
+	}
+}
+
+// DefaultPixelUploadConfig returns the default timings for real-time pixel
+// updates sent with SetPixels.
+func DefaultPixelUploadConfig() UploadConfig {
+	return UploadConfig{
+		PacketDelay: PacketDelay,
+	}
+}
@@ -0,0 +1,385 @@
+// Package qrcode encodes short strings as QR codes small enough to be legible
+// on the 64x64 display: byte mode, low error correction, versions 1 to 3 (21
+// to 29 modules), each module drawn as a 2x2 pixel square.
+//
+// The standard asks for a 4 module quiet zone around the code, which only
+// version 1 gets at this scale: versions 2 and 3 are left with 3 and 1
+// modules. 1 pixel modules would leave room for it but are hard for a phone to
+// pick up on the LEDs, so the modules are kept large and some scanners may
+// need a few tries on the longest data.
+package qrcode
+
+import (
+	"fmt"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+)
+
+// MaxDataLength is the most bytes fitting a version 3 code with low error
+// correction, the largest drawn with 2x2 pixel modules
+const MaxDataLength = 53
+
+// version holds the layout of a QR code version with low error correction.
+// Versions 1 to 3 have a single error correction block.
+type version struct {
+	number        int
+	dataCodewords int
+	ecCodewords   int
+	alignment     int // Center of the bottom right alignment pattern, 0 if none
+}
+
+var versions = []version{
+	{number: 1, dataCodewords: 19, ecCodewords: 7},
+	{number: 2, dataCodewords: 34, ecCodewords: 10, alignment: 18},
+	{number: 3, dataCodewords: 55, ecCodewords: 15, alignment: 22},
+}
+
+// Code is an encoded QR code.
+type Code struct {
+	Version  int
+	Size     int // Modules per side
+	Mask     int // Mask pattern applied to the data (0-7)
+	modules  [][]bool
+	function [][]bool // Modules of the finder, timing, alignment and format patterns
+}
+
+// Dark returns true if the module at x, y is dark. Modules outside the code
+// are light.
+func (c *Code) Dark(x, y int) bool {
+	if x < 0 || x >= c.Size || y < 0 || y >= c.Size {
+		return false
+	}
+	return c.modules[y][x]
+}
+
+// Encode encodes data in the smallest version fitting it, picking the mask
+// that makes the code easiest to scan. Returns an error if data is empty or
+// longer than MaxDataLength bytes.
+func Encode(data string) (*Code, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("no data to encode")
+	}
+	if len(data) > MaxDataLength {
+		return nil, fmt.Errorf("data too long: %d bytes (max %d to fit the display legibly)", len(data), MaxDataLength)
+	}
+
+	var v version
+	for _, v = range versions {
+		// 4 bits of mode and 8 bits of length precede the data
+		if 12+8*len(data) <= 8*v.dataCodewords {
+			break
+		}
+	}
+
+	codewords := dataCodewords([]byte(data), v.dataCodewords)
+	codewords = append(codewords, rsRemainder(codewords, rsDivisor(v.ecCodewords))...)
+
+	var best *Code
+	bestPenalty := 0
+	for mask := 0; mask < 8; mask++ {
+		c := newCode(v)
+		c.drawCodewords(codewords)
+		c.applyMask(mask)
+		c.drawFormatBits(mask)
+		if penalty := c.penalty(); best == nil || penalty < bestPenalty {
+			best, bestPenalty = c, penalty
+		}
+	}
+	return best, nil
+}
+
+// dataCodewords returns the byte mode bit stream of data, padded to capacity
+// codewords.
+func dataCodewords(data []byte, capacity int) []byte {
+	var bits []bool
+	appendBits := func(value, n int) {
+		for i := n - 1; i >= 0; i-- {
+			bits = append(bits, (value>>i)&1 == 1)
+		}
+	}
+
+	appendBits(0x4, 4) // Byte mode
+	appendBits(len(data), 8)
+	for _, b := range data {
+		appendBits(int(b), 8)
+	}
+
+	// Terminator, then zeros up to a byte boundary
+	appendBits(0, min(4, 8*capacity-len(bits)))
+	appendBits(0, (8-len(bits)%8)%8)
+
+	codewords := make([]byte, 0, capacity)
+	for i := 0; i < len(bits); i += 8 {
+		var b byte
+		for j := 0; j < 8; j++ {
+			if bits[i+j] {
+				b |= 1 << (7 - j)
+			}
+		}
+		codewords = append(codewords, b)
+	}
+	for pad := byte(0xEC); len(codewords) < capacity; pad ^= 0xEC ^ 0x11 {
+		codewords = append(codewords, pad)
+	}
+	return codewords
+}
+
+// newCode returns a code of the given version with its function patterns
+// drawn and the format areas reserved.
+func newCode(v version) *Code {
+	size := 17 + 4*v.number
+	c := &Code{Version: v.number, Size: size}
+	c.modules = make([][]bool, size)
+	c.function = make([][]bool, size)
+	for y := range c.modules {
+		c.modules[y] = make([]bool, size)
+		c.function[y] = make([]bool, size)
+	}
+
+	// Timing patterns
+	for i := 0; i < size; i++ {
+		c.setFunction(6, i, i%2 == 0)
+		c.setFunction(i, 6, i%2 == 0)
+	}
+
+	// Finder patterns with their light separators
+	for _, corner := range [][2]int{{3, 3}, {size - 4, 3}, {3, size - 4}} {
+		for dy := -4; dy <= 4; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				x, y := corner[0]+dx, corner[1]+dy
+				if x < 0 || x >= size || y < 0 || y >= size {
+					continue
+				}
+				dist := max(abs(dx), abs(dy))
+				c.setFunction(x, y, dist != 2 && dist != 4)
+			}
+		}
+	}
+
+	if v.alignment > 0 {
+		for dy := -2; dy <= 2; dy++ {
+			for dx := -2; dx <= 2; dx++ {
+				c.setFunction(v.alignment+dx, v.alignment+dy, max(abs(dx), abs(dy)) != 1)
+			}
+		}
+	}
+
+	// Reserve the format areas, drawn once the mask is chosen
+	c.drawFormatBits(0)
+	return c
+}
+
+func (c *Code) setFunction(x, y int, dark bool) {
+	c.modules[y][x] = dark
+	c.function[y][x] = true
+}
+
+// drawCodewords places the codeword bits in the zigzag order of the standard:
+// two columns at a time from the right, alternating upwards and downwards,
+// skipping the function patterns.
+func (c *Code) drawCodewords(codewords []byte) {
+	i := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			// Skip the vertical timing pattern
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < c.Size; vert++ {
+			y := vert
+			if upward {
+				y = c.Size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if c.function[y][x] || i >= len(codewords)*8 {
+					continue
+				}
+				c.modules[y][x] = (codewords[i/8]>>(7-i%8))&1 == 1
+				i++
+			}
+		}
+	}
+}
+
+// applyMask flips the data modules selected by the mask pattern.
+func (c *Code) applyMask(mask int) {
+	c.Mask = mask
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if c.function[y][x] {
+				continue
+			}
+			var flip bool
+			switch mask {
+			case 0:
+				flip = (x+y)%2 == 0
+			case 1:
+				flip = y%2 == 0
+			case 2:
+				flip = x%3 == 0
+			case 3:
+				flip = (x+y)%3 == 0
+			case 4:
+				flip = (x/3+y/2)%2 == 0
+			case 5:
+				flip = x*y%2+x*y%3 == 0
+			case 6:
+				flip = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				flip = ((x+y)%2+x*y%3)%2 == 0
+			}
+			c.modules[y][x] = c.modules[y][x] != flip
+		}
+	}
+}
+
+// formatBits returns the 15 format bits for low error correction and the
+// given mask: 5 data bits, 10 BCH error correction bits, XORed with 0x5412.
+func formatBits(mask int) int {
+	data := 1<<3 | mask // Low error correction is 01
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+// drawFormatBits draws both copies of the format bits, and the dark module
+// next to the bottom left finder pattern.
+func (c *Code) drawFormatBits(mask int) {
+	bits := formatBits(mask)
+	bit := func(i int) bool { return (bits>>i)&1 == 1 }
+
+	// Around the top left finder pattern
+	for i := 0; i <= 5; i++ {
+		c.setFunction(8, i, bit(i))
+	}
+	c.setFunction(8, 7, bit(6))
+	c.setFunction(8, 8, bit(7))
+	c.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		c.setFunction(14-i, 8, bit(i))
+	}
+
+	// Split between the top right and bottom left finder patterns
+	for i := 0; i < 8; i++ {
+		c.setFunction(c.Size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		c.setFunction(8, c.Size-15+i, bit(i))
+	}
+	c.setFunction(8, c.Size-8, true)
+}
+
+// penalty scores how hard the code is to scan, following the four rules of
+// the standard: long runs, 2x2 blocks, finder-like patterns and dark/light
+// imbalance. Lower is better.
+func (c *Code) penalty() int {
+	penalty := 0
+	finderLike := []bool{true, false, true, true, true, false, true}
+
+	for _, horizontal := range []bool{true, false} {
+		at := func(i, j int) bool {
+			if horizontal {
+				return c.modules[i][j]
+			}
+			return c.modules[j][i]
+		}
+		for i := 0; i < c.Size; i++ {
+			run := 1
+			for j := 1; j <= c.Size; j++ {
+				if j < c.Size && at(i, j) == at(i, j-1) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					penalty += 3 + run - 5
+				}
+				run = 1
+			}
+
+			// 1:1:3:1:1 patterns with 4 light modules on either side
+			for j := 0; j+len(finderLike) <= c.Size; j++ {
+				match := true
+				for k, dark := range finderLike {
+					if at(i, j+k) != dark {
+						match = false
+						break
+					}
+				}
+				if match && (c.lightRun(at, i, j-4, j) || c.lightRun(at, i, j+7, j+11)) {
+					penalty += 40
+				}
+			}
+		}
+	}
+
+	dark := 0
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if c.modules[y][x] {
+				dark++
+			}
+			if x > 0 && y > 0 {
+				m := c.modules[y][x]
+				if m == c.modules[y-1][x] && m == c.modules[y][x-1] && m == c.modules[y-1][x-1] {
+					penalty += 3
+				}
+			}
+		}
+	}
+
+	percent := dark * 100 / (c.Size * c.Size)
+	penalty += 10 * (abs(percent-50) / 5)
+	return penalty
+}
+
+// lightRun returns true if modules from..to (exclusive) of line i are all
+// light, counting modules outside the code as light.
+func (c *Code) lightRun(at func(i, j int) bool, i, from, to int) bool {
+	for j := from; j < to; j++ {
+		if j >= 0 && j < c.Size && at(i, j) {
+			return false
+		}
+	}
+	return true
+}
+
+// Scale returns the pixels per module side drawing the code as large as
+// possible with at least a 1 module quiet zone on the display.
+func (c *Code) Scale() int {
+	return max(1, graphic.DisplayWidth/(c.Size+2))
+}
+
+// QuietZone returns the width in whole modules of the light margin left around
+// the code once drawn, 4 or more meeting the standard.
+func (c *Code) QuietZone() int {
+	scale := c.Scale()
+	return (graphic.DisplayWidth - c.Size*scale) / 2 / scale
+}
+
+// Draw fills buf with the light color and draws the dark modules scaled and
+// centered on it.
+func (c *Code) Draw(buf []byte, dark, light graphic.Color) {
+	copy(buf, graphic.NewBufferWithColor(light))
+
+	scale := c.Scale()
+	offset := (graphic.DisplayWidth - c.Size*scale) / 2
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if !c.modules[y][x] {
+				continue
+			}
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					graphic.SetPixel(buf, offset+x*scale+dx, offset+y*scale+dy, dark)
+				}
+			}
+		}
+	}
+}
+
+func abs(v int) int {
+	return max(v, -v)
+}
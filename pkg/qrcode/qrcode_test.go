@@ -0,0 +1,194 @@
+package qrcode
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+)
+
+func TestRSRemainder(t *testing.T) {
+	// "HELLO WORLD" encoded as version 1 with medium error correction
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	expected := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	assert.Equal(t, expected, rsRemainder(data, rsDivisor(10)))
+}
+
+func TestFormatBits(t *testing.T) {
+	expected := []string{
+		"111011111000100",
+		"111001011110011",
+		"111110110101010",
+		"111100010011101",
+		"110011000101111",
+		"110001100011000",
+		"110110001000001",
+		"110100101110110",
+	}
+	for mask, bits := range expected {
+		assert.Equal(t, bits, fmt.Sprintf("%015b", formatBits(mask)), "mask %d", mask)
+	}
+}
+
+func TestEncodeVersion(t *testing.T) {
+	tests := []struct {
+		length  int
+		version int
+	}{
+		{length: 1, version: 1},
+		{length: 17, version: 1},
+		{length: 18, version: 2},
+		{length: 32, version: 2},
+		{length: 33, version: 3},
+		{length: MaxDataLength, version: 3},
+	}
+
+	for _, tt := range tests {
+		c, err := Encode(strings.Repeat("a", tt.length))
+		require.NoError(t, err)
+		assert.Equal(t, tt.version, c.Version, "length %d", tt.length)
+		assert.Equal(t, 17+4*tt.version, c.Size, "length %d", tt.length)
+	}
+}
+
+func TestEncodeInvalidData(t *testing.T) {
+	_, err := Encode("")
+	assert.Error(t, err)
+	_, err = Encode(strings.Repeat("a", MaxDataLength+1))
+	assert.Error(t, err)
+}
+
+// assertFinder checks the 7x7 finder pattern with its top left module at x, y
+// and its light separator
+func assertFinder(t *testing.T, c *Code, x, y int) {
+	for dy := -1; dy <= 7; dy++ {
+		for dx := -1; dx <= 7; dx++ {
+			ring := max(abs(dx-3), abs(dy-3))
+			expected := ring != 2 && ring != 4
+			assert.Equal(t, expected, c.Dark(x+dx, y+dy), "finder at %d,%d module %d,%d", x, y, x+dx, y+dy)
+		}
+	}
+}
+
+func TestEncodeFinderPatterns(t *testing.T) {
+	c, err := Encode("HELLO")
+	require.NoError(t, err)
+	require.Equal(t, 21, c.Size)
+
+	assertFinder(t, c, 0, 0)
+	assertFinder(t, c, c.Size-7, 0)
+	assertFinder(t, c, 0, c.Size-7)
+
+	// The bottom right corner has no finder pattern, only data
+	finderLike := true
+	for d := 0; d < 7; d++ {
+		if !c.Dark(c.Size-1-d, c.Size-1) {
+			finderLike = false
+		}
+	}
+	assert.False(t, finderLike)
+
+	// Timing patterns alternate between the finder patterns
+	for i := 8; i < c.Size-8; i++ {
+		assert.Equal(t, i%2 == 0, c.Dark(i, 6), "horizontal timing at %d", i)
+		assert.Equal(t, i%2 == 0, c.Dark(6, i), "vertical timing at %d", i)
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	for _, data := range []string{"HELLO", "https://example.com/x", "WIFI:S:home;T:WPA;P:correct horse battery;;"} {
+		c, err := Encode(data)
+		require.NoError(t, err)
+
+		// Both copies of the format bits tell the mask
+		first, second := 0, 0
+		for i := 0; i <= 5; i++ {
+			first |= bit(c.Dark(8, i)) << i
+		}
+		first |= bit(c.Dark(8, 7))<<6 | bit(c.Dark(8, 8))<<7 | bit(c.Dark(7, 8))<<8
+		for i := 9; i < 15; i++ {
+			first |= bit(c.Dark(14-i, 8)) << i
+		}
+		for i := 0; i < 8; i++ {
+			second |= bit(c.Dark(c.Size-1-i, 8)) << i
+		}
+		for i := 8; i < 15; i++ {
+			second |= bit(c.Dark(8, c.Size-15+i)) << i
+		}
+		assert.Equal(t, formatBits(c.Mask), first, data)
+		assert.Equal(t, formatBits(c.Mask), second, data)
+
+		// Unmasking the data modules gives back the codewords
+		unmasked := newCode(versions[c.Version-1])
+		for y := 0; y < c.Size; y++ {
+			for x := 0; x < c.Size; x++ {
+				if !unmasked.function[y][x] {
+					unmasked.modules[y][x] = c.modules[y][x]
+				}
+			}
+		}
+		unmasked.applyMask(c.Mask)
+
+		v := versions[c.Version-1]
+		expected := dataCodewords([]byte(data), v.dataCodewords)
+		expected = append(expected, rsRemainder(expected, rsDivisor(v.ecCodewords))...)
+		reference := newCode(v)
+		reference.drawCodewords(expected)
+		assert.Equal(t, reference.modules, unmasked.modules, data)
+
+		// The data codewords hold the byte mode header and the data
+		assert.Equal(t, byte(0x40|len(data)>>4), expected[0])
+		assert.Equal(t, byte(len(data)<<4|int(data[0])>>4), expected[1])
+	}
+}
+
+func bit(dark bool) int {
+	if dark {
+		return 1
+	}
+	return 0
+}
+
+func TestDraw(t *testing.T) {
+	c, err := Encode("HELLO")
+	require.NoError(t, err)
+	require.Equal(t, 2, c.Scale())
+	require.Equal(t, 5, c.QuietZone())
+
+	buf := graphic.NewBuffer()
+	c.Draw(buf, graphic.Black, graphic.White)
+
+	// 21 modules of 2x2 pixels centered on the display
+	offset := (graphic.DisplayWidth - 2*c.Size) / 2
+	pixel := func(x, y int) graphic.Color {
+		i := (y*graphic.DisplayWidth + x) * 3
+		return graphic.Color{buf[i], buf[i+1], buf[i+2]}
+	}
+	assert.Equal(t, graphic.White, pixel(0, 0))
+	assert.Equal(t, graphic.White, pixel(offset-1, offset-1))
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			expected := graphic.White
+			if c.Dark(x, y) {
+				expected = graphic.Black
+			}
+			require.Equal(t, expected, pixel(offset+2*x, offset+2*y), "module %d,%d", x, y)
+			require.Equal(t, expected, pixel(offset+2*x+1, offset+2*y+1), "module %d,%d", x, y)
+		}
+	}
+
+	// The largest codes trade the quiet zone for 2x2 pixel modules
+	c, err = Encode(strings.Repeat("a", 20))
+	require.NoError(t, err)
+	assert.Equal(t, 2, c.Version)
+	assert.Equal(t, 3, c.QuietZone())
+
+	c, err = Encode(strings.Repeat("a", MaxDataLength))
+	require.NoError(t, err)
+	assert.Equal(t, 2, c.Scale())
+	assert.Equal(t, 1, c.QuietZone())
+}
@@ -0,0 +1,152 @@
+// Package schedule shows playlist items at given times of the day, e.g.
+// "every day at 09:00 show text GOOD MORNING".
+package schedule
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/atomicfile"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/playlist"
+)
+
+// maxCatchUp is the longest gap between two checks the scheduler catches up on
+const maxCatchUp = 24 * time.Hour
+
+// weekdays maps the day names accepted in entries to time.Weekday
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Entry shows an item at a time of the day. The item duration is ignored: the
+// content stays on the display until something else is shown.
+type Entry struct {
+	At   string        `json:"at"`             // Time of day as "HH:MM" (24h)
+	Days []string      `json:"days,omitempty"` // Days of the week ("mon".."sun"), empty means every day
+	Item playlist.Item `json:"item"`
+}
+
+// ParseTimeOfDay parses a "HH:MM" (24h) time of day.
+func ParseTimeOfDay(s string) (hour, minute int, err error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid time of day %q (expected HH:MM)", s)
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+// Validate checks the entry time, days and item.
+func (e Entry) Validate() error {
+	if _, _, err := ParseTimeOfDay(e.At); err != nil {
+		return err
+	}
+	for _, day := range e.Days {
+		if _, ok := weekdays[strings.ToLower(day)]; !ok {
+			return fmt.Errorf("invalid day %q (valid: sun, mon, tue, wed, thu, fri, sat)", day)
+		}
+	}
+	return e.Item.ValidateContent()
+}
+
+// Matches returns true if the entry is due at the minute of t (in t's location).
+func (e Entry) Matches(t time.Time) bool {
+	hour, minute, err := ParseTimeOfDay(e.At)
+	if err != nil {
+		return false
+	}
+	if t.Hour() != hour || t.Minute() != minute {
+		return false
+	}
+	if len(e.Days) == 0 {
+		return true
+	}
+	for _, day := range e.Days {
+		if weekdays[strings.ToLower(day)] == t.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// Load reads entries from a JSON file. A missing file is an empty schedule.
+func Load(path string) ([]Entry, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schedule: %w", err)
+	}
+
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse schedule: %w", err)
+	}
+	for idx, entry := range entries {
+		if err := entry.Validate(); err != nil {
+			return nil, fmt.Errorf("entry %d: %w", idx, err)
+		}
+	}
+	return entries, nil
+}
+
+// Save writes entries to a JSON file, replacing it atomically.
+func Save(path string, entries []Entry) error {
+	if entries == nil {
+		entries = []Entry{}
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode schedule: %w", err)
+	}
+	if err := atomicfile.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to save schedule: %w", err)
+	}
+	return nil
+}
+
+// Scheduler returns the entry that became due since the previous check. It
+// doesn't read the clock itself: callers pass the current time, so it can be
+// driven by a fake clock in tests.
+type Scheduler struct {
+	entries []Entry
+	last    time.Time // Time of the previous check
+}
+
+// NewScheduler creates a scheduler whose first check starts after now.
+func NewScheduler(entries []Entry, now time.Time) *Scheduler {
+	return &Scheduler{entries: entries, last: now}
+}
+
+// Due returns the entry matching the latest minute after the previous check,
+// up to and including now, and true, or false if none matches. The display
+// shows one item at a time, so when a late check missed several entries only
+// the most recent one is returned, and of entries at the same minute the last
+// one in schedule order. An entry is returned at most once per matching
+// minute, so checking several times within a minute doesn't repeat it.
+func (s *Scheduler) Due(now time.Time) (Entry, bool) {
+	from := s.last.Truncate(time.Minute).Add(time.Minute)
+	if now.Sub(from) > maxCatchUp {
+		from = now.Add(-maxCatchUp).Truncate(time.Minute)
+	}
+	s.last = now
+
+	for minute := now.Truncate(time.Minute); !minute.Before(from); minute = minute.Add(-time.Minute) {
+		for i := len(s.entries) - 1; i >= 0; i-- {
+			if s.entries[i].Matches(minute) {
+				return s.entries[i], true
+			}
+		}
+	}
+	return Entry{}, false
+}
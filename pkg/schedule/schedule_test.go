@@ -0,0 +1,165 @@
+package schedule
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/playlist"
+)
+
+var (
+	goodMorning = Entry{
+		At:   "09:00",
+		Item: playlist.Item{Type: playlist.TypeText, Params: map[string]string{"text": "GOOD MORNING"}},
+	}
+	weekdayFire = Entry{
+		At:   "18:00",
+		Days: []string{"mon", "tue", "wed", "thu", "fri"},
+		Item: playlist.Item{Type: playlist.TypeFire},
+	}
+)
+
+// 2024-01-01 is a Monday
+func at(day, hour, minute, second int) time.Time {
+	return time.Date(2024, 1, day, hour, minute, second, 0, time.UTC)
+}
+
+func TestEntryMatches(t *testing.T) {
+	tests := []struct {
+		name     string
+		entry    Entry
+		time     time.Time
+		expected bool
+	}{
+		{name: "every day at the exact minute", entry: goodMorning, time: at(1, 9, 0, 0), expected: true},
+		{name: "every day within the minute", entry: goodMorning, time: at(3, 9, 0, 59), expected: true},
+		{name: "every day one minute later", entry: goodMorning, time: at(1, 9, 1, 0), expected: false},
+		{name: "every day other hour", entry: goodMorning, time: at(1, 21, 0, 0), expected: false},
+		{name: "weekday on Monday", entry: weekdayFire, time: at(1, 18, 0, 0), expected: true},
+		{name: "weekday on Friday", entry: weekdayFire, time: at(5, 18, 0, 30), expected: true},
+		{name: "weekday on Saturday", entry: weekdayFire, time: at(6, 18, 0, 0), expected: false},
+		{name: "weekday on Sunday", entry: weekdayFire, time: at(7, 18, 0, 0), expected: false},
+		{name: "days are case insensitive", entry: Entry{At: "18:00", Days: []string{"SAT"}}, time: at(6, 18, 0, 0), expected: true},
+		{name: "invalid time never matches", entry: Entry{At: "25:00"}, time: at(1, 1, 0, 0), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.entry.Matches(tt.time))
+		})
+	}
+}
+
+func TestEntryValidate(t *testing.T) {
+	assert.NoError(t, goodMorning.Validate())
+	assert.NoError(t, weekdayFire.Validate())
+
+	invalid := map[string]Entry{
+		"invalid time":   {At: "9am", Item: playlist.Item{Type: playlist.TypeFire}},
+		"invalid hour":   {At: "24:00", Item: playlist.Item{Type: playlist.TypeFire}},
+		"invalid day":    {At: "09:00", Days: []string{"monday"}, Item: playlist.Item{Type: playlist.TypeFire}},
+		"invalid item":   {At: "09:00", Item: playlist.Item{Type: "video"}},
+		"missing params": {At: "09:00", Item: playlist.Item{Type: playlist.TypeText}},
+	}
+	for name, entry := range invalid {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, entry.Validate())
+		})
+	}
+}
+
+func TestSchedulerDue(t *testing.T) {
+	s := NewScheduler([]Entry{goodMorning, weekdayFire}, at(1, 8, 59, 30))
+
+	// Not due yet
+	_, ok := s.Due(at(1, 8, 59, 59))
+	assert.False(t, ok)
+
+	// Due once when the minute starts, not again within the same minute
+	entry, ok := s.Due(at(1, 9, 0, 1))
+	assert.True(t, ok)
+	assert.Equal(t, goodMorning, entry)
+	_, ok = s.Due(at(1, 9, 0, 30))
+	assert.False(t, ok)
+	_, ok = s.Due(at(1, 9, 1, 0))
+	assert.False(t, ok)
+
+	// A late check still catches an entry that became due in between
+	entry, ok = s.Due(at(1, 18, 5, 0))
+	assert.True(t, ok)
+	assert.Equal(t, weekdayFire, entry)
+
+	// Saturday: only the every day entry
+	s = NewScheduler([]Entry{goodMorning, weekdayFire}, at(6, 0, 0, 0))
+	entry, ok = s.Due(at(6, 23, 59, 0))
+	assert.True(t, ok)
+	assert.Equal(t, goodMorning, entry)
+}
+
+func TestSchedulerDueOnlyMostRecent(t *testing.T) {
+	evening := Entry{At: "18:00", Item: playlist.Item{Type: playlist.TypeText, Params: map[string]string{"text": "GOOD EVENING"}}}
+
+	// Both entries were missed, only the latest is shown
+	s := NewScheduler([]Entry{goodMorning, weekdayFire}, at(1, 8, 0, 0))
+	entry, ok := s.Due(at(1, 20, 0, 0))
+	assert.True(t, ok)
+	assert.Equal(t, weekdayFire, entry)
+
+	// The latest minute wins regardless of the schedule order
+	s = NewScheduler([]Entry{weekdayFire, goodMorning}, at(1, 8, 0, 0))
+	entry, ok = s.Due(at(1, 20, 0, 0))
+	assert.True(t, ok)
+	assert.Equal(t, weekdayFire, entry)
+
+	// Entries at the same minute: the last one in schedule order
+	s = NewScheduler([]Entry{weekdayFire, evening}, at(1, 17, 0, 0))
+	entry, ok = s.Due(at(1, 18, 0, 0))
+	assert.True(t, ok)
+	assert.Equal(t, evening, entry)
+}
+
+func TestSchedulerDueLimitsCatchUp(t *testing.T) {
+	s := NewScheduler([]Entry{goodMorning}, at(1, 0, 0, 0))
+
+	// After a week without checks, only the last day is caught up
+	entry, ok := s.Due(at(8, 12, 0, 0))
+	assert.True(t, ok)
+	assert.Equal(t, goodMorning, entry)
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schedule.json")
+
+	// A missing file is an empty schedule
+	entries, err := Load(path)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	require.NoError(t, Save(path, []Entry{goodMorning, weekdayFire}))
+
+	entries, err = Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, []Entry{goodMorning, weekdayFire}, entries)
+
+	// No temporary files are left behind
+	files, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schedule.json")
+
+	require.NoError(t, os.WriteFile(path, []byte(`[{`), 0o644))
+	_, err := Load(path)
+	assert.Error(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte(`[{"at": "09:00", "item": {"type": "video"}}]`), 0o644))
+	_, err = Load(path)
+	assert.Error(t, err)
+}
@@ -0,0 +1,187 @@
+// Package screensaver generates a bouncing logo screensaver: a small labeled
+// tile bounces around the display, changing color whenever it hits a wall.
+package screensaver
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/gif"
+	"strings"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/text"
+)
+
+const (
+	maxLabelLength = 3
+	maxSpeed       = 4
+	tilePadding    = 2 // Pixels between the tile border and the label
+)
+
+// Options configures the screensaver animation.
+type Options struct {
+	Label      string          // Text shown in the tile (1-3 characters)
+	Colors     []graphic.Color // Colors the tile cycles through on wall hits
+	Speed      int             // Pixels moved per frame on each axis (1-4)
+	X, Y       int             // Starting position of the tile top-left corner
+	FrameDelay int             // Delay per frame (10ms units)
+	MaxFrames  int             // Longest loop allowed
+	Loops      int             // GIF loop count (0 loops forever)
+}
+
+// DefaultOptions returns a "DVD" tile bouncing through four colors, looping
+// forever.
+func DefaultOptions() Options {
+	return Options{
+		Label:      "DVD",
+		Colors:     []graphic.Color{graphic.Red, graphic.Yellow, graphic.Cyan, graphic.Magenta},
+		Speed:      2,
+		X:          10,
+		Y:          30,
+		FrameDelay: 5, // 50ms per frame
+		MaxFrames:  128,
+		Loops:      0,
+	}
+}
+
+// State is the position, direction and color of the tile.
+type State struct {
+	X, Y   int // Top-left corner of the tile
+	VX, VY int // Pixels moved per frame on each axis
+	Color  int // Index in Options.Colors
+}
+
+// Step moves the tile one frame within an area where its top-left corner
+// ranges from (0, 0) to (maxX, maxY), reflecting it off the walls it reaches.
+// The color advances once per frame in which a wall is hit, so hitting a
+// corner (two walls at once) changes the color once. Returns the new state and
+// whether a wall was hit.
+func Step(s State, maxX, maxY, numColors int) (State, bool) {
+	var hitX, hitY bool
+	s.X, s.VX, hitX = reflect(s.X+s.VX, s.VX, maxX)
+	s.Y, s.VY, hitY = reflect(s.Y+s.VY, s.VY, maxY)
+
+	hit := hitX || hitY
+	if hit && numColors > 0 {
+		s.Color = (s.Color + 1) % numColors
+	}
+	return s, hit
+}
+
+// reflect bounces pos back into 0..max, reversing the velocity when it
+// reaches or goes past a wall.
+func reflect(pos, vel, max int) (int, int, bool) {
+	switch {
+	case pos <= 0:
+		return -pos, abs(vel), true
+	case pos >= max:
+		return 2*max - pos, -abs(vel), true
+	default:
+		return pos, vel, false
+	}
+}
+
+func abs(v int) int {
+	return max(v, -v)
+}
+
+// tileSize returns the side of the square tile holding the label. The tile is
+// square so it bounces with the same period on both axes, keeping the loop
+// short.
+func tileSize(label string) int {
+	return max(text.TextWidth(label), text.FontHeight) + 2*(tilePadding+1)
+}
+
+// LoopLength returns the number of frames after which the tile is back to its
+// starting position, direction and color, or an error if that takes more than
+// opts.MaxFrames frames.
+func LoopLength(opts Options) (int, error) {
+	start, maxX, maxY := startState(opts)
+	s := start
+	for frame := 1; frame <= opts.MaxFrames; frame++ {
+		s, _ = Step(s, maxX, maxY, len(opts.Colors))
+		if s == start {
+			return frame, nil
+		}
+	}
+	return 0, fmt.Errorf("the animation doesn't loop within %d frames: try another speed, start position or number of colors", opts.MaxFrames)
+}
+
+// startState returns the starting state and the largest top-left corner
+// position of the tile.
+func startState(opts Options) (State, int, int) {
+	size := tileSize(opts.Label)
+	maxX := graphic.DisplayWidth - size
+	maxY := graphic.DisplayHeight - size
+	return State{
+		X:  max(0, min(opts.X, maxX)),
+		Y:  max(0, min(opts.Y, maxY)),
+		VX: opts.Speed,
+		VY: opts.Speed,
+	}, maxX, maxY
+}
+
+// validate checks the options are in range.
+func (opts Options) validate() error {
+	n := len([]rune(opts.Label))
+	if n < 1 || n > maxLabelLength {
+		return fmt.Errorf("invalid label %q (must be 1-%d characters)", opts.Label, maxLabelLength)
+	}
+	if len(opts.Colors) == 0 {
+		return fmt.Errorf("no colors")
+	}
+	if opts.Speed < 1 || opts.Speed > maxSpeed {
+		return fmt.Errorf("invalid speed: %d (valid: 1-%d)", opts.Speed, maxSpeed)
+	}
+	return nil
+}
+
+// drawTile draws the tile with its border and label in the given color.
+func drawTile(buf []byte, label string, x, y int, color graphic.Color) {
+	size := tileSize(label)
+	for i := 0; i < size; i++ {
+		graphic.SetPixel(buf, x+i, y, color)
+		graphic.SetPixel(buf, x+i, y+size-1, color)
+		graphic.SetPixel(buf, x, y+i, color)
+		graphic.SetPixel(buf, x+size-1, y+i, color)
+	}
+	textX := x + (size-text.TextWidth(label))/2
+	textY := y + (size-text.FontHeight)/2
+	text.DrawText(buf, label, textX, textY, color)
+}
+
+// GenerateGIF generates the screensaver animation, one full loop long, so the
+// tile moves seamlessly from the last frame back to the first. Returns an
+// error if an option is out of range or the loop is longer than
+// opts.MaxFrames.
+func GenerateGIF(opts Options) ([]byte, error) {
+	opts.Label = strings.ToUpper(opts.Label)
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	frames, err := LoopLength(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	s, maxX, maxY := startState(opts)
+	g := &gif.GIF{
+		Image:     make([]*image.Paletted, frames),
+		Delay:     make([]int, frames),
+		LoopCount: opts.Loops,
+	}
+	for f := 0; f < frames; f++ {
+		buf := graphic.NewBuffer()
+		drawTile(buf, opts.Label, s.X, s.Y, opts.Colors[s.Color])
+		g.Image[f] = graphic.RGBToPalettedMedianCut(buf, graphic.MaxPaletteColors)
+		g.Delay[f] = opts.FrameDelay
+		s, _ = Step(s, maxX, maxY, len(opts.Colors))
+	}
+
+	var out bytes.Buffer
+	if err := gif.EncodeAll(&out, g); err != nil {
+		return nil, fmt.Errorf("failed to encode GIF: %w", err)
+	}
+	return out.Bytes(), nil
+}
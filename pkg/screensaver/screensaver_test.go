@@ -0,0 +1,121 @@
+package screensaver
+
+import (
+	"bytes"
+	"image/gif"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStepReflectsOffWalls(t *testing.T) {
+	const maxX, maxY, numColors = 40, 40, 4
+
+	tests := map[string]struct {
+		in       State
+		expected State
+		hit      bool
+	}{
+		"no wall": {
+			in:       State{X: 10, Y: 20, VX: 2, VY: -2},
+			expected: State{X: 12, Y: 18, VX: 2, VY: -2},
+		},
+		"left wall": {
+			in:       State{X: 1, Y: 20, VX: -2, VY: 2},
+			expected: State{X: 1, Y: 22, VX: 2, VY: 2, Color: 1},
+			hit:      true,
+		},
+		"right wall": {
+			in:       State{X: 39, Y: 20, VX: 2, VY: 2},
+			expected: State{X: 39, Y: 22, VX: -2, VY: 2, Color: 1},
+			hit:      true,
+		},
+		"top wall": {
+			in:       State{X: 20, Y: 2, VX: 2, VY: -2},
+			expected: State{X: 22, Y: 0, VX: 2, VY: 2, Color: 1},
+			hit:      true,
+		},
+		"bottom wall": {
+			in:       State{X: 20, Y: 38, VX: -2, VY: 2},
+			expected: State{X: 18, Y: 40, VX: -2, VY: -2, Color: 1},
+			hit:      true,
+		},
+		"corner changes the color once": {
+			in:       State{X: 38, Y: 1, VX: 2, VY: -2, Color: 2},
+			expected: State{X: 40, Y: 1, VX: -2, VY: 2, Color: 3},
+			hit:      true,
+		},
+		"color wraps around": {
+			in:       State{X: 1, Y: 20, VX: -2, VY: 2, Color: 3},
+			expected: State{X: 1, Y: 22, VX: 2, VY: 2, Color: 0},
+			hit:      true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual, hit := Step(tc.in, maxX, maxY, numColors)
+			assert.Equal(t, tc.expected, actual)
+			assert.Equal(t, tc.hit, hit)
+		})
+	}
+}
+
+func TestStepStaysInBounds(t *testing.T) {
+	s := State{X: 3, Y: 17, VX: 3, VY: -3}
+	for i := 0; i < 1000; i++ {
+		s, _ = Step(s, 41, 41, 4)
+		require.GreaterOrEqual(t, s.X, 0)
+		require.LessOrEqual(t, s.X, 41)
+		require.GreaterOrEqual(t, s.Y, 0)
+		require.LessOrEqual(t, s.Y, 41)
+	}
+}
+
+func TestGenerateGIF(t *testing.T) {
+	for _, speed := range []int{1, 2, 3, 4} {
+		opts := DefaultOptions()
+		opts.Speed = speed
+
+		frames, err := LoopLength(opts)
+		require.NoError(t, err)
+
+		data, err := GenerateGIF(opts)
+		require.NoError(t, err)
+
+		g, err := gif.DecodeAll(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Len(t, g.Image, frames, "speed %d", speed)
+		assert.Equal(t, 64, g.Config.Width)
+		assert.Equal(t, 64, g.Config.Height)
+
+		// One step after the last frame the tile is back to its start
+		start, maxX, maxY := startState(opts)
+		s := start
+		for f := 0; f < frames; f++ {
+			s, _ = Step(s, maxX, maxY, len(opts.Colors))
+		}
+		assert.Equal(t, start, s, "speed %d", speed)
+	}
+}
+
+func TestGenerateGIFInvalidOptions(t *testing.T) {
+	tests := map[string]func(*Options){
+		"empty label":   func(o *Options) { o.Label = "" },
+		"long label":    func(o *Options) { o.Label = "LOGO" },
+		"no colors":     func(o *Options) { o.Colors = nil },
+		"zero speed":    func(o *Options) { o.Speed = 0 },
+		"too fast":      func(o *Options) { o.Speed = 5 },
+		"loop too long": func(o *Options) { o.MaxFrames = 10 },
+	}
+
+	for name, modify := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts := DefaultOptions()
+			modify(&opts)
+			_, err := GenerateGIF(opts)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -14,6 +14,8 @@ type AnimationOptions struct {
 	BlinkOffDelay int // Off-frame delay for blink (default: 30 = 300ms)
 	LetterDelay   int // Delay between letters for appear animations (default: 20 = 200ms)
 	HoldDelay     int // Hold on final frame (default: 100 = 1s)
+	Loops         int // GIF loop count for looping animations (default: 0 = loop forever)
+	ScrollStep    int // Pixels scrolled per frame for scroll animations (default: 1)
 }
 
 // DefaultAnimationOptions returns sensible default animation options.
@@ -24,19 +26,21 @@ func DefaultAnimationOptions() AnimationOptions {
 		BlinkOffDelay: 30,  // 300ms
 		LetterDelay:   20,  // 200ms
 		HoldDelay:     100, // 1s
+		Loops:         0,   // Loop forever
+		ScrollStep:    1,   // 1px per frame
 	}
 }
 
 // GenerateBlinkingText creates a blinking text animation.
 // Frame 1: Text ON (centered, shadowed)
 // Frame 2: Background only
-// LoopCount = 0 (loops forever)
+// LoopCount = opts.Loops (0 loops forever)
 // Automatically wraps text to multiple lines if it doesn't fit.
 func GenerateBlinkingText(text string, opts AnimationOptions) *graphic.Image {
 	// Frame 1: Text on
 	onBuf := graphic.NewBufferWithColor(opts.Background)
-	lines := WrapText(text)
-	if len(lines) <= 1 {
+	lines := WrapTextWithOptions(text, opts.TextOptions)
+	if len(lines) <= 1 && opts.Align == AlignCenter {
 		DrawTextCentered(onBuf, text, opts.TextOptions)
 	} else {
 		DrawMultiLineCentered(onBuf, lines, opts.TextOptions)
@@ -48,7 +52,7 @@ func GenerateBlinkingText(text string, opts AnimationOptions) *graphic.Image {
 	g := &gif.GIF{
 		Image:     []*image.Paletted{graphic.RGBToPaletted(onBuf), graphic.RGBToPaletted(offBuf)},
 		Delay:     []int{opts.FrameDelay, opts.BlinkOffDelay},
-		LoopCount: 0, // Loop forever
+		LoopCount: opts.Loops,
 	}
 
 	return &graphic.Image{
@@ -68,7 +72,7 @@ type AppearingFrame struct {
 // This avoids GIF loop issues by giving the caller control over playback.
 // Automatically wraps text to multiple lines if it doesn't fit.
 func GenerateAppearingFrames(text string, opts AnimationOptions) []AppearingFrame {
-	lines := WrapText(text)
+	lines := WrapTextWithOptions(text, opts.TextOptions)
 	if len(lines) == 0 || (len(lines) == 1 && len(lines[0]) == 0) {
 		buf := graphic.NewBufferWithColor(opts.Background)
 		return []AppearingFrame{{Data: buf, Delay: opts.HoldDelay}}
@@ -92,8 +96,7 @@ func GenerateAppearingFrames(text string, opts AnimationOptions) []AppearingFram
 	}
 
 	// Calculate vertical centering
-	totalHeight := TextBlockHeight(lines)
-	startY := (graphic.DisplayHeight - totalHeight) / 2
+	startY := opts.blockStartY(lines)
 
 	var frames []AppearingFrame
 
@@ -118,9 +121,8 @@ func GenerateAppearingFrames(text string, opts AnimationOptions) []AppearingFram
 			}
 			if showCount > 0 {
 				partial := string(lineRunes[:showCount])
-				lineWidth := TextWidth(line) // Full line width for consistent positioning
-				x := (graphic.DisplayWidth - lineWidth) / 2
-				y := startY + lineIdx*(FontHeight+LineSpacing)
+				x := opts.lineX(textWidth(line, opts.TextOptions)) // Full line width for consistent positioning
+				y := startY + lineIdx*opts.lineStep()
 				DrawTextShadowed(buf, partial, x, y, opts.TextOptions)
 			}
 			charCount -= len(lineRunes)
@@ -141,7 +143,7 @@ func GenerateAppearingFrames(text string, opts AnimationOptions) []AppearingFram
 // The final frame has a very long delay (max uint16 = ~10 minutes) to simulate non-looping.
 // Automatically wraps text to multiple lines if it doesn't fit.
 func GenerateAppearingText(text string, opts AnimationOptions) *graphic.Image {
-	lines := WrapText(text)
+	lines := WrapTextWithOptions(text, opts.TextOptions)
 	if len(lines) == 0 || (len(lines) == 1 && len(lines[0]) == 0) {
 		// Empty text: just return a single background frame
 		buf := graphic.NewBufferWithColor(opts.Background)
@@ -174,8 +176,7 @@ func GenerateAppearingText(text string, opts AnimationOptions) *graphic.Image {
 	}
 
 	// Calculate vertical centering
-	totalHeight := TextBlockHeight(lines)
-	startY := (graphic.DisplayHeight - totalHeight) / 2
+	startY := opts.blockStartY(lines)
 
 	var frames []*image.Paletted
 	var delays []int
@@ -202,9 +203,8 @@ func GenerateAppearingText(text string, opts AnimationOptions) *graphic.Image {
 			}
 			if showCount > 0 {
 				partial := string(lineRunes[:showCount])
-				lineWidth := TextWidth(line) // Full line width for consistent positioning
-				x := (graphic.DisplayWidth - lineWidth) / 2
-				y := startY + lineIdx*(FontHeight+LineSpacing)
+				x := opts.lineX(textWidth(line, opts.TextOptions)) // Full line width for consistent positioning
+				y := startY + lineIdx*opts.lineStep()
 				DrawTextShadowed(buf, partial, x, y, opts.TextOptions)
 			}
 			charCount -= len(lineRunes)
@@ -231,10 +231,10 @@ func GenerateAppearingText(text string, opts AnimationOptions) *graphic.Image {
 
 // GenerateAppearDisappearText creates a looping animation where text appears
 // letter-by-letter, holds, then disappears letter-by-letter (first-to-last removal).
-// LoopCount = 0 (loops forever)
+// LoopCount = opts.Loops (0 loops forever)
 // Automatically wraps text to multiple lines if it doesn't fit.
 func GenerateAppearDisappearText(text string, opts AnimationOptions) *graphic.Image {
-	lines := WrapText(text)
+	lines := WrapTextWithOptions(text, opts.TextOptions)
 	if len(lines) == 0 || (len(lines) == 1 && len(lines[0]) == 0) {
 		buf := graphic.NewBufferWithColor(opts.Background)
 		return &graphic.Image{
@@ -242,7 +242,7 @@ func GenerateAppearDisappearText(text string, opts AnimationOptions) *graphic.Im
 			GIFData: &gif.GIF{
 				Image:     []*image.Paletted{graphic.RGBToPaletted(buf)},
 				Delay:     []int{opts.HoldDelay},
-				LoopCount: 0,
+				LoopCount: opts.Loops,
 			},
 		}
 	}
@@ -260,14 +260,13 @@ func GenerateAppearDisappearText(text string, opts AnimationOptions) *graphic.Im
 			GIFData: &gif.GIF{
 				Image:     []*image.Paletted{graphic.RGBToPaletted(buf)},
 				Delay:     []int{opts.HoldDelay},
-				LoopCount: 0,
+				LoopCount: opts.Loops,
 			},
 		}
 	}
 
 	// Calculate vertical centering
-	totalHeight := TextBlockHeight(lines)
-	startY := (graphic.DisplayHeight - totalHeight) / 2
+	startY := opts.blockStartY(lines)
 
 	var frames []*image.Paletted
 	var delays []int
@@ -289,9 +288,8 @@ func GenerateAppearDisappearText(text string, opts AnimationOptions) *graphic.Im
 				}
 				if showCount > 0 {
 					partial := string(lineRunes[:showCount])
-					lineWidth := TextWidth(line)
-					x := (graphic.DisplayWidth - lineWidth) / 2
-					y := startY + lineIdx*(FontHeight+LineSpacing)
+					x := opts.lineX(textWidth(line, opts.TextOptions))
+					y := startY + lineIdx*opts.lineStep()
 					DrawTextShadowed(buf, partial, x, y, opts.TextOptions)
 				}
 				charCount -= len(lineRunes)
@@ -322,11 +320,13 @@ func GenerateAppearDisappearText(text string, opts AnimationOptions) *graphic.Im
 				}
 				// Show remaining characters on this line
 				remaining := string(lineRunes[skipCount:])
-				lineWidth := TextWidth(line)
-				x := (graphic.DisplayWidth - lineWidth) / 2
+				x := opts.lineX(textWidth(line, opts.TextOptions))
 				// Shift x position to account for removed characters
-				xOffset := x + skipCount*FontSpacing
-				y := startY + lineIdx*(FontHeight+LineSpacing)
+				xOffset := x
+				for _, char := range lineRunes[:skipCount] {
+					xOffset += charMetrics(char, opts.Proportional).advance
+				}
+				y := startY + lineIdx*opts.lineStep()
 				DrawTextShadowed(buf, remaining, xOffset, y, opts.TextOptions)
 				skipCount = 0 // Remaining lines show in full
 
@@ -336,9 +336,8 @@ func GenerateAppearDisappearText(text string, opts AnimationOptions) *graphic.Im
 					if len(nextLine) == 0 {
 						continue
 					}
-					nextLineWidth := TextWidth(nextLine)
-					nextX := (graphic.DisplayWidth - nextLineWidth) / 2
-					nextY := startY + nextLineIdx*(FontHeight+LineSpacing)
+					nextX := opts.lineX(textWidth(nextLine, opts.TextOptions))
+					nextY := startY + nextLineIdx*opts.lineStep()
 					DrawTextShadowed(buf, nextLine, nextX, nextY, opts.TextOptions)
 				}
 				break
@@ -358,7 +357,7 @@ func GenerateAppearDisappearText(text string, opts AnimationOptions) *graphic.Im
 		GIFData: &gif.GIF{
 			Image:     frames,
 			Delay:     delays,
-			LoopCount: 0, // Loop forever
+			LoopCount: opts.Loops,
 		},
 	}
 }
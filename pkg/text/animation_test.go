@@ -0,0 +1,246 @@
+package text
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+)
+
+func TestAnimationLoops(t *testing.T) {
+	generators := map[string]func(string, AnimationOptions) *graphic.Image{
+		"blink":            GenerateBlinkingText,
+		"appear-disappear": GenerateAppearDisappearText,
+		"fireworks":        GenerateFireworksText,
+	}
+
+	for name, generate := range generators {
+		for _, loops := range []int{0, 3} {
+			t.Run(name, func(t *testing.T) {
+				opts := DefaultAnimationOptions()
+				opts.Loops = loops
+
+				data, err := generate("HI", opts).GIFBytes()
+				require.NoError(t, err)
+
+				g, err := gif.DecodeAll(bytes.NewReader(data))
+				require.NoError(t, err)
+				assert.Equal(t, loops, g.LoopCount)
+			})
+		}
+	}
+}
+
+// framesOf returns the frames of a generated static or animated image.
+func framesOf(img *graphic.Image) []*image.Paletted {
+	if img.Type == graphic.ImageTypeStatic {
+		return []*image.Paletted{graphic.RGBToPaletted(img.StaticData)}
+	}
+	return img.GIFData.Image
+}
+
+func rgba(c graphic.Color) color.RGBA {
+	return color.RGBA{c[0], c[1], c[2], 255}
+}
+
+// inkBounds returns the smallest rectangle holding every pixel that isn't the
+// background color in any of the frames.
+func inkBounds(frames []*image.Paletted, background graphic.Color) image.Rectangle {
+	var bounds image.Rectangle
+	for _, frame := range frames {
+		bg := uint8(frame.Palette.Index(rgba(background)))
+		for y := 0; y < graphic.DisplayHeight; y++ {
+			for x := 0; x < graphic.DisplayWidth; x++ {
+				if frame.ColorIndexAt(x, y) != bg {
+					bounds = bounds.Union(image.Rect(x, y, x+1, y+1))
+				}
+			}
+		}
+	}
+	return bounds
+}
+
+func TestAnimationsHonorTextOptions(t *testing.T) {
+	// Wraps to 8 lines, 84 pixels tall, with the defaults, but to 5 lines, 39
+	// pixels tall, with proportional glyphs and 1 pixel between lines
+	msg := strings.TrimSpace(strings.Repeat("HI ", 24))
+
+	opts := DefaultAnimationOptions()
+	opts.ShadowX, opts.ShadowY = 0, 0
+	opts.Proportional = true
+	opts.LineSpacing = 1
+	opts.Align = AlignLeft
+
+	lines := WrapTextWithOptions(msg, opts.TextOptions)
+	require.Len(t, lines, 5)
+	require.Len(t, WrapText(msg), 8)
+	startY := opts.blockStartY(lines)
+	height := TextBlockHeightWithOptions(lines, opts.TextOptions)
+	width := 0
+	for _, line := range lines {
+		width = max(width, TextWidthProportional(line))
+	}
+	expected := image.Rect(0, startY, width, startY+height)
+
+	generators := map[string]func(string, AnimationOptions) *graphic.Image{
+		"static": func(msg string, opts AnimationOptions) *graphic.Image {
+			return GenerateStaticText(msg, opts.TextOptions)
+		},
+		"blink":            GenerateBlinkingText,
+		"appear":           GenerateAppearingText,
+		"appear-disappear": GenerateAppearDisappearText,
+		"rainbow":          GenerateRainbowText,
+	}
+	for name, generate := range generators {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, expected, inkBounds(framesOf(generate(msg, opts)), opts.Background))
+		})
+	}
+
+	t.Run("wave", func(t *testing.T) {
+		bounds := inkBounds(framesOf(GenerateWaveText(msg, opts)), opts.Background)
+		assert.Equal(t, expected.Min.X, bounds.Min.X)
+		assert.Equal(t, expected.Max.X, bounds.Max.X)
+		assert.GreaterOrEqual(t, bounds.Min.Y, expected.Min.Y-waveAmplitude)
+		assert.LessOrEqual(t, bounds.Max.Y, expected.Max.Y+waveAmplitude)
+	})
+
+	t.Run("fireworks", func(t *testing.T) {
+		// Fireworks are drawn all over the display, so only check that the
+		// top left pixel of the first 'H' is drawn with the text color
+		for _, frame := range framesOf(GenerateFireworksText(msg, opts)) {
+			assert.Equal(t, frame.Palette.Index(rgba(opts.TextColor)), int(frame.ColorIndexAt(0, startY)))
+		}
+	})
+
+	t.Run("scroll-up", func(t *testing.T) {
+		img := GenerateVerticalScrollText(lines, opts)
+		assert.Len(t, img.GIFData.Image, VerticalScrollFrameCount(height, 1))
+
+		// The text block reaches the top of the display after scrolling one
+		// display height
+		frame := img.GIFData.Image[graphic.DisplayHeight]
+		assert.Equal(t, image.Rect(0, 0, width, height), inkBounds([]*image.Paletted{frame}, opts.Background))
+	})
+}
+
+func TestGenerateRainbowText(t *testing.T) {
+	t.Run("adjacent letters get distinct colors", func(t *testing.T) {
+		buf := graphic.NewBuffer()
+		lines := WrapText("HI")
+		drawRainbowLines(buf, lines, (graphic.DisplayHeight-FontHeight)/2, 0, DefaultTextOptions())
+
+		pixelAt := func(x, y int) graphic.Color {
+			offset := (y*graphic.DisplayWidth + x) * 3
+			return graphic.Color{buf[offset], buf[offset+1], buf[offset+2]}
+		}
+
+		// "HI" is 11 pixels wide, so it starts at x = 26 and y = 28.
+		// 'H' has a pixel in column 0 on the first row, 'I' in column 2.
+		first := pixelAt(26, 28)
+		second := pixelAt(32+2, 28)
+		assert.Equal(t, graphic.HSVToColor(0, 1, 1), first)
+		assert.Equal(t, graphic.HSVToColor(rainbowLetterShift, 1, 1), second)
+		assert.NotEqual(t, first, second)
+	})
+
+	t.Run("loop wraps cleanly", func(t *testing.T) {
+		step := 360.0 / rainbowFrames
+		for letter := 0; letter < 5; letter++ {
+			last := rainbowHue(rainbowFrames-1, letter)
+			first := rainbowHue(0, letter)
+			assert.InDelta(t, first, math.Mod(last+step, 360), 0.0001)
+			assert.InDelta(t, first, rainbowHue(rainbowFrames, letter), 0.0001)
+		}
+	})
+
+	t.Run("generates a looping GIF", func(t *testing.T) {
+		data, err := GenerateRainbowText("HELLO", DefaultAnimationOptions()).GIFBytes()
+		require.NoError(t, err)
+
+		g, err := gif.DecodeAll(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Len(t, g.Image, rainbowFrames)
+		assert.Equal(t, 0, g.LoopCount)
+	})
+}
+
+func TestGenerateWaveText(t *testing.T) {
+	t.Run("first letter returns to baseline at the loop boundary", func(t *testing.T) {
+		assert.Equal(t, 0, waveOffset(0, 0))
+		assert.Equal(t, 0, waveOffset(waveFrames, 0))
+		for letter := 0; letter < 8; letter++ {
+			assert.Equal(t, waveOffset(0, letter), waveOffset(waveFrames, letter))
+		}
+	})
+
+	t.Run("letters move within the amplitude", func(t *testing.T) {
+		moved := false
+		for frame := 0; frame < waveFrames; frame++ {
+			offset := waveOffset(frame, 0)
+			assert.LessOrEqual(t, offset, waveAmplitude)
+			assert.GreaterOrEqual(t, offset, -waveAmplitude)
+			if offset != 0 {
+				moved = true
+			}
+		}
+		assert.True(t, moved)
+	})
+
+	t.Run("adjacent letters are out of phase", func(t *testing.T) {
+		assert.NotEqual(t, waveOffset(0, 0), waveOffset(0, 1))
+	})
+
+	t.Run("generates a frame per step of the period", func(t *testing.T) {
+		data, err := GenerateWaveText("WAVE", DefaultAnimationOptions()).GIFBytes()
+		require.NoError(t, err)
+
+		g, err := gif.DecodeAll(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Len(t, g.Image, waveFrames)
+	})
+}
+
+func TestGenerateVerticalScrollText(t *testing.T) {
+	lines := make([]string, 10)
+	for i := range lines {
+		lines[i] = "LINE"
+	}
+	// 10 lines = 10*7 + 9*4 = 106 pixels, scrolled over 64 + 106 = 170 pixels
+	require.Equal(t, 106, TextBlockHeight(lines))
+
+	tests := []struct {
+		step           int
+		expectedFrames int
+	}{
+		{step: 1, expectedFrames: 170},
+		{step: 2, expectedFrames: 85},
+		{step: 3, expectedFrames: 57},
+	}
+
+	for _, tt := range tests {
+		opts := DefaultAnimationOptions()
+		opts.ScrollStep = tt.step
+
+		img := GenerateVerticalScrollText(lines, opts)
+		assert.Len(t, img.GIFData.Image, tt.expectedFrames, "step %d", tt.step)
+		assert.Equal(t, tt.expectedFrames, VerticalScrollFrameCount(106, tt.step))
+	}
+
+	t.Run("first frame is blank so the loop restarts seamlessly", func(t *testing.T) {
+		img := GenerateVerticalScrollText(lines, DefaultAnimationOptions())
+		frames := img.GIFData.Image
+
+		blank := graphic.RGBToPaletted(graphic.NewBuffer())
+		assert.Equal(t, blank.Pix, frames[0].Pix)
+		assert.NotEqual(t, blank.Pix, frames[len(frames)/2].Pix)
+	})
+}
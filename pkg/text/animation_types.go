@@ -34,6 +34,22 @@ var AnimationTypes = []AnimationType{
 		Name:        "fireworks",
 		Description: "Text with colorful fireworks (loops forever)",
 	},
+	{
+		Name:        "rainbow",
+		Description: "Letters cycle through rainbow colors (loops forever)",
+	},
+	{
+		Name:        "wave",
+		Description: "Letters bob up and down in a wave (loops forever)",
+	},
+	{
+		Name:        "scroll-up",
+		Description: "Text scrolls upward like movie credits (loops forever)",
+	},
+	{
+		Name:        "banner",
+		Description: "Text scrolls right to left inside a colored bar (loops forever)",
+	},
 }
 
 // AnimationTypeNames returns a list of primary animation type names.
@@ -64,6 +80,16 @@ func GenerateAnimation(animationType, text string, opts AnimationOptions) (*grap
 		return GenerateAppearDisappearText(text, opts), ""
 	case "fireworks":
 		return GenerateFireworksText(text, opts), ""
+	case "rainbow":
+		return GenerateRainbowText(text, opts), ""
+	case "wave":
+		return GenerateWaveText(text, opts), ""
+	case "scroll-up":
+		return GenerateVerticalScrollText(WrapTextWithOptions(text, opts.TextOptions), opts), ""
+	case "banner":
+		bannerOpts := DefaultBannerOptions()
+		bannerOpts.AnimationOptions = opts
+		return GenerateBannerText(text, bannerOpts), ""
 	default:
 		return nil, "unknown animation type: " + animationType + " (valid: " + AnimationTypeNamesString() + ")"
 	}
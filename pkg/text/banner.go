@@ -0,0 +1,85 @@
+package text
+
+import (
+	"image"
+	"image/gif"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+)
+
+// BannerOptions configures a banner: a single line of text scrolling from
+// right to left inside a solid colored bar. The rest of the display is filled
+// with the Background color.
+type BannerOptions struct {
+	AnimationOptions
+	BarColor  graphic.Color // Bar fill color
+	BarHeight int           // Bar height in pixels (default: 13)
+	BarY      int           // Top row of the bar (default: vertically centered)
+}
+
+// DefaultBannerOptions returns a vertically centered blue bar.
+func DefaultBannerOptions() BannerOptions {
+	const barHeight = FontHeight + 6
+	return BannerOptions{
+		AnimationOptions: DefaultAnimationOptions(),
+		BarColor:         graphic.Blue,
+		BarHeight:        barHeight,
+		BarY:             (graphic.DisplayHeight - barHeight) / 2,
+	}
+}
+
+// HorizontalScrollFrameCount returns the number of frames needed to scroll a
+// line of the given width from just right of the display to just left of it.
+func HorizontalScrollFrameCount(lineWidth, step int) int {
+	if step < 1 {
+		step = 1
+	}
+	travel := graphic.DisplayWidth + lineWidth
+	return (travel + step - 1) / step
+}
+
+// GenerateBannerText creates a looping animation of the text scrolling
+// horizontally inside the bar, like a stock ticker. The text enters from the
+// right and leaves at the left, so the loop restarts seamlessly.
+// opts.ScrollStep sets the speed in pixels per frame.
+// LoopCount = opts.Loops (0 loops forever)
+func GenerateBannerText(text string, opts BannerOptions) *graphic.Image {
+	step := max(opts.ScrollStep, 1)
+	numFrames := HorizontalScrollFrameCount(textWidth(text, opts.TextOptions), step)
+
+	frames := make([]*image.Paletted, numFrames)
+	delays := make([]int, numFrames)
+
+	for frame := 0; frame < numFrames; frame++ {
+		buf := graphic.NewBufferWithColor(opts.Background)
+		drawBannerFrame(buf, text, graphic.DisplayWidth-frame*step, opts)
+
+		frames[frame] = graphic.RGBToPaletted(buf)
+		delays[frame] = scrollFrameDelay
+	}
+
+	return &graphic.Image{
+		Type: graphic.ImageTypeAnimated,
+		GIFData: &gif.GIF{
+			Image:     frames,
+			Delay:     delays,
+			LoopCount: opts.Loops,
+		},
+	}
+}
+
+// drawBannerFrame draws the bar with the text at x, vertically centered in
+// the bar. The text and its shadow are clipped to the bar.
+func drawBannerFrame(buf []byte, text string, x int, opts BannerOptions) {
+	barTop := max(opts.BarY, 0)
+	barBottom := min(opts.BarY+opts.BarHeight, graphic.DisplayHeight)
+	if barTop >= barBottom {
+		return
+	}
+
+	bar := graphic.NewBufferWithColor(opts.BarColor)
+	DrawTextShadowed(bar, text, x, opts.BarY+(opts.BarHeight-FontHeight)/2, opts.TextOptions)
+
+	rowSize := graphic.DisplayWidth * 3
+	copy(buf[barTop*rowSize:barBottom*rowSize], bar[barTop*rowSize:barBottom*rowSize])
+}
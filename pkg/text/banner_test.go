@@ -0,0 +1,65 @@
+package text
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+)
+
+func TestGenerateBannerText(t *testing.T) {
+	opts := DefaultBannerOptions()
+	opts.Background = graphic.DarkRed
+	opts.BarY = 40
+
+	t.Run("bar is drawn only inside its rows", func(t *testing.T) {
+		buf := graphic.NewBufferWithColor(opts.Background)
+		drawBannerFrame(buf, "HELLO", 10, opts)
+
+		glyphPixels := 0
+		for y := 0; y < graphic.DisplayHeight; y++ {
+			for x := 0; x < graphic.DisplayWidth; x++ {
+				offset := (y*graphic.DisplayWidth + x) * 3
+				pixel := graphic.Color{buf[offset], buf[offset+1], buf[offset+2]}
+
+				if y < opts.BarY || y >= opts.BarY+opts.BarHeight {
+					require.Equal(t, opts.Background, pixel, "pixel (%d, %d) outside the bar", x, y)
+					continue
+				}
+				if pixel != opts.BarColor {
+					require.Contains(t, []graphic.Color{opts.TextColor, opts.ShadowColor}, pixel, "pixel (%d, %d) inside the bar", x, y)
+					glyphPixels++
+				}
+			}
+		}
+		assert.Positive(t, glyphPixels)
+	})
+
+	t.Run("glyphs are clipped to a short bar", func(t *testing.T) {
+		opts := opts
+		opts.BarHeight = 4
+		buf := graphic.NewBufferWithColor(opts.Background)
+		drawBannerFrame(buf, "HELLO", 10, opts)
+
+		assert.Equal(t, graphic.NewBufferWithColor(opts.Background)[:opts.BarY*graphic.DisplayWidth*3], buf[:opts.BarY*graphic.DisplayWidth*3])
+		assert.Equal(t, graphic.NewBufferWithColor(opts.Background)[(opts.BarY+4)*graphic.DisplayWidth*3:], buf[(opts.BarY+4)*graphic.DisplayWidth*3:])
+	})
+
+	t.Run("text scrolls across the whole display", func(t *testing.T) {
+		opts := opts
+		opts.ScrollStep = 2
+
+		// "HELLO" is 29 pixels wide, scrolled over 64 + 29 = 93 pixels
+		img := GenerateBannerText("HELLO", opts)
+		assert.Len(t, img.GIFData.Image, 47)
+		assert.Equal(t, 47, HorizontalScrollFrameCount(29, 2))
+
+		// The first frame is the empty bar, so the loop restarts seamlessly
+		empty := graphic.NewBufferWithColor(opts.Background)
+		drawBannerFrame(empty, "", 0, opts)
+		assert.Equal(t, graphic.RGBToPaletted(empty).Pix, img.GIFData.Image[0].Pix)
+		assert.NotEqual(t, graphic.RGBToPaletted(empty).Pix, img.GIFData.Image[20].Pix)
+	})
+}
@@ -29,3 +29,23 @@ func DrawText(buf []byte, text string, x, y int, color graphic.Color) int {
 	}
 	return x - startX - 1 // Subtract trailing gap
 }
+
+// DrawTextProportional draws a string of text at the given position using
+// proportional glyph widths. Returns the total width in pixels of the drawn text.
+func DrawTextProportional(buf []byte, text string, x, y int, color graphic.Color) int {
+	startX := x
+	for _, char := range text {
+		m := charMetrics(char, true)
+		DrawChar(buf, char, x-m.offset, y, color)
+		x += m.advance
+	}
+	return x - startX - 1 // Subtract trailing gap
+}
+
+// drawText draws text honoring opts.Proportional.
+func drawText(buf []byte, text string, x, y int, color graphic.Color, opts TextOptions) int {
+	if opts.Proportional {
+		return DrawTextProportional(buf, text, x, y, color)
+	}
+	return DrawText(buf, text, x, y, color)
+}
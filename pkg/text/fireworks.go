@@ -183,13 +183,9 @@ func fadeFireworkColor(c graphic.Color, life, maxLife int) graphic.Color {
 
 // GenerateFireworksText creates an animated text display with colorful fireworks.
 // The text is displayed centered with fireworks exploding around it.
-// LoopCount = 0 (loops forever)
+// LoopCount = opts.Loops (0 loops forever)
 func GenerateFireworksText(text string, opts AnimationOptions) *graphic.Image {
-	lines := WrapText(text)
-
-	// Calculate text positioning
-	totalHeight := TextBlockHeight(lines)
-	startY := (graphic.DisplayHeight - totalHeight) / 2
+	lines := WrapTextWithOptions(text, opts.TextOptions)
 
 	// Initialize random generator
 	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
@@ -232,19 +228,7 @@ func GenerateFireworksText(text string, opts AnimationOptions) *graphic.Image {
 		}
 
 		// Draw text ON TOP
-		if len(lines) == 1 {
-			DrawTextCentered(buf, lines[0], opts.TextOptions)
-		} else {
-			for lineIdx, line := range lines {
-				if len(line) == 0 {
-					continue
-				}
-				lineWidth := TextWidth(line)
-				x := (graphic.DisplayWidth - lineWidth) / 2
-				y := startY + lineIdx*(FontHeight+LineSpacing)
-				DrawTextShadowed(buf, line, x, y, opts.TextOptions)
-			}
-		}
+		DrawMultiLineCentered(buf, lines, opts.TextOptions)
 
 		frames = append(frames, graphic.RGBToPaletted(buf))
 		delays = append(delays, fwFrameDelay)
@@ -255,7 +239,7 @@ func GenerateFireworksText(text string, opts AnimationOptions) *graphic.Image {
 		GIFData: &gif.GIF{
 			Image:     frames,
 			Delay:     delays,
-			LoopCount: 0, // Loop forever
+			LoopCount: opts.Loops,
 		},
 	}
 }
@@ -8,8 +8,8 @@ const (
 	LineSpacing = 4 // Pixels between lines
 )
 
-// font5x7 contains 5x7 pixel bitmap font data for uppercase letters,
-// digits, and common punctuation. Each row is encoded as a uint8 bitmask
+// font5x7 contains 5x7 pixel bitmap font data for uppercase and lowercase
+// letters, digits, and common punctuation. Each row is encoded as a uint8 bitmask
 // where bit 0 is the leftmost pixel.
 var font5x7 = map[rune][7]uint8{
 	// Uppercase letters
@@ -40,6 +40,34 @@ var font5x7 = map[rune][7]uint8{
 	'Y': {0x11, 0x11, 0x0A, 0x04, 0x04, 0x04, 0x04},
 	'Z': {0x1F, 0x10, 0x08, 0x04, 0x02, 0x01, 0x1F},
 
+	// Lowercase letters (descenders of g, j, p, q, y are raised to fit 7 rows)
+	'a': {0x00, 0x00, 0x0E, 0x10, 0x1E, 0x11, 0x1E},
+	'b': {0x01, 0x01, 0x0F, 0x11, 0x11, 0x11, 0x0F},
+	'c': {0x00, 0x00, 0x0E, 0x01, 0x01, 0x11, 0x0E},
+	'd': {0x10, 0x10, 0x1E, 0x11, 0x11, 0x11, 0x1E},
+	'e': {0x00, 0x00, 0x0E, 0x11, 0x1F, 0x01, 0x0E},
+	'f': {0x0C, 0x12, 0x02, 0x07, 0x02, 0x02, 0x02},
+	'g': {0x00, 0x1E, 0x11, 0x11, 0x1E, 0x10, 0x0E},
+	'h': {0x01, 0x01, 0x0D, 0x13, 0x11, 0x11, 0x11},
+	'i': {0x04, 0x00, 0x06, 0x04, 0x04, 0x04, 0x0E},
+	'j': {0x08, 0x00, 0x0C, 0x08, 0x08, 0x09, 0x06},
+	'k': {0x01, 0x01, 0x09, 0x05, 0x03, 0x05, 0x09},
+	'l': {0x06, 0x04, 0x04, 0x04, 0x04, 0x04, 0x0E},
+	'm': {0x00, 0x00, 0x0B, 0x15, 0x15, 0x11, 0x11},
+	'n': {0x00, 0x00, 0x0D, 0x13, 0x11, 0x11, 0x11},
+	'o': {0x00, 0x00, 0x0E, 0x11, 0x11, 0x11, 0x0E},
+	'p': {0x00, 0x0F, 0x11, 0x11, 0x0F, 0x01, 0x01},
+	'q': {0x00, 0x1E, 0x11, 0x11, 0x1E, 0x10, 0x10},
+	'r': {0x00, 0x00, 0x0D, 0x13, 0x01, 0x01, 0x01},
+	's': {0x00, 0x00, 0x1E, 0x01, 0x0E, 0x10, 0x0F},
+	't': {0x02, 0x02, 0x07, 0x02, 0x02, 0x12, 0x0C},
+	'u': {0x00, 0x00, 0x11, 0x11, 0x11, 0x19, 0x16},
+	'v': {0x00, 0x00, 0x11, 0x11, 0x11, 0x0A, 0x04},
+	'w': {0x00, 0x00, 0x11, 0x11, 0x15, 0x15, 0x0A},
+	'x': {0x00, 0x00, 0x11, 0x0A, 0x04, 0x0A, 0x11},
+	'y': {0x00, 0x11, 0x11, 0x11, 0x1E, 0x10, 0x0E},
+	'z': {0x00, 0x00, 0x1F, 0x08, 0x04, 0x02, 0x1F},
+
 	// Digits
 	'0': {0x0E, 0x11, 0x19, 0x15, 0x13, 0x11, 0x0E},
 	'1': {0x04, 0x06, 0x04, 0x04, 0x04, 0x04, 0x0E},
@@ -65,6 +93,70 @@ var font5x7 = map[rune][7]uint8{
 	'=': {0x00, 0x00, 0x1F, 0x00, 0x1F, 0x00, 0x00},
 	':': {0x00, 0x00, 0x04, 0x00, 0x00, 0x04, 0x00},
 	'\'': {0x04, 0x04, 0x02, 0x00, 0x00, 0x00, 0x00},
+	';': {0x00, 0x00, 0x04, 0x00, 0x00, 0x04, 0x02},
+	'"': {0x0A, 0x0A, 0x00, 0x00, 0x00, 0x00, 0x00},
+	'/': {0x10, 0x10, 0x08, 0x04, 0x02, 0x01, 0x01},
+	'\\': {0x01, 0x01, 0x02, 0x04, 0x08, 0x10, 0x10},
+	'(': {0x08, 0x04, 0x02, 0x02, 0x02, 0x04, 0x08},
+	')': {0x02, 0x04, 0x08, 0x08, 0x08, 0x04, 0x02},
+	'[': {0x0E, 0x02, 0x02, 0x02, 0x02, 0x02, 0x0E},
+	']': {0x0E, 0x08, 0x08, 0x08, 0x08, 0x08, 0x0E},
+	'<': {0x08, 0x04, 0x02, 0x01, 0x02, 0x04, 0x08},
+	'>': {0x02, 0x04, 0x08, 0x10, 0x08, 0x04, 0x02},
+	'_': {0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x1F},
+	'*': {0x00, 0x04, 0x15, 0x0E, 0x15, 0x04, 0x00},
+	'#': {0x0A, 0x0A, 0x1F, 0x0A, 0x1F, 0x0A, 0x0A},
+	'%': {0x03, 0x13, 0x08, 0x04, 0x02, 0x19, 0x18},
+	'&': {0x06, 0x09, 0x05, 0x02, 0x15, 0x09, 0x16},
+	'@': {0x0E, 0x11, 0x1D, 0x15, 0x1D, 0x01, 0x0E},
+	'$': {0x04, 0x1E, 0x05, 0x0E, 0x14, 0x0F, 0x04},
+}
+
+// proportionalSpaceAdvance is the advance of a blank glyph (space) in proportional mode.
+const proportionalSpaceAdvance = 3
+
+// glyphMetrics describes how a glyph is placed in proportional mode.
+type glyphMetrics struct {
+	offset  int // Empty columns on the left of the bitmap, skipped when drawing
+	advance int // Inked width plus 1px gap
+}
+
+// proportionalMetrics is the per-glyph advance table for proportional mode,
+// derived from the inked columns of each bitmap in font5x7.
+var proportionalMetrics = buildProportionalMetrics()
+
+func buildProportionalMetrics() map[rune]glyphMetrics {
+	metrics := make(map[rune]glyphMetrics, len(font5x7))
+	for char, data := range font5x7 {
+		var columns uint8
+		for _, row := range data {
+			columns |= row
+		}
+		if columns == 0 {
+			metrics[char] = glyphMetrics{offset: 0, advance: proportionalSpaceAdvance}
+			continue
+		}
+		left, right := 0, FontWidth-1
+		for columns&(1<<left) == 0 {
+			left++
+		}
+		for columns&(1<<right) == 0 {
+			right--
+		}
+		metrics[char] = glyphMetrics{offset: left, advance: right - left + 2}
+	}
+	return metrics
+}
+
+// charMetrics returns the glyph metrics for char. In fixed-width mode, and for
+// unknown runes, every glyph advances by FontSpacing.
+func charMetrics(char rune, proportional bool) glyphMetrics {
+	if proportional {
+		if m, ok := proportionalMetrics[char]; ok {
+			return m
+		}
+	}
+	return glyphMetrics{offset: 0, advance: FontSpacing}
 }
 
 // TextWidth calculates the pixel width of a text string.
@@ -74,3 +166,24 @@ func TextWidth(text string) int {
 	}
 	return len([]rune(text))*FontSpacing - 1 // Remove trailing gap
 }
+
+// TextWidthProportional calculates the pixel width of a text string
+// drawn with proportional glyph widths.
+func TextWidthProportional(text string) int {
+	if len(text) == 0 {
+		return 0
+	}
+	width := 0
+	for _, char := range text {
+		width += charMetrics(char, true).advance
+	}
+	return width - 1 // Remove trailing gap
+}
+
+// textWidth calculates the pixel width of a text string honoring opts.Proportional.
+func textWidth(text string, opts TextOptions) int {
+	if opts.Proportional {
+		return TextWidthProportional(text)
+	}
+	return TextWidth(text)
+}
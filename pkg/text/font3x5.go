@@ -0,0 +1,104 @@
+package text
+
+import "github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+
+// Small font dimensions
+const (
+	SmallFontWidth   = 3 // Character width in pixels
+	SmallFontHeight  = 5 // Character height in pixels
+	SmallFontSpacing = 4 // Horizontal spacing between characters (includes 1px gap)
+)
+
+// font3x5 contains 3x5 pixel glyphs for uppercase letters, digits and a few
+// punctuation marks, for labels that don't fit with the 5x7 font and the
+// matrix grot rain. Each row is a string where '#' is a lit pixel.
+var font3x5 = map[rune][SmallFontHeight]string{
+	'A': {".#.", "#.#", "###", "#.#", "#.#"},
+	'B': {"##.", "#.#", "##.", "#.#", "##."},
+	'C': {".##", "#..", "#..", "#..", ".##"},
+	'D': {"##.", "#.#", "#.#", "#.#", "##."},
+	'E': {"###", "#..", "##.", "#..", "###"},
+	'F': {"###", "#..", "##.", "#..", "#.."},
+	'G': {".##", "#..", "#.#", "#.#", ".##"},
+	'H': {"#.#", "#.#", "###", "#.#", "#.#"},
+	'I': {"###", ".#.", ".#.", ".#.", "###"},
+	'J': {"..#", "..#", "..#", "#.#", ".#."},
+	'K': {"#.#", "#.#", "##.", "#.#", "#.#"},
+	'L': {"#..", "#..", "#..", "#..", "###"},
+	'M': {"#.#", "###", "###", "#.#", "#.#"},
+	'N': {"##.", "#.#", "#.#", "#.#", "#.#"},
+	'O': {".#.", "#.#", "#.#", "#.#", ".#."},
+	'P': {"##.", "#.#", "##.", "#..", "#.."},
+	'Q': {".#.", "#.#", "#.#", "##.", ".##"},
+	'R': {"##.", "#.#", "##.", "#.#", "#.#"},
+	'S': {".##", "#..", ".#.", "..#", "##."},
+	'T': {"###", ".#.", ".#.", ".#.", ".#."},
+	'U': {"#.#", "#.#", "#.#", "#.#", "###"},
+	'V': {"#.#", "#.#", "#.#", "#.#", ".#."},
+	'W': {"#.#", "#.#", "###", "###", "#.#"},
+	'X': {"#.#", "#.#", ".#.", "#.#", "#.#"},
+	'Y': {"#.#", "#.#", ".#.", ".#.", ".#."},
+	'Z': {"###", "..#", ".#.", "#..", "###"},
+	'0': {"###", "#.#", "#.#", "#.#", "###"},
+	'1': {".#.", "##.", ".#.", ".#.", "###"},
+	'2': {"###", "..#", "###", "#..", "###"},
+	'3': {"###", "..#", "###", "..#", "###"},
+	'4': {"#.#", "#.#", "###", "..#", "..#"},
+	'5': {"###", "#..", "###", "..#", "###"},
+	'6': {"#..", "#..", "###", "#.#", "###"},
+	'7': {"###", "..#", ".#.", ".#.", ".#."},
+	'8': {"###", "#.#", "###", "#.#", "###"},
+	'9': {"###", "#.#", "###", "..#", "..#"},
+	'!': {".#.", ".#.", ".#.", "...", ".#."},
+	'?': {"###", "..#", ".#.", "...", ".#."},
+	'.': {"...", "...", "...", "...", ".#."},
+	':': {"...", ".#.", "...", ".#.", "..."},
+	'-': {"...", "...", "###", "...", "..."},
+	'+': {"...", ".#.", "###", ".#.", "..."},
+	'=': {"...", "###", "...", "###", "..."},
+}
+
+// SmallGlyph returns the rows of the 3x5 glyph of char, or false if the small
+// font has no glyph for it.
+func SmallGlyph(char rune) ([SmallFontHeight]string, bool) {
+	glyph, ok := font3x5[char]
+	return glyph, ok
+}
+
+// DrawSmallChar draws a single character at the given position using the 3x5
+// font. Returns the width of the character drawn (SmallFontWidth for known
+// chars, 0 for unknown).
+func DrawSmallChar(buf []byte, char rune, x, y int, color graphic.Color) int {
+	glyph, ok := font3x5[char]
+	if !ok {
+		return 0
+	}
+	for row, line := range glyph {
+		for col, c := range line {
+			if c == '#' {
+				graphic.SetPixel(buf, x+col, y+row, color)
+			}
+		}
+	}
+	return SmallFontWidth
+}
+
+// DrawSmallText draws a string of text at the given position using the 3x5
+// font. Returns the total width in pixels of the drawn text.
+func DrawSmallText(buf []byte, text string, x, y int, color graphic.Color) int {
+	startX := x
+	for _, char := range text {
+		DrawSmallChar(buf, char, x, y, color)
+		x += SmallFontSpacing
+	}
+	return x - startX - 1 // Subtract trailing gap
+}
+
+// SmallTextWidth returns the width in pixels of text drawn with the 3x5 font.
+func SmallTextWidth(text string) int {
+	n := len([]rune(text))
+	if n == 0 {
+		return 0
+	}
+	return n*SmallFontSpacing - 1
+}
@@ -0,0 +1,88 @@
+package text
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+)
+
+// Overflow is the strategy for text too tall to fit the display.
+type Overflow int
+
+// Overflow strategies
+const (
+	OverflowError    Overflow = iota // Refuse the text
+	OverflowScroll                   // Show it with the scroll-up animation
+	OverflowTruncate                 // Keep the lines that fit, ending with an ellipsis
+)
+
+// Ellipsis is appended to truncated text
+const Ellipsis = "..."
+
+// ParseOverflow parses an overflow strategy name (error, scroll, truncate).
+func ParseOverflow(name string) (Overflow, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "error", "":
+		return OverflowError, nil
+	case "scroll":
+		return OverflowScroll, nil
+	case "truncate":
+		return OverflowTruncate, nil
+	default:
+		return OverflowError, fmt.Errorf("unknown overflow strategy: %s (valid: error, scroll, truncate)", name)
+	}
+}
+
+// IsScrolling returns true if the animation scrolls the text, so it can be
+// taller than the display.
+func IsScrolling(animation string) bool {
+	switch strings.ToLower(strings.TrimSpace(animation)) {
+	case "scroll-up", "banner":
+		return true
+	}
+	return false
+}
+
+// FitText checks that msg wrapped with opts fits the display with the given
+// animation and, if it doesn't, applies the overflow strategy. Returns the
+// message and animation to generate.
+func FitText(msg, animation string, opts TextOptions, overflow Overflow) (string, string, error) {
+	lines := WrapTextWithOptions(msg, opts)
+	blockHeight := TextBlockHeightWithOptions(lines, opts)
+	if blockHeight <= graphic.DisplayHeight || IsScrolling(animation) {
+		return msg, animation, nil
+	}
+
+	switch overflow {
+	case OverflowScroll:
+		return msg, "scroll-up", nil
+	case OverflowTruncate:
+		return TruncateText(lines, opts), animation, nil
+	default:
+		return "", "", fmt.Errorf("text too long: wrapped to %d lines (%d pixels, max %d)", len(lines), blockHeight, graphic.DisplayHeight)
+	}
+}
+
+// TruncateText keeps the wrapped lines fitting the display height, ending the
+// last one with an ellipsis, and joins them back into a message.
+func TruncateText(lines []string, opts TextOptions) string {
+	n := len(lines)
+	for n > 0 && TextBlockHeightWithOptions(lines[:n], opts) > graphic.DisplayHeight {
+		n--
+	}
+	if n == len(lines) {
+		return strings.Join(lines, " ")
+	}
+	if n == 0 {
+		return ""
+	}
+
+	// Shorten the last line until the ellipsis fits
+	last := []rune(lines[n-1])
+	for len(last) > 0 && textWidth(string(last)+Ellipsis, opts) > graphic.DisplayWidth {
+		last = last[:len(last)-1]
+	}
+	kept := append(lines[:n-1:n-1], strings.TrimRight(string(last), " ")+Ellipsis)
+	return strings.Join(kept, " ")
+}
@@ -0,0 +1,86 @@
+package text
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+)
+
+// tooTallMessage wraps to more lines than fit the display
+var tooTallMessage = strings.Repeat("HELLO WORLD ", 12)
+
+func TestParseOverflow(t *testing.T) {
+	for name, expected := range map[string]Overflow{
+		"":         OverflowError,
+		"error":    OverflowError,
+		"Scroll":   OverflowScroll,
+		"truncate": OverflowTruncate,
+	} {
+		overflow, err := ParseOverflow(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, expected, overflow, name)
+	}
+
+	_, err := ParseOverflow("shrink")
+	assert.Error(t, err)
+}
+
+func TestFitText(t *testing.T) {
+	opts := DefaultTextOptions()
+	require.Greater(t, TextBlockHeightWithOptions(WrapTextWithOptions(tooTallMessage, opts), opts), graphic.DisplayHeight)
+
+	t.Run("text that fits is unchanged", func(t *testing.T) {
+		for _, overflow := range []Overflow{OverflowError, OverflowScroll, OverflowTruncate} {
+			msg, animation, err := FitText("HELLO", "blink", opts, overflow)
+			require.NoError(t, err)
+			assert.Equal(t, "HELLO", msg)
+			assert.Equal(t, "blink", animation)
+		}
+	})
+
+	t.Run("scrolling animations can be taller than the display", func(t *testing.T) {
+		msg, animation, err := FitText(tooTallMessage, "banner", opts, OverflowError)
+		require.NoError(t, err)
+		assert.Equal(t, tooTallMessage, msg)
+		assert.Equal(t, "banner", animation)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, _, err := FitText(tooTallMessage, "none", opts, OverflowError)
+		assert.ErrorContains(t, err, "text too long")
+	})
+
+	t.Run("scroll", func(t *testing.T) {
+		msg, animation, err := FitText(tooTallMessage, "none", opts, OverflowScroll)
+		require.NoError(t, err)
+		assert.Equal(t, tooTallMessage, msg)
+		assert.Equal(t, "scroll-up", animation)
+	})
+
+	t.Run("truncate", func(t *testing.T) {
+		msg, animation, err := FitText(tooTallMessage, "none", opts, OverflowTruncate)
+		require.NoError(t, err)
+		assert.Equal(t, "none", animation)
+		assert.True(t, strings.HasSuffix(msg, Ellipsis), msg)
+
+		lines := WrapTextWithOptions(msg, opts)
+		assert.LessOrEqual(t, TextBlockHeightWithOptions(lines, opts), graphic.DisplayHeight)
+		assert.True(t, strings.HasPrefix(tooTallMessage, strings.TrimSuffix(msg, Ellipsis)), msg)
+	})
+
+	t.Run("truncate with proportional glyphs and wider line spacing", func(t *testing.T) {
+		opts := DefaultTextOptions()
+		opts.Proportional = true
+		opts.LineSpacing = 6
+
+		msg, _, err := FitText(tooTallMessage, "none", opts, OverflowTruncate)
+		require.NoError(t, err)
+		lines := WrapTextWithOptions(msg, opts)
+		assert.LessOrEqual(t, TextBlockHeightWithOptions(lines, opts), graphic.DisplayHeight)
+		assert.True(t, strings.HasSuffix(lines[len(lines)-1], Ellipsis), lines)
+	})
+}
@@ -0,0 +1,75 @@
+package text
+
+import (
+	"fmt"
+	"image"
+	"image/gif"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+)
+
+// CaptionMargin is the space between a caption and the bottom of the display
+const CaptionMargin = 2
+
+// captionColors is the palette size of captioned animation frames
+const captionColors = 256
+
+// OverlayText draws shadowed (or outlined) text onto an existing 64x64 RGB
+// frame in place, e.g. a label over an image. Pixels outside the display are
+// clipped. Returns an error if buf isn't a full frame.
+func OverlayText(buf []byte, msg string, x, y int, opts TextOptions) error {
+	if err := graphic.ValidateFrame(buf); err != nil {
+		return err
+	}
+	DrawTextShadowed(buf, msg, x, y, opts)
+	return nil
+}
+
+// DrawCaption draws a single line caption centered at the bottom of a 64x64
+// RGB frame in place. Returns an error if the caption is wider than the
+// display.
+func DrawCaption(buf []byte, msg string, opts TextOptions) error {
+	width := textWidth(msg, opts)
+	if width > graphic.DisplayWidth {
+		return fmt.Errorf("caption too long: %d pixels wide (max %d)", width, graphic.DisplayWidth)
+	}
+	x := (graphic.DisplayWidth - width) / 2
+	y := graphic.DisplayHeight - FontHeight - CaptionMargin
+	return OverlayText(buf, msg, x, y, opts)
+}
+
+// CaptionImage returns a copy of a static or animated image with a caption
+// drawn at the bottom of every frame.
+func CaptionImage(img *graphic.Image, msg string, opts TextOptions) (*graphic.Image, error) {
+	if img.Type == graphic.ImageTypeStatic {
+		buf := append([]byte(nil), img.StaticData...)
+		if err := DrawCaption(buf, msg, opts); err != nil {
+			return nil, err
+		}
+		return &graphic.Image{Type: graphic.ImageTypeStatic, StaticData: buf}, nil
+	}
+
+	if img.GIFData == nil || len(img.GIFData.Image) == 0 {
+		return nil, fmt.Errorf("cannot caption an animated image without frames")
+	}
+	frames := make([]*image.Paletted, len(img.GIFData.Image))
+	for i := range frames {
+		frame, err := img.Frame(i)
+		if err != nil {
+			return nil, err
+		}
+		if err := DrawCaption(frame.StaticData, msg, opts); err != nil {
+			return nil, err
+		}
+		frames[i] = graphic.RGBToPalettedMedianCut(frame.StaticData, captionColors)
+	}
+
+	return &graphic.Image{
+		Type: graphic.ImageTypeAnimated,
+		GIFData: &gif.GIF{
+			Image:     frames,
+			Delay:     append([]int(nil), img.GIFData.Delay...),
+			LoopCount: img.GIFData.LoopCount,
+		},
+	}, nil
+}
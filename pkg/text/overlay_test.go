@@ -0,0 +1,97 @@
+package text
+
+import (
+	"image"
+	"image/gif"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+)
+
+func TestOverlayText(t *testing.T) {
+	background := graphic.Color{0, 0, 80}
+	buf := graphic.NewBufferWithColor(background)
+	opts := DefaultTextOptions()
+
+	require.NoError(t, OverlayText(buf, "HI", 10, 20, opts))
+
+	// Draw the same text on a transparent reference to find the glyph pixels
+	reference := graphic.NewBuffer()
+	DrawTextShadowed(reference, "HI", 10, 20, opts)
+
+	for y := 0; y < graphic.DisplayHeight; y++ {
+		for x := 0; x < graphic.DisplayWidth; x++ {
+			offset := (y*graphic.DisplayWidth + x) * 3
+			got := graphic.Color{buf[offset], buf[offset+1], buf[offset+2]}
+			drawn := graphic.Color{reference[offset], reference[offset+1], reference[offset+2]}
+			if drawn == graphic.Black {
+				require.Equal(t, background, got, "pixel %d,%d outside the text was modified", x, y)
+			} else {
+				require.Equal(t, drawn, got, "pixel %d,%d", x, y)
+			}
+		}
+	}
+
+	// The top-left pixel of "H" is set in the text color
+	offset := (20*graphic.DisplayWidth + 10) * 3
+	assert.Equal(t, opts.TextColor, graphic.Color{buf[offset], buf[offset+1], buf[offset+2]})
+
+	assert.Error(t, OverlayText(make([]byte, 10), "HI", 0, 0, opts))
+}
+
+func TestDrawCaption(t *testing.T) {
+	opts := DefaultTextOptions()
+	buf := graphic.NewBuffer()
+	require.NoError(t, DrawCaption(buf, "HI", opts))
+
+	// Only the bottom rows are touched
+	top := graphic.DisplayHeight - FontHeight - CaptionMargin
+	assert.Equal(t, graphic.NewBuffer()[:top*graphic.DisplayWidth*3], buf[:top*graphic.DisplayWidth*3])
+	assert.NotEqual(t, graphic.NewBuffer(), buf)
+
+	assert.ErrorContains(t, DrawCaption(buf, "A CAPTION FAR TOO LONG", opts), "caption too long")
+}
+
+func TestCaptionImage(t *testing.T) {
+	opts := DefaultTextOptions()
+
+	t.Run("static image", func(t *testing.T) {
+		img := &graphic.Image{Type: graphic.ImageTypeStatic, StaticData: graphic.NewBuffer()}
+		captioned, err := CaptionImage(img, "HI", opts)
+		require.NoError(t, err)
+		assert.NotEqual(t, img.StaticData, captioned.StaticData)
+		assert.Equal(t, graphic.NewBuffer(), img.StaticData, "the original image is not modified")
+	})
+
+	t.Run("animated image", func(t *testing.T) {
+		img := &graphic.Image{
+			Type: graphic.ImageTypeAnimated,
+			GIFData: &gif.GIF{
+				Image: []*image.Paletted{
+					graphic.RGBToPaletted(graphic.NewBufferWithColor(graphic.Red)),
+					graphic.RGBToPaletted(graphic.NewBufferWithColor(graphic.Blue)),
+				},
+				Delay: []int{10, 20},
+			},
+		}
+		captioned, err := CaptionImage(img, "HI", opts)
+		require.NoError(t, err)
+		require.Len(t, captioned.GIFData.Image, 2)
+		assert.Equal(t, []int{10, 20}, captioned.GIFData.Delay)
+
+		// Every frame keeps its background and gets the caption, whose "H"
+		// starts at the top-left of the centered caption
+		captionX := (graphic.DisplayWidth - TextWidth("HI")) / 2
+		captionY := graphic.DisplayHeight - FontHeight - CaptionMargin
+		offset := (captionY*graphic.DisplayWidth + captionX) * 3
+		for i, want := range []graphic.Color{graphic.Red, graphic.Blue} {
+			frame, err := captioned.Frame(i)
+			require.NoError(t, err)
+			assert.Equal(t, want, graphic.Color(frame.StaticData[0:3]), "frame %d", i)
+			assert.Equal(t, opts.TextColor, graphic.Color(frame.StaticData[offset:offset+3]), "frame %d", i)
+		}
+	})
+}
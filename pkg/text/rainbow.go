@@ -0,0 +1,83 @@
+package text
+
+import (
+	"image"
+	"image/gif"
+	"math"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+)
+
+// Rainbow animation constants
+const (
+	rainbowFrames      = 36 // Frames for a full trip around the color wheel
+	rainbowFrameDelay  = 8  // 80ms per frame
+	rainbowLetterShift = 30 // Hue difference between adjacent letters (degrees)
+)
+
+// rainbowHue returns the hue (0-360) of the letter at letterIdx in the given frame.
+// The hue shifts by 360/rainbowFrames per frame so the last frame wraps
+// seamlessly into the first.
+func rainbowHue(frame, letterIdx int) float64 {
+	hue := float64(frame)*360/rainbowFrames + float64(letterIdx)*rainbowLetterShift
+	return math.Mod(hue, 360)
+}
+
+// GenerateRainbowText creates a looping animation where each letter has a
+// different hue and the whole phrase cycles through the color spectrum.
+// opts.TextColor is ignored, shadows are derived from each letter's color.
+// LoopCount = opts.Loops (0 loops forever)
+// Automatically wraps text to multiple lines if it doesn't fit.
+func GenerateRainbowText(text string, opts AnimationOptions) *graphic.Image {
+	lines := WrapTextWithOptions(text, opts.TextOptions)
+	startY := opts.blockStartY(lines)
+
+	frames := make([]*image.Paletted, rainbowFrames)
+	delays := make([]int, rainbowFrames)
+
+	for frame := 0; frame < rainbowFrames; frame++ {
+		buf := graphic.NewBufferWithColor(opts.Background)
+		drawRainbowLines(buf, lines, startY, frame, opts.TextOptions)
+		frames[frame] = graphic.RGBToPaletted(buf)
+		delays[frame] = rainbowFrameDelay
+	}
+
+	return &graphic.Image{
+		Type: graphic.ImageTypeAnimated,
+		GIFData: &gif.GIF{
+			Image:     frames,
+			Delay:     delays,
+			LoopCount: opts.Loops,
+		},
+	}
+}
+
+// drawRainbowLines draws lines aligned as set by opts.Align with per-letter
+// colors for the given frame. Shadows are drawn first so they never cover an
+// adjacent letter.
+func drawRainbowLines(buf []byte, lines []string, startY, frame int, opts TextOptions) {
+	for _, shadow := range []bool{true, false} {
+		letterIdx := 0
+		for lineIdx, line := range lines {
+			x := opts.lineX(textWidth(line, opts))
+			y := startY + lineIdx*opts.lineStep()
+			for _, char := range line {
+				m := charMetrics(char, opts.Proportional)
+				if char == ' ' {
+					x += m.advance
+					continue
+				}
+				color := graphic.HSVToColor(rainbowHue(frame, letterIdx), 1, 1)
+				if shadow {
+					if opts.ShadowX != 0 || opts.ShadowY != 0 {
+						DrawChar(buf, char, x-m.offset+opts.ShadowX, y+opts.ShadowY, graphic.ShadowFor(color))
+					}
+				} else {
+					DrawChar(buf, char, x-m.offset, y, color)
+				}
+				x += m.advance
+				letterIdx++
+			}
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package text
+
+import (
+	"image"
+	"image/gif"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+)
+
+// scrollFrameDelay is the delay between vertical scroll frames (50ms).
+const scrollFrameDelay = 5
+
+// VerticalScrollFrameCount returns the number of frames needed to scroll a
+// block of the given height from just below the display to just above it.
+func VerticalScrollFrameCount(blockHeight, step int) int {
+	if step < 1 {
+		step = 1
+	}
+	travel := graphic.DisplayHeight + blockHeight
+	return (travel + step - 1) / step
+}
+
+// GenerateVerticalScrollText creates a looping animation that scrolls lines
+// upward like movie credits, so content taller than the display is shown
+// over time. Lines too wide for the display are wrapped, empty lines are kept
+// as blank space. The text enters from the bottom and leaves at the top, so
+// the loop restarts seamlessly. opts.ScrollStep sets the speed in pixels per frame.
+// LoopCount = opts.Loops (0 loops forever)
+func GenerateVerticalScrollText(lines []string, opts AnimationOptions) *graphic.Image {
+	var wrapped []string
+	for _, line := range lines {
+		if len(line) == 0 {
+			wrapped = append(wrapped, "")
+			continue
+		}
+		wrapped = append(wrapped, WrapTextWithOptions(line, opts.TextOptions)...)
+	}
+
+	step := max(opts.ScrollStep, 1)
+	numFrames := VerticalScrollFrameCount(TextBlockHeightWithOptions(wrapped, opts.TextOptions), step)
+
+	frames := make([]*image.Paletted, numFrames)
+	delays := make([]int, numFrames)
+
+	for frame := 0; frame < numFrames; frame++ {
+		buf := graphic.NewBufferWithColor(opts.Background)
+
+		// The viewport moves down a virtual canvas where the text block starts
+		// right below the first screen. Pixels outside the display are clipped.
+		top := graphic.DisplayHeight - frame*step
+		for i, line := range wrapped {
+			y := top + i*opts.lineStep()
+			if len(line) == 0 || y+FontHeight < 0 || y >= graphic.DisplayHeight {
+				continue
+			}
+			x := opts.lineX(textWidth(line, opts.TextOptions))
+			DrawTextShadowed(buf, line, x, y, opts.TextOptions)
+		}
+
+		frames[frame] = graphic.RGBToPaletted(buf)
+		delays[frame] = scrollFrameDelay
+	}
+
+	return &graphic.Image{
+		Type: graphic.ImageTypeAnimated,
+		GIFData: &gif.GIF{
+			Image:     frames,
+			Delay:     delays,
+			LoopCount: opts.Loops,
+		},
+	}
+}
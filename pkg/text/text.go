@@ -1,6 +1,7 @@
 package text
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
@@ -13,8 +14,79 @@ type TextOptions struct {
 	Background  graphic.Color // Background fill color
 	ShadowX     int       // Shadow X offset (default: 1)
 	ShadowY     int       // Shadow Y offset (default: 1)
+
+	// Proportional uses per-glyph widths instead of FontSpacing for every
+	// character (default: false). Honored by the helpers taking TextOptions.
+	Proportional bool
+
+	// Outline draws the shadow color in all 8 directions around each glyph
+	// instead of a single offset shadow, for readability over busy
+	// backgrounds (default: false). Honored by DrawTextShadowed.
+	Outline bool
+
+	// Align is the horizontal alignment of multi-line text (default:
+	// AlignCenter). Honored by DrawMultiLineCentered and every text
+	// animation but the banner.
+	Align Align
+
+	// LineSpacing overrides the pixels between lines of multi-line text
+	// (default: 0, which uses the LineSpacing constant).
+	LineSpacing int
+}
+
+// Align is the horizontal alignment of text lines.
+type Align int
+
+// Alignments
+const (
+	AlignCenter Align = iota
+	AlignLeft
+	AlignRight
+)
+
+// ParseAlign parses an alignment name (left, center, right).
+func ParseAlign(name string) (Align, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "center", "":
+		return AlignCenter, nil
+	case "left":
+		return AlignLeft, nil
+	case "right":
+		return AlignRight, nil
+	default:
+		return AlignCenter, fmt.Errorf("unknown alignment: %s (valid: left, center, right)", name)
+	}
+}
+
+// lineX returns the x position of a line of the given width for opts.Align.
+func (opts TextOptions) lineX(lineWidth int) int {
+	switch opts.Align {
+	case AlignLeft:
+		return 0
+	case AlignRight:
+		return graphic.DisplayWidth - lineWidth
+	default:
+		return (graphic.DisplayWidth - lineWidth) / 2
+	}
 }
 
+// lineStep returns the vertical distance between the tops of two lines.
+func (opts TextOptions) lineStep() int {
+	if opts.LineSpacing != 0 {
+		return FontHeight + opts.LineSpacing
+	}
+	return FontHeight + LineSpacing
+}
+
+// blockStartY returns the y position of the first line of a vertically
+// centered block of lines.
+func (opts TextOptions) blockStartY(lines []string) int {
+	return (graphic.DisplayHeight - TextBlockHeightWithOptions(lines, opts)) / 2
+}
+
+// outlineOffsets are the 8 neighbor offsets drawn for an outline
+var outlineOffsets = [8][2]int{{-1, -1}, {0, -1}, {1, -1}, {-1, 0}, {1, 0}, {-1, 1}, {0, 1}, {1, 1}}
+
 // DefaultTextOptions returns sensible default options.
 func DefaultTextOptions() TextOptions {
 	return TextOptions{
@@ -26,21 +98,25 @@ func DefaultTextOptions() TextOptions {
 	}
 }
 
-// DrawTextShadowed draws text with a shadow at the given position.
-// Returns the width of the drawn text.
+// DrawTextShadowed draws text with a shadow (or an outline, if opts.Outline is
+// set) at the given position. Returns the width of the drawn text.
 func DrawTextShadowed(buf []byte, text string, x, y int, opts TextOptions) int {
 	// Draw shadow first (if offset is non-zero)
-	if opts.ShadowX != 0 || opts.ShadowY != 0 {
-		DrawText(buf, text, x+opts.ShadowX, y+opts.ShadowY, opts.ShadowColor)
+	if opts.Outline {
+		for _, offset := range outlineOffsets {
+			drawText(buf, text, x+offset[0], y+offset[1], opts.ShadowColor, opts)
+		}
+	} else if opts.ShadowX != 0 || opts.ShadowY != 0 {
+		drawText(buf, text, x+opts.ShadowX, y+opts.ShadowY, opts.ShadowColor, opts)
 	}
 	// Draw main text
-	return DrawText(buf, text, x, y, opts.TextColor)
+	return drawText(buf, text, x, y, opts.TextColor, opts)
 }
 
 // DrawTextCentered draws single-line text centered on the display with shadow.
 // Returns the calculated x, y position where the text was drawn.
 func DrawTextCentered(buf []byte, text string, opts TextOptions) (x, y int) {
-	textW := TextWidth(text)
+	textW := textWidth(text, opts)
 	x = (graphic.DisplayWidth - textW) / 2
 	y = (graphic.DisplayHeight - FontHeight) / 2
 	DrawTextShadowed(buf, text, x, y, opts)
@@ -50,6 +126,12 @@ func DrawTextCentered(buf []byte, text string, opts TextOptions) (x, y int) {
 // WrapText wraps text to fit within the display width.
 // Words are kept together when possible; long words are broken character by character.
 func WrapText(text string) []string {
+	return WrapTextWithOptions(text, TextOptions{})
+}
+
+// WrapTextWithOptions wraps text like WrapText, measuring lines with
+// proportional glyph widths when opts.Proportional is set.
+func WrapTextWithOptions(text string, opts TextOptions) []string {
 	words := strings.Fields(text)
 	if len(words) == 0 {
 		return []string{}
@@ -59,7 +141,7 @@ func WrapText(text string) []string {
 	var currentLine string
 
 	for _, word := range words {
-		wordWidth := TextWidth(word)
+		wordWidth := textWidth(word, opts)
 
 		// If word itself is too wide, break it character by character
 		if wordWidth > graphic.DisplayWidth {
@@ -73,7 +155,7 @@ func WrapText(text string) []string {
 			chunk := ""
 			for _, r := range runes {
 				testChunk := chunk + string(r)
-				if TextWidth(testChunk) > graphic.DisplayWidth {
+				if textWidth(testChunk, opts) > graphic.DisplayWidth {
 					if chunk != "" {
 						lines = append(lines, chunk)
 					}
@@ -93,7 +175,7 @@ func WrapText(text string) []string {
 		} else {
 			// Try adding word to current line with a space
 			testLine := currentLine + " " + word
-			if TextWidth(testLine) <= graphic.DisplayWidth {
+			if textWidth(testLine, opts) <= graphic.DisplayWidth {
 				currentLine = testLine
 			} else {
 				// Start new line
@@ -119,22 +201,30 @@ func TextBlockHeight(lines []string) int {
 	return len(lines)*FontHeight + (len(lines)-1)*LineSpacing
 }
 
-// DrawMultiLineCentered draws multi-line text centered both horizontally and vertically.
+// TextBlockHeightWithOptions calculates the total height of a multi-line text
+// block like TextBlockHeight, honoring opts.LineSpacing.
+func TextBlockHeightWithOptions(lines []string, opts TextOptions) int {
+	if len(lines) == 0 {
+		return 0
+	}
+	return (len(lines)-1)*opts.lineStep() + FontHeight
+}
+
+// DrawMultiLineCentered draws multi-line text centered vertically. Lines are
+// centered horizontally, or aligned to an edge as set by opts.Align.
 func DrawMultiLineCentered(buf []byte, lines []string, opts TextOptions) {
 	if len(lines) == 0 {
 		return
 	}
 
-	totalHeight := TextBlockHeight(lines)
-	startY := (graphic.DisplayHeight - totalHeight) / 2
+	startY := opts.blockStartY(lines)
 
 	for i, line := range lines {
 		if len(line) == 0 {
 			continue
 		}
-		lineWidth := TextWidth(line)
-		x := (graphic.DisplayWidth - lineWidth) / 2
-		y := startY + i*(FontHeight+LineSpacing)
+		x := opts.lineX(textWidth(line, opts))
+		y := startY + i*opts.lineStep()
 		DrawTextShadowed(buf, line, x, y, opts)
 	}
 }
@@ -143,8 +233,8 @@ func DrawMultiLineCentered(buf []byte, lines []string, opts TextOptions) {
 // Automatically wraps text to multiple lines if it doesn't fit.
 func GenerateStaticText(text string, opts TextOptions) *graphic.Image {
 	buf := graphic.NewBufferWithColor(opts.Background)
-	lines := WrapText(text)
-	if len(lines) <= 1 {
+	lines := WrapTextWithOptions(text, opts)
+	if len(lines) <= 1 && opts.Align == AlignCenter {
 		DrawTextCentered(buf, text, opts)
 	} else {
 		DrawMultiLineCentered(buf, lines, opts)
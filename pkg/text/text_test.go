@@ -112,3 +112,245 @@ func TestDrawChar(t *testing.T) {
 		assert.Equal(t, 0, w)
 	})
 }
+
+func TestDrawSmallText(t *testing.T) {
+	assert.Equal(t, 0, SmallTextWidth(""))
+	assert.Equal(t, 7, SmallTextWidth("2K"))
+
+	buf := graphic.NewBuffer()
+	w := DrawSmallText(buf, "2K", 10, 20, graphic.White)
+	assert.Equal(t, SmallTextWidth("2K"), w)
+
+	// Every lit pixel of the glyphs is drawn at its offset
+	for i, char := range "2K" {
+		glyph, ok := SmallGlyph(char)
+		require.True(t, ok)
+		for row, line := range glyph {
+			for col, c := range line {
+				offset := ((20+row)*graphic.DisplayWidth + 10 + i*SmallFontSpacing + col) * 3
+				assert.Equal(t, c == '#', buf[offset] == 255, "%c row %d col %d", char, row, col)
+			}
+		}
+	}
+
+	assert.Equal(t, 0, DrawSmallChar(buf, 'k', 0, 0, graphic.White), "no lowercase glyphs")
+}
+
+func TestLowercaseGlyphs(t *testing.T) {
+	pixelAt := func(buf []byte, x, y int) graphic.Color {
+		offset := (y*graphic.DisplayWidth + x) * 3
+		return graphic.Color{buf[offset], buf[offset+1], buf[offset+2]}
+	}
+
+	t.Run("all lowercase letters are defined", func(t *testing.T) {
+		for r := 'a'; r <= 'z'; r++ {
+			buf := graphic.NewBuffer()
+			assert.Equal(t, FontWidth, DrawChar(buf, r, 0, 0, graphic.White), "missing glyph %q", r)
+		}
+	})
+
+	t.Run("lowercase glyphs differ from uppercase", func(t *testing.T) {
+		for r := 'a'; r <= 'z'; r++ {
+			assert.NotEqual(t, font5x7[r-'a'+'A'], font5x7[r], "glyph %q is the same as uppercase", r)
+		}
+	})
+
+	t.Run("a has an empty top row and a bowl at the bottom", func(t *testing.T) {
+		buf := graphic.NewBuffer()
+		DrawChar(buf, 'a', 0, 0, graphic.White)
+		for x := 0; x < FontWidth; x++ {
+			assert.Equal(t, graphic.Black, pixelAt(buf, x, 0))
+		}
+		assert.Equal(t, graphic.White, pixelAt(buf, 0, 5))
+		assert.Equal(t, graphic.White, pixelAt(buf, 4, 5))
+	})
+
+	t.Run("l has its stem in the middle column", func(t *testing.T) {
+		buf := graphic.NewBuffer()
+		DrawChar(buf, 'l', 0, 0, graphic.White)
+		for y := 1; y < FontHeight; y++ {
+			assert.Equal(t, graphic.White, pixelAt(buf, 2, y))
+		}
+	})
+}
+
+func TestMixedCaseText(t *testing.T) {
+	for _, char := range "Hello, World! (it's 50% \"fun\"; a/b)" {
+		buf := graphic.NewBuffer()
+		assert.Equal(t, FontWidth, DrawChar(buf, char, 0, 0, graphic.White), "missing glyph %q", char)
+	}
+
+	// Mixed case renders the lowercase glyphs, not an uppercase substitution
+	mixed := graphic.NewBuffer()
+	DrawText(mixed, "Hi", 0, 0, graphic.White)
+	upper := graphic.NewBuffer()
+	DrawText(upper, "HI", 0, 0, graphic.White)
+	assert.NotEqual(t, upper, mixed)
+}
+
+func TestProportionalText(t *testing.T) {
+	t.Run("narrow glyphs take less space than wide ones", func(t *testing.T) {
+		assert.Equal(t, TextWidth("III"), TextWidth("WWW"))
+		assert.Less(t, TextWidthProportional("III"), TextWidthProportional("WWW"))
+	})
+
+	t.Run("glyph advances match inked columns", func(t *testing.T) {
+		// 'I' is inked in columns 1-3, 'W' in columns 0-4
+		assert.Equal(t, 3*4-1, TextWidthProportional("III"))
+		assert.Equal(t, 3*6-1, TextWidthProportional("WWW"))
+		assert.Equal(t, 0, TextWidthProportional(""))
+	})
+
+	t.Run("drawn width matches measured width", func(t *testing.T) {
+		buf := graphic.NewBuffer()
+		assert.Equal(t, TextWidthProportional("Hi 1."), DrawTextProportional(buf, "Hi 1.", 0, 0, graphic.White))
+	})
+
+	t.Run("left padding of the glyph is skipped", func(t *testing.T) {
+		// 'I' has its top bar in columns 1-3, drawn at x = 0-2 in proportional mode
+		buf := graphic.NewBuffer()
+		DrawTextProportional(buf, "I", 0, 0, graphic.White)
+		assert.Equal(t, []byte{255, 255, 255}, buf[0:3])
+		assert.Equal(t, []byte{0, 0, 0}, buf[9:12])
+	})
+
+	t.Run("wrapping packs more characters per line", func(t *testing.T) {
+		msg := strings.Repeat("I", 12)
+		assert.Len(t, WrapText(msg), 2)
+
+		opts := DefaultTextOptions()
+		opts.Proportional = true
+		assert.Len(t, WrapTextWithOptions(msg, opts), 1)
+	})
+
+	t.Run("fixed width is the default", func(t *testing.T) {
+		assert.False(t, DefaultTextOptions().Proportional)
+	})
+}
+
+func TestDrawTextOutline(t *testing.T) {
+	pixelAt := func(buf []byte, x, y int) graphic.Color {
+		offset := (y*graphic.DisplayWidth + x) * 3
+		return graphic.Color{buf[offset], buf[offset+1], buf[offset+2]}
+	}
+
+	opts := DefaultTextOptions()
+	opts.ShadowColor = graphic.Blue
+	opts.Outline = true
+
+	// 'I' has its stem in column 2
+	buf := graphic.NewBuffer()
+	DrawTextShadowed(buf, "I", 10, 10, opts)
+
+	t.Run("every glyph pixel is surrounded by glyph or outline pixels", func(t *testing.T) {
+		for y := 10; y < 10+FontHeight; y++ {
+			for x := 10; x < 10+FontWidth; x++ {
+				if pixelAt(buf, x, y) != opts.TextColor {
+					continue
+				}
+				for _, offset := range outlineOffsets {
+					neighbor := pixelAt(buf, x+offset[0], y+offset[1])
+					assert.Contains(t, []graphic.Color{opts.TextColor, opts.ShadowColor}, neighbor, "neighbor %v of (%d, %d)", offset, x, y)
+				}
+			}
+		}
+	})
+
+	t.Run("stem is outlined on both sides", func(t *testing.T) {
+		assert.Equal(t, opts.TextColor, pixelAt(buf, 12, 13))
+		assert.Equal(t, opts.ShadowColor, pixelAt(buf, 11, 13))
+		assert.Equal(t, opts.ShadowColor, pixelAt(buf, 13, 13))
+	})
+
+	t.Run("drop shadow is the default", func(t *testing.T) {
+		opts.Outline = false
+		buf := graphic.NewBuffer()
+		DrawTextShadowed(buf, "I", 10, 10, opts)
+		assert.Equal(t, opts.TextColor, pixelAt(buf, 12, 13))
+		assert.Equal(t, graphic.Black, pixelAt(buf, 11, 13))
+		assert.Equal(t, opts.ShadowColor, pixelAt(buf, 13, 13))
+	})
+}
+
+func TestDrawMultiLineAlignment(t *testing.T) {
+	lines := []string{"AB", "HELLO"}
+
+	// inkedColumns returns the first and last column drawn with the text
+	// color in the rows of each line
+	inkedColumns := func(buf []byte, opts TextOptions) [][2]int {
+		var columns [][2]int
+		startY := opts.blockStartY(lines)
+		for i := range lines {
+			first, last := -1, -1
+			for y := startY + i*opts.lineStep(); y < startY+i*opts.lineStep()+FontHeight; y++ {
+				for x := 0; x < graphic.DisplayWidth; x++ {
+					offset := (y*graphic.DisplayWidth + x) * 3
+					if (graphic.Color{buf[offset], buf[offset+1], buf[offset+2]}) != opts.TextColor {
+						continue
+					}
+					if first < 0 || x < first {
+						first = x
+					}
+					last = max(last, x)
+				}
+			}
+			columns = append(columns, [2]int{first, last})
+		}
+		return columns
+	}
+
+	t.Run("left alignment starts every line at x=0", func(t *testing.T) {
+		opts := DefaultTextOptions()
+		opts.Align = AlignLeft
+		buf := graphic.NewBuffer()
+		DrawMultiLineCentered(buf, lines, opts)
+
+		columns := inkedColumns(buf, opts)
+		assert.Equal(t, 0, columns[0][0])
+		assert.Equal(t, 0, columns[1][0])
+		// The shadow of the 'A' left stem (rows 1-6 of column 0) is still drawn
+		offset := ((opts.blockStartY(lines)+2)*graphic.DisplayWidth + 1) * 3
+		assert.Equal(t, opts.ShadowColor, graphic.Color{buf[offset], buf[offset+1], buf[offset+2]})
+	})
+
+	t.Run("right alignment flushes every line to the right edge", func(t *testing.T) {
+		opts := DefaultTextOptions()
+		opts.Align = AlignRight
+		buf := graphic.NewBuffer()
+		DrawMultiLineCentered(buf, lines, opts)
+
+		columns := inkedColumns(buf, opts)
+		assert.Equal(t, graphic.DisplayWidth-1, columns[0][1])
+		assert.Equal(t, graphic.DisplayWidth-1, columns[1][1])
+	})
+
+	t.Run("center alignment is the default", func(t *testing.T) {
+		opts := DefaultTextOptions()
+		buf := graphic.NewBuffer()
+		DrawMultiLineCentered(buf, lines, opts)
+
+		columns := inkedColumns(buf, opts)
+		assert.Equal(t, (graphic.DisplayWidth-TextWidth("HELLO"))/2, columns[1][0])
+	})
+
+	t.Run("line spacing override", func(t *testing.T) {
+		opts := DefaultTextOptions()
+		assert.Equal(t, FontHeight+LineSpacing, opts.lineStep())
+		opts.LineSpacing = 1
+		assert.Equal(t, FontHeight+1, opts.lineStep())
+
+		// The block stays vertically centered
+		assert.Equal(t, (graphic.DisplayHeight-(2*FontHeight+1))/2, opts.blockStartY(lines))
+	})
+}
+
+func TestParseAlign(t *testing.T) {
+	for name, expected := range map[string]Align{"left": AlignLeft, "Center": AlignCenter, "right": AlignRight, "": AlignCenter} {
+		align, err := ParseAlign(name)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, align)
+	}
+
+	_, err := ParseAlign("justify")
+	assert.Error(t, err)
+}
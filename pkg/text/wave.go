@@ -0,0 +1,61 @@
+package text
+
+import (
+	"image"
+	"image/gif"
+	"math"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+)
+
+// Wave animation constants
+const (
+	waveFrames     = 16          // Frames for a full sine period
+	waveFrameDelay = 6           // 60ms per frame
+	waveAmplitude  = 3           // Max vertical offset in pixels
+	wavePhaseShift = math.Pi / 4 // Phase difference between adjacent letters
+)
+
+// waveOffset returns the vertical offset of the letter at letterIdx in the given frame.
+// A full sine period spans waveFrames, so the last frame wraps seamlessly into the first.
+func waveOffset(frame, letterIdx int) int {
+	angle := 2*math.Pi*float64(frame)/waveFrames + float64(letterIdx)*wavePhaseShift
+	return int(math.Round(waveAmplitude * math.Sin(angle)))
+}
+
+// GenerateWaveText creates a looping animation where letters bob up and down
+// in a sine wave, each letter offset by its index in the line.
+// LoopCount = opts.Loops (0 loops forever)
+// Automatically wraps text to multiple lines if it doesn't fit.
+func GenerateWaveText(text string, opts AnimationOptions) *graphic.Image {
+	lines := WrapTextWithOptions(text, opts.TextOptions)
+	startY := opts.blockStartY(lines)
+
+	frames := make([]*image.Paletted, waveFrames)
+	delays := make([]int, waveFrames)
+
+	for frame := 0; frame < waveFrames; frame++ {
+		buf := graphic.NewBufferWithColor(opts.Background)
+
+		for lineIdx, line := range lines {
+			x := opts.lineX(textWidth(line, opts.TextOptions))
+			y := startY + lineIdx*opts.lineStep()
+			for letterIdx, char := range []rune(line) {
+				DrawTextShadowed(buf, string(char), x, y+waveOffset(frame, letterIdx), opts.TextOptions)
+				x += charMetrics(char, opts.Proportional).advance
+			}
+		}
+
+		frames[frame] = graphic.RGBToPaletted(buf)
+		delays[frame] = waveFrameDelay
+	}
+
+	return &graphic.Image{
+		Type: graphic.ImageTypeAnimated,
+		GIFData: &gif.GIF{
+			Image:     frames,
+			Delay:     delays,
+			LoopCount: opts.Loops,
+		},
+	}
+}
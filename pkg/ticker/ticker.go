@@ -0,0 +1,86 @@
+// Package ticker periodically reads text from an external source, such as a
+// URL or a local file, for a news or status ticker.
+package ticker
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// MaxTextSize is the maximum number of bytes read from a source, keeping the
+// scroll animation to a reasonable GIF size
+const MaxTextSize = 512
+
+// fetchTimeout bounds how long fetching a URL source can take
+const fetchTimeout = 10 * time.Second
+
+// Ticker tracks the text of a source across refreshes.
+type Ticker struct {
+	source string
+	client *http.Client
+	text   string // Last text successfully fetched
+}
+
+// New creates a ticker reading from source: an http(s) URL or a file path.
+func New(source string) *Ticker {
+	return &Ticker{
+		source: source,
+		client: &http.Client{Timeout: fetchTimeout},
+	}
+}
+
+// Text returns the last text successfully fetched.
+func (t *Ticker) Text() string {
+	return t.text
+}
+
+// Refresh fetches the source and returns true if its text changed since the
+// previous refresh. On error the last text is kept, so callers can go on
+// showing it.
+func (t *Ticker) Refresh() (bool, error) {
+	text, err := t.fetch()
+	if err != nil {
+		return false, err
+	}
+	if text == "" {
+		return false, fmt.Errorf("source %s is empty", t.source)
+	}
+	if text == t.text {
+		return false, nil
+	}
+	t.text = text
+	return true, nil
+}
+
+// fetch reads the source text, with surrounding whitespace trimmed.
+func (t *Ticker) fetch() (string, error) {
+	var r io.Reader
+	if strings.HasPrefix(t.source, "http://") || strings.HasPrefix(t.source, "https://") {
+		resp, err := t.client.Get(t.source)
+		if err != nil {
+			return "", fmt.Errorf("failed to fetch %s: %w", t.source, err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("failed to fetch %s: unexpected status %s", t.source, resp.Status)
+		}
+		r = resp.Body
+	} else {
+		f, err := os.Open(t.source)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", t.source, err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, MaxTextSize))
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", t.source, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
@@ -0,0 +1,92 @@
+package ticker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshFromURL(t *testing.T) {
+	var (
+		mx     sync.Mutex
+		text   = "FIRST"
+		status = http.StatusOK
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mx.Lock()
+		defer mx.Unlock()
+		w.WriteHeader(status)
+		w.Write([]byte(text + "\n"))
+	}))
+	defer server.Close()
+
+	tk := New(server.URL)
+
+	changed, err := tk.Refresh()
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "FIRST", tk.Text())
+
+	// Same content
+	changed, err = tk.Refresh()
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	// Changed content is picked up
+	mx.Lock()
+	text = "SECOND"
+	mx.Unlock()
+	changed, err = tk.Refresh()
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "SECOND", tk.Text())
+
+	// Errors keep the last content
+	mx.Lock()
+	status = http.StatusInternalServerError
+	mx.Unlock()
+	changed, err = tk.Refresh()
+	assert.Error(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, "SECOND", tk.Text())
+}
+
+func TestRefreshFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ticker.txt")
+	require.NoError(t, os.WriteFile(path, []byte("  HELLO  \n"), 0o644))
+
+	tk := New(path)
+	changed, err := tk.Refresh()
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "HELLO", tk.Text())
+
+	// Empty content is an error and keeps the last content
+	require.NoError(t, os.WriteFile(path, []byte("\n"), 0o644))
+	_, err = tk.Refresh()
+	assert.Error(t, err)
+	assert.Equal(t, "HELLO", tk.Text())
+
+	// Missing file
+	require.NoError(t, os.Remove(path))
+	_, err = tk.Refresh()
+	assert.Error(t, err)
+	assert.Equal(t, "HELLO", tk.Text())
+}
+
+func TestRefreshLimitsTextSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ticker.txt")
+	require.NoError(t, os.WriteFile(path, []byte(strings.Repeat("A", MaxTextSize*2)), 0o644))
+
+	tk := New(path)
+	_, err := tk.Refresh()
+	require.NoError(t, err)
+	assert.Len(t, tk.Text(), MaxTextSize)
+}
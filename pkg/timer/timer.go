@@ -0,0 +1,47 @@
+// Package timer renders a countdown timer for the iDotMatrix display.
+package timer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/protocol"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/text"
+)
+
+// FormatRemaining formats the remaining duration as MM:SS.
+// Partial seconds are rounded up so the display only shows 00:00 once the
+// countdown is over. Minutes are not wrapped into hours (3661s is "61:01").
+func FormatRemaining(d time.Duration) string {
+	if d <= 0 {
+		return "00:00"
+	}
+	secs := int((d + time.Second - 1) / time.Second)
+	return fmt.Sprintf("%02d:%02d", secs/60, secs%60)
+}
+
+// Renderer handles diff-based rendering of the countdown to the device
+type Renderer struct {
+	*protocol.DiffRenderer
+	opts text.TextOptions
+}
+
+// NewRenderer creates a new renderer
+func NewRenderer(device protocol.DeviceConnection, opts text.TextOptions) *Renderer {
+	r := &Renderer{
+		DiffRenderer: protocol.NewDiffRenderer(device),
+		opts:         opts,
+	}
+	background := graphic.NewBufferWithColor(opts.Background)
+	r.SetPrevBuffer(background)
+	r.SetCurrBuffer(background)
+	return r
+}
+
+// Render draws the remaining time centered on the current buffer.
+// This is a pure function that updates the buffer without I/O
+func (r *Renderer) Render(remaining time.Duration) {
+	copy(r.Buffer(), graphic.NewBufferWithColor(r.opts.Background))
+	text.DrawTextCentered(r.Buffer(), FormatRemaining(remaining), r.opts)
+}
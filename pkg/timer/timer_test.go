@@ -0,0 +1,58 @@
+package timer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pracucci/idotmatrix-overclocked/pkg/graphic"
+	"github.com/pracucci/idotmatrix-overclocked/pkg/text"
+)
+
+func TestFormatRemaining(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    time.Duration
+		expected string
+	}{
+		{name: "zero", input: 0, expected: "00:00"},
+		{name: "negative", input: -5 * time.Second, expected: "00:00"},
+		{name: "seconds only", input: 9 * time.Second, expected: "00:09"},
+		{name: "90 seconds", input: 90 * time.Second, expected: "01:30"},
+		{name: "over an hour keeps counting minutes", input: 3661 * time.Second, expected: "61:01"},
+		{name: "partial second rounds up", input: 1500 * time.Millisecond, expected: "00:02"},
+		{name: "just under a minute", input: 59*time.Second + time.Millisecond, expected: "01:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, FormatRemaining(tt.input))
+		})
+	}
+}
+
+func TestRendererComputeDiff(t *testing.T) {
+	r := NewRenderer(nil, text.DefaultTextOptions())
+
+	// Nothing rendered yet
+	assert.Empty(t, r.ComputeDiff())
+
+	// Rendering the same time twice only differs from the blank buffer once
+	r.Render(90 * time.Second)
+	assert.NotEmpty(t, r.ComputeDiff())
+	r.SetPrevBuffer(r.Buffer())
+	r.Render(90 * time.Second)
+	assert.Empty(t, r.ComputeDiff())
+
+	// Going from 01:30 to 01:29 only touches the last two digits
+	r.Render(89 * time.Second)
+	diff := r.ComputeDiff()
+	assert.NotEmpty(t, diff)
+	lastDigitsX := (graphic.DisplayWidth-text.TextWidth("01:29"))/2 + 3*text.FontSpacing
+	for _, points := range diff {
+		for _, p := range points {
+			assert.GreaterOrEqual(t, p.X, lastDigitsX)
+		}
+	}
+}
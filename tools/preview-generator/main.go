@@ -8,6 +8,7 @@ import (
 	"image"
 	"image/gif"
 	"io"
+	"math/rand"
 	"os"
 	"path/filepath"
 
@@ -22,6 +23,15 @@ import (
 
 const previewDir = "pkg/assets/preview"
 
+// firePreviewSeed makes the fire preview reproducible across runs.
+const firePreviewSeed = 42
+
+// tetrisPreviewSeed makes the pieces dealt in the tetris preview reproducible across runs.
+const tetrisPreviewSeed = 42
+
+// tetrisPreviewPieces is how many pieces the bot places in the tetris preview.
+const tetrisPreviewPieces = 5
+
 func main() {
 	if err := os.MkdirAll(previewDir, 0755); err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create %s directory: %v\n", previewDir, err)
@@ -104,7 +114,7 @@ func generateGrotPreview(outputPath string) error {
 
 // generateFirePreview generates a DOOM-style fire animation.
 func generateFirePreview(outputPath string) error {
-	gifData := fire.GenerateGIF()
+	gifData := fire.GenerateGIFSeeded(firePreviewSeed)
 	return os.WriteFile(outputPath, gifData, 0644)
 }
 
@@ -227,7 +237,10 @@ func brightenBackground(buf []byte) {
 
 // generateTetrisPreview creates a tetris game preview GIF with two phases:
 // 1. Cover image (1s)
-// 2. Gameplay simulation - T piece dropping to the bottom
+// 2. Gameplay simulation - the auto-play bot placing the first pieces
+//
+// Pieces are hard dropped like the bot does in the game, rather than falling
+// one row per frame, which kept the GIF several times larger.
 func generateTetrisPreview(outputPath string) error {
 	var frames []*image.Paletted
 	var delays []int
@@ -238,35 +251,49 @@ func generateTetrisPreview(outputPath string) error {
 	frames = append(frames, coverFrame)
 	delays = append(delays, 100) // 1 second
 
-	// Phase 2: Gameplay simulation - T piece dropping from top to bottom
+	// Phase 2: Gameplay simulation - the bot moves each piece to its best
+	// placement, then hard drops it
 	background := tetris.GenerateGameBackground()
-	board := tetris.NewBoard()
+	renderer := tetris.NewRenderer(nil)
 
-	renderer := &tetris.Renderer{}
-	renderer.SetPrevBuffer(background)
+	state := tetris.NewGameState()
+	bag := tetris.NewBag(rand.New(rand.NewSource(tetrisPreviewSeed)))
+	state.Next = bag.Next()
+	for i := 1; i < tetris.PreviewSize; i++ {
+		state.Queue = append(state.Queue, bag.Next())
+	}
 
-	// T piece at rotation 0 has cells at relative positions: (1,0), (0,1), (1,1), (2,1)
-	// So the lowest cell is at Y+1, meaning piece can go to Y = BoardHeight - 2
-	maxY := tetris.BoardHeight - 2
+	addFrame := func() {
+		renderer.RenderState(state.Board, state.Current, background)
+		renderer.RenderPanels(state)
+		frames = append(frames, graphic.RGBToPaletted(renderer.GetCurrBuffer()))
+		delays = append(delays, 15) // 150ms per frame
+	}
 
-	for pieceY := 0; pieceY <= maxY; pieceY++ {
-		currentPiece := tetris.Tetromino{
-			Type:     tetris.TetrominoT,
-			Rotation: tetris.Rotation0,
-			X:        3,
-			Y:        pieceY,
+	for piece := 0; piece < tetrisPreviewPieces && state.SpawnPiece(bag.Next()); piece++ {
+		addFrame()
+
+		// Rotate and move until the bot would hard drop
+	moves:
+		for i := 0; i < 2*tetris.BoardWidth; i++ {
+			switch tetris.AutoKey(state) {
+			case 'w':
+				state.TryRotate()
+			case 'a':
+				state.TryMove(-1, 0)
+			case 'd':
+				state.TryMove(1, 0)
+			default:
+				break moves
+			}
+			addFrame()
 		}
 
-		renderer.RenderState(board, &currentPiece, background)
-		frameBuf := renderer.GetCurrBuffer()
-
-		// Make a copy of the buffer for the frame
-		frameCopy := make([]byte, len(frameBuf))
-		copy(frameCopy, frameBuf)
-
-		frames = append(frames, graphic.RGBToPaletted(frameCopy))
-		delays = append(delays, 10) // 100ms per frame
+		state.HardDrop()
+		state.LockAndClear()
 	}
+	addFrame()
+	delays[len(delays)-1] = 100 // Hold the final board for 1 second
 
 	// Encode and write GIF
 	g := &gif.GIF{